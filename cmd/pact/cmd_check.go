@@ -2,98 +2,483 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
+	"pact/internal/application/depgraph"
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+	"pact/internal/infrastructure/project"
+	"pact/internal/infrastructure/report"
 	"pact/pkg/pact"
 )
 
+type checkOptions struct {
+	missing       bool
+	cycles        bool   // --cycles: 関係の循環を検出する
+	cyclesDiagram string // --cycles-diagram: 循環を強調したクラス図の出力先
+	rules         bool   // --rules: .pactconfig の依存関係のルールを検査する
+	format        report.Format
+	threshold     float64
+	thresholdSet  bool
+	patterns      []string
+}
+
+func parseCheckOptions(args []string) (*checkOptions, error) {
+	opts := &checkOptions{format: report.FormatText}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--missing" || arg == "-m":
+			opts.missing = true
+		case arg == "--cycles":
+			opts.cycles = true
+		case arg == "--rules":
+			opts.rules = true
+		case arg == "--cycles-diagram":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			opts.cyclesDiagram = args[i]
+			opts.cycles = true
+		case arg == "--threshold":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			value, err := strconv.ParseFloat(strings.TrimSuffix(args[i], "%"), 64)
+			if err != nil || value < 0 || value > 100 {
+				return nil, fmt.Errorf("invalid threshold: %s (expected 0-100)", args[i])
+			}
+			opts.threshold = value
+			opts.thresholdSet = true
+		case arg == "--format" || arg == "-f":
+			format, err := parseFormatFlag(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.format = format
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		default:
+			opts.patterns = append(opts.patterns, arg)
+		}
+	}
+
+	return opts, nil
+}
+
 func cmdCheck(args []string) error {
-	showMissing := false
-	var patterns []string
+	opts, err := parseCheckOptions(args)
+	if err != nil {
+		return err
+	}
+	showMissing := opts.missing
+	patterns := opts.patterns
+	text := opts.format == report.FormatText
+	result := newReport()
+	var proj *project.Project
 
-	for _, arg := range args {
-		if arg == "--missing" || arg == "-m" {
-			showMissing = true
-		} else if !strings.HasPrefix(arg, "-") {
-			patterns = append(patterns, arg)
+	// パス指定なしでプロジェクト内ならソースコードに対するカバレッジを検査する
+	// 他の検査も指定されていれば、その後に続けて結果をまとめる
+	var coverageErr error
+	if showMissing && len(patterns) == 0 {
+		if loaded, err := project.Load("."); err == nil {
+			proj = loaded
+			coverageErr = checkCoverage(proj, opts, result)
+			if !opts.cycles && !opts.rules {
+				if !text {
+					if err := result.Write(os.Stdout, opts.format); err != nil {
+						return err
+					}
+				}
+				return coverageErr
+			}
+			showMissing = false
 		}
 	}
 
-	// Default to current directory
-	if len(patterns) == 0 {
-		patterns = []string{"."}
+	// 依存関係のルールは .pactconfig から読み込む
+	if opts.rules && proj == nil {
+		proj, err = project.Load(".")
+		if err != nil {
+			return fmt.Errorf("--rules requires a project: %w", err)
+		}
 	}
 
-	// Find all pact files
-	pactFiles := expandFiles(patterns)
+	pactFiles, err := checkTargets(patterns, proj)
+	if err != nil {
+		return err
+	}
 
 	if len(pactFiles) == 0 {
-		if showMissing {
-			fmt.Println("No .pact files found")
-			return fmt.Errorf("no files found")
+		if !text {
+			if err := result.Write(os.Stdout, opts.format); err != nil {
+				return err
+			}
 		}
-		fmt.Println("No .pact files found")
-		return nil
+		return fmt.Errorf("no .pact files found")
 	}
 
-	client := pact.New()
+	loader := newSpecLoader(pact.New())
 	hasErrors := false
 	components := make(map[string]bool)
-	dependencies := make(map[string][]string)
+	var dependencies []dependencyRef
+	graph := depgraph.New()
+	var specs []*ast.SpecFile
 
 	// Parse all files and collect components/dependencies
 	for _, file := range pactFiles {
-		spec, err := client.ParseFile(file)
+		spec, err := loader.load(file)
 		if err != nil {
-			fmt.Printf("Error parsing %s: %v\n", file, err)
+			if text {
+				fmt.Printf("Error parsing %s: %v\n", file, err)
+			}
+			for _, e := range flattenErrors(err) {
+				result.Add(relPath(file), e)
+			}
 			hasErrors = true
 			continue
 		}
 
-		// Collect components
-		if spec.Component != nil {
-			components[spec.Component.Name] = true
-			for _, rel := range spec.Component.Body.Relations {
-				if rel.Kind == "depends_on" {
-					dependencies[spec.Component.Name] = append(dependencies[spec.Component.Name], rel.Target)
-				}
-			}
-		}
+		graph.AddSpec(spec)
+		specs = append(specs, spec)
+
+		// Component は Components の最後の要素と同じなので Components だけを見る
 		for _, comp := range spec.Components {
 			components[comp.Name] = true
 			for _, rel := range comp.Body.Relations {
-				if rel.Kind == "depends_on" {
-					dependencies[comp.Name] = append(dependencies[comp.Name], rel.Target)
+				if rel.Kind == ast.RelationDependsOn {
+					dependencies = append(dependencies, dependencyRef{file: file, rel: rel})
+				}
+			}
+		}
+
+		// import 先で宣言されたコンポーネントも定義済みとして扱う
+		closure, err := loader.closure(spec)
+		if err != nil {
+			if text {
+				fmt.Printf("Error resolving imports of %s: %v\n", file, err)
+			}
+			result.Add(relPath(file), err)
+			hasErrors = true
+			continue
+		}
+		for _, imported := range closure[1:] {
+			graph.AddSpec(imported)
+			for _, comp := range imported.Components {
+				components[comp.Name] = true
+			}
+		}
+		// エイリアス付きの import は修飾名でも参照できる
+		imported, _ := loader.imports(file, spec)
+		for _, imp := range spec.Imports {
+			if target, ok := imported[imp.Path]; ok && imp.Alias != nil {
+				for _, comp := range target.Components {
+					components[*imp.Alias+"."+comp.Name] = true
 				}
 			}
 		}
 	}
 
 	// Check for missing dependencies
+	var missing []string
 	if showMissing {
-		missing := make(map[string]bool)
-		for _, deps := range dependencies {
-			for _, dep := range deps {
-				if !components[dep] {
-					missing[dep] = true
-				}
+		seen := make(map[string]bool)
+		for _, dep := range dependencies {
+			if components[dep.rel.Target] {
+				continue
+			}
+			result.Diagnostics = append(result.Diagnostics, errors.Diagnostic{
+				File:     relPath(dep.file),
+				Line:     dep.rel.Pos.Line,
+				Column:   dep.rel.Pos.Column,
+				Code:     errors.CodeMissingComponent,
+				Severity: errors.SeverityError,
+				Message:  fmt.Sprintf("component '%s' is not declared in any checked file", dep.rel.Target),
+			})
+			if !seen[dep.rel.Target] {
+				seen[dep.rel.Target] = true
+				missing = append(missing, dep.rel.Target)
 			}
 		}
+		sort.Strings(missing)
+	}
 
-		if len(missing) > 0 {
-			fmt.Println("Missing components:")
-			for name := range missing {
-				fmt.Printf("  - %s\n", name)
+	// Check for dependency cycles
+	var cycles []depgraph.Cycle
+	if opts.cycles {
+		cycles = graph.Cycles()
+		for _, cycle := range cycles {
+			rel := cycle.Edges[0].Relation
+			result.Diagnostics = append(result.Diagnostics, errors.Diagnostic{
+				File:     relPath(cycle.Edges[0].File),
+				Line:     rel.Pos.Line,
+				Column:   rel.Pos.Column,
+				Code:     errors.CodeDependencyCycle,
+				Severity: errors.SeverityError,
+				Message:  "dependency cycle: " + cycle.String(),
+			})
+		}
+		if opts.cyclesDiagram != "" && len(specs) > 0 {
+			if err := writeCyclesDiagram(loader.client, graph, cycles, opts.cyclesDiagram); err != nil {
+				return err
 			}
-			return fmt.Errorf("missing components found")
 		}
-		fmt.Println("No missing components")
 	}
 
+	// Check dependency rules
+	var violations []depgraph.Violation
+	if opts.rules {
+		violations = graph.CheckRules(proj.Config.Rules, func(file string) string {
+			return rulePath(proj, file)
+		})
+		for _, v := range violations {
+			result.Diagnostics = append(result.Diagnostics, errors.Diagnostic{
+				File:     relPath(v.Edge.File),
+				Line:     v.Edge.Relation.Pos.Line,
+				Column:   v.Edge.Relation.Pos.Column,
+				Code:     errors.CodeDependencyRule,
+				Severity: errors.SeverityError,
+				Message:  v.String(),
+			})
+		}
+	}
+
+	if !text {
+		if result.Properties == nil {
+			result.Properties = make(map[string]interface{})
+		}
+		result.Properties["files"] = len(pactFiles)
+		result.Properties["components"] = len(components)
+		if err := result.Write(os.Stdout, opts.format); err != nil {
+			return err
+		}
+	}
+
+	if opts.cycles && text {
+		printCycles(cycles)
+	}
+	if opts.rules && text {
+		printViolations(violations)
+	}
+
+	// 失敗した検査はまとめて1つのエラーにする
+	var failures []string
+	if coverageErr != nil {
+		failures = append(failures, coverageErr.Error())
+	}
+	if showMissing {
+		if len(missing) > 0 {
+			if text {
+				fmt.Println("Missing components:")
+				for _, name := range missing {
+					fmt.Printf("  - %s\n", name)
+				}
+			}
+			failures = append(failures, "missing components found")
+		} else if text {
+			fmt.Println("No missing components")
+		}
+	}
+	if len(cycles) > 0 {
+		failures = append(failures, "dependency cycles found")
+	}
+	if len(violations) > 0 {
+		failures = append(failures, "dependency rule violations found")
+	}
 	if hasErrors {
-		return fmt.Errorf("check failed")
+		failures = append(failures, "check failed")
 	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+
+	if text {
+		fmt.Printf("Checked %d files, found %d components\n", len(pactFiles), len(components))
+	}
+	return nil
+}
+
+// checkTargets は検査するファイルを返す
+// パス指定なしでプロジェクト内なら pact_root の仕様を、それ以外はカレントディレクトリの仕様を検査する
+func checkTargets(patterns []string, proj *project.Project) ([]string, error) {
+	if len(patterns) > 0 {
+		return expandExisting(patterns)
+	}
+	if proj == nil {
+		loaded, err := project.Load(".")
+		if err != nil {
+			return expandExisting([]string{"."})
+		}
+		proj = loaded
+	}
+	return proj.PactFiles()
+}
+
+// printCycles は循環ごとに、循環を作る関係の位置を表示する
+func printCycles(cycles []depgraph.Cycle) {
+	if len(cycles) == 0 {
+		fmt.Println("No dependency cycles")
+		return
+	}
+	fmt.Println("Dependency cycles:")
+	for _, cycle := range cycles {
+		fmt.Printf("  %s\n", cycle)
+		for _, edge := range cycle.Edges {
+			fmt.Printf("    %s:%s: %s\n", relPath(edge.File), edge.Relation.Pos, edge)
+		}
+	}
+}
+
+// rulePath はルールの path と比較する仕様のパスを返す
+// pact_root 内の仕様は pact_root から、それ以外はプロジェクトルートからの相対パスにする
+func rulePath(proj *project.Project, file string) string {
+	if rel, err := proj.RelPath(file); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return proj.ProjectPath(absPath(file))
+}
+
+// printViolations はルールへの違反を、違反した関係の位置とともに表示する
+func printViolations(violations []depgraph.Violation) {
+	if len(violations) == 0 {
+		fmt.Println("No dependency rule violations")
+		return
+	}
+	fmt.Println("Dependency rule violations:")
+	for _, v := range violations {
+		fmt.Printf("  %s:%s: %s\n", relPath(v.Edge.File), v.Edge.Relation.Pos, v)
+	}
+}
+
+// writeCyclesDiagram は全コンポーネントのクラス図を、循環するコンポーネントと関係を強調して書き出す
+func writeCyclesDiagram(client *pact.Client, graph *depgraph.Graph, cycles []depgraph.Cycle, path string) error {
+	spec := &ast.SpecFile{}
+	for _, node := range graph.Nodes() {
+		spec.Components = append(spec.Components, *node.Component)
+	}
+	diagram, err := client.ToClassDiagram(spec)
+	if err != nil {
+		return err
+	}
+
+	inCycle := make(map[string]bool)
+	cycleEdges := make(map[*ast.RelationDecl]bool)
+	for _, cycle := range cycles {
+		for _, edge := range cycle.Edges {
+			inCycle[edge.From] = true
+			cycleEdges[edge.Relation] = true
+		}
+	}
+	for i := range diagram.Nodes {
+		diagram.Nodes[i].Highlighted = inCycle[diagram.Nodes[i].ID]
+	}
+	for i := range diagram.Edges {
+		e := &diagram.Edges[i]
+		for _, edge := range graph.Edges() {
+			if cycleEdges[edge.Relation] && edge.From == e.From && edge.Relation.Target == e.To {
+				e.Highlighted = true
+			}
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := client.RenderClassDiagram(diagram, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote cycles diagram to %s\n", path)
+	return nil
+}
+
+// dependencyRef は depends on の参照元を表す
+type dependencyRef struct {
+	file string
+	rel  ast.RelationDecl
+}
+
+// checkCoverage は仕様がないソースファイルと孤立した仕様を報告する
+// カバレッジが閾値を下回る場合はエラーを返す
+// テキスト以外の形式では診断を result に追加し、出力は呼び出し側が行う
+func checkCoverage(proj *project.Project, opts *checkOptions, result *report.Report) error {
+	coverage, err := proj.Coverage()
+	if err != nil {
+		return err
+	}
+
+	percent := coverage.Percent()
+	threshold := proj.Config.CoverageThreshold
+	if opts.thresholdSet {
+		threshold = opts.threshold
+	}
+	belowThreshold := percent < threshold
+	thresholdErr := fmt.Errorf("coverage %.1f%% is below threshold %.1f%%", percent, threshold)
 
-	fmt.Printf("Checked %d files, found %d components\n", len(pactFiles), len(components))
+	if opts.format != report.FormatText {
+		for _, m := range coverage.Missing {
+			result.Diagnostics = append(result.Diagnostics, errors.Diagnostic{
+				File:     relPath(m.Source),
+				Code:     errors.CodeMissingSpec,
+				Severity: errors.SeverityWarning,
+				Message:  "no spec found (expected " + proj.ProjectPath(m.Expected) + ")",
+			})
+		}
+		for _, spec := range coverage.Orphaned {
+			result.Diagnostics = append(result.Diagnostics, errors.Diagnostic{
+				File:     relPath(spec),
+				Code:     errors.CodeOrphanedSpec,
+				Severity: errors.SeverityWarning,
+				Message:  "no source file corresponds to this spec",
+			})
+		}
+		if belowThreshold {
+			result.Diagnostics = append(result.Diagnostics, errors.Diagnostic{
+				Code:     errors.CodeCoverage,
+				Severity: errors.SeverityError,
+				Message:  thresholdErr.Error(),
+			})
+		}
+		result.Properties = map[string]interface{}{
+			"coverage":  percent,
+			"covered":   len(coverage.Covered),
+			"sources":   len(coverage.Sources),
+			"threshold": threshold,
+		}
+		if belowThreshold {
+			return thresholdErr
+		}
+		return nil
+	}
+
+	if len(coverage.Missing) > 0 {
+		fmt.Printf("Missing specs (%d):\n", len(coverage.Missing))
+		for _, m := range coverage.Missing {
+			fmt.Printf("  - %s -> %s\n", proj.ProjectPath(m.Source), proj.ProjectPath(m.Expected))
+		}
+	}
+	if len(coverage.Orphaned) > 0 {
+		fmt.Printf("Orphaned specs (%d):\n", len(coverage.Orphaned))
+		for _, spec := range coverage.Orphaned {
+			fmt.Printf("  - %s\n", proj.ProjectPath(spec))
+		}
+	}
+
+	fmt.Printf("Coverage: %.1f%% (%d/%d source files)\n", percent, len(coverage.Covered), len(coverage.Sources))
+
+	if belowThreshold {
+		return thresholdErr
+	}
 	return nil
 }
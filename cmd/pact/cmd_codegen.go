@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"pact/internal/domain/ast"
+	"pact/internal/infrastructure/codegen"
+	"pact/internal/infrastructure/project"
+	"pact/pkg/pact"
+)
+
+type codegenOptions struct {
+	target      string // go, ts, fsm
+	output      string // -o: 出力ディレクトリ
+	pkg         string // --package: Go のパッケージ名
+	declaration bool   // --dts: TypeScript の .d.ts を出力する
+	patterns    []string
+}
+
+// codegenTargets は codegen のターゲット名（言語名の別名を含む）
+var codegenTargets = map[string]string{
+	"go":         "go",
+	"golang":     "go",
+	"ts":         "ts",
+	"typescript": "ts",
+	"fsm":        "fsm",
+}
+
+func parseCodegenOptions(args []string) (*codegenOptions, error) {
+	opts := &codegenOptions{output: "."}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-o" || arg == "--output" || arg == "-p" || arg == "--package":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if arg == "-o" || arg == "--output" {
+				opts.output = args[i]
+			} else {
+				opts.pkg = args[i]
+			}
+		case arg == "--dts" || arg == "--declaration":
+			opts.declaration = true
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		case opts.target == "" && len(opts.patterns) == 0 && isCodegenTarget(arg):
+			opts.target = arg
+		default:
+			opts.patterns = append(opts.patterns, arg)
+		}
+	}
+
+	if opts.target == "" {
+		return opts, nil
+	}
+	target, ok := codegenTargets[strings.ToLower(opts.target)]
+	if !ok {
+		return nil, fmt.Errorf("unknown codegen target: %s (expected: go, ts, fsm)", opts.target)
+	}
+	opts.target = target
+	return opts, nil
+}
+
+// isCodegenTarget は最初の引数がターゲット名かどうかを判定する
+// 既知のターゲットでなくても、パスにもパターンにも見えない名前はターゲットとして扱う（エラーにするため）
+func isCodegenTarget(arg string) bool {
+	if _, ok := codegenTargets[strings.ToLower(arg)]; ok {
+		return true
+	}
+	if _, err := os.Stat(arg); err == nil {
+		return false
+	}
+	return !strings.ContainsAny(arg, "./\\*?[")
+}
+
+func cmdCodegen(args []string) error {
+	opts, err := parseCodegenOptions(args)
+	if err != nil {
+		return err
+	}
+
+	files, err := validateTargets(opts.patterns)
+	if err != nil {
+		return err
+	}
+
+	// プロジェクト内の仕様は pact_root からのディレクトリ構造を出力先に反映する
+	proj, _ := project.Load(".")
+	if opts.target == "" {
+		// ターゲットを省略した場合はプロジェクトの language に従う
+		if proj == nil {
+			return fmt.Errorf("missing target (expected: go, ts, fsm)")
+		}
+		target, ok := codegenTargets[strings.ToLower(proj.Config.Language)]
+		if !ok {
+			return fmt.Errorf("codegen is not supported for language: %s", proj.Config.Language)
+		}
+		opts.target = target
+	}
+	if proj != nil && proj.Config.Codegen.TypeScript.Declaration {
+		opts.declaration = true
+	}
+
+	loader := newSpecLoader(pact.New())
+	written, unchanged := 0, 0
+
+	for _, file := range files {
+		spec, err := loader.load(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", relPath(file), err)
+		}
+
+		dir := opts.output
+		if proj != nil {
+			if rel, err := proj.RelPath(file); err == nil && !strings.HasPrefix(rel, "..") {
+				dir = filepath.Join(opts.output, filepath.Dir(rel))
+			}
+		}
+		stem := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+		var path string
+		var content []byte
+		switch opts.target {
+		case "fsm":
+			// states ブロックのないファイルからは生成しない
+			if !codegen.HasStates(spec) {
+				continue
+			}
+			path = filepath.Join(dir, stem+"_fsm.go")
+			content, err = generateFSM(spec, file, dir, opts.pkg)
+		case "ts":
+			path = filepath.Join(dir, stem+".ts")
+			if opts.declaration {
+				path = filepath.Join(dir, stem+".d.ts")
+			}
+			content, err = generateTS(spec, file, loader, proj, opts.declaration)
+		default:
+			path = filepath.Join(dir, stem+"_gen.go")
+			content, err = generateGo(spec, file, dir, opts.pkg)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", relPath(file), err)
+		}
+
+		// 内容が同じなら書き込まない（go generate で繰り返し実行しても差分を出さない）
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+			unchanged++
+			continue
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("  Generated %s\n", relPath(path))
+		written++
+	}
+
+	fmt.Printf("Generated %d file(s), %d unchanged\n", written, unchanged)
+	return nil
+}
+
+func generateGo(spec *ast.SpecFile, file, dir, pkg string) ([]byte, error) {
+	if pkg == "" {
+		pkg = packageNameFor(dir)
+	}
+	return codegen.Go(spec, codegen.GoOptions{Package: pkg, Source: filepath.ToSlash(relPath(file))})
+}
+
+func generateFSM(spec *ast.SpecFile, file, dir, pkg string) ([]byte, error) {
+	if pkg == "" {
+		pkg = packageNameFor(dir)
+	}
+	return codegen.FSM(spec, codegen.GoOptions{Package: pkg, Source: filepath.ToSlash(relPath(file))})
+}
+
+func generateTS(spec *ast.SpecFile, file string, loader *specLoader, proj *project.Project, declaration bool) ([]byte, error) {
+	// import 先の型名を知るために import されたファイルも読み込む（解決できない import は無視する）
+	imports, _ := loader.imports(file, spec)
+	opts := codegen.TSOptions{
+		Source:      filepath.ToSlash(relPath(file)),
+		Declaration: declaration,
+		Imports:     imports,
+	}
+	if proj != nil {
+		opts.Types = proj.Config.Codegen.TypeScript.Types
+	}
+	return codegen.TypeScript(spec, opts)
+}
+
+// packageNameFor は出力ディレクトリ名から Go のパッケージ名を導出する
+func packageNameFor(dir string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(absPath(dir))) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+		}
+	}
+	name := sb.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "model"
+	}
+	return name
+}
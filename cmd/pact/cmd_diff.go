@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pact/internal/application/specdiff"
+	"pact/internal/domain/ast"
+	"pact/internal/infrastructure/report"
+	"pact/pkg/pact"
+)
+
+// diff の終了コード（構文エラーは exitSyntaxError）
+const exitBreakingChanges = 5 // 破壊的な変更がある
+
+type diffOptions struct {
+	format report.Format
+	old    string
+	new    string
+}
+
+func parseDiffOptions(args []string) (*diffOptions, error) {
+	opts := &diffOptions{format: report.FormatText}
+
+	var paths []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format" || arg == "-f":
+			format, err := parseFormatFlag(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			if format == report.FormatSARIF {
+				return nil, fmt.Errorf("diff does not support format: %s", format)
+			}
+			opts.format = format
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		default:
+			paths = append(paths, arg)
+		}
+	}
+
+	if len(paths) != 2 {
+		return nil, fmt.Errorf("usage: pact diff [--format text|json] OLD NEW (files or directories)")
+	}
+	opts.old, opts.new = paths[0], paths[1]
+	return opts, nil
+}
+
+func cmdDiff(args []string) error {
+	opts, err := parseDiffOptions(args)
+	if err != nil {
+		return err
+	}
+
+	oldFiles, err := diffTargets(opts.old)
+	if err != nil {
+		return err
+	}
+	newFiles, err := diffTargets(opts.new)
+	if err != nil {
+		return err
+	}
+	oldDir, newDir := isDir(opts.old), isDir(opts.new)
+	if oldDir != newDir {
+		return fmt.Errorf("cannot compare a file with a directory: %s, %s", opts.old, opts.new)
+	}
+
+	loader := newSpecLoader(pact.New())
+	oldSpecs, oldErrors := loadDiffSpecs(loader, oldFiles)
+	newSpecs, newErrors := loadDiffSpecs(loader, newFiles)
+	if syntaxErrors := oldErrors + newErrors; syntaxErrors > 0 {
+		return &exitError{code: exitSyntaxError, message: fmt.Sprintf("diff failed: %d file(s) with syntax errors", syntaxErrors)}
+	}
+
+	changes := specdiff.Compare(oldSpecs, newSpecs)
+	breaking := 0
+	for _, c := range changes {
+		if c.Breaking {
+			breaking++
+		}
+	}
+
+	if opts.format == report.FormatJSON {
+		if err := writeDiffJSON(opts, changes, breaking); err != nil {
+			return err
+		}
+	} else {
+		printDiff(opts, changes, breaking)
+	}
+
+	if breaking > 0 {
+		return &exitError{code: exitBreakingChanges, message: fmt.Sprintf("%d breaking change(s)", breaking)}
+	}
+	return nil
+}
+
+// diffTargets は比較する .pact ファイルを返す（ディレクトリは再帰的に展開する）
+func diffTargets(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	return expandFiles([]string{filepath.Join(path, "...")}), nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// loadDiffSpecs は仕様を読み込み、読み込めなかったファイルを表示して件数を返す
+func loadDiffSpecs(loader *specLoader, files []string) ([]*ast.SpecFile, int) {
+	var specs []*ast.SpecFile
+	failed := 0
+	for _, file := range files {
+		spec, err := loader.load(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", relPath(file), err)
+			failed++
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	return specs, failed
+}
+
+// printDiff は変更を破壊的な変更・互換性のある変更の順に、宣言の位置とともに表示する
+func printDiff(opts *diffOptions, changes []specdiff.Change, breaking int) {
+	fmt.Printf("Comparing %s -> %s\n", opts.old, opts.new)
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+		return
+	}
+	for _, group := range []struct {
+		title    string
+		breaking bool
+		count    int
+	}{
+		{"Breaking changes", true, breaking},
+		{"Compatible changes", false, len(changes) - breaking},
+	} {
+		if group.count == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%d):\n", group.title, group.count)
+		for _, c := range changes {
+			if c.Breaking != group.breaking {
+				continue
+			}
+			fmt.Printf("  %s %s:%s: %s\n", diffSymbol(c.Kind), relPath(c.File), c.Pos, c)
+		}
+	}
+	fmt.Printf("\n%d breaking, %d compatible change(s)\n", breaking, len(changes)-breaking)
+}
+
+// diffSymbol は変更の種類を +・-・~ で表す
+func diffSymbol(kind specdiff.Kind) string {
+	switch kind {
+	case specdiff.KindAdded:
+		return "+"
+	case specdiff.KindRemoved:
+		return "-"
+	}
+	return "~"
+}
+
+// diffResult は JSON 出力
+type diffResult struct {
+	Old        string      `json:"old"`
+	New        string      `json:"new"`
+	Breaking   int         `json:"breaking"`
+	Compatible int         `json:"compatible"`
+	Changes    []diffEntry `json:"changes"`
+}
+
+// diffEntry は JSON 出力の1つの変更
+type diffEntry struct {
+	Kind     string `json:"kind"`
+	Element  string `json:"element"`
+	Name     string `json:"name"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	Breaking bool   `json:"breaking"`
+	Reason   string `json:"reason,omitempty"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+func writeDiffJSON(opts *diffOptions, changes []specdiff.Change, breaking int) error {
+	entries := make([]diffEntry, len(changes))
+	for i, c := range changes {
+		entries[i] = diffEntry{
+			Kind:     string(c.Kind),
+			Element:  string(c.Element),
+			Name:     c.Name,
+			Old:      c.Old,
+			New:      c.New,
+			Breaking: c.Breaking,
+			Reason:   c.Reason,
+			File:     filepath.ToSlash(relPath(c.File)),
+			Line:     c.Pos.Line,
+			Column:   c.Pos.Column,
+		}
+	}
+	data, err := json.MarshalIndent(diffResult{
+		Old:        opts.old,
+		New:        opts.new,
+		Breaking:   breaking,
+		Compatible: len(changes) - breaking,
+		Changes:    entries,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"pact/pkg/pact"
+)
+
+type fmtOptions struct {
+	write    bool // -w: ファイルを書き換える
+	list     bool // -l: 整形が必要なファイルを一覧する
+	check    bool // --check: 整形が必要なファイルがあれば失敗する
+	patterns []string
+}
+
+func parseFmtOptions(args []string) (*fmtOptions, error) {
+	opts := &fmtOptions{}
+
+	for _, arg := range args {
+		switch {
+		case arg == "-w" || arg == "--write":
+			opts.write = true
+		case arg == "-l" || arg == "--list":
+			opts.list = true
+		case arg == "--check":
+			opts.check = true
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		default:
+			opts.patterns = append(opts.patterns, arg)
+		}
+	}
+
+	return opts, nil
+}
+
+func cmdFmt(args []string) error {
+	opts, err := parseFmtOptions(args)
+	if err != nil {
+		return err
+	}
+
+	files, err := validateTargets(opts.patterns)
+	if err != nil {
+		return err
+	}
+
+	client := pact.New()
+	syntaxErrors := 0
+	var unformatted []string
+
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		out, err := client.Format(src)
+		if err != nil {
+			for _, e := range flattenErrors(err) {
+				fmt.Fprintf(os.Stderr, "%s:%v\n", relPath(file), e)
+			}
+			syntaxErrors++
+			continue
+		}
+
+		changed := !bytes.Equal(src, out)
+		if changed {
+			unformatted = append(unformatted, file)
+		}
+
+		switch {
+		case opts.list || opts.check:
+			if changed {
+				fmt.Println(relPath(file))
+			}
+		case !opts.write:
+			os.Stdout.Write(out)
+		}
+
+		if opts.write && changed {
+			if err := os.WriteFile(file, out, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	if syntaxErrors > 0 {
+		return &exitError{code: exitSyntaxError, message: fmt.Sprintf("fmt failed: %d file(s) with syntax errors", syntaxErrors)}
+	}
+	if opts.check && !opts.write && len(unformatted) > 0 {
+		return fmt.Errorf("%d file(s) need formatting", len(unformatted))
+	}
+	return nil
+}
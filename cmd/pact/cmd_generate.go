@@ -6,13 +6,17 @@ import (
 	"path/filepath"
 	"strings"
 
+	"pact/internal/infrastructure/config"
+	"pact/internal/infrastructure/project"
 	"pact/pkg/pact"
 )
 
 type generateOptions struct {
-	output string
-	types  []string
-	files  []string
+	output    string
+	types     []string
+	files     []string
+	outputSet bool // -o が明示されたか
+	typesSet  bool // -t が明示されたか
 }
 
 func parseGenerateOptions(args []string) (*generateOptions, error) {
@@ -30,12 +34,14 @@ func parseGenerateOptions(args []string) (*generateOptions, error) {
 			}
 			i++
 			opts.output = args[i]
+			opts.outputSet = true
 		case arg == "-t" || arg == "--type":
 			if i+1 >= len(args) {
 				return nil, fmt.Errorf("missing value for %s", arg)
 			}
 			i++
 			opts.types = strings.Split(args[i], ",")
+			opts.typesSet = true
 		case strings.HasPrefix(arg, "-"):
 			return nil, fmt.Errorf("unknown option: %s", arg)
 		default:
@@ -43,10 +49,6 @@ func parseGenerateOptions(args []string) (*generateOptions, error) {
 		}
 	}
 
-	if len(opts.files) == 0 {
-		return nil, fmt.Errorf("no input files specified")
-	}
-
 	return opts, nil
 }
 
@@ -56,6 +58,11 @@ func cmdGenerate(args []string) error {
 		return err
 	}
 
+	// ファイル指定がなければ .pactconfig に従ってプロジェクト全体を生成する
+	if len(opts.files) == 0 {
+		return generateProject(opts)
+	}
+
 	// Create output directory if needed
 	if opts.output != "." {
 		if err := os.MkdirAll(opts.output, 0755); err != nil {
@@ -80,43 +87,53 @@ func cmdGenerate(args []string) error {
 	client := pact.New()
 
 	for _, file := range files {
-		fmt.Printf("Processing %s...\n", file)
-
-		spec, err := client.ParseFile(file)
-		if err != nil {
-			return fmt.Errorf("failed to parse %s: %w", file, err)
+		if err := generateFile(client, file, opts.output, opts.types); err != nil {
+			return err
 		}
+	}
 
-		baseName := strings.TrimSuffix(filepath.Base(file), ".pact")
+	fmt.Println("Done!")
+	return nil
+}
 
-		// Generate class diagram
-		if shouldGenerate(opts.types, "class") {
-			if err := generateClassDiagram(client, spec, opts.output, baseName); err != nil {
-				fmt.Printf("  Warning: class diagram: %v\n", err)
-			} else {
-				fmt.Printf("  Generated %s_class.svg\n", baseName)
-			}
-		}
+// generateProject は .pactconfig を探し、pact_root 以下の全ファイルから図を生成する
+// 出力は pact_root のディレクトリ構造を output_dir 以下に反映する
+func generateProject(opts *generateOptions) error {
+	proj, err := project.Load(".")
+	if err != nil {
+		return fmt.Errorf("no input files specified and no %s found", config.ConfigFileName)
+	}
 
-		// Generate sequence diagrams
-		if shouldGenerate(opts.types, "sequence") {
-			if err := generateSequenceDiagrams(client, spec, opts.output, baseName); err != nil {
-				fmt.Printf("  Warning: sequence diagram: %v\n", err)
-			}
-		}
+	files, err := proj.PactFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .pact files found in %s", proj.PactRoot())
+	}
 
-		// Generate state diagrams
-		if shouldGenerate(opts.types, "state") {
-			if err := generateStateDiagrams(client, spec, opts.output, baseName); err != nil {
-				fmt.Printf("  Warning: state diagram: %v\n", err)
+	types := proj.Config.Diagrams
+	if opts.typesSet {
+		types = opts.types
+	}
+
+	client := pact.New()
+
+	for _, file := range files {
+		output := proj.OutputDirFor(file)
+		if opts.outputSet {
+			rel, err := proj.RelPath(file)
+			if err != nil {
+				return err
 			}
+			output = filepath.Join(opts.output, filepath.Dir(rel))
+		}
+		if err := os.MkdirAll(output, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
 		}
 
-		// Generate flowcharts
-		if shouldGenerate(opts.types, "flow") {
-			if err := generateFlowcharts(client, spec, opts.output, baseName); err != nil {
-				fmt.Printf("  Warning: flowchart: %v\n", err)
-			}
+		if err := generateFile(client, file, output, types); err != nil {
+			return err
 		}
 	}
 
@@ -124,6 +141,57 @@ func cmdGenerate(args []string) error {
 	return nil
 }
 
+// generateFile は1つの .pact ファイルから指定された種類の図を生成する
+func generateFile(client *pact.Client, file, output string, types []string) error {
+	fmt.Printf("Processing %s...\n", relPath(file))
+
+	// import 先も含めて読み込み、ファイルをまたぐ参照を解決する
+	files, err := client.ParseFileWithImports(file)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	generateDiagrams(client, files[0], files[1:], file, output, types)
+	return nil
+}
+
+// generateDiagrams はパース済みの仕様から指定された種類の図を生成する
+// imports は spec の import 先で、図の変換時に一緒に渡される
+// 個々の図の生成失敗は警告として出力し、処理は継続する
+func generateDiagrams(client *pact.Client, spec *pact.SpecFile, imports []*pact.SpecFile, file, output string, types []string) {
+	baseName := strings.TrimSuffix(filepath.Base(file), ".pact")
+
+	// Generate class diagram
+	if shouldGenerate(types, "class") {
+		if err := generateClassDiagram(client, spec, imports, output, baseName); err != nil {
+			fmt.Printf("  Warning: class diagram: %v\n", err)
+		} else {
+			fmt.Printf("  Generated %s_class.svg\n", baseName)
+		}
+	}
+
+	// Generate sequence diagrams
+	if shouldGenerate(types, "sequence") {
+		if err := generateSequenceDiagrams(client, spec, imports, output, baseName); err != nil {
+			fmt.Printf("  Warning: sequence diagram: %v\n", err)
+		}
+	}
+
+	// Generate state diagrams
+	if shouldGenerate(types, "state") {
+		if err := generateStateDiagrams(client, spec, imports, output, baseName); err != nil {
+			fmt.Printf("  Warning: state diagram: %v\n", err)
+		}
+	}
+
+	// Generate flowcharts
+	if shouldGenerate(types, "flow") {
+		if err := generateFlowcharts(client, spec, imports, output, baseName); err != nil {
+			fmt.Printf("  Warning: flowchart: %v\n", err)
+		}
+	}
+}
+
 func shouldGenerate(types []string, target string) bool {
 	for _, t := range types {
 		if t == "all" || t == target {
@@ -133,8 +201,8 @@ func shouldGenerate(types []string, target string) bool {
 	return false
 }
 
-func generateClassDiagram(client *pact.Client, spec *pact.SpecFile, output, baseName string) error {
-	diagram, err := client.ToClassDiagram(spec)
+func generateClassDiagram(client *pact.Client, spec *pact.SpecFile, imports []*pact.SpecFile, output, baseName string) error {
+	diagram, err := client.ToClassDiagram(spec, imports...)
 	if err != nil {
 		return err
 	}
@@ -149,7 +217,7 @@ func generateClassDiagram(client *pact.Client, spec *pact.SpecFile, output, base
 	return client.RenderClassDiagram(diagram, f)
 }
 
-func generateSequenceDiagrams(client *pact.Client, spec *pact.SpecFile, output, baseName string) error {
+func generateSequenceDiagrams(client *pact.Client, spec *pact.SpecFile, imports []*pact.SpecFile, output, baseName string) error {
 	// Find all flows
 	flows := getFlowNames(spec)
 	if len(flows) == 0 {
@@ -157,7 +225,7 @@ func generateSequenceDiagrams(client *pact.Client, spec *pact.SpecFile, output,
 	}
 
 	for _, flowName := range flows {
-		diagram, err := client.ToSequenceDiagram(spec, flowName)
+		diagram, err := client.ToSequenceDiagram(spec, flowName, imports...)
 		if err != nil {
 			continue
 		}
@@ -178,7 +246,7 @@ func generateSequenceDiagrams(client *pact.Client, spec *pact.SpecFile, output,
 	return nil
 }
 
-func generateStateDiagrams(client *pact.Client, spec *pact.SpecFile, output, baseName string) error {
+func generateStateDiagrams(client *pact.Client, spec *pact.SpecFile, imports []*pact.SpecFile, output, baseName string) error {
 	// Find all states
 	stateNames := getStateNames(spec)
 	if len(stateNames) == 0 {
@@ -186,7 +254,7 @@ func generateStateDiagrams(client *pact.Client, spec *pact.SpecFile, output, bas
 	}
 
 	for _, stateName := range stateNames {
-		diagram, err := client.ToStateDiagram(spec, stateName)
+		diagram, err := client.ToStateDiagram(spec, stateName, imports...)
 		if err != nil {
 			continue
 		}
@@ -207,7 +275,7 @@ func generateStateDiagrams(client *pact.Client, spec *pact.SpecFile, output, bas
 	return nil
 }
 
-func generateFlowcharts(client *pact.Client, spec *pact.SpecFile, output, baseName string) error {
+func generateFlowcharts(client *pact.Client, spec *pact.SpecFile, imports []*pact.SpecFile, output, baseName string) error {
 	// Find all flows
 	flows := getFlowNames(spec)
 	if len(flows) == 0 {
@@ -215,7 +283,7 @@ func generateFlowcharts(client *pact.Client, spec *pact.SpecFile, output, baseNa
 	}
 
 	for _, flowName := range flows {
-		diagram, err := client.ToFlowchart(spec, flowName)
+		diagram, err := client.ToFlowchart(spec, flowName, imports...)
 		if err != nil {
 			continue
 		}
@@ -3,30 +3,115 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
+
+	domainConfig "pact/internal/domain/config"
+	"pact/internal/infrastructure/config"
+	"pact/internal/infrastructure/project"
 )
 
+type initOptions struct {
+	cfg      *domainConfig.Config
+	scaffold bool
+}
+
+func parseInitOptions(args []string) (*initOptions, error) {
+	opts := &initOptions{cfg: domainConfig.Default()}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--scaffold" {
+			opts.scaffold = true
+			continue
+		}
+
+		switch arg {
+		case "--source-root", "--pact-root", "--output-dir", "--language", "--diagrams", "--exclude":
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return nil, fmt.Errorf("unknown option: %s", arg)
+			}
+			return nil, fmt.Errorf("unexpected argument: %s", arg)
+		}
+
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value for %s", arg)
+		}
+		i++
+		value := args[i]
+
+		switch arg {
+		case "--source-root":
+			opts.cfg.SourceRoot = value
+		case "--pact-root":
+			opts.cfg.PactRoot = value
+		case "--output-dir":
+			opts.cfg.OutputDir = value
+		case "--language":
+			if len(project.SourceExtensions(value)) == 0 {
+				return nil, fmt.Errorf("unsupported language: %s", value)
+			}
+			opts.cfg.Language = strings.ToLower(value)
+		case "--diagrams":
+			opts.cfg.Diagrams = splitList(value)
+		case "--exclude":
+			opts.cfg.Exclude = append(opts.cfg.Exclude, splitList(value)...)
+		}
+	}
+
+	return opts, nil
+}
+
 func cmdInit(args []string) error {
-	configPath := ".pactconfig"
+	opts, err := parseInitOptions(args)
+	if err != nil {
+		return err
+	}
+
+	configPath := config.ConfigFileName
 
 	// Check if already exists
 	if _, err := os.Stat(configPath); err == nil {
 		fmt.Println("Config file already exists, overwriting...")
 	}
 
-	config := `# Pact configuration file
-src: .
-output: diagrams
-types:
-  - class
-  - sequence
-  - state
-  - flow
-`
-
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+	if err := config.NewLoader().Save(configPath, opts.cfg); err != nil {
 		return fmt.Errorf("failed to create config: %w", err)
 	}
 
 	fmt.Println("Created .pactconfig")
+
+	if !opts.scaffold {
+		return nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	proj := project.New(wd, opts.cfg)
+	if _, err := os.Stat(proj.SourceRoot()); err != nil {
+		return fmt.Errorf("source_root not found: %s", opts.cfg.SourceRoot)
+	}
+
+	created, err := proj.CreateStubs()
+	for _, path := range created {
+		fmt.Printf("  Created %s\n", relPath(path))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Scaffolded %d spec(s) under %s\n", len(created), opts.cfg.PactRoot)
 	return nil
 }
+
+// splitList はカンマ区切りの値を分割し、空要素を除く
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"pact/internal/infrastructure/lsp"
+)
+
+func cmdLSP(args []string) error {
+	for _, arg := range args {
+		// エディタが付ける --stdio は既定の動作なので受け付ける
+		if arg != "--stdio" {
+			return fmt.Errorf("unknown option: %s", arg)
+		}
+	}
+	return lsp.NewServer(os.Stdin, os.Stdout, version).Run()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"pact/internal/infrastructure/project"
+	"pact/internal/infrastructure/scaffold"
+)
+
+type scaffoldOptions struct {
+	language string
+	force    bool // --force: 既存の仕様ファイルを上書きする
+	dryRun   bool // --dry-run: 書き込まずに生成内容を表示する
+}
+
+func parseScaffoldOptions(args []string) (*scaffoldOptions, error) {
+	opts := &scaffoldOptions{}
+
+	for _, arg := range args {
+		switch {
+		case arg == "--force" || arg == "-f":
+			opts.force = true
+		case arg == "--dry-run":
+			opts.dryRun = true
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		case opts.language == "":
+			opts.language = strings.ToLower(arg)
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	return opts, nil
+}
+
+func cmdScaffold(args []string) error {
+	opts, err := parseScaffoldOptions(args)
+	if err != nil {
+		return err
+	}
+
+	proj, err := project.Load(".")
+	if err != nil {
+		return err
+	}
+	if opts.language == "" {
+		opts.language = strings.ToLower(proj.Config.Language)
+	}
+	if opts.language != "go" {
+		return fmt.Errorf("scaffold is not supported for language: %s", opts.language)
+	}
+
+	files, err := scaffold.FromGo(proj)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		for i, f := range files {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("==> %s <==\n", relPath(f.Path))
+			os.Stdout.Write(f.Content())
+		}
+		return nil
+	}
+
+	written, err := scaffold.Write(files, opts.force)
+	for _, path := range written {
+		fmt.Printf("  Created %s\n", relPath(path))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Scaffolded %d spec(s) from %d source file(s)", len(written), len(files))
+	if skipped := len(files) - len(written); skipped > 0 {
+		fmt.Printf(", %d existing spec(s) kept (use --force to overwrite)", skipped)
+	}
+	fmt.Println()
+	return nil
+}
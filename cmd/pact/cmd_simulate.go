@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"pact/internal/application/simulator"
+	"pact/internal/domain/ast"
+	"pact/internal/domain/diagram/state"
+	"pact/internal/domain/statechart"
+	"pact/internal/infrastructure/formatter"
+	"pact/pkg/pact"
+)
+
+type simulateOptions struct {
+	states string // --states: 対象の states ブロック名
+	events string // -e: スクリプトを引数で渡す
+	repl   bool   // -i, --repl: 対話モード
+	trace  string // --trace: 通った経路を強調した状態図の出力先
+	file   string
+	script string // スクリプトファイル（省略時は標準入力）
+}
+
+func parseSimulateOptions(args []string) (*simulateOptions, error) {
+	opts := &simulateOptions{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--states" || arg == "-s" || arg == "-e" || arg == "--events" || arg == "--trace":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			switch arg {
+			case "--states", "-s":
+				opts.states = args[i]
+			case "--trace":
+				opts.trace = args[i]
+			default:
+				opts.events = args[i]
+			}
+		case arg == "-i" || arg == "--repl":
+			opts.repl = true
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		case opts.file == "":
+			opts.file = arg
+		case opts.script == "":
+			opts.script = arg
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	if opts.file == "" {
+		return nil, fmt.Errorf("no input file (usage: pact simulate [--states NAME] file.pact [script])")
+	}
+	if opts.script != "" && opts.events != "" {
+		return nil, fmt.Errorf("-e cannot be combined with a script file")
+	}
+	return opts, nil
+}
+
+func cmdSimulate(args []string) error {
+	opts, err := parseSimulateOptions(args)
+	if err != nil {
+		return err
+	}
+
+	client := pact.New()
+	spec, err := client.ParseFile(opts.file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", relPath(opts.file), err)
+	}
+	decl, err := findStates(spec, opts.states)
+	if err != nil {
+		return fmt.Errorf("%s: %w", relPath(opts.file), err)
+	}
+	sim := simulator.New(statechart.New(decl))
+
+	if opts.repl {
+		runREPL(sim, os.Stdin, os.Stdout)
+	} else {
+		script := opts.events
+		if script == "" {
+			var data []byte
+			if opts.script != "" {
+				data, err = os.ReadFile(opts.script)
+			} else {
+				data, err = io.ReadAll(os.Stdin)
+			}
+			if err != nil {
+				return err
+			}
+			script = string(data)
+		}
+		commands, err := simulator.ParseScript(script)
+		if err != nil {
+			return fmt.Errorf("script: %w", err)
+		}
+
+		printStep(os.Stdout, sim.Start())
+		for _, cmd := range commands {
+			printStep(os.Stdout, cmd.Apply(sim))
+		}
+	}
+
+	if opts.trace != "" {
+		return writeTrace(client, spec, decl.Name, sim, opts.trace)
+	}
+	return nil
+}
+
+// findStates は名前の states ブロックを探す（名前を省略した場合は唯一の states ブロック）
+func findStates(spec *ast.SpecFile, name string) (*ast.StatesDecl, error) {
+	components := spec.Components
+	if len(components) == 0 && spec.Component != nil {
+		components = []ast.ComponentDecl{*spec.Component}
+	}
+	var all []*ast.StatesDecl
+	for i := range components {
+		for j := range components[i].Body.States {
+			all = append(all, &components[i].Body.States[j])
+		}
+	}
+
+	var names []string
+	for _, decl := range all {
+		if decl.Name == name {
+			return decl, nil
+		}
+		names = append(names, decl.Name)
+	}
+	switch {
+	case len(all) == 0:
+		return nil, fmt.Errorf("no states block found")
+	case name != "":
+		return nil, fmt.Errorf("states not found: %s (available: %s)", name, strings.Join(names, ", "))
+	case len(all) > 1:
+		return nil, fmt.Errorf("multiple states blocks, choose one with --states (available: %s)", strings.Join(names, ", "))
+	}
+	return all[0], nil
+}
+
+// printStep は1つのステップの結果を表示する
+func printStep(w io.Writer, step *simulator.Step) {
+	trigger := step.Trigger
+	if strings.HasPrefix(trigger, "+") {
+		trigger += " (t=" + simulator.FormatDuration(step.At) + ")"
+	}
+	fmt.Fprintln(w, trigger)
+
+	for _, a := range step.Actions {
+		fmt.Fprintf(w, "  %-6s %s\n", a.Kind, actionText(a))
+	}
+
+	if step.Ignored {
+		if step.Unknown {
+			fmt.Fprintln(w, "  ignored (unknown event)")
+		} else {
+			fmt.Fprintln(w, "  ignored (no enabled transition)")
+		}
+	}
+	if len(step.Unbound) > 0 {
+		fmt.Fprintf(w, "  unbound %s\n", strings.Join(step.Unbound, ", "))
+	}
+	fmt.Fprintf(w, "  state  %s\n", step.Configuration)
+}
+
+func actionText(a simulator.Action) string {
+	if a.Kind == simulator.ActionTake {
+		return transitionText(a.Transition)
+	}
+	if a.State != nil {
+		return a.Name + " (" + a.State.Name + ")"
+	}
+	return a.Name
+}
+
+// transitionText は遷移を "From -> To on E when G" の形で表す
+func transitionText(t *statechart.Transition) string {
+	text := t.Source.Name + " -> " + t.Target.Name
+	switch {
+	case t.Event != "":
+		text += " on " + t.Event
+	case t.After > 0:
+		text += " after " + simulator.FormatDuration(t.After)
+	case t.Condition != nil:
+		text += " when " + formatter.Expr(t.Condition)
+	}
+	if t.Guard != nil {
+		text += " when " + formatter.Expr(t.Guard)
+	}
+	return text
+}
+
+const replHelp = `Enter events, bindings (key=value) or time advances (+5m), separated by spaces.
+  :state    show the current configuration
+  :events   list the events of the states block
+  :reset    restart from the initial state (bindings are kept)
+  :help     show this help
+  :quit     exit`
+
+// runREPL は対話モードでステップを1行ずつ実行する
+func runREPL(sim *simulator.Simulator, r io.Reader, w io.Writer) {
+	fmt.Fprintf(w, "Simulating %s. Type :help for commands.\n", sim.Chart().Name)
+	printStep(w, sim.Start())
+
+	scanner := bufio.NewScanner(r)
+	for {
+		fmt.Fprint(w, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(w)
+			return
+		}
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+			continue
+		case ":quit", ":q", ":exit":
+			return
+		case ":help", ":h":
+			fmt.Fprintln(w, replHelp)
+			continue
+		case ":state", ":s":
+			fmt.Fprintf(w, "  state  %s (t=%s)\n", sim.Configuration(), simulator.FormatDuration(sim.Now()))
+			continue
+		case ":events", ":e":
+			fmt.Fprintf(w, "  events %s\n", strings.Join(sim.Chart().Events(), ", "))
+			continue
+		case ":reset", ":r":
+			sim.Reset()
+			printStep(w, sim.Start())
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			fmt.Fprintf(w, "unknown command: %s (type :help)\n", line)
+			continue
+		}
+
+		commands, err := simulator.ParseScript(line)
+		if err != nil {
+			fmt.Fprintf(w, "error: %v\n", err)
+			continue
+		}
+		for _, cmd := range commands {
+			printStep(w, cmd.Apply(sim))
+		}
+	}
+}
+
+// writeTrace は通った状態と遷移を強調した状態図を書き出す
+func writeTrace(client *pact.Client, spec *ast.SpecFile, name string, sim *simulator.Simulator, path string) error {
+	diagram, err := client.ToStateDiagram(spec, name)
+	if err != nil {
+		return err
+	}
+
+	states, transitions := sim.Visited()
+	visited := make(map[string]bool)
+	for _, st := range states {
+		visited[st.Name] = true
+	}
+	highlightStates(diagram.States, visited)
+	for i := range diagram.Transitions {
+		t := &diagram.Transitions[i]
+		if t.From == "__initial__" {
+			t.Highlighted = visited[t.To]
+			continue
+		}
+		for _, taken := range transitions {
+			if taken.Source.Name == t.From && taken.Target.Name == t.To && sameTrigger(t.Trigger, taken) {
+				t.Highlighted = true
+			}
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := client.RenderStateDiagram(diagram, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote trace to %s\n", path)
+	return nil
+}
+
+func highlightStates(states []state.State, visited map[string]bool) {
+	for i := range states {
+		s := &states[i]
+		s.Highlighted = visited[s.Name]
+		highlightStates(s.Children, visited)
+		for j := range s.Regions {
+			highlightStates(s.Regions[j].States, visited)
+		}
+	}
+}
+
+// sameTrigger は状態図の遷移のトリガーが実行された遷移のものと一致するかどうかを返す
+func sameTrigger(trigger state.Trigger, t *statechart.Transition) bool {
+	switch trig := trigger.(type) {
+	case *state.EventTrigger:
+		return trig.Event == t.Event
+	case *state.AfterTrigger:
+		return t.After > 0 && statechart.Duration(ast.Duration{Value: trig.Duration.Value, Unit: trig.Duration.Unit}) == t.After
+	case *state.WhenTrigger:
+		return t.Condition != nil
+	}
+	return t.Event == "" && t.After == 0 && t.Condition == nil
+}
@@ -2,35 +2,218 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 
+	"pact/internal/application/validator"
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+	"pact/internal/infrastructure/project"
+	"pact/internal/infrastructure/report"
 	"pact/pkg/pact"
 )
 
+// validate の終了コード
+const (
+	exitSyntaxError   = 2 // 構文エラーがある
+	exitSemanticError = 3 // 構文は正しいが意味エラーがある
+	exitWarnings      = 4 // --strict で警告のみがある
+)
+
+type validateOptions struct {
+	strict   bool
+	format   report.Format
+	patterns []string
+}
+
+func parseValidateOptions(args []string) (*validateOptions, error) {
+	opts := &validateOptions{format: report.FormatText}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--strict":
+			opts.strict = true
+		case arg == "--format" || arg == "-f":
+			format, err := parseFormatFlag(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.format = format
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		default:
+			opts.patterns = append(opts.patterns, arg)
+		}
+	}
+
+	return opts, nil
+}
+
 func cmdValidate(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("no files specified")
+	opts, err := parseValidateOptions(args)
+	if err != nil {
+		return err
 	}
 
-	// Expand file patterns
-	files := expandFiles(args)
+	files, err := validateTargets(opts.patterns)
+	if err != nil {
+		return err
+	}
 
-	client := pact.New()
-	hasErrors := false
+	text := opts.format == report.FormatText
+	result := newReport()
+	loader := newSpecLoader(pact.New())
+	syntaxErrors := 0
 
 	for _, file := range files {
-		_, err := client.ParseFile(file)
-		if err != nil {
-			fmt.Printf("Error in %s: %v\n", file, err)
-			hasErrors = true
-		} else {
-			fmt.Printf("%s: OK\n", file)
+		before := len(result.Diagnostics)
+		syntaxErrors += validateFile(result, loader, file)
+		if text {
+			printFileStatus(file, result.Diagnostics[before:])
 		}
 	}
 
-	if hasErrors {
-		return fmt.Errorf("validation failed")
+	if err := result.Write(os.Stdout, opts.format); err != nil {
+		return err
 	}
 
+	errCount := result.Count(errors.SeverityError)
+	warnCount := result.Count(errors.SeverityWarning)
+	switch {
+	case syntaxErrors > 0:
+		return &exitError{code: exitSyntaxError, message: fmt.Sprintf("validation failed: %d syntax error(s)", syntaxErrors)}
+	case errCount > 0:
+		return &exitError{code: exitSemanticError, message: fmt.Sprintf("validation failed: %d error(s)", errCount)}
+	case opts.strict && warnCount > 0:
+		return &exitError{code: exitWarnings, message: fmt.Sprintf("validation failed: %d warning(s) in strict mode", warnCount)}
+	}
+
+	if !text {
+		return nil
+	}
+	if warnCount > 0 {
+		fmt.Printf("All files valid (%d warning(s))\n", warnCount)
+		return nil
+	}
 	fmt.Println("All files valid!")
 	return nil
 }
+
+// validateTargets は検証対象のファイルを返す
+// 引数がなければ .pactconfig のプロジェクト全体を対象にする
+// 対象のファイルが1つもなければエラーを返す
+func validateTargets(patterns []string) ([]string, error) {
+	if len(patterns) > 0 {
+		return expandExisting(patterns)
+	}
+
+	proj, err := project.Load(".")
+	if err != nil {
+		return nil, fmt.Errorf("no files specified and no .pactconfig found")
+	}
+	files, err := proj.PactFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .pact files found")
+	}
+	return files, nil
+}
+
+// validateFile はファイルをパースし、import を解決した上で全ての検証を実行する
+// 診断を result に追加し、構文エラーの件数を返す
+func validateFile(result *report.Report, loader *specLoader, file string) int {
+	display := relPath(file)
+
+	spec, err := loader.load(file)
+	if err != nil {
+		errs := flattenErrors(err)
+		for _, e := range errs {
+			result.Add(display, e)
+		}
+		return len(errs)
+	}
+
+	imports, importErrs := loader.imports(file, spec)
+	diags := importErrs
+
+	v := validator.NewValidator()
+	v.SetImports(imports)
+	v.SetSpecImports(loader.importMaps(imports))
+	diags = append(diags, flattenErrors(v.ValidateAll(spec))...)
+	for _, w := range v.GetWarnings().Warnings {
+		diags = append(diags, w)
+	}
+
+	sortByPosition(diags)
+	for _, e := range diags {
+		result.Add(display, e)
+	}
+	return 0
+}
+
+// printFileStatus はファイルごとの検証結果を1行で出力する
+func printFileStatus(file string, diags []errors.Diagnostic) {
+	errCount, warnCount := 0, 0
+	for _, d := range diags {
+		if d.Severity == errors.SeverityWarning {
+			warnCount++
+		} else {
+			errCount++
+		}
+	}
+	switch {
+	case errCount > 0:
+		fmt.Printf("%s: %d error(s), %d warning(s)\n", relPath(file), errCount, warnCount)
+	case warnCount > 0:
+		fmt.Printf("%s: OK (%d warning(s))\n", relPath(file), warnCount)
+	default:
+		fmt.Printf("%s: OK\n", relPath(file))
+	}
+}
+
+// flattenErrors は MultiError を個々のエラーに展開する
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if me, ok := err.(*errors.MultiError); ok {
+		var errs []error
+		for _, e := range me.Errors {
+			errs = append(errs, flattenErrors(e)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
+
+// sortByPosition はエラーを出現位置の順に並べる
+func sortByPosition(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, b := errorPos(errs[i]), errorPos(errs[j])
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// errorPos はエラーの位置情報を返す
+func errorPos(err error) ast.Position {
+	switch e := err.(type) {
+	case *errors.ParseError:
+		return e.Pos
+	case *errors.SemanticError:
+		return e.Pos
+	case *errors.ImportError:
+		return e.Pos
+	case *errors.ValidationError:
+		return e.Pos
+	case *errors.Warning:
+		return e.Pos
+	}
+	return ast.NoPos
+}
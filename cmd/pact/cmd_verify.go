@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"pact/internal/application/verifier"
+	"pact/internal/domain/errors"
+	"pact/internal/infrastructure/project"
+	"pact/internal/infrastructure/report"
+	"pact/internal/infrastructure/scaffold"
+	"pact/pkg/pact"
+)
+
+type verifyOptions struct {
+	format   report.Format
+	patterns []string
+}
+
+func parseVerifyOptions(args []string) (*verifyOptions, error) {
+	opts := &verifyOptions{format: report.FormatText}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format" || arg == "-f":
+			format, err := parseFormatFlag(args, &i)
+			if err != nil {
+				return nil, err
+			}
+			opts.format = format
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		default:
+			opts.patterns = append(opts.patterns, arg)
+		}
+	}
+
+	return opts, nil
+}
+
+func cmdVerify(args []string) error {
+	opts, err := parseVerifyOptions(args)
+	if err != nil {
+		return err
+	}
+
+	proj, err := project.Load(".")
+	if err != nil {
+		return fmt.Errorf("verify requires a .pactconfig: %w", err)
+	}
+	if lang := strings.ToLower(proj.Config.Language); lang != "go" {
+		return fmt.Errorf("verify is not supported for language: %s", lang)
+	}
+
+	files, err := validateTargets(opts.patterns)
+	if err != nil {
+		return err
+	}
+
+	// ソースコードから導出した仕様を、対応する仕様ファイルのパスごとに保持する
+	generated, err := scaffold.FromGo(proj)
+	if err != nil {
+		return err
+	}
+	loader := newSpecLoader(pact.New())
+	derived := make(map[string]*scaffold.File)
+	names := make(map[string][]string)
+	for _, f := range generated {
+		derived[absPath(f.Path)] = f
+		if spec, err := loader.load(f.Path); err == nil {
+			name := f.Spec.Components[0].Name
+			for _, comp := range spec.Components {
+				names[name] = append(names[name], comp.Name)
+			}
+		}
+	}
+
+	text := opts.format == report.FormatText
+	result := newReport()
+	syntaxErrors := 0
+
+	for _, file := range files {
+		before := len(result.Diagnostics)
+		syntaxErrors += verifyFile(result, loader, derived[absPath(file)], names, file)
+		if text {
+			printFileStatus(file, result.Diagnostics[before:])
+		}
+	}
+
+	if err := result.Write(os.Stdout, opts.format); err != nil {
+		return err
+	}
+
+	errCount := result.Count(errors.SeverityError)
+	switch {
+	case syntaxErrors > 0:
+		return &exitError{code: exitSyntaxError, message: fmt.Sprintf("verification failed: %d syntax error(s)", syntaxErrors)}
+	case errCount > 0:
+		return &exitError{code: exitSemanticError, message: fmt.Sprintf("verification failed: %d difference(s) between spec and code", errCount)}
+	}
+	if text {
+		fmt.Println("All specs match the code!")
+	}
+	return nil
+}
+
+// verifyFile は仕様ファイルと対応するソースファイルを突き合わせ、差分を result に追加する
+// 構文エラーの件数を返す
+func verifyFile(result *report.Report, loader *specLoader, code *scaffold.File, names map[string][]string, file string) int {
+	display := relPath(file)
+
+	spec, err := loader.load(file)
+	if err != nil {
+		errs := flattenErrors(err)
+		for _, e := range errs {
+			result.Add(display, e)
+		}
+		return len(errs)
+	}
+
+	if code == nil {
+		for _, comp := range spec.Components {
+			result.Add(display, &errors.ValidationError{
+				Pos:     comp.Pos,
+				Type:    errors.CodeDriftMissing,
+				Name:    comp.Name,
+				Message: "no source file mirrors this spec under source_root",
+			})
+		}
+		return 0
+	}
+
+	v := verifier.NewVerifier()
+	v.SetComponentNames(names)
+	diags := flattenErrors(v.Verify(spec, code.Spec, code.Source))
+	sortByPosition(diags)
+	for _, e := range diags {
+		result.Add(display, e)
+	}
+	return 0
+}
@@ -1,9 +1,216 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"pact/internal/infrastructure/resolver"
+	"pact/internal/infrastructure/watcher"
+	"pact/pkg/pact"
+)
+
+type watchOptions struct {
+	output   string
+	types    []string
+	paths    []string
+	interval time.Duration
+	debounce time.Duration
+}
+
+func parseWatchOptions(args []string) (*watchOptions, error) {
+	opts := &watchOptions{
+		output:   ".",
+		types:    []string{"all"},
+		interval: watcher.DefaultPollInterval,
+		debounce: watcher.DefaultDebounce,
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-o" || arg == "--output":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			opts.output = args[i]
+		case arg == "-t" || arg == "--type":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			opts.types = strings.Split(args[i], ",")
+		case arg == "--interval" || arg == "--debounce":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			d, err := time.ParseDuration(args[i])
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid duration for %s: %s", arg, args[i])
+			}
+			if arg == "--interval" {
+				opts.interval = d
+			} else {
+				opts.debounce = d
+			}
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown option: %s", arg)
+		default:
+			opts.paths = append(opts.paths, arg)
+		}
+	}
+
+	if len(opts.paths) == 0 {
+		opts.paths = []string{"."}
+	}
+
+	return opts, nil
+}
 
 func cmdWatch(args []string) error {
-	fmt.Println("Watch mode is not implemented yet")
-	fmt.Println("Use a file watcher like entr or fswatch with 'pact generate'")
-	return nil
+	opts, err := parseWatchOptions(args)
+	if err != nil {
+		return err
+	}
+
+	if opts.output != "." {
+		if err := os.MkdirAll(opts.output, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	s := &watchSession{
+		client: pact.New(),
+		graph:  resolver.NewImportGraph(),
+		opts:   opts,
+	}
+
+	// 起動時に全ファイルを生成し、インポートグラフを構築する
+	for _, file := range findPactFiles(opts.paths) {
+		s.regenerate(file)
+	}
+
+	w := watcher.NewPollingWatcher(opts.interval)
+	events, err := w.Watch(watcher.NewWatchMode(opts.paths...))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = w.Stop() }()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	fmt.Printf("Watching %s for changes (Ctrl+C to stop)\n", strings.Join(opts.paths, ", "))
+
+	batches := watcher.Debounce(events, opts.debounce)
+	for {
+		select {
+		case batch, ok := <-batches:
+			if !ok {
+				return nil
+			}
+			s.handle(batch)
+		case <-interrupt:
+			fmt.Println("\nStopped watching")
+			return nil
+		}
+	}
+}
+
+// watchSession は watch 実行中の状態を保持する
+type watchSession struct {
+	client *pact.Client
+	graph  *resolver.ImportGraph
+	opts   *watchOptions
+}
+
+// handle は変更イベントのまとまりを処理し、影響を受けるファイルだけを再生成する
+func (s *watchSession) handle(batch []watcher.WatchEvent) {
+	affected := make(map[string]bool)
+
+	for _, ev := range batch {
+		switch ev.Type {
+		case watcher.WatchEventDeleted:
+			fmt.Printf("[%s] Deleted %s\n", timestamp(), ev.Path)
+		case watcher.WatchEventCreated:
+			fmt.Printf("[%s] Created %s\n", timestamp(), ev.Path)
+		default:
+			fmt.Printf("[%s] Changed %s\n", timestamp(), ev.Path)
+		}
+
+		// インポート元は変更前のグラフから求める
+		for _, dep := range s.graph.Dependents(ev.Path) {
+			affected[dep] = true
+		}
+
+		if ev.Type == watcher.WatchEventDeleted {
+			s.graph.Remove(ev.Path)
+			continue
+		}
+		affected[absPath(ev.Path)] = true
+	}
+
+	files := make([]string, 0, len(affected))
+	for file := range affected {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		s.regenerate(file)
+	}
+}
+
+// regenerate はファイルをパースして図を再生成する
+// パースエラーは出力するだけで監視は継続する
+// import が解決できない場合は警告を出し、ファイル単体で生成する
+func (s *watchSession) regenerate(file string) {
+	display := relPath(file)
+	files, err := s.client.ParseFileWithImports(file)
+	if len(files) == 0 {
+		fmt.Printf("Error in %s: %v\n", display, err)
+		return
+	}
+	// import が解決できなくても監視中は単体で図を更新する
+	if err != nil {
+		fmt.Printf("Warning: imports of %s: %v\n", display, err)
+	}
+	spec := files[0]
+	s.graph.Update(file, spec)
+
+	fmt.Printf("Processing %s...\n", display)
+	generateDiagrams(s.client, spec, files[1:], file, s.opts.output, s.opts.types)
+}
+
+// findPactFiles はパス以下の .pact ファイルを再帰的に収集する
+func findPactFiles(paths []string) []string {
+	var files []string
+	for _, root := range paths {
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !d.IsDir() && filepath.Ext(path) == ".pact" {
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+	sort.Strings(files)
+	return files
+}
+
+func timestamp() string {
+	return time.Now().Format("15:04:05")
 }
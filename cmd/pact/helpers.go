@@ -1,27 +1,86 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"pact/internal/infrastructure/project"
+	"pact/internal/infrastructure/report"
 )
 
 // expandFiles はファイルパターンのリストを展開し、実際のファイルパスのリストを返す
-// ディレクトリが指定された場合は *.pact ファイルを自動展開する
+// ディレクトリは直下の *.pact、dir/... と ** は再帰的に展開し、
+// .pactconfig の exclude と .pactignore に一致するファイルを除いてパス順に返す
 func expandFiles(patterns []string) []string {
-	var files []string
-	for _, pattern := range patterns {
-		info, err := os.Stat(pattern)
-		if err == nil && info.IsDir() {
-			matches, _ := filepath.Glob(filepath.Join(pattern, "*.pact"))
-			files = append(files, matches...)
-		} else {
-			matches, _ := filepath.Glob(pattern)
-			if len(matches) == 0 {
-				files = append(files, pattern)
-			} else {
-				files = append(files, matches...)
-			}
+	return project.LoadOrDefault(".").ExpandPatterns(patterns)
+}
+
+// expandExisting は expandFiles で展開し、存在しないファイルや展開結果が空のときはエラーを返す
+func expandExisting(patterns []string) ([]string, error) {
+	files := expandFiles(patterns)
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			return nil, fmt.Errorf("file not found: %s", f)
 		}
 	}
-	return files
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .pact files found")
+	}
+	return files, nil
+}
+
+// absPath はパスを絶対パスに変換する（失敗時はそのまま返す）
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
+
+// relPath は表示用にカレントディレクトリからの相対パスを返す
+func relPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
+
+// exitError は終了コードを指定するエラー
+type exitError struct {
+	code    int
+	message string
+}
+
+func (e *exitError) Error() string {
+	return e.message
+}
+
+// exitCode はエラーに対応する終了コードを返す
+func exitCode(err error) int {
+	if e, ok := err.(*exitError); ok {
+		return e.code
+	}
+	return 1
+}
+
+// newReport はCLIの診断出力用のレポートを作成する
+func newReport() *report.Report {
+	return &report.Report{Tool: "pact", Version: version}
+}
+
+// parseFormatFlag は --format の値を読み取り、インデックスを進める
+func parseFormatFlag(args []string, i *int) (report.Format, error) {
+	if *i+1 >= len(args) {
+		return "", fmt.Errorf("missing value for %s", args[*i])
+	}
+	*i++
+	return report.ParseFormat(args[*i])
 }
@@ -20,27 +20,62 @@ func main() {
 	case "init":
 		if err := cmdInit(args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 	case "generate":
 		if err := cmdGenerate(args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 	case "validate":
 		if err := cmdValidate(args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 	case "check":
 		if err := cmdCheck(args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
+		}
+	case "fmt":
+		if err := cmdFmt(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	case "codegen":
+		if err := cmdCodegen(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	case "verify":
+		if err := cmdVerify(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	case "diff":
+		if err := cmdDiff(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	case "simulate":
+		if err := cmdSimulate(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	case "scaffold":
+		if err := cmdScaffold(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	case "lsp":
+		if err := cmdLSP(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 	case "watch":
 		if err := cmdWatch(args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 	case "version", "-v", "--version":
 		fmt.Printf("pact version %s\n", version)
@@ -62,16 +97,48 @@ Usage:
 Commands:
   init        Initialize a new .pactconfig file
   generate    Generate diagrams from .pact files
-  validate    Validate .pact files
-  check       Check for missing components
+  validate    Validate .pact files (syntax, references and warnings)
+  check       Check spec coverage, missing components, dependency cycles and rules
+  fmt         Format .pact files in canonical style
+  codegen     Generate source code from .pact files (go, ts, fsm)
+  verify      Compare .pact specs with the source code they mirror
+  scaffold    Generate .pact specs from existing source code
+  diff        Compare two versions of specs and classify breaking changes
+  simulate    Step through a states block with a script of events
   watch       Watch for file changes and regenerate
+  lsp         Start the language server on stdio
   version     Show version information
   help        Show this help message
 
 Examples:
   pact init
+  pact init --source-root ./internal --scaffold
+  pact generate                 # generate the whole project from .pactconfig
   pact generate service.pact
   pact generate -o output/ -t class service.pact
   pact validate *.pact
-  pact check --missing`)
+  pact validate .pact/...       # recurse; exclude and .pactignore apply
+  pact validate --strict        # validate the whole project, fail on warnings
+  pact check --missing          # list source files without specs
+  pact check --missing --threshold 80
+  pact check --cycles --cycles-diagram cycles.svg .pact/...   # depends on cycles
+  pact check --rules .pact/...                              # dependency rules in .pactconfig
+  pact validate --format sarif > pact.sarif   # formats: text, json, sarif
+  pact fmt -w .pact/...         # rewrite files in place
+  pact fmt --check              # list unformatted files and fail
+  pact scaffold go              # one spec per Go file under source_root
+  pact scaffold go --force      # overwrite existing specs
+  pact verify                   # report drift between specs and Go code
+  pact codegen go -o internal/model .pact/...   # structs, enums, interfaces, constructors
+  pact codegen ts --dts -o web/src/model .pact/... # TypeScript declarations
+  pact codegen fsm -o internal/model .pact/...      # state machines from states blocks
+  pact diff old.pact new.pact   # exit 5 on breaking changes
+  pact diff --format json base/.pact .pact   # directories are compared recursively
+  pact simulate -e "pay order.paid=true ship +30m" order.pact
+  pact simulate --states OrderState --trace path.svg order.pact script.txt
+  pact simulate -i order.pact                     # interactive REPL
+  pact watch -o diagrams/ .pact/
+
+Exit codes (validate):
+  0  valid    2  syntax errors    3  semantic errors    4  warnings with --strict`)
 }
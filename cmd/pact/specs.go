@@ -0,0 +1,124 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+	"pact/internal/infrastructure/resolver"
+	"pact/pkg/pact"
+)
+
+// specLoader は .pact ファイルのパース結果を絶対パスごとにキャッシュする
+type specLoader struct {
+	client *pact.Client
+	specs  map[string]*ast.SpecFile
+	errs   map[string]error
+}
+
+func newSpecLoader(client *pact.Client) *specLoader {
+	return &specLoader{
+		client: client,
+		specs:  make(map[string]*ast.SpecFile),
+		errs:   make(map[string]error),
+	}
+}
+
+// load はファイルをパースする（同じファイルは一度だけパースする）
+func (l *specLoader) load(file string) (*ast.SpecFile, error) {
+	path := absPath(file)
+	if spec, ok := l.specs[path]; ok {
+		return spec, nil
+	}
+	if err, ok := l.errs[path]; ok {
+		return nil, err
+	}
+
+	spec, err := l.client.ParseFile(path)
+	if err != nil {
+		l.errs[path] = err
+		return nil, err
+	}
+	spec.Path = path
+	l.specs[path] = spec
+	return spec, nil
+}
+
+// imports はファイルの import 文を解決し、import 文のパスをキーにした仕様を返す
+// 解決できない import と import の循環はエラーとして返す
+func (l *specLoader) imports(file string, spec *ast.SpecFile) (map[string]*ast.SpecFile, []error) {
+	resolved := make(map[string]*ast.SpecFile)
+	var errs []error
+
+	dir := filepath.Dir(absPath(file))
+	for _, imp := range spec.Imports {
+		target := filepath.Join(dir, imp.Path)
+		if _, err := os.Stat(target); err != nil {
+			errs = append(errs, &errors.ImportError{Pos: imp.Pos, Path: imp.Path, Message: "file not found"})
+			continue
+		}
+		imported, err := l.load(target)
+		if err != nil {
+			errs = append(errs, &errors.ImportError{Pos: imp.Pos, Path: imp.Path, Message: "failed to parse", Cause: err})
+			continue
+		}
+		resolved[imp.Path] = imported
+	}
+
+	// 直接の import が解決できた場合のみ、推移的な import と循環を検査する
+	if len(errs) == 0 && len(spec.Imports) > 0 {
+		if _, err := l.closure(spec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return resolved, errs
+}
+
+// importMaps は import 先の仕様それぞれの import 文を推移的に解決し、仕様ごとに import 文のパスをキーにした表を返す
+// 解決できない import は表に含めない（その仕様を検証するときにエラーになる）
+func (l *specLoader) importMaps(imports map[string]*ast.SpecFile) map[*ast.SpecFile]map[string]*ast.SpecFile {
+	maps := make(map[*ast.SpecFile]map[string]*ast.SpecFile)
+	var visit func(spec *ast.SpecFile)
+	visit = func(spec *ast.SpecFile) {
+		if _, ok := maps[spec]; ok {
+			return
+		}
+		resolved := make(map[string]*ast.SpecFile)
+		maps[spec] = resolved
+		dir := filepath.Dir(absPath(spec.Path))
+		for _, imp := range spec.Imports {
+			imported, err := l.load(filepath.Join(dir, imp.Path))
+			if err != nil {
+				continue
+			}
+			resolved[imp.Path] = imported
+			visit(imported)
+		}
+	}
+	for _, spec := range imports {
+		visit(spec)
+	}
+	return maps
+}
+
+// closure は spec とその推移的な import 先を返す（spec が先頭、以降は依存順）
+func (l *specLoader) closure(spec *ast.SpecFile) ([]*ast.SpecFile, error) {
+	ordered, err := resolver.NewResolver(l).ResolveFile(spec, "")
+	if err != nil {
+		return nil, err
+	}
+	files := []*ast.SpecFile{spec}
+	for _, f := range ordered {
+		if f != spec {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// ParseFile は resolver.Parser を実装する
+func (l *specLoader) ParseFile(path string) (*ast.SpecFile, error) {
+	return l.load(path)
+}
@@ -0,0 +1,306 @@
+package depgraph
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"pact/internal/domain/ast"
+)
+
+// Node はコンポーネントを表すノード
+type Node struct {
+	Name      string
+	File      string // 宣言した仕様のパス
+	Component *ast.ComponentDecl
+	key       string // 宣言した仕様と名前から作る識別子
+}
+
+// Edge は関係（depends on・extends など）を表すエッジ
+type Edge struct {
+	From     string
+	To       string
+	File     string // 関係を宣言した仕様のパス
+	Relation *ast.RelationDecl
+	from     *Node
+}
+
+// String は "A depends on B" の形で表す
+func (e Edge) String() string {
+	return e.From + " " + strings.ReplaceAll(string(e.Relation.Kind), "_", " ") + " " + e.To
+}
+
+// fileImports は仕様の import 先
+type fileImports struct {
+	aliases map[string]string // エイリアス → import 先の仕様
+	plain   []string          // エイリアスなしの import 先の仕様
+}
+
+// Graph はコンポーネントの関係のグラフ
+// ノードは宣言した仕様と名前で区別し、別の仕様にある同じ名前のコンポーネントは別のノードにする
+type Graph struct {
+	nodes   map[string]*Node   // ノードの識別子 → ノード
+	byName  map[string][]*Node // 名前 → 宣言順のノード
+	order   []*Node            // 宣言順のノード
+	index   map[*Node]int      // ノード → 宣言順
+	imports map[string]*fileImports
+	edges   []Edge
+}
+
+// New は空のグラフを作る
+func New() *Graph {
+	return &Graph{
+		nodes:   make(map[string]*Node),
+		byName:  make(map[string][]*Node),
+		index:   make(map[*Node]int),
+		imports: make(map[string]*fileImports),
+	}
+}
+
+// AddSpec は仕様のコンポーネントと関係をグラフに加える
+// 同じ仕様を複数回加えた場合は最初のものを使う
+func (g *Graph) AddSpec(spec *ast.SpecFile) {
+	file := fileKey(spec.Path)
+	if _, ok := g.imports[file]; !ok {
+		imports := &fileImports{aliases: make(map[string]string)}
+		for _, imp := range spec.Imports {
+			if spec.Path == "" {
+				break
+			}
+			target := fileKey(filepath.Join(filepath.Dir(spec.Path), imp.Path))
+			if imp.Alias != nil {
+				imports.aliases[*imp.Alias] = target
+			} else {
+				imports.plain = append(imports.plain, target)
+			}
+		}
+		g.imports[file] = imports
+	}
+
+	for i := range spec.Components {
+		comp := &spec.Components[i]
+		key := file + "\x00" + comp.Name
+		if _, ok := g.nodes[key]; ok {
+			continue
+		}
+		node := &Node{Name: comp.Name, File: spec.Path, Component: comp, key: key}
+		g.nodes[key] = node
+		g.byName[comp.Name] = append(g.byName[comp.Name], node)
+		g.index[node] = len(g.order)
+		g.order = append(g.order, node)
+		for j := range comp.Body.Relations {
+			rel := &comp.Body.Relations[j]
+			g.edges = append(g.edges, Edge{From: comp.Name, To: rel.Target, File: spec.Path, Relation: rel, from: node})
+		}
+	}
+}
+
+// resolve は from で宣言された関係の対象のノードを返す（グラフにない対象は nil）
+// 修飾名は from のエイリアスの import 先で引く
+// 修飾しない名前は from 自身、エイリアスなしの import 先、宣言順で最初のノードの順で引く
+func (g *Graph) resolve(from *Node, target string) *Node {
+	file := fileKey(from.File)
+	imports := g.imports[file]
+	if alias, member, ok := ast.SplitQualifiedName(target); ok {
+		if imported, ok := imports.aliases[alias]; ok {
+			return g.nodes[imported+"\x00"+member]
+		}
+		return nil
+	}
+	if node, ok := g.nodes[file+"\x00"+target]; ok {
+		return node
+	}
+	for _, imported := range imports.plain {
+		if node, ok := g.nodes[imported+"\x00"+target]; ok {
+			return node
+		}
+	}
+	if nodes := g.byName[target]; len(nodes) > 0 {
+		return nodes[0]
+	}
+	return nil
+}
+
+// Target はエッジの対象のノードを返す（グラフにない対象は nil）
+func (g *Graph) Target(e Edge) *Node {
+	return g.resolve(e.from, e.To)
+}
+
+// Nodes は宣言順のノードを返す
+func (g *Graph) Nodes() []*Node {
+	return append([]*Node(nil), g.order...)
+}
+
+// Edges は全てのエッジを返す（対象がグラフにないエッジを含む）
+func (g *Graph) Edges() []Edge {
+	return g.edges
+}
+
+// outgoing はノードから出る、対象がグラフにあるエッジとその対象を返す
+func (g *Graph) outgoing() map[*Node][]link {
+	out := make(map[*Node][]link)
+	for _, e := range g.edges {
+		if target := g.Target(e); target != nil {
+			out[e.from] = append(out[e.from], link{edge: e, to: target})
+		}
+	}
+	return out
+}
+
+// link は対象を解決したエッジ
+type link struct {
+	edge Edge
+	to   *Node
+}
+
+// fileKey は仕様のパスを比較できる形にする
+func fileKey(path string) string {
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// Components は強連結成分を返す（Tarjan のアルゴリズム。成分内のノードは宣言順）
+func (g *Graph) Components() [][]*Node {
+	out := g.outgoing()
+	index := make(map[*Node]int)
+	low := make(map[*Node]int)
+	onStack := make(map[*Node]bool)
+	var stack []*Node
+	var components [][]*Node
+	next := 0
+
+	var visit func(*Node)
+	visit = func(node *Node) {
+		index[node], low[node] = next, next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, l := range out[node] {
+			if _, seen := index[l.to]; !seen {
+				visit(l.to)
+				low[node] = min(low[node], low[l.to])
+			} else if onStack[l.to] {
+				low[node] = min(low[node], index[l.to])
+			}
+		}
+		if low[node] != index[node] {
+			return
+		}
+		var component []*Node
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		components = append(components, g.declarationOrder(component))
+	}
+	for _, node := range g.order {
+		if _, seen := index[node]; !seen {
+			visit(node)
+		}
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return g.index[components[i][0]] < g.index[components[j][0]]
+	})
+	return components
+}
+
+func (g *Graph) declarationOrder(nodes []*Node) []*Node {
+	sort.SliceStable(nodes, func(i, j int) bool { return g.index[nodes[i]] < g.index[nodes[j]] })
+	return nodes
+}
+
+// Cycle は関係の循環
+type Cycle struct {
+	Components []string // 循環を辿る順のコンポーネント（先頭に戻る辺は Edges の最後）
+	Edges      []Edge
+}
+
+// String は "A -> B -> C -> A" の形で表す
+func (c Cycle) String() string {
+	return strings.Join(append(append([]string(nil), c.Components...), c.Components[0]), " -> ")
+}
+
+// Cycles は関係の循環を返す
+// 強連結成分ごとに、成分内の各コンポーネントを通る最短の循環を重複なく返す
+func (g *Graph) Cycles() []Cycle {
+	out := g.outgoing()
+	var cycles []Cycle
+	for _, component := range g.Components() {
+		members := make(map[*Node]bool)
+		for _, node := range component {
+			members[node] = true
+		}
+		seen := make(map[string]bool)
+		for _, start := range component {
+			edges, ok := shortestCycle(start, out, members)
+			if !ok {
+				continue
+			}
+			key := cycleKey(edges)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			cycle := Cycle{Edges: edges}
+			for _, edge := range edges {
+				cycle.Components = append(cycle.Components, edge.From)
+			}
+			cycles = append(cycles, cycle)
+		}
+	}
+	return cycles
+}
+
+// shortestCycle は start から成分内のエッジだけを辿って start に戻る最短の循環を幅優先で探す
+func shortestCycle(start *Node, out map[*Node][]link, members map[*Node]bool) ([]Edge, bool) {
+	prev := make(map[*Node]Edge)
+	visited := map[*Node]bool{}
+	queue := []*Node{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, l := range out[node] {
+			if !members[l.to] {
+				continue
+			}
+			if l.to == start {
+				edges := []Edge{l.edge}
+				for at := node; at != start; at = prev[at].from {
+					edges = append([]Edge{prev[at]}, edges...)
+				}
+				return edges, true
+			}
+			if !visited[l.to] {
+				visited[l.to] = true
+				prev[l.to] = l.edge
+				queue = append(queue, l.to)
+			}
+		}
+	}
+	return nil, false
+}
+
+// cycleKey は回転を同一視した循環のキー（辞書順で最小の識別子のノードから始める）
+func cycleKey(edges []Edge) string {
+	keys := make([]string, len(edges))
+	first := 0
+	for i, edge := range edges {
+		keys[i] = edge.from.key
+		if keys[i] < keys[first] {
+			first = i
+		}
+	}
+	rotated := append(append([]string(nil), keys[first:]...), keys[:first]...)
+	return strings.Join(rotated, "\x01")
+}
@@ -0,0 +1,160 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"pact/internal/domain/ast"
+)
+
+// component は depends on だけを持つコンポーネントを作る（line は最初の関係の行）
+func component(name string, line int, targets ...string) ast.ComponentDecl {
+	comp := ast.ComponentDecl{Name: name}
+	for i, target := range targets {
+		comp.Body.Relations = append(comp.Body.Relations, ast.RelationDecl{
+			Pos:    ast.Position{Line: line + i},
+			Kind:   ast.RelationDependsOn,
+			Target: target,
+		})
+	}
+	return comp
+}
+
+func cycleStrings(cycles []Cycle) []string {
+	var found []string
+	for _, c := range cycles {
+		found = append(found, c.String())
+	}
+	return found
+}
+
+// =============================================================================
+// DG001-DG004: 関係の循環
+// =============================================================================
+
+// DG001: 循環がなければ何も返さない
+func TestGraph_NoCycles(t *testing.T) {
+	g := New()
+	g.AddSpec(&ast.SpecFile{Components: []ast.ComponentDecl{
+		component("A", 1, "B", "C"),
+		component("B", 3, "C"),
+		component("C", 4, "External"),
+	}})
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Errorf("unexpected cycles: %v", cycleStrings(cycles))
+	}
+	if n := len(g.Components()); n != 3 {
+		t.Errorf("expected 3 components, got %d", n)
+	}
+}
+
+// DG002: ファイルをまたぐ循環と、各辺の位置
+func TestGraph_CycleAcrossFiles(t *testing.T) {
+	g := New()
+	g.AddSpec(&ast.SpecFile{Path: "a.pact", Components: []ast.ComponentDecl{component("A", 1, "B")}})
+	g.AddSpec(&ast.SpecFile{Path: "b.pact", Components: []ast.ComponentDecl{component("B", 2, "C")}})
+	g.AddSpec(&ast.SpecFile{Path: "c.pact", Components: []ast.ComponentDecl{component("C", 3, "A", "D"), component("D", 5)}})
+
+	cycles := g.Cycles()
+	if got := cycleStrings(cycles); strings.Join(got, "|") != "A -> B -> C -> A" {
+		t.Fatalf("unexpected cycles: %v", got)
+	}
+	var edges []string
+	for _, e := range cycles[0].Edges {
+		edges = append(edges, e.File+":"+e.Relation.Pos.String()+" "+e.String())
+	}
+	want := "a.pact:1:0 A depends on B|b.pact:2:0 B depends on C|c.pact:3:0 C depends on A"
+	if strings.Join(edges, "|") != want {
+		t.Errorf("got %v, want %s", edges, want)
+	}
+}
+
+// DG003: 1つの強連結成分に複数の循環がある場合と自己参照
+func TestGraph_MultipleCycles(t *testing.T) {
+	g := New()
+	g.AddSpec(&ast.SpecFile{Components: []ast.ComponentDecl{
+		component("A", 1, "B"),
+		component("B", 2, "A", "C"),
+		component("C", 4, "B"),
+		component("Self", 5, "Self"),
+	}})
+	got := cycleStrings(g.Cycles())
+	want := []string{"A -> B -> A", "C -> B -> C", "Self -> Self"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// DG004: エイリアス付きの import の修飾名は import 先のコンポーネントに解決する
+func TestGraph_QualifiedTarget(t *testing.T) {
+	pay := "pay"
+	g := New()
+	g.AddSpec(&ast.SpecFile{
+		Path:       "/proj/orders.pact",
+		Imports:    []ast.ImportDecl{{Path: "billing/payments.pact", Alias: &pay}},
+		Components: []ast.ComponentDecl{component("Orders", 1, "pay.Payments")},
+	})
+	g.AddSpec(&ast.SpecFile{
+		Path:       "/proj/billing/payments.pact",
+		Imports:    []ast.ImportDecl{{Path: "../orders.pact"}},
+		Components: []ast.ComponentDecl{component("Payments", 1, "Orders")},
+	})
+	if got := cycleStrings(g.Cycles()); strings.Join(got, "|") != "Orders -> Payments -> Orders" {
+		t.Errorf("unexpected cycles: %v", got)
+	}
+}
+
+// =============================================================================
+// DG009: 別の仕様にある同じ名前のコンポーネント
+// =============================================================================
+
+// sameNameGraph は sales/order.pact の Order が billing/order.pact の Order に依存するグラフを作る
+// back があれば billing 側の Invoice から sales の Order に戻る関係を加える
+func sameNameGraph(back bool) *Graph {
+	billing, sales := "billing", "sales"
+	invoice := component("Invoice", 2)
+	imports := []ast.ImportDecl(nil)
+	if back {
+		invoice = component("Invoice", 2, "sales.Order")
+		imports = []ast.ImportDecl{{Path: "../sales/order.pact", Alias: &sales}}
+	}
+	g := New()
+	g.AddSpec(&ast.SpecFile{
+		Path:       "/proj/sales/order.pact",
+		Imports:    []ast.ImportDecl{{Path: "../billing/order.pact", Alias: &billing}},
+		Components: []ast.ComponentDecl{component("Order", 1, "billing.Order")},
+	})
+	g.AddSpec(&ast.SpecFile{
+		Path:       "/proj/billing/order.pact",
+		Imports:    imports,
+		Components: []ast.ComponentDecl{component("Order", 1, "Invoice"), invoice},
+	})
+	return g
+}
+
+// DG009: 同じ名前のコンポーネントは仕様ごとに別のノードにし、修飾名は import 先に解決する
+func TestGraph_SameNameInDifferentFiles(t *testing.T) {
+	g := sameNameGraph(false)
+	if n := len(g.Nodes()); n != 3 {
+		t.Fatalf("expected 3 nodes, got %d", n)
+	}
+	if target := g.Target(g.Edges()[0]); target == nil || target.File != "/proj/billing/order.pact" {
+		t.Errorf("expected billing.Order to resolve to billing/order.pact, got %+v", target)
+	}
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Errorf("unexpected cycles: %v", cycleStrings(cycles))
+	}
+
+	cycles := sameNameGraph(true).Cycles()
+	if got := cycleStrings(cycles); strings.Join(got, "|") != "Order -> Order -> Invoice -> Order" {
+		t.Fatalf("unexpected cycles: %v", got)
+	}
+	var files []string
+	for _, e := range cycles[0].Edges {
+		files = append(files, e.File)
+	}
+	want := "/proj/sales/order.pact|/proj/billing/order.pact|/proj/billing/order.pact"
+	if strings.Join(files, "|") != want {
+		t.Errorf("got %v, want %s", files, want)
+	}
+}
@@ -0,0 +1,143 @@
+package depgraph
+
+import (
+	"fmt"
+	"path"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/config"
+)
+
+// Violation は依存関係のルールへの違反
+type Violation struct {
+	Rule   *config.Rule
+	Edge   Edge
+	Reason string // 違反の理由（"is forbidden" など）
+}
+
+// String は "rule 'R': A depends on B is forbidden" の形で表す
+func (v Violation) String() string {
+	s := fmt.Sprintf("rule '%s': %s %s", v.Rule.Name, v.Edge, v.Reason)
+	if v.Rule.Message != "" {
+		s += " (" + v.Rule.Message + ")"
+	}
+	return s
+}
+
+// endpoint はルールの判定に使う関係の端のコンポーネント
+type endpoint struct {
+	name       string
+	node       *Node   // グラフにない対象（external など）は nil
+	path       string  // 宣言した仕様のパス（Selector.Path と比較する形）
+	targetType *string // 依存先の場合の depends on の対象の種類
+}
+
+// CheckRules は関係をルールと照合し、違反を関係の宣言順・ルール順に返す
+// projectPath は仕様のパスを Selector.Path と比較するパス（pact_root からの相対パスなど）に変換する
+func (g *Graph) CheckRules(rules []config.Rule, projectPath func(string) string) []Violation {
+	var violations []Violation
+	for _, e := range g.edges {
+		from := endpoint{name: e.From, node: e.from}
+		to := endpoint{name: e.To, node: g.Target(e), targetType: e.Relation.TargetType}
+		if to.node != nil {
+			to.name = to.node.Name
+		}
+		for _, ep := range []*endpoint{&from, &to} {
+			if ep.node != nil {
+				ep.path = projectPath(ep.node.File)
+			}
+		}
+
+		for i := range rules {
+			rule := &rules[i]
+			if !appliesTo(rule, e) || !matches(rule.From, from) || !matches(rule.To, to) {
+				continue
+			}
+			if reason := violates(rule, from, to); reason != "" {
+				violations = append(violations, Violation{Rule: rule, Edge: e, Reason: reason})
+			}
+		}
+	}
+	return violations
+}
+
+// appliesTo は関係の種類がルールの対象かどうかを返す
+func appliesTo(rule *config.Rule, e Edge) bool {
+	if len(rule.Relations) == 0 {
+		return true
+	}
+	for _, kind := range rule.Relations {
+		if ast.RelationKind(kind) == e.Relation.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// violates は対象の関係がルールに違反する理由を返す（違反しなければ空文字列）
+func violates(rule *config.Rule, from, to endpoint) string {
+	switch {
+	case matchesAny(rule.Forbid, to):
+		return "is forbidden"
+	case len(rule.Allow) > 0 && !matchesAny(rule.Allow, to):
+		return "is not allowed"
+	case len(rule.AllowFrom) > 0 && !matchesAny(rule.AllowFrom, from):
+		return "is not allowed from '" + from.name + "'"
+	}
+	return ""
+}
+
+func matchesAny(selectors []config.Selector, ep endpoint) bool {
+	for _, s := range selectors {
+		if matches(s, ep) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches はコンポーネントが Selector の条件をすべて満たすかどうかを返す
+// グラフにない対象はアノテーションとパスの条件に一致しない
+func matches(s config.Selector, ep endpoint) bool {
+	if s.IsZero() {
+		return true
+	}
+	if s.Name != "" {
+		if ok, _ := path.Match(s.Name, ep.name); !ok {
+			return false
+		}
+	}
+	if s.TargetType != "" && (ep.targetType == nil || *ep.targetType != s.TargetType) {
+		return false
+	}
+	if s.Path != "" && (ep.node == nil || !config.MatchAny([]string{s.Path}, ep.path)) {
+		return false
+	}
+	if s.Annotation != "" && (ep.node == nil || !hasAnnotation(ep.node.Component, s)) {
+		return false
+	}
+	return true
+}
+
+// hasAnnotation はコンポーネントが Selector のアノテーションを持つかどうかを返す
+// 値を指定した場合は、いずれかの引数の値が一致するものに限る
+func hasAnnotation(comp *ast.ComponentDecl, s config.Selector) bool {
+	name, value, err := s.AnnotationPattern()
+	if err != nil {
+		return false
+	}
+	for _, ann := range comp.Annotations {
+		if ann.Name != name {
+			continue
+		}
+		if value == nil {
+			return true
+		}
+		for _, arg := range ann.Args {
+			if arg.Value == *value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/config"
+)
+
+// layered はアノテーション @layer("layer") を付けたコンポーネントを作る
+func layered(layer, name string, line int, targets ...string) ast.ComponentDecl {
+	comp := component(name, line, targets...)
+	comp.Annotations = []ast.AnnotationDecl{{Name: "layer", Args: []ast.AnnotationArg{{Value: layer}}}}
+	return comp
+}
+
+func violationStrings(violations []Violation) []string {
+	var found []string
+	for _, v := range violations {
+		found = append(found, v.String())
+	}
+	return found
+}
+
+func layeredGraph() *Graph {
+	external := "external"
+	api := layered("api", "OrderController", 1, "OrderService", "PaymentGateway")
+	api.Body.Relations[1].TargetType = &external
+	service := layered("domain", "OrderService", 3, "OrderRepository", "Mailer")
+	service.Body.Relations[1].TargetType = &external
+
+	g := New()
+	g.AddSpec(&ast.SpecFile{Path: "/proj/api/order.pact", Components: []ast.ComponentDecl{api}})
+	g.AddSpec(&ast.SpecFile{Path: "/proj/domain/order.pact", Components: []ast.ComponentDecl{service}})
+	g.AddSpec(&ast.SpecFile{Path: "/proj/infra/repository.pact", Components: []ast.ComponentDecl{
+		layered("infrastructure", "OrderRepository", 5),
+	}})
+	return g
+}
+
+func projectPath(path string) string {
+	return strings.TrimPrefix(path, "/proj/")
+}
+
+// =============================================================================
+// DG005-DG008: 依存関係のルール
+// =============================================================================
+
+// DG005: forbid はアノテーションで選んだ依存先への関係を違反にする
+func TestGraph_CheckRules_Forbid(t *testing.T) {
+	rules := []config.Rule{{
+		Name:    "domain-independence",
+		Message: "use a port interface",
+		From:    config.Selector{Annotation: `@layer("domain")`},
+		Forbid:  []config.Selector{{Annotation: `layer("infrastructure")`}},
+	}}
+
+	violations := layeredGraph().CheckRules(rules, projectPath)
+	expected := "rule 'domain-independence': OrderService depends on OrderRepository is forbidden (use a port interface)"
+	if got := violationStrings(violations); strings.Join(got, "|") != expected {
+		t.Fatalf("unexpected violations: %v", got)
+	}
+	if line := violations[0].Edge.Relation.Pos.Line; line != 3 {
+		t.Errorf("expected violation at line 3, got %d", line)
+	}
+}
+
+// DG006: allow_from は対象の種類で選んだ依存先への関係を、許可した依存元だけに限る
+func TestGraph_CheckRules_AllowFrom(t *testing.T) {
+	rules := []config.Rule{{
+		Name:      "external-via-api",
+		To:        config.Selector{TargetType: "external"},
+		AllowFrom: []config.Selector{{Annotation: `layer("api")`}},
+	}}
+
+	got := violationStrings(layeredGraph().CheckRules(rules, projectPath))
+	expected := "rule 'external-via-api': OrderService depends on Mailer is not allowed from 'OrderService'"
+	if strings.Join(got, "|") != expected {
+		t.Errorf("unexpected violations: %v", got)
+	}
+}
+
+// DG007: allow はパスで選んだ依存元の依存先を、許可したものだけに限る（グラフにない対象はパスに一致しない）
+func TestGraph_CheckRules_AllowPath(t *testing.T) {
+	rules := []config.Rule{{
+		Name:  "domain-only-domain",
+		From:  config.Selector{Path: "domain"},
+		Allow: []config.Selector{{Path: "domain/**"}, {Name: "*Repository"}},
+	}}
+
+	got := violationStrings(layeredGraph().CheckRules(rules, projectPath))
+	expected := "rule 'domain-only-domain': OrderService depends on Mailer is not allowed"
+	if strings.Join(got, "|") != expected {
+		t.Errorf("unexpected violations: %v", got)
+	}
+}
+
+// DG008: relations で関係の種類を絞り込む
+func TestGraph_CheckRules_Relations(t *testing.T) {
+	base := component("Base", 1)
+	child := component("Child", 2, "Base")
+	child.Body.Relations[0].Kind = ast.RelationExtends
+	g := New()
+	g.AddSpec(&ast.SpecFile{Components: []ast.ComponentDecl{base, child}})
+
+	rules := []config.Rule{{
+		Name:      "no-dependencies-on-base",
+		Relations: []string{"depends_on"},
+		Forbid:    []config.Selector{{Name: "Base"}},
+	}}
+	if got := g.CheckRules(rules, projectPath); len(got) != 0 {
+		t.Errorf("extends should not be checked: %v", violationStrings(got))
+	}
+
+	rules[0].Relations = nil
+	if got := violationStrings(g.CheckRules(rules, projectPath)); strings.Join(got, "|") != "rule 'no-dependencies-on-base': Child extends Base is forbidden" {
+		t.Errorf("unexpected violations: %v", got)
+	}
+}
+
+// =============================================================================
+// DG010: 同じ名前のコンポーネントへのルール
+// =============================================================================
+
+// DG010: ルールは関係を宣言した仕様のコンポーネントと、その仕様から解決した対象で判定する
+func TestGraph_CheckRules_SameName(t *testing.T) {
+	domain := "domain"
+	g := New()
+	g.AddSpec(&ast.SpecFile{
+		Path:       "/proj/api/order.pact",
+		Imports:    []ast.ImportDecl{{Path: "../domain/order.pact", Alias: &domain}},
+		Components: []ast.ComponentDecl{layered("api", "Order", 1, "domain.Order")},
+	})
+	g.AddSpec(&ast.SpecFile{
+		Path:       "/proj/domain/order.pact",
+		Components: []ast.ComponentDecl{layered("domain", "Order", 1, "Repository")},
+	})
+	g.AddSpec(&ast.SpecFile{
+		Path:       "/proj/infra/repository.pact",
+		Components: []ast.ComponentDecl{layered("infrastructure", "Repository", 1)},
+	})
+
+	rules := []config.Rule{
+		{
+			Name:  "api-via-domain",
+			From:  config.Selector{Annotation: `@layer("api")`},
+			Allow: []config.Selector{{Annotation: `@layer("domain")`}},
+		},
+		{
+			Name:   "domain-independence",
+			From:   config.Selector{Path: "domain/**"},
+			Forbid: []config.Selector{{Path: "infra/**"}},
+		},
+	}
+	got := violationStrings(g.CheckRules(rules, projectPath))
+	expected := "rule 'domain-independence': Order depends on Repository is forbidden"
+	if strings.Join(got, "|") != expected {
+		t.Errorf("unexpected violations: %v", got)
+	}
+}
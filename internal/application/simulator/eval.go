@@ -0,0 +1,215 @@
+package simulator
+
+import (
+	"fmt"
+
+	"pact/internal/domain/ast"
+)
+
+// evaluator はガード式をバインドされた値で評価する
+//
+// 名前は値の表の名前で引く。フィールド参照は "order.paid"、引数のない呼び出しは
+// "order.isPaid()" または "order.isPaid" で引く。値のない名前は null として扱い、unbound に記録する
+type evaluator struct {
+	bindings map[string]interface{}
+	unbound  []string
+}
+
+func (e *evaluator) eval(expr ast.Expr) interface{} {
+	switch x := expr.(type) {
+	case *ast.LiteralExpr:
+		return normalize(x.Value)
+
+	case *ast.VariableExpr:
+		return e.lookup(x.Name)
+
+	case *ast.FieldExpr:
+		if path := dottedName(x); path != "" {
+			return e.lookup(path)
+		}
+		return nil
+
+	case *ast.CallExpr:
+		if path := dottedName(x); path != "" {
+			if v, ok := e.bindings[path+"()"]; ok {
+				return v
+			}
+			return e.lookup(path)
+		}
+		return nil
+
+	case *ast.UnaryExpr:
+		v := e.eval(x.Operand)
+		switch x.Op {
+		case "!":
+			return !truthy(v)
+		case "-":
+			if f, ok := v.(float64); ok {
+				return -f
+			}
+		}
+		return nil
+
+	case *ast.BinaryExpr:
+		return e.binary(x)
+
+	case *ast.TernaryExpr:
+		if truthy(e.eval(x.Condition)) {
+			return e.eval(x.Then)
+		}
+		return e.eval(x.Else)
+
+	case *ast.NullishExpr:
+		if v := e.eval(x.Left); v != nil {
+			return v
+		}
+		if x.Right != nil {
+			return e.eval(x.Right)
+		}
+	}
+	return nil
+}
+
+func (e *evaluator) binary(x *ast.BinaryExpr) interface{} {
+	// && と || は短絡評価する
+	switch x.Op {
+	case "&&":
+		return truthy(e.eval(x.Left)) && truthy(e.eval(x.Right))
+	case "||":
+		return truthy(e.eval(x.Left)) || truthy(e.eval(x.Right))
+	}
+
+	left, right := e.eval(x.Left), e.eval(x.Right)
+	switch x.Op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+
+	if l, ok := left.(string); ok {
+		r, ok := right.(string)
+		if !ok {
+			return nil
+		}
+		switch x.Op {
+		case "+":
+			return l + r
+		case "<":
+			return l < r
+		case ">":
+			return l > r
+		case "<=":
+			return l <= r
+		case ">=":
+			return l >= r
+		}
+		return nil
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil
+	}
+	switch x.Op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		if r == 0 {
+			return nil
+		}
+		return l / r
+	case "%":
+		if r == 0 {
+			return nil
+		}
+		return float64(int64(l) % int64(r))
+	case "<":
+		return l < r
+	case ">":
+		return l > r
+	case "<=":
+		return l <= r
+	case ">=":
+		return l >= r
+	}
+	return nil
+}
+
+func (e *evaluator) lookup(name string) interface{} {
+	if v, ok := e.bindings[name]; ok {
+		return v
+	}
+	if !contains(e.unbound, name) {
+		e.unbound = append(e.unbound, name)
+	}
+	return nil
+}
+
+// dottedName はフィールド参照・引数のない呼び出しを "a.b.c" の形にする（それ以外は ""）
+func dottedName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.VariableExpr:
+		return x.Name
+	case *ast.FieldExpr:
+		if obj := dottedName(x.Object); obj != "" {
+			return obj + "." + x.Field
+		}
+	case *ast.CallExpr:
+		if len(x.Args) > 0 {
+			return ""
+		}
+		if x.Object == nil {
+			return x.Method
+		}
+		if obj := dottedName(x.Object); obj != "" {
+			return obj + "." + x.Method
+		}
+	}
+	return ""
+}
+
+// normalize は数値を float64 にそろえる
+func normalize(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return v
+}
+
+func equal(a, b interface{}) bool {
+	return a == b
+}
+
+// truthy は値を真偽値として解釈する（null・false・0・空文字列は偽）
+func truthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case float64:
+		return x != 0
+	case string:
+		return x != ""
+	}
+	return true
+}
+
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", x)
+	}
+	return fmt.Sprint(v)
+}
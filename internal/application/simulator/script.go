@@ -0,0 +1,192 @@
+package simulator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CommandKind はスクリプトの1手の種類
+type CommandKind int
+
+const (
+	CommandEvent   CommandKind = iota // イベントの発火（pay）
+	CommandBind                       // ガードの値の設定（order.paid=true）
+	CommandAdvance                    // 時間の経過（+5m）
+)
+
+// Command はスクリプトの1手
+type Command struct {
+	Line     int
+	Kind     CommandKind
+	Event    string
+	Name     string
+	Value    interface{}
+	Duration time.Duration
+}
+
+// Apply はコマンドをシミュレーターで実行する
+func (c Command) Apply(s *Simulator) *Step {
+	switch c.Kind {
+	case CommandBind:
+		return s.Bind(c.Name, c.Value)
+	case CommandAdvance:
+		return s.Advance(c.Duration)
+	default:
+		return s.Fire(c.Event)
+	}
+}
+
+// ParseScript はイベントのスクリプトを解析する
+//
+// 1手は空白・カンマ・改行で区切る。# から行末まではコメント。
+// key=value はガードの値の設定、+5m は時間の経過、それ以外はイベントとして扱う
+func ParseScript(text string) ([]Command, error) {
+	var commands []Command
+	for i, line := range strings.Split(text, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		for _, token := range splitTokens(line) {
+			cmd, err := ParseCommand(token)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			cmd.Line = i + 1
+			commands = append(commands, cmd)
+		}
+	}
+	return commands, nil
+}
+
+// ParseCommand はスクリプトの1手を解析する
+func ParseCommand(token string) (Command, error) {
+	if strings.HasPrefix(token, "+") {
+		d, err := ParseDuration(token[1:])
+		if err != nil {
+			return Command{}, err
+		}
+		return Command{Kind: CommandAdvance, Duration: d}, nil
+	}
+	if idx := strings.Index(token, "="); idx >= 0 {
+		name := token[:idx]
+		if name == "" {
+			return Command{}, fmt.Errorf("missing name in binding %q", token)
+		}
+		return Command{Kind: CommandBind, Name: name, Value: ParseValue(token[idx+1:])}, nil
+	}
+	return Command{Kind: CommandEvent, Event: token}, nil
+}
+
+// splitTokens は空白とカンマで区切る（引用符の中は区切らない）
+func splitTokens(line string) []string {
+	var tokens []string
+	var current strings.Builder
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			current.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			current.WriteRune(r)
+		case r == ' ' || r == '\t' || r == ',' || r == '\r':
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// ParseValue はスクリプトの値を解釈する
+// true・false・null・数値はそれぞれの型に、引用符で囲んだ文字列とそれ以外は文字列にする
+func ParseValue(text string) interface{} {
+	switch text {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		return text[1 : len(text)-1]
+	}
+	return text
+}
+
+var durationUnits = []struct {
+	name string
+	unit time.Duration
+}{
+	{"ms", time.Millisecond},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// ParseDuration は .pact の期間の表記（5m、90s、1h30m、500ms、2d）を解析する
+func ParseDuration(text string) (time.Duration, error) {
+	if text == "" {
+		return 0, fmt.Errorf("missing duration after '+'")
+	}
+	var total time.Duration
+	rest := text
+	for rest != "" {
+		n := 0
+		for n < len(rest) && rest[n] >= '0' && rest[n] <= '9' {
+			n++
+		}
+		if n == 0 {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+		value, _ := strconv.Atoi(rest[:n])
+		rest = rest[n:]
+		matched := false
+		for _, u := range durationUnits {
+			if strings.HasPrefix(rest, u.name) {
+				total += time.Duration(value) * u.unit
+				rest = rest[len(u.name):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return 0, fmt.Errorf("invalid duration %q (units: ms, s, m, h, d)", text)
+		}
+	}
+	return total, nil
+}
+
+// FormatDuration は期間を .pact の表記にする（1h30m など）
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	var b strings.Builder
+	for _, u := range []struct {
+		name string
+		unit time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}, {"ms", time.Millisecond}} {
+		if n := d / u.unit; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, u.name)
+			d -= n * u.unit
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,413 @@
+package simulator
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"pact/internal/domain/statechart"
+)
+
+// maxSteps はひとつのトリガーで辿るイベントのない遷移の上限（無限ループの防止）
+const maxSteps = 100
+
+// ActionKind はステップ中に起きた出来事の種類
+type ActionKind string
+
+const (
+	ActionEntry      ActionKind = "entry"
+	ActionExit       ActionKind = "exit"
+	ActionTake       ActionKind = "take" // 遷移の実行（Name は空）
+	ActionTransition ActionKind = "do"
+)
+
+// Action はステップ中に起きた出来事（実行順）
+type Action struct {
+	Kind       ActionKind
+	Name       string
+	State      *statechart.State      // entry・exit の対象
+	Transition *statechart.Transition // 遷移のアクションの場合
+}
+
+// Step はひとつのトリガー（開始・イベント・時間経過・値の設定）の処理結果
+type Step struct {
+	Trigger       string
+	At            time.Duration            // 処理を終えた時点の経過時間
+	Transitions   []*statechart.Transition // 実行された遷移（実行順）
+	Actions       []Action
+	Ignored       bool     // イベントで遷移が起きなかった
+	Unknown       bool     // states ブロックにないイベント
+	Unbound       []string // ガードが参照したが値が設定されていない名前
+	Configuration string
+}
+
+// Simulator は states ブロックをコードなしで実行する
+//
+// 意味論は pact codegen fsm が生成するステートマシンと同じで、1つのトリガーは
+// run-to-completion で処理する。時間は仮想の時計で進め、after の遷移は期限の順に発火する
+type Simulator struct {
+	chart    *statechart.Chart
+	bindings map[string]interface{}
+	active   map[*statechart.State]bool
+	entered  map[*statechart.State]time.Duration
+	now      time.Duration
+	started  bool
+	step     *Step
+
+	visitedStates      map[*statechart.State]bool
+	visitedTransitions map[*statechart.Transition]bool
+}
+
+// New は states ブロックのシミュレーターを作成する
+func New(chart *statechart.Chart) *Simulator {
+	s := &Simulator{chart: chart, bindings: make(map[string]interface{})}
+	s.Reset()
+	return s
+}
+
+// Reset は開始前の状態に戻す（ガードの値は保持する）
+func (s *Simulator) Reset() {
+	s.active = make(map[*statechart.State]bool)
+	s.entered = make(map[*statechart.State]time.Duration)
+	s.visitedStates = make(map[*statechart.State]bool)
+	s.visitedTransitions = make(map[*statechart.Transition]bool)
+	s.now = 0
+	s.started = false
+}
+
+// Chart はシミュレーション対象の状態の木を返す
+func (s *Simulator) Chart() *statechart.Chart {
+	return s.chart
+}
+
+// Start は初期状態に入る（開始済みなら何もしない）
+func (s *Simulator) Start() *Step {
+	return s.run("init", func() {
+		s.ensureStarted()
+	})
+}
+
+// Fire はイベントを処理する
+func (s *Simulator) Fire(event string) *Step {
+	return s.run(event, func() {
+		s.ensureStarted()
+		known := false
+		for _, e := range s.chart.Events() {
+			known = known || e == event
+		}
+		if !s.microstep(func(t *statechart.Transition) bool { return t.Event == event }) {
+			s.step.Ignored = true
+			s.step.Unknown = !known
+			return
+		}
+		s.settle()
+	})
+}
+
+// Advance は時計を d だけ進め、期限を迎えた after の遷移を期限の順に実行する
+func (s *Simulator) Advance(d time.Duration) *Step {
+	return s.run("+"+FormatDuration(d), func() {
+		s.ensureStarted()
+		until := s.now + d
+		for i := 0; i < maxSteps; i++ {
+			due, ok := s.nextDeadline()
+			if !ok || due > until {
+				break
+			}
+			s.now = due
+			if s.microstep(s.expired) {
+				s.settle()
+			}
+		}
+		s.now = until
+	})
+}
+
+// Bind はガードが参照する名前に値を設定し、条件で待っている遷移を評価し直す
+func (s *Simulator) Bind(name string, value interface{}) *Step {
+	return s.run(name+"="+formatValue(value), func() {
+		s.bindings[name] = value
+		if s.started {
+			s.settle()
+		}
+	})
+}
+
+// Now は開始からの経過時間を返す
+func (s *Simulator) Now() time.Duration {
+	return s.now
+}
+
+// Done は最上位の終了状態に到達したかどうかを返す
+func (s *Simulator) Done() bool {
+	for _, st := range s.chart.Root.Children {
+		if s.active[st] && st.Kind == statechart.KindFinal {
+			return true
+		}
+	}
+	return false
+}
+
+// Active は現在の末端の状態を文書順で返す
+func (s *Simulator) Active() []*statechart.State {
+	var states []*statechart.State
+	for _, st := range s.chart.States {
+		if s.active[st] && !s.hasActiveChild(st) {
+			states = append(states, st)
+		}
+	}
+	return states
+}
+
+// IsActive は状態が現在アクティブかどうかを返す
+func (s *Simulator) IsActive(st *statechart.State) bool {
+	return s.active[st]
+}
+
+// Visited はこれまでに入った状態と実行された遷移を返す（文書順）
+func (s *Simulator) Visited() ([]*statechart.State, []*statechart.Transition) {
+	var states []*statechart.State
+	for _, st := range s.chart.States {
+		if s.visitedStates[st] {
+			states = append(states, st)
+		}
+	}
+	var transitions []*statechart.Transition
+	for _, t := range s.chart.Transitions {
+		if s.visitedTransitions[t] {
+			transitions = append(transitions, t)
+		}
+	}
+	return states, transitions
+}
+
+// Configuration は現在の状態の構成を文字列で返す
+// 階層状態は "Active/Running"、並行状態は "Checkout{Payment: Authorizing, Shipping: Packing}" と表す
+func (s *Simulator) Configuration() string {
+	var parts []string
+	for _, st := range s.chart.Root.Children {
+		if s.active[st] {
+			parts = append(parts, s.describe(st))
+		}
+	}
+	if len(parts) == 0 {
+		return "(not started)"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *Simulator) describe(st *statechart.State) string {
+	if st.Kind == statechart.KindParallel {
+		var regions []string
+		for _, region := range st.Children {
+			regions = append(regions, region.Name+": "+s.describeChildren(region))
+		}
+		return st.Name + "{" + strings.Join(regions, ", ") + "}"
+	}
+	if children := s.describeChildren(st); children != "" {
+		return st.Name + "/" + children
+	}
+	return st.Name
+}
+
+func (s *Simulator) describeChildren(st *statechart.State) string {
+	var parts []string
+	for _, child := range st.Children {
+		if s.active[child] {
+			parts = append(parts, s.describe(child))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// run はトリガーの処理を1つのステップとして記録する
+func (s *Simulator) run(trigger string, body func()) *Step {
+	s.step = &Step{Trigger: trigger}
+	body()
+	step := s.step
+	s.step = nil
+	step.At = s.now
+	step.Configuration = s.Configuration()
+	sort.Strings(step.Unbound)
+	return step
+}
+
+func (s *Simulator) ensureStarted() {
+	if s.started {
+		return
+	}
+	s.started = true
+	if initial := s.chart.Root.DefaultChild(); initial != nil {
+		s.enterTree(initial)
+	}
+	s.settle()
+}
+
+func (s *Simulator) settle() {
+	for i := 0; i < maxSteps && s.microstep((*statechart.Transition).Eventless); i++ {
+	}
+}
+
+func (s *Simulator) expired(t *statechart.Transition) bool {
+	return t.After > 0 && s.entered[t.Source]+t.After <= s.now
+}
+
+// nextDeadline はアクティブな状態の after の遷移のうち、最も早い期限を返す
+func (s *Simulator) nextDeadline() (time.Duration, bool) {
+	var next time.Duration
+	found := false
+	for _, t := range s.chart.Transitions {
+		if t.After > 0 && s.active[t.Source] {
+			if due := s.entered[t.Source] + t.After; !found || due < next {
+				next, found = due, true
+			}
+		}
+	}
+	return next, found
+}
+
+// microstep は match で選ばれる有効な遷移を実行する
+// アクティブな末端の状態ごとに、その状態から祖先に向かって最初に有効な遷移を1つ選ぶ
+// （既に選んだ遷移と抜ける状態が重なるものは選ばない）。抜ける状態を深い順に抜け、
+// 遷移のアクションを実行してから遷移先に入る
+func (s *Simulator) microstep(match func(t *statechart.Transition) bool) bool {
+	var selected []*statechart.Transition
+	exiting := make(map[*statechart.State]bool)
+	for _, leaf := range s.Active() {
+	search:
+		for st := leaf; st != nil && !st.IsRoot(); st = st.Parent {
+			for _, t := range st.Transitions {
+				if !match(t) || !s.enabled(t) {
+					continue
+				}
+				exits := s.exitSet(t)
+				for _, x := range exits {
+					if exiting[x] {
+						break search
+					}
+				}
+				for _, x := range exits {
+					exiting[x] = true
+				}
+				selected = append(selected, t)
+				break search
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return false
+	}
+
+	for i := len(s.chart.States) - 1; i >= 0; i-- {
+		if st := s.chart.States[i]; exiting[st] {
+			s.exitState(st)
+		}
+	}
+	for _, t := range selected {
+		s.visitedTransitions[t] = true
+		s.step.Transitions = append(s.step.Transitions, t)
+		s.step.Actions = append(s.step.Actions, Action{Kind: ActionTake, Transition: t})
+		for _, name := range t.Actions {
+			s.step.Actions = append(s.step.Actions, Action{Kind: ActionTransition, Name: name, Transition: t})
+		}
+	}
+	for _, t := range selected {
+		s.enter(t)
+	}
+	return true
+}
+
+// enabled は遷移の条件とガードが成り立つかどうかを返す
+func (s *Simulator) enabled(t *statechart.Transition) bool {
+	e := &evaluator{bindings: s.bindings}
+	ok := (t.Condition == nil || truthy(e.eval(t.Condition))) && (t.Guard == nil || truthy(e.eval(t.Guard)))
+	for _, name := range e.unbound {
+		if !contains(s.step.Unbound, name) {
+			s.step.Unbound = append(s.step.Unbound, name)
+		}
+	}
+	return ok
+}
+
+func (s *Simulator) exitSet(t *statechart.Transition) []*statechart.State {
+	domain := t.Domain()
+	var states []*statechart.State
+	for _, st := range s.chart.States {
+		if s.active[st] && domain.IsAncestorOf(st) {
+			states = append(states, st)
+		}
+	}
+	return states
+}
+
+func (s *Simulator) enter(t *statechart.Transition) {
+	domain := t.Domain()
+	var path []*statechart.State
+	for st := t.Target; st != domain; st = st.Parent {
+		path = append(path, st)
+	}
+	for i := len(path) - 1; i > 0; i-- {
+		st := path[i]
+		s.enterState(st)
+		if st.Kind == statechart.KindParallel {
+			for _, region := range st.Children {
+				if region != path[i-1] {
+					s.enterTree(region)
+				}
+			}
+		}
+	}
+	s.enterTree(t.Target)
+}
+
+// enterTree は状態とその既定の子孫に入る
+func (s *Simulator) enterTree(st *statechart.State) {
+	if s.active[st] {
+		return
+	}
+	s.enterState(st)
+	if st.Kind == statechart.KindParallel {
+		for _, region := range st.Children {
+			s.enterTree(region)
+		}
+	} else if initial := st.DefaultChild(); initial != nil {
+		s.enterTree(initial)
+	}
+}
+
+func (s *Simulator) enterState(st *statechart.State) {
+	if s.active[st] {
+		return
+	}
+	s.active[st] = true
+	s.entered[st] = s.now
+	s.visitedStates[st] = true
+	for _, name := range st.Entry {
+		s.step.Actions = append(s.step.Actions, Action{Kind: ActionEntry, Name: name, State: st})
+	}
+}
+
+func (s *Simulator) exitState(st *statechart.State) {
+	for _, name := range st.Exit {
+		s.step.Actions = append(s.step.Actions, Action{Kind: ActionExit, Name: name, State: st})
+	}
+	delete(s.active, st)
+}
+
+func (s *Simulator) hasActiveChild(st *statechart.State) bool {
+	for _, child := range st.Children {
+		if s.active[child] {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package simulator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/statechart"
+)
+
+func on(from, to, event string) ast.TransitionDecl {
+	return ast.TransitionDecl{From: from, To: to, Trigger: &ast.EventTrigger{Event: event}}
+}
+
+func variable(name string) ast.Expr {
+	return &ast.VariableExpr{Name: name}
+}
+
+// actions はステップのアクションを "kind name" の一覧にする
+func actions(step *Step) string {
+	var parts []string
+	for _, a := range step.Actions {
+		if a.Kind == ActionTake {
+			parts = append(parts, "take "+a.Transition.Source.Name+"->"+a.Transition.Target.Name)
+			continue
+		}
+		parts = append(parts, string(a.Kind)+" "+a.Name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orderChart() *statechart.Chart {
+	return statechart.New(&ast.StatesDecl{
+		Name:    "Order",
+		Initial: "Pending",
+		Finals:  []string{"Delivered"},
+		States: []ast.StateDecl{
+			{Name: "Pending", Entry: []string{"createOrder"}, Exit: []string{"lock"}},
+			{Name: "Shipped", Entry: []string{"track"}},
+		},
+		Transitions: []ast.TransitionDecl{
+			{From: "Pending", To: "Shipped", Trigger: &ast.EventTrigger{Event: "ship"}, Guard: &ast.FieldExpr{Object: variable("order"), Field: "paid"}, Actions: []string{"notify"}},
+			on("Shipped", "Delivered", "deliver"),
+			{From: "Pending", To: "Cancelled", Trigger: &ast.AfterTrigger{Duration: ast.Duration{Value: 30, Unit: "m"}}},
+		},
+	})
+}
+
+// =============================================================================
+// SM001-SM006: シミュレーション
+// =============================================================================
+
+// SM001: イベントで遷移し、exit・遷移・entry の順にアクションが実行される
+func TestSimulator_Fire(t *testing.T) {
+	sim := New(orderChart())
+
+	step := sim.Start()
+	if step.Configuration != "Pending" || actions(step) != "entry createOrder" {
+		t.Fatalf("unexpected start: %s [%s]", step.Configuration, actions(step))
+	}
+
+	sim.Bind("order.paid", true)
+	step = sim.Fire("ship")
+	if step.Configuration != "Shipped" {
+		t.Errorf("expected Shipped, got %s", step.Configuration)
+	}
+	if got := actions(step); got != "exit lock, take Pending->Shipped, do notify, entry track" {
+		t.Errorf("unexpected actions: %s", got)
+	}
+
+	sim.Fire("deliver")
+	if !sim.Done() {
+		t.Errorf("expected the machine to be done in %s", sim.Configuration())
+	}
+}
+
+// SM002: 遷移しないイベントは無視され、値のないガードの名前が報告される
+func TestSimulator_Ignored(t *testing.T) {
+	sim := New(orderChart())
+
+	step := sim.Fire("ship")
+	if !step.Ignored || step.Unknown {
+		t.Errorf("expected ship to be ignored as a known event: %+v", step)
+	}
+	if strings.Join(step.Unbound, ",") != "order.paid" {
+		t.Errorf("unexpected unbound names: %v", step.Unbound)
+	}
+	if step.Configuration != "Pending" {
+		t.Errorf("expected to stay in Pending, got %s", step.Configuration)
+	}
+
+	if step := sim.Fire("refund"); !step.Ignored || !step.Unknown {
+		t.Errorf("expected refund to be reported as unknown: %+v", step)
+	}
+}
+
+// SM003: 時間を進めると期限を迎えた after の遷移が実行される
+func TestSimulator_Advance(t *testing.T) {
+	sim := New(orderChart())
+	sim.Start()
+
+	if step := sim.Advance(10 * time.Minute); len(step.Transitions) != 0 || step.Configuration != "Pending" {
+		t.Errorf("expected no transition before the deadline: %s", step.Configuration)
+	}
+	step := sim.Advance(25 * time.Minute)
+	if step.Configuration != "Cancelled" || step.Trigger != "+25m" {
+		t.Errorf("expected Cancelled after 30m, got %s (%s)", step.Configuration, step.Trigger)
+	}
+	if sim.Now() != 35*time.Minute {
+		t.Errorf("unexpected clock: %v", sim.Now())
+	}
+}
+
+// SM004: 階層状態と並行状態の構成
+func TestSimulator_HierarchyAndParallel(t *testing.T) {
+	running := "Running"
+	sim := New(statechart.New(&ast.StatesDecl{
+		Name:    "Job",
+		Initial: "Active",
+		States: []ast.StateDecl{{
+			Name:        "Active",
+			Initial:     &running,
+			Exit:        []string{"cleanup"},
+			States:      []ast.StateDecl{{Name: "Running"}, {Name: "Paused"}},
+			Transitions: []ast.TransitionDecl{on("Running", "Paused", "pause")},
+		}},
+		Parallels: []ast.ParallelDecl{{
+			Name: "Checkout",
+			Regions: []ast.RegionDecl{
+				{Name: "Payment", Initial: "Authorizing", Transitions: []ast.TransitionDecl{on("Authorizing", "Charged", "charged")}},
+				{Name: "Shipping", Initial: "Packing"},
+			},
+		}},
+		Transitions: []ast.TransitionDecl{on("Active", "Checkout", "checkout")},
+	}))
+
+	if got := sim.Start().Configuration; got != "Active/Running" {
+		t.Errorf("unexpected start configuration: %s", got)
+	}
+	if got := sim.Fire("pause").Configuration; got != "Active/Paused" {
+		t.Errorf("unexpected configuration after pause: %s", got)
+	}
+	step := sim.Fire("checkout")
+	if step.Configuration != "Checkout{Payment: Authorizing, Shipping: Packing}" {
+		t.Errorf("unexpected parallel configuration: %s", step.Configuration)
+	}
+	if actions(step) != "exit cleanup, take Active->Checkout" {
+		t.Errorf("expected the parent exit action to run: %s", actions(step))
+	}
+	if got := sim.Fire("charged").Configuration; got != "Checkout{Payment: Charged, Shipping: Packing}" {
+		t.Errorf("unexpected configuration after charged: %s", got)
+	}
+
+	states, transitions := sim.Visited()
+	var visited []string
+	for _, st := range states {
+		visited = append(visited, st.Name)
+	}
+	if strings.Join(visited, ",") != "Active,Running,Paused,Checkout,Payment,Authorizing,Charged,Shipping,Packing" {
+		t.Errorf("unexpected visited states: %v", visited)
+	}
+	if len(transitions) != 3 {
+		t.Errorf("expected 3 visited transitions, got %d", len(transitions))
+	}
+}
+
+// SM005: 条件で待つ遷移は値を設定したときに評価し直される
+func TestSimulator_WhenCondition(t *testing.T) {
+	sim := New(statechart.New(&ast.StatesDecl{
+		Name:    "Payment",
+		Initial: "Waiting",
+		Transitions: []ast.TransitionDecl{{
+			From: "Waiting",
+			To:   "Failed",
+			Trigger: &ast.WhenTrigger{Condition: &ast.BinaryExpr{
+				Left:  variable("retries"),
+				Op:    ">=",
+				Right: &ast.LiteralExpr{Value: int64(3)},
+			}},
+		}},
+	}))
+
+	sim.Bind("retries", float64(1))
+	if got := sim.Start().Configuration; got != "Waiting" {
+		t.Errorf("unexpected start configuration: %s", got)
+	}
+	if got := sim.Bind("retries", float64(3)).Configuration; got != "Failed" {
+		t.Errorf("expected the condition to fire, got %s", got)
+	}
+}
+
+// SM006: スクリプトの解析
+func TestParseScript(t *testing.T) {
+	commands, err := ParseScript("order.paid=true, ship # comment\n+1h30m\nname=\"a b\" retries=3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Command{
+		{Line: 1, Kind: CommandBind, Name: "order.paid", Value: true},
+		{Line: 1, Kind: CommandEvent, Event: "ship"},
+		{Line: 2, Kind: CommandAdvance, Duration: 90 * time.Minute},
+		{Line: 3, Kind: CommandBind, Name: "name", Value: "a b"},
+		{Line: 3, Kind: CommandBind, Name: "retries", Value: float64(3)},
+	}
+	if len(commands) != len(want) {
+		t.Fatalf("expected %d commands, got %d: %+v", len(want), len(commands), commands)
+	}
+	for i := range want {
+		if commands[i] != want[i] {
+			t.Errorf("command %d = %+v, want %+v", i, commands[i], want[i])
+		}
+	}
+
+	if _, err := ParseScript("+5x"); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected an invalid duration error, got %v", err)
+	}
+	if got := FormatDuration(90 * time.Minute); got != "1h30m" {
+		t.Errorf("FormatDuration = %q", got)
+	}
+}
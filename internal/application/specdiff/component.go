@@ -0,0 +1,253 @@
+package specdiff
+
+import (
+	"strings"
+
+	"pact/internal/domain/ast"
+	"pact/internal/infrastructure/formatter"
+)
+
+// component はコンポーネントの関係・provides・requires・states を比較する
+func (d *differ) component(o, n *ast.ComponentDecl) {
+	oldFile, newFile := d.old.files[o], d.new.files[n]
+	d.relations(n.Name, oldFile, newFile, o.Body.Relations, n.Body.Relations)
+	d.interfaces(n.Name, oldFile, newFile, o.Body.Provides, n.Body.Provides, true)
+	d.interfaces(n.Name, oldFile, newFile, o.Body.Requires, n.Body.Requires, false)
+	d.statesBlocks(n.Name, oldFile, newFile, o.Body.States, n.Body.States)
+}
+
+// relations は関係を種類と対象で対応付けて比較する
+// extends・implements の削除は、コンポーネントを親の型として扱えなくなるため破壊的な変更とする
+func (d *differ) relations(owner, oldFile, newFile string, old, new []ast.RelationDecl) {
+	key := func(r *ast.RelationDecl) string {
+		return owner + " " + strings.ReplaceAll(string(r.Kind), "_", " ") + " " + r.Target
+	}
+	oldRelations := make(map[string]*ast.RelationDecl)
+	for i := range old {
+		oldRelations[key(&old[i])] = &old[i]
+	}
+	newRelations := make(map[string]*ast.RelationDecl)
+	for i := range new {
+		newRelations[key(&new[i])] = &new[i]
+	}
+
+	for i := range old {
+		o := &old[i]
+		n, ok := newRelations[key(o)]
+		if !ok {
+			breaking := o.Kind == ast.RelationExtends || o.Kind == ast.RelationImplements
+			d.removed(location{file: oldFile, pos: o.Pos}, ElementRelation, key(o), "", breaking, "")
+			continue
+		}
+		if before, after := relationText(o), relationText(n); before != after {
+			d.changed(location{file: newFile, pos: n.Pos}, ElementRelation, key(n), before, after, false, "")
+		}
+	}
+	for i := range new {
+		if n := &new[i]; oldRelations[key(n)] == nil {
+			d.added(location{file: newFile, pos: n.Pos}, ElementRelation, key(n), "", false, "")
+		}
+	}
+}
+
+// relationText は関係の対象を "Target: type as alias" の形にする
+func relationText(r *ast.RelationDecl) string {
+	text := r.Target
+	if r.TargetType != nil {
+		text += ": " + *r.TargetType
+	}
+	if r.Alias != nil {
+		text += " as " + *r.Alias
+	}
+	return text
+}
+
+// interfaces は provides（provided が true）または requires のインターフェースを名前で対応付けて比較する
+// provides は削除が、requires は追加が破壊的な変更になる
+func (d *differ) interfaces(owner, oldFile, newFile string, old, new []ast.InterfaceDecl, provided bool) {
+	keyword := "requires"
+	if provided {
+		keyword = "provides"
+	}
+	newInterfaces := make(map[string]*ast.InterfaceDecl)
+	for i := range new {
+		newInterfaces[new[i].Name] = &new[i]
+	}
+	oldInterfaces := make(map[string]bool)
+	for i := range old {
+		o := &old[i]
+		oldInterfaces[o.Name] = true
+		name := owner + "." + o.Name
+		if n, ok := newInterfaces[o.Name]; ok {
+			d.methods(name, oldFile, newFile, o.Methods, n.Methods, provided)
+		} else {
+			d.removed(location{file: oldFile, pos: o.Pos}, ElementInterface, name, keyword, provided, "")
+		}
+	}
+	for i := range new {
+		if n := &new[i]; !oldInterfaces[n.Name] {
+			d.added(location{file: newFile, pos: n.Pos}, ElementInterface, owner+"."+n.Name, keyword, !provided, requirement(provided))
+		}
+	}
+}
+
+// requirement は requires への追加の理由を返す
+func requirement(provided bool) string {
+	if provided {
+		return ""
+	}
+	return "new requirement"
+}
+
+// methods はインターフェースのメソッドを名前で対応付けて比較する
+func (d *differ) methods(owner, oldFile, newFile string, old, new []ast.MethodDecl, provided bool) {
+	newMethods := make(map[string]*ast.MethodDecl)
+	for i := range new {
+		newMethods[new[i].Name] = &new[i]
+	}
+	oldMethods := make(map[string]bool)
+	for i := range old {
+		o := &old[i]
+		oldMethods[o.Name] = true
+		name := owner + "." + o.Name
+		if n, ok := newMethods[o.Name]; ok {
+			d.method(name, oldFile, newFile, o, n, provided)
+		} else {
+			d.removed(location{file: oldFile, pos: o.Pos}, ElementMethod, name, methodSignature(o), provided, "")
+		}
+	}
+	for i := range new {
+		if n := &new[i]; !oldMethods[n.Name] {
+			d.added(location{file: newFile, pos: n.Pos}, ElementMethod, owner+"."+n.Name, methodSignature(n), !provided, requirement(provided))
+		}
+	}
+}
+
+// method はメソッドのパラメータ・戻り値型・例外・async を比較する
+// provides のメソッドは呼び出し側から見て、パラメータの型を広げる・戻り値型を狭める・例外を減らす変更に互換性がある
+// requires のメソッドは実装する側から見るため、型と例外の向きが逆になる
+func (d *differ) method(name, oldFile, newFile string, o, n *ast.MethodDecl, provided bool) {
+	where := location{file: newFile, pos: n.Pos}
+	if o.Async != n.Async {
+		d.changed(where, ElementMethod, name, methodSignature(o), methodSignature(n), true, "async changed")
+	}
+	d.params(name, oldFile, newFile, o.Params, n.Params, provided)
+
+	oldReturn, newReturn := returnType(o), returnType(n)
+	if v := compareTypes(oldReturn, newReturn); v != sameType {
+		compatible := (provided && v == narrowed) || (!provided && v == widened)
+		d.changed(where, ElementMethod, name, "returns "+formatter.Type(oldReturn), "returns "+formatter.Type(newReturn), !compatible, v.String())
+	}
+
+	added, removed := difference(o.Throws, n.Throws), difference(n.Throws, o.Throws)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	var reasons []string
+	if len(added) > 0 {
+		reasons = append(reasons, "newly thrown "+quoted(added))
+	}
+	if len(removed) > 0 {
+		reasons = append(reasons, "no longer thrown "+quoted(removed))
+	}
+	breaking := (provided && len(added) > 0) || (!provided && len(removed) > 0)
+	d.changed(where, ElementMethod, name, throwsText(o.Throws), throwsText(n.Throws), breaking, strings.Join(reasons, ", "))
+}
+
+// params はパラメータを名前で対応付けて比較する
+// provides のメソッドの末尾への null 許容のパラメータの追加だけは互換性がある
+func (d *differ) params(method, oldFile, newFile string, old, new []ast.ParamDecl, provided bool) {
+	name := func(p *ast.ParamDecl) string {
+		return method + "(" + p.Name + ")"
+	}
+	oldParams := make(map[string]*ast.ParamDecl)
+	for i := range old {
+		oldParams[old[i].Name] = &old[i]
+	}
+	newParams := make(map[string]*ast.ParamDecl)
+	for i := range new {
+		newParams[new[i].Name] = &new[i]
+	}
+
+	// 両方にあるパラメータの並び順
+	var oldOrder, newOrder []string
+	for i := range old {
+		if newParams[old[i].Name] != nil {
+			oldOrder = append(oldOrder, old[i].Name)
+		}
+	}
+	for i := range new {
+		if oldParams[new[i].Name] != nil {
+			newOrder = append(newOrder, new[i].Name)
+		}
+	}
+	if strings.Join(oldOrder, ",") != strings.Join(newOrder, ",") {
+		d.changed(location{file: newFile, pos: new[0].Pos}, ElementParam, method+"(...)",
+			strings.Join(oldOrder, ", "), strings.Join(newOrder, ", "), true, "parameter order changed")
+	}
+
+	for i := range old {
+		o := &old[i]
+		n, ok := newParams[o.Name]
+		if !ok {
+			d.removed(location{file: oldFile, pos: o.Pos}, ElementParam, name(o), formatter.Type(o.Type), true, "")
+			continue
+		}
+		if v := compareTypes(o.Type, n.Type); v != sameType {
+			compatible := (provided && v == widened) || (!provided && v == narrowed)
+			d.changed(location{file: newFile, pos: n.Pos}, ElementParam, name(n), formatter.Type(o.Type), formatter.Type(n.Type), !compatible, v.String())
+		}
+	}
+	lastCommon := -1
+	for i := range new {
+		if oldParams[new[i].Name] != nil {
+			lastCommon = i
+		}
+	}
+	for i := range new {
+		n := &new[i]
+		if oldParams[n.Name] != nil {
+			continue
+		}
+		optional := provided && i > lastCommon && n.Type.Nullable
+		reason := "required parameter"
+		switch {
+		case !provided:
+			reason = "new parameter"
+		case optional:
+			reason = "optional parameter"
+		}
+		d.added(location{file: newFile, pos: n.Pos}, ElementParam, name(n), formatter.Type(n.Type), !optional, reason)
+	}
+}
+
+// returnType はメソッドの戻り値型を返す（省略時は void）
+func returnType(m *ast.MethodDecl) ast.TypeExpr {
+	if m.ReturnType == nil {
+		return ast.TypeExpr{Name: "void"}
+	}
+	return *m.ReturnType
+}
+
+// throwsText は例外の一覧を "throws A, B" の形にする
+func throwsText(throws []string) string {
+	if len(throws) == 0 {
+		return "no throws"
+	}
+	return "throws " + strings.Join(throws, ", ")
+}
+
+// difference は b にあって a にない名前を b の順で返す
+func difference(a, b []string) []string {
+	in := make(map[string]bool)
+	for _, name := range a {
+		in[name] = true
+	}
+	var diff []string
+	for _, name := range b {
+		if !in[name] {
+			diff = append(diff, name)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,213 @@
+// Package specdiff compares two versions of .pact specs and classifies each change as breaking or compatible.
+package specdiff
+
+import (
+	"fmt"
+
+	"pact/internal/domain/ast"
+)
+
+// Kind は変更の種類
+type Kind string
+
+const (
+	KindAdded   Kind = "added"
+	KindRemoved Kind = "removed"
+	KindChanged Kind = "changed"
+)
+
+// Element は変更された宣言の種類
+type Element string
+
+const (
+	ElementComponent  Element = "component"
+	ElementType       Element = "type"
+	ElementField      Element = "field"
+	ElementEnumValue  Element = "enum value"
+	ElementInterface  Element = "interface"
+	ElementMethod     Element = "method"
+	ElementParam      Element = "parameter"
+	ElementRelation   Element = "relation"
+	ElementStates     Element = "states"
+	ElementState      Element = "state"
+	ElementTransition Element = "transition"
+)
+
+// Change は仕様の1つの変更
+type Change struct {
+	Kind     Kind
+	Element  Element
+	Name     string // 宣言の名前（"Order.id"、"OrderService.Create(amount)" など）
+	Old      string // 変更前の表記（added では空）
+	New      string // 変更後の表記（removed では空）
+	Breaking bool
+	Reason   string       // 分類の理由（"narrowed type" など）
+	File     string       // 変更後の宣言のある仕様のパス（removed では変更前）
+	Pos      ast.Position // File 内の宣言の位置
+}
+
+// String は "changed parameter 'A.M(x)': float -> int (narrowed type)" の形で表す
+func (c Change) String() string {
+	s := fmt.Sprintf("%s %s '%s'", c.Kind, c.Element, c.Name)
+	switch {
+	case c.Kind == KindChanged:
+		s += ": " + c.Old + " -> " + c.New
+	case c.Kind == KindAdded && c.New != "":
+		s += ": " + c.New
+	case c.Kind == KindRemoved && c.Old != "":
+		s += ": " + c.Old
+	}
+	if c.Reason != "" {
+		s += " (" + c.Reason + ")"
+	}
+	return s
+}
+
+// HasBreaking は破壊的な変更があるかどうかを返す
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare は変更前と変更後の仕様を比較し、変更を返す
+// コンポーネントと型はファイルをまたいで名前で対応付ける（別のファイルへの移動は変更にならない）
+// 同じ名前の宣言が複数ある場合は最初のものを使う
+func Compare(old, new []*ast.SpecFile) []Change {
+	d := &differ{old: collect(old), new: collect(new)}
+
+	for _, name := range d.old.typeOrder {
+		o := d.old.types[name]
+		if n, ok := d.new.types[name]; ok {
+			d.typeDecl(o, n)
+		} else {
+			d.removed(d.old.at(o, o.Pos), ElementType, name, string(o.Kind), true, "")
+		}
+	}
+	for _, name := range d.new.typeOrder {
+		if _, ok := d.old.types[name]; !ok {
+			n := d.new.types[name]
+			d.added(d.new.at(n, n.Pos), ElementType, name, string(n.Kind), false, "")
+		}
+	}
+
+	for _, name := range d.old.componentOrder {
+		o := d.old.components[name]
+		if n, ok := d.new.components[name]; ok {
+			d.component(o, n)
+		} else {
+			d.removed(d.old.at(o, o.Pos), ElementComponent, name, "", true, "")
+		}
+	}
+	for _, name := range d.new.componentOrder {
+		if _, ok := d.old.components[name]; !ok {
+			n := d.new.components[name]
+			d.added(d.new.at(n, n.Pos), ElementComponent, name, "", false, "")
+		}
+	}
+
+	for _, name := range d.old.interfaceOrder {
+		o := d.old.interfaces[name]
+		if n, ok := d.new.interfaces[name]; ok {
+			d.methods(name, d.old.files[o], d.new.files[n], o.Methods, n.Methods, true)
+		} else {
+			d.removed(d.old.at(o, o.Pos), ElementInterface, name, "", true, "")
+		}
+	}
+	for _, name := range d.new.interfaceOrder {
+		if _, ok := d.old.interfaces[name]; !ok {
+			n := d.new.interfaces[name]
+			d.added(d.new.at(n, n.Pos), ElementInterface, name, "", false, "")
+		}
+	}
+	return d.changes
+}
+
+// declarations は比較する宣言を名前で引ける表
+type declarations struct {
+	componentOrder []string
+	components     map[string]*ast.ComponentDecl
+	typeOrder      []string
+	types          map[string]*ast.TypeDecl
+	interfaceOrder []string
+	interfaces     map[string]*ast.InterfaceDecl // ファイルレベルのインターフェース
+	files          map[interface{}]string        // 宣言 → 宣言のある仕様のパス
+}
+
+func collect(specs []*ast.SpecFile) *declarations {
+	decls := &declarations{
+		components: make(map[string]*ast.ComponentDecl),
+		types:      make(map[string]*ast.TypeDecl),
+		interfaces: make(map[string]*ast.InterfaceDecl),
+		files:      make(map[interface{}]string),
+	}
+	addType := func(t *ast.TypeDecl, file string) {
+		if _, ok := decls.types[t.Name]; !ok {
+			decls.types[t.Name] = t
+			decls.typeOrder = append(decls.typeOrder, t.Name)
+			decls.files[t] = file
+		}
+	}
+	for _, spec := range specs {
+		for i := range spec.Types {
+			addType(&spec.Types[i], spec.Path)
+		}
+		for i := range spec.Interfaces {
+			iface := &spec.Interfaces[i]
+			if _, ok := decls.interfaces[iface.Name]; !ok {
+				decls.interfaces[iface.Name] = iface
+				decls.interfaceOrder = append(decls.interfaceOrder, iface.Name)
+				decls.files[iface] = spec.Path
+			}
+		}
+		for i := range spec.Components {
+			comp := &spec.Components[i]
+			if _, ok := decls.components[comp.Name]; !ok {
+				decls.components[comp.Name] = comp
+				decls.componentOrder = append(decls.componentOrder, comp.Name)
+				decls.files[comp] = spec.Path
+			}
+			for j := range comp.Body.Types {
+				addType(&comp.Body.Types[j], spec.Path)
+			}
+		}
+	}
+	return decls
+}
+
+// at は宣言の位置を返す（decl は表に登録した宣言）
+func (decls *declarations) at(decl interface{}, pos ast.Position) location {
+	return location{file: decls.files[decl], pos: pos}
+}
+
+// location は変更を報告する位置
+type location struct {
+	file string
+	pos  ast.Position
+}
+
+// differ は比較で見つけた変更を集める
+type differ struct {
+	old, new *declarations
+	changes  []Change
+}
+
+func (d *differ) add(where location, c Change) {
+	c.File, c.Pos = where.file, where.pos
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) added(where location, element Element, name, text string, breaking bool, reason string) {
+	d.add(where, Change{Kind: KindAdded, Element: element, Name: name, New: text, Breaking: breaking, Reason: reason})
+}
+
+func (d *differ) removed(where location, element Element, name, text string, breaking bool, reason string) {
+	d.add(where, Change{Kind: KindRemoved, Element: element, Name: name, Old: text, Breaking: breaking, Reason: reason})
+}
+
+func (d *differ) changed(where location, element Element, name, old, new string, breaking bool, reason string) {
+	d.add(where, Change{Kind: KindChanged, Element: element, Name: name, Old: old, New: new, Breaking: breaking, Reason: reason})
+}
@@ -0,0 +1,214 @@
+package specdiff
+
+import (
+	"strings"
+	"testing"
+
+	"pact/internal/domain/ast"
+)
+
+func typ(name string, nullable bool) ast.TypeExpr {
+	return ast.TypeExpr{Name: name, Nullable: nullable}
+}
+
+func param(name string, t ast.TypeExpr) ast.ParamDecl {
+	return ast.ParamDecl{Name: name, Type: t}
+}
+
+func method(name string, ret *ast.TypeExpr, throws []string, params ...ast.ParamDecl) ast.MethodDecl {
+	return ast.MethodDecl{Name: name, Params: params, ReturnType: ret, Throws: throws}
+}
+
+// service は OrderAPI を provides するコンポーネントの仕様を作る
+func service(path string, methods ...ast.MethodDecl) *ast.SpecFile {
+	return &ast.SpecFile{Path: path, Components: []ast.ComponentDecl{{
+		Name: "OrderService",
+		Body: ast.ComponentBody{Provides: []ast.InterfaceDecl{{Name: "OrderAPI", Methods: methods}}},
+	}}}
+}
+
+// changeStrings は変更を "breaking: ..." または "compatible: ..." の形にする
+func changeStrings(changes []Change) []string {
+	var found []string
+	for _, c := range changes {
+		class := "compatible"
+		if c.Breaking {
+			class = "breaking"
+		}
+		found = append(found, class+": "+c.String())
+	}
+	return found
+}
+
+func assertChanges(t *testing.T, changes []Change, expected ...string) {
+	t.Helper()
+	got := changeStrings(changes)
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected changes:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+// =============================================================================
+// SD001-SD006: 仕様の比較
+// =============================================================================
+
+// SD001: 同じ仕様には変更がない
+func TestCompare_NoChanges(t *testing.T) {
+	order := typ("Order", false)
+	spec := service("a.pact", method("Create", &order, []string{"Declined"}, param("amount", typ("float", false))))
+	if changes := Compare([]*ast.SpecFile{spec}, []*ast.SpecFile{spec}); len(changes) != 0 {
+		t.Errorf("unexpected changes: %v", changeStrings(changes))
+	}
+}
+
+// SD002: provides のメソッドの削除・パラメータの型の縮小・例外の追加は破壊的な変更
+func TestCompare_ProvidedMethods(t *testing.T) {
+	order := typ("Order", false)
+	nullableOrder := typ("Order", true)
+	old := service("old.pact",
+		method("Create", &order, nil, param("amount", typ("float", false)), param("note", typ("string", true))),
+		method("Find", &nullableOrder, nil, param("id", typ("string", false))),
+		method("Cancel", nil, nil, param("id", typ("string", false))),
+	)
+	new := service("new.pact",
+		method("Create", &order, []string{"Declined"}, param("amount", typ("int", false)), param("note", typ("string", false))),
+		method("Find", &order, nil, param("id", typ("string", false)), param("tenant", typ("string", true))),
+		method("Refund", nil, nil, param("id", typ("string", false))),
+	)
+
+	changes := Compare([]*ast.SpecFile{old}, []*ast.SpecFile{new})
+	assertChanges(t, changes,
+		"breaking: changed parameter 'OrderService.OrderAPI.Create(amount)': float -> int (narrowed type)",
+		"breaking: changed parameter 'OrderService.OrderAPI.Create(note)': string? -> string (narrowed type)",
+		"breaking: changed method 'OrderService.OrderAPI.Create': no throws -> throws Declined (newly thrown 'Declined')",
+		"compatible: added parameter 'OrderService.OrderAPI.Find(tenant)': string? (optional parameter)",
+		"compatible: changed method 'OrderService.OrderAPI.Find': returns Order? -> returns Order (narrowed type)",
+		"breaking: removed method 'OrderService.OrderAPI.Cancel': Cancel(id: string)",
+		"compatible: added method 'OrderService.OrderAPI.Refund': Refund(id: string)",
+	)
+	if !HasBreaking(changes) {
+		t.Error("expected breaking changes")
+	}
+	if changes[5].File != "old.pact" || changes[6].File != "new.pact" {
+		t.Errorf("removed changes should point to the old file: %s, %s", changes[5].File, changes[6].File)
+	}
+}
+
+// SD003: requires のメソッドは追加が破壊的な変更になり、型と例外の向きが逆になる
+func TestCompare_RequiredMethods(t *testing.T) {
+	spec := func(methods ...ast.MethodDecl) *ast.SpecFile {
+		return &ast.SpecFile{Components: []ast.ComponentDecl{{
+			Name: "Checkout",
+			Body: ast.ComponentBody{Requires: []ast.InterfaceDecl{{Name: "Payments", Methods: methods}}},
+		}}}
+	}
+	old := spec(method("Charge", nil, []string{"Declined"}, param("amount", typ("float", false))))
+	new := spec(
+		method("Charge", nil, nil, param("amount", typ("int", false))),
+		method("Refund", nil, nil),
+	)
+
+	assertChanges(t, Compare([]*ast.SpecFile{old}, []*ast.SpecFile{new}),
+		"compatible: changed parameter 'Checkout.Payments.Charge(amount)': float -> int (narrowed type)",
+		"breaking: changed method 'Checkout.Payments.Charge': throws Declined -> no throws (no longer thrown 'Declined')",
+		"breaking: added method 'Checkout.Payments.Refund': Refund() (new requirement)",
+	)
+}
+
+// SD004: 型・フィールド・列挙値・関係の変更（ファイルをまたいだ移動は変更にならない）
+func TestCompare_TypesAndRelations(t *testing.T) {
+	external := "external"
+	old := []*ast.SpecFile{
+		{Path: "types.pact", Types: []ast.TypeDecl{
+			{Name: "Order", Kind: ast.TypeKindStruct, Fields: []ast.FieldDecl{
+				{Name: "id", Type: typ("string", false)},
+				{Name: "total", Type: typ("int", false)},
+				{Name: "memo", Type: typ("string", false)},
+			}},
+			{Name: "Status", Kind: ast.TypeKindEnum, Values: []string{"PENDING", "PAID", "VOID"}},
+		}},
+		{Path: "service.pact", Components: []ast.ComponentDecl{{Name: "OrderService", Body: ast.ComponentBody{
+			Relations: []ast.RelationDecl{
+				{Kind: ast.RelationDependsOn, Target: "Gateway"},
+				{Kind: ast.RelationImplements, Target: "Service"},
+			},
+		}}}},
+	}
+	new := []*ast.SpecFile{
+		{Path: "service.pact", Components: []ast.ComponentDecl{{Name: "OrderService", Body: ast.ComponentBody{
+			Types: []ast.TypeDecl{
+				{Name: "Order", Kind: ast.TypeKindStruct, Fields: []ast.FieldDecl{
+					{Name: "id", Type: typ("string", false)},
+					{Name: "total", Type: typ("float", false)},
+					{Name: "memo", Type: typ("string", false), Visibility: ast.VisibilityPrivate},
+					{Name: "note", Type: typ("string", true)},
+					{Name: "currency", Type: typ("string", false)},
+				}},
+				{Name: "Status", Kind: ast.TypeKindEnum, Values: []string{"PENDING", "PAID", "REFUNDED"}},
+			},
+			Relations: []ast.RelationDecl{
+				{Kind: ast.RelationDependsOn, Target: "Gateway", TargetType: &external},
+				{Kind: ast.RelationDependsOn, Target: "Mailer"},
+			},
+		}}}},
+	}
+
+	assertChanges(t, Compare(old, new),
+		"compatible: changed field 'Order.total': int -> float (widened type)",
+		"breaking: changed field 'Order.memo': public -> private (visibility changed)",
+		"compatible: added field 'Order.note': string? (optional field)",
+		"breaking: added field 'Order.currency': string (required field)",
+		"breaking: removed enum value 'Status.VOID'",
+		"compatible: added enum value 'Status.REFUNDED'",
+		"compatible: changed relation 'OrderService depends on Gateway': Gateway -> Gateway: external",
+		"breaking: removed relation 'OrderService implements Service'",
+		"compatible: added relation 'OrderService depends on Mailer'",
+	)
+}
+
+// SD005: 状態・遷移の追加と削除、遷移先とアクションの変更
+func TestCompare_States(t *testing.T) {
+	on := func(event string) ast.Trigger { return &ast.EventTrigger{Event: event} }
+	spec := func(initial string, states []string, transitions ...ast.TransitionDecl) *ast.SpecFile {
+		decl := ast.StatesDecl{Name: "Lifecycle", Initial: initial, Transitions: transitions}
+		for _, name := range states {
+			decl.States = append(decl.States, ast.StateDecl{Name: name})
+		}
+		return &ast.SpecFile{Components: []ast.ComponentDecl{{Name: "Order", Body: ast.ComponentBody{States: []ast.StatesDecl{decl}}}}}
+	}
+	guard := &ast.BinaryExpr{Left: &ast.VariableExpr{Name: "amount"}, Op: ">", Right: &ast.LiteralExpr{Value: int64(0)}}
+	old := spec("Pending", []string{"Pending", "Paid", "Cancelled"},
+		ast.TransitionDecl{From: "Pending", To: "Paid", Trigger: on("pay"), Guard: guard},
+		ast.TransitionDecl{From: "Pending", To: "Cancelled", Trigger: on("cancel")},
+		ast.TransitionDecl{From: "Paid", To: "Cancelled", Trigger: on("refund"), Actions: []string{"notify"}},
+	)
+	new := spec("Pending", []string{"Pending", "Paid", "Refunded"},
+		ast.TransitionDecl{From: "Pending", To: "Paid", Trigger: on("pay"), Guard: guard},
+		ast.TransitionDecl{From: "Paid", To: "Refunded", Trigger: on("refund"), Actions: []string{"notify", "log"}},
+		ast.TransitionDecl{From: "Paid", To: "Pending", Trigger: &ast.AfterTrigger{Duration: ast.Duration{Value: 30, Unit: "m"}}},
+	)
+
+	assertChanges(t, Compare([]*ast.SpecFile{old}, []*ast.SpecFile{new}),
+		"breaking: removed state 'Order.Lifecycle.Cancelled'",
+		"compatible: added state 'Order.Lifecycle.Refunded'",
+		"breaking: removed transition 'Order.Lifecycle: Pending -> Cancelled on cancel'",
+		"breaking: changed transition 'Order.Lifecycle: Paid on refund': Cancelled -> Refunded (target changed)",
+		"compatible: changed transition 'Order.Lifecycle: Paid -> Refunded on refund': do [notify] -> do [notify, log]",
+		"compatible: added transition 'Order.Lifecycle: Paid -> Pending after 30m'",
+	)
+}
+
+// SD006: コンポーネントの追加・削除
+func TestCompare_Components(t *testing.T) {
+	old := &ast.SpecFile{Path: "old.pact", Components: []ast.ComponentDecl{{Name: "A", Pos: ast.Position{Line: 1}}, {Name: "B", Pos: ast.Position{Line: 3}}}}
+	new := &ast.SpecFile{Path: "new.pact", Components: []ast.ComponentDecl{{Name: "A"}, {Name: "C", Pos: ast.Position{Line: 5}}}}
+
+	changes := Compare([]*ast.SpecFile{old}, []*ast.SpecFile{new})
+	assertChanges(t, changes,
+		"breaking: removed component 'B'",
+		"compatible: added component 'C'",
+	)
+	if changes[0].Pos.Line != 3 || changes[1].Pos.Line != 5 {
+		t.Errorf("unexpected positions: %v, %v", changes[0].Pos, changes[1].Pos)
+	}
+}
@@ -0,0 +1,224 @@
+package specdiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pact/internal/domain/ast"
+)
+
+// statesBlocks は states ブロックを名前で対応付けて比較する
+func (d *differ) statesBlocks(owner, oldFile, newFile string, old, new []ast.StatesDecl) {
+	newBlocks := make(map[string]*ast.StatesDecl)
+	for i := range new {
+		newBlocks[new[i].Name] = &new[i]
+	}
+	oldBlocks := make(map[string]bool)
+	for i := range old {
+		o := &old[i]
+		oldBlocks[o.Name] = true
+		name := owner + "." + o.Name
+		if n, ok := newBlocks[o.Name]; ok {
+			d.statesBlock(name, oldFile, newFile, o, n)
+		} else {
+			d.removed(location{file: oldFile, pos: o.Pos}, ElementStates, name, "", true, "")
+		}
+	}
+	for i := range new {
+		if n := &new[i]; !oldBlocks[n.Name] {
+			d.added(location{file: newFile, pos: n.Pos}, ElementStates, owner+"."+n.Name, "", false, "")
+		}
+	}
+}
+
+// statesBlock は初期状態・状態・遷移を比較する
+// 状態と遷移の削除、初期状態と遷移先の変更は、受け付けるイベントの列が変わるため破壊的な変更とする
+func (d *differ) statesBlock(name, oldFile, newFile string, o, n *ast.StatesDecl) {
+	if o.Initial != n.Initial {
+		d.changed(location{file: newFile, pos: n.Pos}, ElementStates, name, "initial "+o.Initial, "initial "+n.Initial, true, "initial state changed")
+	}
+
+	oldMachine, newMachine := flatten(o), flatten(n)
+	for _, path := range oldMachine.order {
+		if _, ok := newMachine.states[path]; !ok {
+			d.removed(location{file: oldFile, pos: oldMachine.states[path]}, ElementState, name+"."+path, "", true, "")
+		}
+	}
+	for _, path := range newMachine.order {
+		if _, ok := oldMachine.states[path]; !ok {
+			d.added(location{file: newFile, pos: newMachine.states[path]}, ElementState, name+"."+path, "", false, "")
+		}
+	}
+
+	newTransitions := make(map[string]*scopedTransition)
+	for i := range newMachine.transitions {
+		t := &newMachine.transitions[i]
+		if _, ok := newTransitions[t.key()]; !ok {
+			newTransitions[t.key()] = t
+		}
+	}
+	oldTransitions := make(map[string]bool)
+	for i := range oldMachine.transitions {
+		t := &oldMachine.transitions[i]
+		oldTransitions[t.key()] = true
+		nt, ok := newTransitions[t.key()]
+		if !ok {
+			d.removed(location{file: oldFile, pos: t.decl.Pos}, ElementTransition, name+": "+t.label(true), "", true, "")
+			continue
+		}
+		where := location{file: newFile, pos: nt.decl.Pos}
+		if t.decl.To != nt.decl.To {
+			d.changed(where, ElementTransition, name+": "+nt.label(false), t.decl.To, nt.decl.To, true, "target changed")
+		}
+		if before, after := actionsText(t.decl.Actions), actionsText(nt.decl.Actions); before != after {
+			d.changed(where, ElementTransition, name+": "+nt.label(true), before, after, false, "")
+		}
+	}
+	for i := range newMachine.transitions {
+		if t := &newMachine.transitions[i]; !oldTransitions[t.key()] {
+			d.added(location{file: newFile, pos: t.decl.Pos}, ElementTransition, name+": "+t.label(true), "", false, "")
+		}
+	}
+}
+
+// machine は入れ子の状態とリージョンを平らにした states ブロック
+type machine struct {
+	order       []string                // 宣言順の状態のパス（"Parent.Child" など）
+	states      map[string]ast.Position // 状態のパス → 位置
+	transitions []scopedTransition
+}
+
+// scopedTransition は遷移と、遷移を宣言した状態・リージョンのパス
+type scopedTransition struct {
+	scope string // 親の状態・リージョンのパス（最上位は空）
+	decl  *ast.TransitionDecl
+}
+
+// key は遷移を元の状態・トリガー・ガードで識別する（遷移先とアクションは変更として比較する）
+func (t *scopedTransition) key() string {
+	return t.scope + "\x00" + t.decl.From + "\x00" + triggerText(t.decl.Trigger) + "\x00" + exprString(t.decl.Guard)
+}
+
+// label は遷移を "Parent: From -> To on event when guard" の形にする（withTarget が false なら遷移先を省く）
+func (t *scopedTransition) label(withTarget bool) string {
+	text := t.decl.From
+	if withTarget {
+		text += " -> " + t.decl.To
+	}
+	if trigger := triggerText(t.decl.Trigger); trigger != "" {
+		text += " " + trigger
+	}
+	if t.decl.Guard != nil {
+		text += " when " + exprString(t.decl.Guard)
+	}
+	if t.scope != "" {
+		text = t.scope + ": " + text
+	}
+	return text
+}
+
+func flatten(decl *ast.StatesDecl) *machine {
+	m := &machine{states: make(map[string]ast.Position)}
+	m.addStates("", decl.States, decl.Transitions)
+	for _, p := range decl.Parallels {
+		m.addState(p.Name, p.Pos)
+		for _, region := range p.Regions {
+			m.addStates(p.Name+"."+region.Name, region.States, region.Transitions)
+		}
+	}
+	return m
+}
+
+func (m *machine) addStates(scope string, states []ast.StateDecl, transitions []ast.TransitionDecl) {
+	prefix := ""
+	if scope != "" {
+		prefix = scope + "."
+	}
+	for i := range states {
+		s := &states[i]
+		m.addState(prefix+s.Name, s.Pos)
+		if len(s.States) > 0 || len(s.Transitions) > 0 {
+			m.addStates(prefix+s.Name, s.States, s.Transitions)
+		}
+	}
+	for i := range transitions {
+		m.transitions = append(m.transitions, scopedTransition{scope: scope, decl: &transitions[i]})
+	}
+}
+
+func (m *machine) addState(path string, pos ast.Position) {
+	if _, ok := m.states[path]; ok {
+		return
+	}
+	m.states[path] = pos
+	m.order = append(m.order, path)
+}
+
+// triggerText はトリガーを .pact の表記にする（トリガーがなければ空文字列）
+func triggerText(trigger ast.Trigger) string {
+	switch t := trigger.(type) {
+	case *ast.EventTrigger:
+		return "on " + t.Event
+	case *ast.AfterTrigger:
+		return "after " + strconv.Itoa(t.Duration.Value) + t.Duration.Unit
+	case *ast.WhenTrigger:
+		return "when " + exprString(t.Condition)
+	}
+	return ""
+}
+
+// actionsText はアクションの一覧を "do [a, b]" の形にする
+func actionsText(actions []string) string {
+	if len(actions) == 0 {
+		return "no actions"
+	}
+	return "do [" + strings.Join(actions, ", ") + "]"
+}
+
+// exprString は式を .pact の表記にする（入れ子の二項演算は括弧で囲む）
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case nil:
+		return ""
+	case *ast.LiteralExpr:
+		if s, ok := e.Value.(string); ok {
+			return strconv.Quote(s)
+		}
+		if e.Value == nil {
+			return "null"
+		}
+		return fmt.Sprint(e.Value)
+	case *ast.VariableExpr:
+		return e.Name
+	case *ast.FieldExpr:
+		return exprString(e.Object) + "." + e.Field
+	case *ast.CallExpr:
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = exprString(arg)
+		}
+		call := e.Method + "(" + strings.Join(args, ", ") + ")"
+		if e.Object != nil {
+			call = exprString(e.Object) + "." + call
+		}
+		return call
+	case *ast.BinaryExpr:
+		return operand(e.Left) + " " + e.Op + " " + operand(e.Right)
+	case *ast.UnaryExpr:
+		return e.Op + operand(e.Operand)
+	case *ast.TernaryExpr:
+		return operand(e.Condition) + " ? " + operand(e.Then) + " : " + operand(e.Else)
+	case *ast.NullishExpr:
+		return operand(e.Left) + " ?? " + operand(e.Right)
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+func operand(expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.BinaryExpr, *ast.TernaryExpr, *ast.NullishExpr:
+		return "(" + exprString(expr) + ")"
+	}
+	return exprString(expr)
+}
@@ -0,0 +1,213 @@
+package specdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"pact/internal/domain/ast"
+	"pact/internal/infrastructure/formatter"
+)
+
+// variance は型の変更の向き
+type variance int
+
+const (
+	sameType     variance = iota
+	widened               // 変更前の型の値をすべて受け入れる（T → T?、int → float など）
+	narrowed              // 変更後の型の値はすべて変更前の型の値（T? → T、float → int など）
+	incompatible          // どちらでもない
+)
+
+func (v variance) String() string {
+	switch v {
+	case widened:
+		return "widened type"
+	case narrowed:
+		return "narrowed type"
+	case incompatible:
+		return "incompatible type"
+	}
+	return ""
+}
+
+// combine は型の部分ごとの変更の向きを合わせる
+func (v variance) combine(o variance) variance {
+	switch {
+	case v == sameType:
+		return o
+	case o == sameType || o == v:
+		return v
+	}
+	return incompatible
+}
+
+// typeKinds は同じ型として扱うプリミティブ型名 → 種類
+var typeKinds = map[string]string{
+	"string": "string", "String": "string",
+	"int": "int", "Int": "int", "integer": "int", "Integer": "int",
+	"float": "float", "Float": "float", "double": "float", "Double": "float",
+	"bool": "bool", "Bool": "bool", "boolean": "bool", "Boolean": "bool",
+	"any": "any", "Any": "any", "object": "any", "Object": "any",
+}
+
+// compareTypes は型の変更の向きを返す
+func compareTypes(old, new ast.TypeExpr) variance {
+	v := compareNames(old.Name, new.Name)
+	if old.Array != new.Array || len(old.TypeParams) != len(new.TypeParams) {
+		return incompatible
+	}
+	for i := range old.TypeParams {
+		if compareTypes(old.TypeParams[i], new.TypeParams[i]) != sameType {
+			return incompatible
+		}
+	}
+	switch {
+	case old.Nullable && !new.Nullable:
+		v = v.combine(narrowed)
+	case !old.Nullable && new.Nullable:
+		v = v.combine(widened)
+	}
+	return v
+}
+
+func compareNames(old, new string) variance {
+	ok, nk := typeKinds[old], typeKinds[new]
+	switch {
+	case old == new || (ok != "" && ok == nk):
+		return sameType
+	case nk == "any":
+		return widened
+	case ok == "any":
+		return narrowed
+	case ok == "int" && nk == "float":
+		return widened
+	case ok == "float" && nk == "int":
+		return narrowed
+	}
+	return incompatible
+}
+
+// typeDecl は型の宣言を比較する
+func (d *differ) typeDecl(o, n *ast.TypeDecl) {
+	oldFile, newFile := d.old.files[o], d.new.files[n]
+	where := location{file: newFile, pos: n.Pos}
+	if o.Kind != n.Kind {
+		d.changed(where, ElementType, n.Name, string(o.Kind), string(n.Kind), true, "kind changed")
+		return
+	}
+
+	switch n.Kind {
+	case ast.TypeKindStruct:
+		d.fields(n.Name, oldFile, newFile, o.Fields, n.Fields)
+	case ast.TypeKindEnum:
+		d.enumValues(n.Name, where, o.Values, n.Values)
+	case ast.TypeKindAlias:
+		if o.BaseType == nil || n.BaseType == nil {
+			return
+		}
+		if v := compareTypes(*o.BaseType, *n.BaseType); v != sameType {
+			d.changed(where, ElementType, n.Name, formatter.Type(*o.BaseType), formatter.Type(*n.BaseType), v != widened, v.String())
+		}
+	}
+}
+
+// fields は構造体のフィールドを比較する
+// 型を広げる変更と null 許容のフィールドの追加は互換性があり、それ以外の変更と削除は破壊的な変更とする
+func (d *differ) fields(owner, oldFile, newFile string, old, new []ast.FieldDecl) {
+	oldFields := make(map[string]*ast.FieldDecl)
+	for i := range old {
+		oldFields[old[i].Name] = &old[i]
+	}
+	newFields := make(map[string]*ast.FieldDecl)
+	for i := range new {
+		newFields[new[i].Name] = &new[i]
+	}
+
+	for i := range old {
+		o := &old[i]
+		name := owner + "." + o.Name
+		n, ok := newFields[o.Name]
+		if !ok {
+			d.removed(location{file: oldFile, pos: o.Pos}, ElementField, name, formatter.Type(o.Type), true, "")
+			continue
+		}
+		where := location{file: newFile, pos: n.Pos}
+		if v := compareTypes(o.Type, n.Type); v != sameType {
+			d.changed(where, ElementField, name, formatter.Type(o.Type), formatter.Type(n.Type), v != widened, v.String())
+		}
+		if visibility(o) != visibility(n) {
+			d.changed(where, ElementField, name, string(visibility(o)), string(visibility(n)),
+				visibility(o) == ast.VisibilityPublic, "visibility changed")
+		}
+	}
+	for i := range new {
+		n := &new[i]
+		if _, ok := oldFields[n.Name]; ok {
+			continue
+		}
+		required := !n.Type.Nullable
+		reason := "optional field"
+		if required {
+			reason = "required field"
+		}
+		d.added(location{file: newFile, pos: n.Pos}, ElementField, owner+"."+n.Name, formatter.Type(n.Type), required, reason)
+	}
+}
+
+// visibility はフィールドの可視性を返す（省略時は public）
+func visibility(f *ast.FieldDecl) ast.Visibility {
+	if f.Visibility == "" {
+		return ast.VisibilityPublic
+	}
+	return f.Visibility
+}
+
+// enumValues は列挙型の値を比較する（値の削除は破壊的な変更）
+func (d *differ) enumValues(owner string, where location, old, new []string) {
+	oldValues := make(map[string]bool)
+	for _, value := range old {
+		oldValues[value] = true
+	}
+	newValues := make(map[string]bool)
+	for _, value := range new {
+		newValues[value] = true
+	}
+	for _, value := range old {
+		if !newValues[value] {
+			d.removed(where, ElementEnumValue, owner+"."+value, "", true, "")
+		}
+	}
+	for _, value := range new {
+		if !oldValues[value] {
+			d.added(where, ElementEnumValue, owner+"."+value, "", false, "")
+		}
+	}
+}
+
+// methodSignature はメソッドを .pact の表記にする
+func methodSignature(m *ast.MethodDecl) string {
+	params := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		params[i] = p.Name + ": " + formatter.Type(p.Type)
+	}
+	sig := m.Name + "(" + strings.Join(params, ", ") + ")"
+	if m.Async {
+		sig = "async " + sig
+	}
+	if m.ReturnType != nil {
+		sig += " -> " + formatter.Type(*m.ReturnType)
+	}
+	if len(m.Throws) > 0 {
+		sig += " throws " + strings.Join(m.Throws, ", ")
+	}
+	return sig
+}
+
+// quoted は名前の一覧を "'A', 'B'" の形にする
+func quoted(names []string) string {
+	q := make([]string, len(names))
+	for i, name := range names {
+		q[i] = fmt.Sprintf("'%s'", name)
+	}
+	return strings.Join(q, ", ")
+}
@@ -25,6 +25,7 @@ func (t *ClassTransformer) Transform(files []*ast.SpecFile, opts *TransformOptio
 	}
 
 	noteCounter := 0
+	names := newNameResolver(files)
 
 	for _, file := range files {
 		// ファイル内の全コンポーネントを収集
@@ -39,11 +40,12 @@ func (t *ClassTransformer) Transform(files []*ast.SpecFile, opts *TransformOptio
 
 			// コンポーネントをノードに変換
 			node := t.transformComponent(comp)
+			node.ID = names.nodeID(file, comp.Name)
 			diagram.Nodes = append(diagram.Nodes, node)
 
 			// コンポーネントのアノテーションからNotesを抽出
 			for _, ann := range comp.Annotations {
-				if note := t.extractNote(&ann, comp.Name, &noteCounter); note != nil {
+				if note := t.extractNote(&ann, node.ID, &noteCounter); note != nil {
 					diagram.Notes = append(diagram.Notes, *note)
 				}
 			}
@@ -51,11 +53,12 @@ func (t *ClassTransformer) Transform(files []*ast.SpecFile, opts *TransformOptio
 			// 型をノードに変換
 			for _, typ := range comp.Body.Types {
 				typeNode := t.transformType(&typ)
+				typeNode.ID = names.nodeID(file, typ.Name)
 				diagram.Nodes = append(diagram.Nodes, typeNode)
 
 				// 型のアノテーションからNotesを抽出
 				for _, ann := range typ.Annotations {
-					if note := t.extractNote(&ann, typ.Name, &noteCounter); note != nil {
+					if note := t.extractNote(&ann, typeNode.ID, &noteCounter); note != nil {
 						diagram.Notes = append(diagram.Notes, *note)
 					}
 				}
@@ -63,21 +66,39 @@ func (t *ClassTransformer) Transform(files []*ast.SpecFile, opts *TransformOptio
 
 			// 関係をエッジに変換
 			for _, rel := range comp.Body.Relations {
-				edge := t.transformRelation(comp.Name, &rel)
+				// 修飾名のターゲットは import 先の宣言に解決する
+				edge := t.transformRelation(node.ID, &rel)
+				edge.To = names.resolve(file, rel.Target)
 				diagram.Edges = append(diagram.Edges, edge)
 			}
 
 			// インターフェースをノードに変換
 			for _, iface := range comp.Body.Requires {
 				ifaceNode := t.transformInterface(&iface)
+				ifaceNode.ID = names.nodeID(file, iface.Name)
 				diagram.Nodes = append(diagram.Nodes, ifaceNode)
 			}
 		}
 	}
 
+	disambiguateNodeNames(diagram.Nodes)
+
 	return diagram, nil
 }
 
+// disambiguateNodeNames は表示名が衝突するノードを修飾名（ノードID）で表示する
+func disambiguateNodeNames(nodes []class.Node) {
+	count := make(map[string]int)
+	for _, node := range nodes {
+		count[node.Name]++
+	}
+	for i := range nodes {
+		if count[nodes[i].Name] > 1 && nodes[i].ID != nodes[i].Name {
+			nodes[i].Name = nodes[i].ID
+		}
+	}
+}
+
 // getComponents はファイルから全コンポーネントを取得する
 func (t *ClassTransformer) getComponents(file *ast.SpecFile) []*ast.ComponentDecl {
 	// Componentsがある場合はそれを使用
@@ -8,7 +8,7 @@ import (
 )
 
 // =============================================================================
-// TC001-TC017: ClassTransformer Tests
+// TC001-TC018: ClassTransformer Tests
 // =============================================================================
 
 // TC001: 空コンポーネント
@@ -513,3 +513,76 @@ func TestClassTransformer_EdgeLineStyle(t *testing.T) {
 		}
 	}
 }
+
+// TC018: エイリアス付き import の宣言は修飾名のノードIDになり、同名でも区別される
+func TestClassTransformer_QualifiedImports(t *testing.T) {
+	billing, shipping := "billing", "shipping"
+	files := []*ast.SpecFile{
+		{
+			Path: "specs/order.pact",
+			Imports: []ast.ImportDecl{
+				{Path: "./billing.pact", Alias: &billing},
+				{Path: "./shipping.pact", Alias: &shipping},
+			},
+			Components: []ast.ComponentDecl{
+				{
+					Name: "OrderService",
+					Body: ast.ComponentBody{
+						Relations: []ast.RelationDecl{
+							{Kind: ast.RelationDependsOn, Target: "billing.Gateway"},
+							{Kind: ast.RelationDependsOn, Target: "shipping.Gateway"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Path: "specs/billing.pact",
+			Components: []ast.ComponentDecl{
+				{
+					Name: "Gateway",
+					Body: ast.ComponentBody{
+						Relations: []ast.RelationDecl{{Kind: ast.RelationDependsOn, Target: "Ledger"}},
+					},
+				},
+				{Name: "Ledger"},
+			},
+		},
+		{
+			Path:       "specs/shipping.pact",
+			Components: []ast.ComponentDecl{{Name: "Gateway"}},
+		},
+	}
+
+	diagram, err := NewClassTransformer().Transform(files, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := make(map[string]string)
+	for _, node := range diagram.Nodes {
+		names[node.ID] = node.Name
+	}
+	for _, id := range []string{"OrderService", "billing.Gateway", "billing.Ledger", "shipping.Gateway"} {
+		if _, ok := names[id]; !ok {
+			t.Errorf("expected node %q, got %v", id, names)
+		}
+	}
+	if names["billing.Ledger"] != "Ledger" {
+		t.Errorf("expected display name 'Ledger', got %q", names["billing.Ledger"])
+	}
+	// 同名の宣言は修飾名で表示する
+	if names["billing.Gateway"] != "billing.Gateway" || names["shipping.Gateway"] != "shipping.Gateway" {
+		t.Errorf("expected qualified display names for colliding nodes, got %v", names)
+	}
+
+	edges := make(map[string]bool)
+	for _, edge := range diagram.Edges {
+		edges[edge.From+"->"+edge.To] = true
+	}
+	for _, want := range []string{"OrderService->billing.Gateway", "OrderService->shipping.Gateway", "billing.Gateway->billing.Ledger"} {
+		if !edges[want] {
+			t.Errorf("expected edge %s, got %v", want, edges)
+		}
+	}
+}
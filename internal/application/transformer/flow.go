@@ -57,6 +57,11 @@ func (t *FlowTransformer) Transform(files []*ast.SpecFile, opts *FlowOptions) (*
 				}
 			}
 		}
+
+		// 先に渡されたファイルを優先する（import 先の同名定義で上書きしない）
+		if targetFlow != nil {
+			break
+		}
 	}
 
 	if targetFlow == nil {
@@ -0,0 +1,118 @@
+package transformer
+
+import (
+	"path/filepath"
+
+	"pact/internal/domain/ast"
+)
+
+// nameResolver は import のエイリアスを使った修飾名を宣言元のファイルに解決する
+// 先頭ファイルがエイリアス付きで import したファイルの宣言は "alias.Name" をノードIDにする
+type nameResolver struct {
+	aliases  map[*ast.SpecFile]map[string]*ast.SpecFile // ファイルごとのエイリアス → import 先
+	prefixes map[*ast.SpecFile]string                   // 先頭ファイルから見たエイリアス
+	declared map[*ast.SpecFile]map[string]bool          // ファイルで宣言された名前
+}
+
+func newNameResolver(files []*ast.SpecFile) *nameResolver {
+	r := &nameResolver{
+		aliases:  make(map[*ast.SpecFile]map[string]*ast.SpecFile),
+		prefixes: make(map[*ast.SpecFile]string),
+		declared: make(map[*ast.SpecFile]map[string]bool),
+	}
+
+	for _, file := range files {
+		r.declared[file] = declaredNames(file)
+
+		aliases := make(map[string]*ast.SpecFile)
+		for _, imp := range file.Imports {
+			if imp.Alias == nil {
+				continue
+			}
+			if target := findImportedFile(files, file, imp.Path); target != nil {
+				aliases[*imp.Alias] = target
+			}
+		}
+		r.aliases[file] = aliases
+	}
+
+	if len(files) > 0 {
+		for alias, target := range r.aliases[files[0]] {
+			if target != files[0] {
+				r.prefixes[target] = alias
+			}
+		}
+	}
+
+	return r
+}
+
+// nodeID は file で宣言された name のノードIDを返す
+func (r *nameResolver) nodeID(file *ast.SpecFile, name string) string {
+	if alias, ok := r.prefixes[file]; ok {
+		return alias + "." + name
+	}
+	return name
+}
+
+// resolve は file 内の参照をノードIDに解決する
+// 解決できない参照はそのまま返す
+func (r *nameResolver) resolve(file *ast.SpecFile, ref string) string {
+	alias, member, ok := ast.SplitQualifiedName(ref)
+	if !ok {
+		if r.declared[file][ref] {
+			return r.nodeID(file, ref)
+		}
+		return ref
+	}
+	if target, ok := r.aliases[file][alias]; ok && r.declared[target][member] {
+		return r.nodeID(target, member)
+	}
+	return ref
+}
+
+// findImportedFile は from の import パスに対応するファイルを files から探す
+func findImportedFile(files []*ast.SpecFile, from *ast.SpecFile, importPath string) *ast.SpecFile {
+	if from.Path == "" {
+		return nil
+	}
+	want := absPath(filepath.Join(filepath.Dir(from.Path), importPath))
+	for _, f := range files {
+		if f.Path != "" && absPath(f.Path) == want {
+			return f
+		}
+	}
+	return nil
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
+
+// declaredNames はファイルで宣言されたコンポーネント名・型名・インターフェース名を返す
+func declaredNames(file *ast.SpecFile) map[string]bool {
+	names := make(map[string]bool)
+	for _, comp := range file.Components {
+		names[comp.Name] = true
+		for _, typ := range comp.Body.Types {
+			names[typ.Name] = true
+		}
+		for _, iface := range comp.Body.Requires {
+			names[iface.Name] = true
+		}
+	}
+	if file.Component != nil {
+		names[file.Component.Name] = true
+	}
+	for _, typ := range file.Types {
+		names[typ.Name] = true
+	}
+	for _, iface := range file.Interfaces {
+		names[iface.Name] = true
+	}
+	return names
+}
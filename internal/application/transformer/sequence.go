@@ -53,6 +53,11 @@ func (t *SequenceTransformer) Transform(files []*ast.SpecFile, opts *SequenceOpt
 				}
 			}
 		}
+
+		// 先に渡されたファイルを優先する（import 先の同名定義で上書きしない）
+		if targetFlow != nil {
+			break
+		}
 	}
 
 	if targetFlow == nil {
@@ -78,6 +83,7 @@ func (t *SequenceTransformer) Transform(files []*ast.SpecFile, opts *SequenceOpt
 	// 依存関係から参加者を収集
 	participantMap := make(map[string]sequence.ParticipantType)
 	participantMap[targetComponent.Name] = sequence.ParticipantTypeDefault
+	qualified := make(map[string]string) // 宣言名 → 修飾名の参加者ID
 
 	for _, rel := range targetComponent.Body.Relations {
 		if rel.Kind == ast.RelationDependsOn {
@@ -94,17 +100,38 @@ func (t *SequenceTransformer) Transform(files []*ast.SpecFile, opts *SequenceOpt
 					pType = sequence.ParticipantTypeActor
 				}
 			}
+			// 修飾名（alias.Name）の参加者は宣言名で表示し、宣言名での呼び出しも同じ参加者に向ける
+			name := rel.Target
+			if _, member, ok := ast.SplitQualifiedName(rel.Target); ok {
+				name = member
+				if _, exists := participantMap[member]; !exists {
+					participantMap[member] = pType
+					qualified[member] = rel.Target
+				}
+			}
 			participantMap[rel.Target] = pType
 			diagram.Participants = append(diagram.Participants, sequence.Participant{
 				ID:   rel.Target,
-				Name: rel.Target,
+				Name: name,
 				Type: pType,
 			})
 		}
 	}
 
+	// 宣言名が衝突する参加者は修飾名で表示する
+	count := make(map[string]int)
+	for _, p := range diagram.Participants {
+		count[p.Name]++
+	}
+	for i := range diagram.Participants {
+		if p := &diagram.Participants[i]; count[p.Name] > 1 {
+			p.Name = p.ID
+		}
+	}
+
 	// ステップをイベントに変換
 	diagram.Events = t.transformSteps(targetFlow.Steps, targetComponent.Name, opts.IncludeReturn)
+	qualifyEvents(diagram.Events, qualified)
 
 	// ステップから参照される参加者を自動追加（依存関係に未定義のもの）
 	t.collectUndeclaredParticipants(targetFlow.Steps, participantMap, diagram)
@@ -213,12 +240,39 @@ func (t *SequenceTransformer) transformSteps(steps []ast.Step, from string, incl
 }
 
 func (t *SequenceTransformer) getCallTarget(call *ast.CallExpr) string {
-	if v, ok := call.Object.(*ast.VariableExpr); ok {
-		return v.Name
+	switch obj := call.Object.(type) {
+	case *ast.VariableExpr:
+		return obj.Name
+	case *ast.FieldExpr:
+		// alias.Component.method() 形式の修飾名
+		if v, ok := obj.Object.(*ast.VariableExpr); ok {
+			return v.Name + "." + obj.Field
+		}
 	}
 	return "Unknown"
 }
 
+// qualifyEvents はメッセージの送受信先を修飾名の参加者IDに置き換える
+func qualifyEvents(events []sequence.Event, qualified map[string]string) {
+	if len(qualified) == 0 {
+		return
+	}
+	for _, event := range events {
+		switch e := event.(type) {
+		case *sequence.MessageEvent:
+			if id, ok := qualified[e.From]; ok {
+				e.From = id
+			}
+			if id, ok := qualified[e.To]; ok {
+				e.To = id
+			}
+		case *sequence.FragmentEvent:
+			qualifyEvents(e.Events, qualified)
+			qualifyEvents(e.AltEvents, qualified)
+		}
+	}
+}
+
 // formatExpr は式を文字列に整形する
 func (t *SequenceTransformer) formatExpr(expr ast.Expr) string {
 	if expr == nil {
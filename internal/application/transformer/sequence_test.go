@@ -332,3 +332,59 @@ func TestSeqTransformer_OptionReturnMessages(t *testing.T) {
 		t.Errorf("expected 1 event, got %d", len(diagram.Events))
 	}
 }
+
+// TS020: 複数ファイルで同名フローがある場合は先に渡されたファイルを使う
+func TestSequenceTransformer_FirstFileWins(t *testing.T) {
+	files := []*ast.SpecFile{
+		{
+			Components: []ast.ComponentDecl{
+				{Name: "Order", Body: ast.ComponentBody{Flows: []ast.FlowDecl{{Name: "Process"}}}},
+			},
+		},
+		{
+			Components: []ast.ComponentDecl{
+				{Name: "Payment", Body: ast.ComponentBody{Flows: []ast.FlowDecl{{Name: "Process"}}}},
+			},
+		},
+	}
+
+	diagram, err := NewSequenceTransformer().Transform(files, &SequenceOptions{FlowName: "Process"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diagram.Participants[0].ID != "Order" {
+		t.Errorf("expected flow from the first file, got participant %q", diagram.Participants[0].ID)
+	}
+}
+
+// TS021: 修飾名の依存先は宣言名で表示し、宣言名・修飾名どちらの呼び出しも同じ参加者に向ける
+func TestSequenceTransformer_QualifiedParticipant(t *testing.T) {
+	steps := []ast.Step{
+		&ast.CallStep{Expr: &ast.CallExpr{Object: &ast.VariableExpr{Name: "PaymentService"}, Method: "Charge"}},
+		&ast.CallStep{Expr: &ast.CallExpr{
+			Object: &ast.FieldExpr{Object: &ast.VariableExpr{Name: "billing"}, Field: "PaymentService"},
+			Method: "Refund",
+		}},
+	}
+	files := []*ast.SpecFile{createTestComponent(steps, []ast.RelationDecl{
+		{Kind: ast.RelationDependsOn, Target: "billing.PaymentService"},
+	})}
+
+	diagram, err := NewSequenceTransformer().Transform(files, &SequenceOptions{FlowName: "Process"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(diagram.Participants) != 2 {
+		t.Fatalf("expected 2 participants, got %+v", diagram.Participants)
+	}
+	p := diagram.Participants[1]
+	if p.ID != "billing.PaymentService" || p.Name != "PaymentService" {
+		t.Errorf("expected billing.PaymentService shown as PaymentService, got %+v", p)
+	}
+	for _, event := range diagram.Events {
+		if msg, ok := event.(*sequence.MessageEvent); ok && msg.To != "billing.PaymentService" {
+			t.Errorf("expected message to billing.PaymentService, got %q", msg.To)
+		}
+	}
+}
@@ -51,6 +51,11 @@ func (t *StateTransformer) Transform(files []*ast.SpecFile, opts *StateOptions)
 				}
 			}
 		}
+
+		// 先に渡されたファイルを優先する（import 先の同名定義で上書きしない）
+		if targetStates != nil {
+			break
+		}
 	}
 
 	if targetStates == nil {
@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"strings"
+
 	"pact/internal/domain/ast"
 	"pact/internal/domain/errors"
 )
@@ -27,12 +29,7 @@ func (v *Validator) validateRelationDuplicates(comp *ast.ComponentDecl) {
 func (v *Validator) validateRelationReferences(comp *ast.ComponentDecl, definedComponents, definedTypes map[string]bool) {
 	for _, rel := range comp.Body.Relations {
 		if !definedComponents[rel.Target] && !definedTypes[rel.Target] {
-			v.errors.Add(&errors.ValidationError{
-				Pos:     rel.Pos,
-				Type:    "undefined",
-				Name:    rel.Target,
-				Message: "referenced component/type is not defined",
-			})
+			v.addUndefined(rel.Pos, rel.Target, "referenced component/type is not defined")
 		}
 	}
 }
@@ -43,27 +40,33 @@ func (v *Validator) checkUnusedImports(spec *ast.SpecFile) {
 		return
 	}
 
-	// importされたパスを収集
+	referenced := referencedNames(spec)
+
 	for _, imp := range spec.Imports {
-		// エイリアスがある場合はエイリアス名で参照されるべき
-		// 簡易チェック: importが存在するが、対応するコンポーネントがない場合
-		alias := imp.Path
-		if imp.Alias != nil {
-			alias = *imp.Alias
-		}
 		used := false
 
-		// コンポーネント内の関係で使用されているか
-		for _, comp := range spec.Components {
-			for _, rel := range comp.Body.Relations {
-				if rel.Target == alias {
+		if imp.Alias != nil {
+			// エイリアス付きの import は修飾名で参照されていれば使用済み
+			prefix := *imp.Alias + "."
+			for name := range referenced {
+				if strings.HasPrefix(name, prefix) {
 					used = true
 					break
 				}
 			}
-			if used {
-				break
+		} else if imported, ok := v.imports[imp.Path]; ok {
+			// import 先の宣言がいずれか参照されていれば使用済み
+			declared := make(map[string]bool)
+			collectDeclarations(imported, declared, declared)
+			for name := range declared {
+				if referenced[name] {
+					used = true
+					break
+				}
 			}
+		} else {
+			// import 先が不明な場合はパス名で参照されているかで判定する
+			used = referenced[imp.Path]
 		}
 
 		if !used {
@@ -76,6 +79,45 @@ func (v *Validator) checkUnusedImports(spec *ast.SpecFile) {
 	}
 }
 
+// referencedNames は仕様内で参照されている関係ターゲット名と型名を収集する
+func referencedNames(spec *ast.SpecFile) map[string]bool {
+	names := make(map[string]bool)
+	addType := func(t *ast.TypeExpr) {
+		if t == nil {
+			return
+		}
+		names[t.Name] = true
+		for _, tp := range t.TypeParams {
+			names[tp.Name] = true
+		}
+	}
+	addInterfaces := func(ifaces []ast.InterfaceDecl) {
+		for _, iface := range ifaces {
+			for _, method := range iface.Methods {
+				for _, param := range method.Params {
+					addType(&param.Type)
+				}
+				addType(method.ReturnType)
+			}
+		}
+	}
+
+	for _, comp := range spec.Components {
+		for _, rel := range comp.Body.Relations {
+			names[rel.Target] = true
+		}
+		for _, typ := range comp.Body.Types {
+			for _, field := range typ.Fields {
+				addType(&field.Type)
+			}
+			addType(typ.BaseType)
+		}
+		addInterfaces(comp.Body.Provides)
+		addInterfaces(comp.Body.Requires)
+	}
+	return names
+}
+
 // checkDeprecatedUsage は @deprecated アノテーションの付いた要素の使用を検出する（L-005）
 func (v *Validator) checkDeprecatedUsage(spec *ast.SpecFile) {
 	// 非推奨の型・メソッドを収集
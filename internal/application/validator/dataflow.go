@@ -0,0 +1,371 @@
+package validator
+
+import (
+	"fmt"
+	"unicode"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+)
+
+// nameSet は変数名の集合
+type nameSet map[string]bool
+
+func (s nameSet) copy() nameSet {
+	c := make(nameSet, len(s))
+	for name := range s {
+		c[name] = true
+	}
+	return c
+}
+
+func (s nameSet) with(name string) nameSet {
+	c := s.copy()
+	c[name] = true
+	return c
+}
+
+func (s nameSet) union(o nameSet) nameSet {
+	c := s.copy()
+	for name := range o {
+		c[name] = true
+	}
+	return c
+}
+
+func (s nameSet) intersect(o nameSet) nameSet {
+	c := make(nameSet)
+	for name := range s {
+		if o[name] {
+			c[name] = true
+		}
+	}
+	return c
+}
+
+// flowScope はフローを検査するための、フローの外側で宣言された名前と型の表
+type flowScope struct {
+	spec   *ast.SpecFile
+	comp   *ast.ComponentDecl
+	method *ast.MethodDecl          // フロー名と同じ名前の provides のメソッド（なければ nil）
+	params map[string]*ast.TypeExpr // メソッドのパラメータ → 型
+	types  map[string]*ast.TypeDecl // 型名 → 宣言（import 先を含む）
+	names  nameSet                  // 変数ではない名前（コンポーネント・型・エイリアス）
+	deps   map[string]*dependency   // depends on の対象名・エイリアス → 依存先
+}
+
+// newFlowScopes はコンポーネントのフローごとの検査用の表を作る
+func (v *Validator) newFlowScopes(spec *ast.SpecFile, comp *ast.ComponentDecl) map[string]*flowScope {
+	types := v.declaredTypes(spec)
+	names := nameSet{"self": true}
+	for name := range types {
+		names[name] = true
+	}
+	for _, c := range spec.Components {
+		names[c.Name] = true
+	}
+	for _, imp := range spec.Imports {
+		if imp.Alias != nil {
+			names[*imp.Alias] = true
+		}
+	}
+	for _, rel := range comp.Body.Relations {
+		names[rel.Target] = true
+		if rel.Alias != nil {
+			names[*rel.Alias] = true
+		}
+	}
+
+	deps := v.dependencies(spec, comp)
+	scopes := make(map[string]*flowScope)
+	for i := range comp.Body.Flows {
+		flow := &comp.Body.Flows[i]
+		scope := &flowScope{spec: spec, comp: comp, types: types, names: names, deps: deps, params: make(map[string]*ast.TypeExpr)}
+		if m := providedMethod(comp, flow.Name); m != nil {
+			scope.method = m
+			for j := range m.Params {
+				scope.params[m.Params[j].Name] = &m.Params[j].Type
+			}
+		}
+		scopes[flow.Name] = scope
+	}
+	return scopes
+}
+
+// declaredTypes は仕様と import 先で宣言された型を名前で引ける表にする
+func (v *Validator) declaredTypes(spec *ast.SpecFile) map[string]*ast.TypeDecl {
+	types := make(map[string]*ast.TypeDecl)
+	collectTypeDecls(spec, "", types)
+	for _, imp := range spec.Imports {
+		imported, ok := v.imports[imp.Path]
+		if !ok {
+			continue
+		}
+		prefix := ""
+		if imp.Alias != nil {
+			prefix = *imp.Alias + "."
+		}
+		collectTypeDecls(imported, prefix, types)
+	}
+	return types
+}
+
+func collectTypeDecls(spec *ast.SpecFile, prefix string, types map[string]*ast.TypeDecl) {
+	for i := range spec.Types {
+		types[prefix+spec.Types[i].Name] = &spec.Types[i]
+	}
+	for i := range spec.Components {
+		for j := range spec.Components[i].Body.Types {
+			typ := &spec.Components[i].Body.Types[j]
+			types[prefix+typ.Name] = typ
+		}
+	}
+}
+
+// providedMethod はコンポーネントが provides するメソッドを名前で探す
+func providedMethod(comp *ast.ComponentDecl, name string) *ast.MethodDecl {
+	for i := range comp.Body.Provides {
+		for j := range comp.Body.Provides[i].Methods {
+			if m := &comp.Body.Provides[i].Methods[j]; m.Name == name {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// isKnownName は名前が変数ではなく、フローの外側の宣言を指すかどうかを返す
+// 大文字で始まる名前はコンポーネントや依存先として扱う
+func (s *flowScope) isKnownName(name string) bool {
+	if s.names[name] {
+		return true
+	}
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+// checkFlowVariables はフローの変数の使い方を検査する（未代入の読み出し・読まれない代入・ループ変数の隠蔽）
+func (v *Validator) checkFlowVariables(spec *ast.SpecFile) {
+	for i := range spec.Components {
+		comp := &spec.Components[i]
+		scopes := v.newFlowScopes(spec, comp)
+		for j := range comp.Body.Flows {
+			flow := &comp.Body.Flows[j]
+			d := &dataflow{
+				v:        v,
+				scope:    scopes[flow.Name],
+				assigned: make(nameSet),
+				reported: make(nameSet),
+				used:     make(map[*ast.AssignStep]bool),
+			}
+			d.run(flow)
+		}
+	}
+}
+
+// dataflow は1つのフローの変数の流れを解析する
+type dataflow struct {
+	v        *Validator
+	scope    *flowScope
+	assigned nameSet // フロー内で代入される名前
+	reported nameSet // 報告済みの未代入の名前
+	used     map[*ast.AssignStep]bool
+	assigns  []*ast.AssignStep
+}
+
+func (d *dataflow) run(flow *ast.FlowDecl) {
+	d.collectAssigns(flow.Steps)
+
+	defined := make(nameSet)
+	for name := range d.scope.params {
+		defined[name] = true
+	}
+	d.forward(flow.Steps, defined, defined.copy(), make(nameSet))
+
+	d.backward(flow.Steps, make(nameSet))
+	for _, s := range d.assigns {
+		if !d.used[s] && s.Variable != "_" {
+			d.v.warn(s.Pos, errors.CodeUnusedAssignment, fmt.Sprintf("value assigned to '%s' is never read", s.Variable))
+		}
+	}
+}
+
+func (d *dataflow) collectAssigns(steps []ast.Step) {
+	for _, step := range steps {
+		switch s := step.(type) {
+		case *ast.AssignStep:
+			d.assigned[s.Variable] = true
+			d.assigns = append(d.assigns, s)
+		case *ast.IfStep:
+			d.collectAssigns(s.Then)
+			d.collectAssigns(s.Else)
+		case *ast.ForStep:
+			d.collectAssigns(s.Body)
+		case *ast.WhileStep:
+			d.collectAssigns(s.Body)
+		}
+	}
+}
+
+// forward はステップを先頭から辿り、必ず代入済みの名前（defined）と代入されうる名前（maybe）を求める
+// loops は外側のループ変数。return・throw で終わる場合は terminated を返す
+func (d *dataflow) forward(steps []ast.Step, defined, maybe, loops nameSet) (nameSet, nameSet, bool) {
+	for _, step := range steps {
+		switch s := step.(type) {
+		case *ast.AssignStep:
+			d.read(s.Value, defined)
+			defined = defined.with(s.Variable)
+			maybe = maybe.with(s.Variable)
+		case *ast.CallStep:
+			d.read(s.Expr, defined)
+		case *ast.ReturnStep:
+			d.read(s.Value, defined)
+			return defined, maybe, true
+		case *ast.ThrowStep:
+			return defined, maybe, true
+		case *ast.IfStep:
+			d.read(s.Condition, defined)
+			thenDefined, thenMaybe, thenEnds := d.forward(s.Then, defined, maybe, loops)
+			elseDefined, elseMaybe, elseEnds := d.forward(s.Else, defined, maybe, loops)
+			maybe = thenMaybe.union(elseMaybe)
+			switch {
+			case thenEnds && elseEnds:
+				return defined, maybe, true
+			case thenEnds:
+				defined = elseDefined
+			case elseEnds:
+				defined = thenDefined
+			default:
+				defined = thenDefined.intersect(elseDefined)
+			}
+		case *ast.ForStep:
+			d.read(s.Iterable, defined)
+			if maybe[s.Variable] || loops[s.Variable] {
+				what := "variable"
+				switch {
+				case loops[s.Variable]:
+					what = "outer loop variable"
+				case d.scope.params[s.Variable] != nil:
+					what = "parameter"
+				}
+				d.v.warn(s.Pos, errors.CodeShadowedVariable, fmt.Sprintf("loop variable '%s' shadows %s '%s'", s.Variable, what, s.Variable))
+			}
+			_, bodyMaybe, _ := d.forward(s.Body, defined.with(s.Variable), maybe, loops.with(s.Variable))
+			maybe = maybe.union(bodyMaybe)
+		case *ast.WhileStep:
+			d.read(s.Condition, defined)
+			_, bodyMaybe, _ := d.forward(s.Body, defined, maybe, loops)
+			maybe = maybe.union(bodyMaybe)
+		}
+	}
+	return defined, maybe, false
+}
+
+// read は式が読む変数が代入済みかを検査する
+// フローで一度も代入されない名前は、対応するメソッドがないフローでは暗黙の入力として扱う
+func (d *dataflow) read(expr ast.Expr, defined nameSet) {
+	for _, ref := range variableRefs(expr) {
+		name := ref.Name
+		if defined[name] || d.reported[name] || d.scope.isKnownName(name) {
+			continue
+		}
+		switch {
+		case d.assigned[name]:
+			d.v.warn(ref.Pos, errors.CodeUnassignedVariable, fmt.Sprintf("variable '%s' may be read before it is assigned", name))
+		case d.scope.method != nil:
+			d.v.warn(ref.Pos, errors.CodeUnassignedVariable, fmt.Sprintf("variable '%s' is never assigned and is not a parameter of '%s'", name, d.scope.method.Name))
+		default:
+			continue
+		}
+		d.reported[name] = true
+	}
+}
+
+// backward はステップを末尾から辿って生存変数を求め、読まれる代入に印を付ける
+// live はステップの後で生存している変数の集合。ステップの前で生存している変数の集合を返す
+func (d *dataflow) backward(steps []ast.Step, live nameSet) nameSet {
+	for i := len(steps) - 1; i >= 0; i-- {
+		switch s := steps[i].(type) {
+		case *ast.AssignStep:
+			if live[s.Variable] {
+				d.used[s] = true
+			}
+			live = live.copy()
+			delete(live, s.Variable)
+			live = live.union(variableNames(s.Value))
+		case *ast.CallStep:
+			live = live.union(variableNames(s.Expr))
+		case *ast.ReturnStep:
+			live = variableNames(s.Value)
+		case *ast.ThrowStep:
+			live = make(nameSet)
+		case *ast.IfStep:
+			thenLive := d.backward(s.Then, live)
+			elseLive := d.backward(s.Else, live)
+			live = thenLive.union(elseLive).union(variableNames(s.Condition))
+		case *ast.ForStep:
+			live = d.loop(s.Body, live.union(variableNames(s.Iterable)), s.Variable)
+		case *ast.WhileStep:
+			live = d.loop(s.Body, live.union(variableNames(s.Condition)), "")
+		}
+	}
+	return live
+}
+
+// loop はループの生存変数を不動点まで求める（本体の末尾では次の繰り返しの生存変数も生きている）
+func (d *dataflow) loop(body []ast.Step, live nameSet, variable string) nameSet {
+	for {
+		in := d.backward(body, live)
+		delete(in, variable)
+		next := live.union(in)
+		if len(next) == len(live) {
+			return live
+		}
+		live = next
+	}
+}
+
+// variableRefs は式が読む変数の参照を出現順に返す（関数呼び出しの関数名は含まない）
+func variableRefs(expr ast.Expr) []*ast.VariableExpr {
+	var refs []*ast.VariableExpr
+	var walk func(ast.Expr)
+	walk = func(expr ast.Expr) {
+		switch e := expr.(type) {
+		case *ast.VariableExpr:
+			refs = append(refs, e)
+		case *ast.FieldExpr:
+			walk(e.Object)
+		case *ast.CallExpr:
+			if e.Object != nil {
+				walk(e.Object)
+			}
+			for _, arg := range e.Args {
+				walk(arg)
+			}
+		case *ast.BinaryExpr:
+			walk(e.Left)
+			walk(e.Right)
+		case *ast.UnaryExpr:
+			walk(e.Operand)
+		case *ast.TernaryExpr:
+			walk(e.Condition)
+			walk(e.Then)
+			walk(e.Else)
+		case *ast.NullishExpr:
+			walk(e.Left)
+			walk(e.Right)
+		}
+	}
+	walk(expr)
+	return refs
+}
+
+func variableNames(expr ast.Expr) nameSet {
+	names := make(nameSet)
+	for _, ref := range variableRefs(expr) {
+		names[ref.Name] = true
+	}
+	return names
+}
@@ -0,0 +1,287 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+)
+
+// dependency は depends on で参照できるコンポーネント
+type dependency struct {
+	spec   *ast.SpecFile // コンポーネントを宣言した仕様
+	comp   *ast.ComponentDecl
+	prefix string // エイリアス付きの import 先の場合は "alias."
+}
+
+// method は依存先が provides するメソッドを名前で探す
+// フローでは Enrich を enrich と書くことが多いため、先頭の1文字の大文字・小文字は区別しない
+func (d *dependency) method(name string) *ast.MethodDecl {
+	if m := providedMethod(d.comp, name); m != nil {
+		return m
+	}
+	for i := range d.comp.Body.Provides {
+		for j := range d.comp.Body.Provides[i].Methods {
+			if m := &d.comp.Body.Provides[i].Methods[j]; sameMethodName(m.Name, name) {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// sameMethodName は先頭の1文字の大文字・小文字を除いて名前が一致するかどうかを返す
+func sameMethodName(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	ra, sizeA := utf8.DecodeRuneInString(a)
+	rb, sizeB := utf8.DecodeRuneInString(b)
+	return unicode.ToLower(ra) == unicode.ToLower(rb) && a[sizeA:] == b[sizeB:]
+}
+
+// methodNames は依存先が provides するメソッドの名前を返す
+func (d *dependency) methodNames() []string {
+	var names []string
+	for _, iface := range d.comp.Body.Provides {
+		for _, m := range iface.Methods {
+			names = append(names, m.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dependencies はコンポーネントの depends on の対象を、対象名とエイリアスで引ける表にする
+// 仕様にも import 先にも見つからない対象（external など）は含めない
+func (v *Validator) dependencies(spec *ast.SpecFile, comp *ast.ComponentDecl) map[string]*dependency {
+	deps := make(map[string]*dependency)
+	for _, rel := range comp.Body.Relations {
+		if rel.Kind != ast.RelationDependsOn {
+			continue
+		}
+		dep := v.findComponent(spec, rel.Target)
+		if dep == nil {
+			continue
+		}
+		deps[rel.Target] = dep
+		if rel.Alias != nil {
+			deps[*rel.Alias] = dep
+		}
+	}
+	return deps
+}
+
+// findComponent はコンポーネントを仕様と import 先から探す（"alias.Name" はエイリアス付きの import 先）
+func (v *Validator) findComponent(spec *ast.SpecFile, name string) *dependency {
+	alias, member, qualified := ast.SplitQualifiedName(name)
+	for _, imp := range spec.Imports {
+		imported, ok := v.imports[imp.Path]
+		if !ok {
+			continue
+		}
+		switch {
+		case qualified && imp.Alias != nil && *imp.Alias == alias:
+			if comp := componentByName(imported, member); comp != nil {
+				return &dependency{spec: imported, comp: comp, prefix: alias + "."}
+			}
+		case !qualified && imp.Alias == nil:
+			if comp := componentByName(imported, name); comp != nil {
+				return &dependency{spec: imported, comp: comp}
+			}
+		}
+	}
+	if qualified {
+		return nil
+	}
+	if comp := componentByName(spec, name); comp != nil {
+		return &dependency{spec: spec, comp: comp}
+	}
+	return nil
+}
+
+func componentByName(spec *ast.SpecFile, name string) *ast.ComponentDecl {
+	for i := range spec.Components {
+		if spec.Components[i].Name == name {
+			return &spec.Components[i]
+		}
+	}
+	return nil
+}
+
+// qualify は依存先で宣言された型名を、フローのある仕様から引ける名前にする
+func (s *flowScope) qualify(t *ast.TypeExpr, prefix string) *ast.TypeExpr {
+	if t == nil || prefix == "" || primitiveKinds[t.Name] != "" {
+		return t
+	}
+	if _, ok := s.types[prefix+t.Name]; !ok {
+		return t
+	}
+	q := *t
+	q.Name = prefix + t.Name
+	return &q
+}
+
+// callTarget は呼び出しの対象のメソッドを解決する
+// self の呼び出しはコンポーネント自身が provides するメソッドだけを解決する（それ以外は内部の処理として扱う）
+// target は呼び出しの対象の名前、ok は対象が検査できるコンポーネントかどうか
+func (s *flowScope) callTarget(call *ast.CallExpr) (method *ast.MethodDecl, dep *dependency, target string, ok bool) {
+	target = dottedName(call.Object)
+	if target == "" {
+		return nil, nil, "", false
+	}
+	if target == "self" {
+		self := &dependency{spec: s.spec, comp: s.comp}
+		return self.method(call.Method), self, target, false
+	}
+	dep, ok = s.deps[target]
+	if !ok || len(dep.methodNames()) == 0 {
+		return nil, nil, target, false
+	}
+	return dep.method(call.Method), dep, target, true
+}
+
+// dottedName は変数とフィールドアクセスの連なりを "a.b.c" の形にする（それ以外の式は空文字列）
+func dottedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.VariableExpr:
+		return e.Name
+	case *ast.FieldExpr:
+		if object := dottedName(e.Object); object != "" {
+			return object + "." + e.Field
+		}
+	}
+	return ""
+}
+
+// checkCalls はフローの呼び出しを依存先のメソッドと照合し、フィールドアクセスを型のフィールドと照合する
+func (v *Validator) checkCalls(steps []ast.Step, env *typeEnv) {
+	for _, step := range steps {
+		switch s := step.(type) {
+		case *ast.AssignStep:
+			v.checkExprCalls(s.Value, env)
+		case *ast.CallStep:
+			v.checkExprCalls(s.Expr, env)
+		case *ast.ReturnStep:
+			v.checkExprCalls(s.Value, env)
+		case *ast.IfStep:
+			v.checkExprCalls(s.Condition, env)
+			v.checkCalls(s.Then, env)
+			v.checkCalls(s.Else, env)
+		case *ast.ForStep:
+			v.checkExprCalls(s.Iterable, env)
+			v.checkCalls(s.Body, env)
+		case *ast.WhileStep:
+			v.checkExprCalls(s.Condition, env)
+			v.checkCalls(s.Body, env)
+		}
+	}
+}
+
+func (v *Validator) checkExprCalls(expr ast.Expr, env *typeEnv) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		v.checkCall(e, env)
+		for _, arg := range e.Args {
+			v.checkExprCalls(arg, env)
+		}
+	case *ast.FieldExpr:
+		v.checkField(e, env)
+		v.checkExprCalls(e.Object, env)
+	case *ast.BinaryExpr:
+		v.checkExprCalls(e.Left, env)
+		v.checkExprCalls(e.Right, env)
+	case *ast.UnaryExpr:
+		v.checkExprCalls(e.Operand, env)
+	case *ast.TernaryExpr:
+		v.checkExprCalls(e.Condition, env)
+		v.checkExprCalls(e.Then, env)
+		v.checkExprCalls(e.Else, env)
+	case *ast.NullishExpr:
+		v.checkExprCalls(e.Left, env)
+		v.checkExprCalls(e.Right, env)
+	}
+}
+
+// checkCall は呼び出しのメソッド名・引数の数・引数の型を検査する
+func (v *Validator) checkCall(call *ast.CallExpr, env *typeEnv) {
+	method, dep, target, checkable := env.scope.callTarget(call)
+	name := target + "." + call.Method
+	if method == nil {
+		if checkable {
+			v.errors.Add(&errors.ValidationError{
+				Pos:     call.Pos,
+				Type:    errors.CodeUnknownMethod,
+				Name:    name,
+				Message: fmt.Sprintf("'%s' does not provide method '%s' (provides: %s)", target, call.Method, strings.Join(dep.methodNames(), ", ")),
+			})
+		}
+		return
+	}
+
+	if len(call.Args) != len(method.Params) {
+		v.errors.Add(&errors.ValidationError{
+			Pos:     call.Pos,
+			Type:    errors.CodeArgumentCount,
+			Name:    name,
+			Message: fmt.Sprintf("expects %d argument(s), got %d", len(method.Params), len(call.Args)),
+		})
+		return
+	}
+	for i, arg := range call.Args {
+		param := method.Params[i]
+		expected := env.scope.qualify(&param.Type, dep.prefix)
+		if t := env.typeOf(arg); t != nil && !env.assignable(t, expected) {
+			v.errors.Add(&errors.ValidationError{
+				Pos:     call.Pos,
+				Type:    errors.CodeTypeMismatch,
+				Name:    name,
+				Message: fmt.Sprintf("argument %d (%s) is %s but the parameter is %s", i+1, param.Name, typeString(t), typeString(expected)),
+			})
+		}
+	}
+}
+
+// checkField はフィールドアクセスが構造体のフィールドを指しているかを検査する
+func (v *Validator) checkField(field *ast.FieldExpr, env *typeEnv) {
+	t := env.typeOf(field.Object)
+	if t == nil {
+		return
+	}
+	decl := env.structDecl(t)
+	if decl == nil || fieldByName(decl, field.Field) != nil {
+		return
+	}
+	v.errors.Add(&errors.ValidationError{
+		Pos:     field.Pos,
+		Type:    errors.CodeUnknownField,
+		Name:    dottedName(field),
+		Message: fmt.Sprintf("type '%s' has no field '%s'", decl.Name, field.Field),
+	})
+}
+
+// structDecl は型が構造体ならその宣言を返す（配列・構造体以外は nil）
+func (env *typeEnv) structDecl(t *ast.TypeExpr) *ast.TypeDecl {
+	t = env.resolve(t)
+	if t.Array {
+		return nil
+	}
+	decl, ok := env.scope.types[t.Name]
+	if !ok || decl.Kind != ast.TypeKindStruct {
+		return nil
+	}
+	return decl
+}
+
+func fieldByName(decl *ast.TypeDecl, name string) *ast.FieldDecl {
+	for i := range decl.Fields {
+		if decl.Fields[i].Name == name {
+			return &decl.Fields[i]
+		}
+	}
+	return nil
+}
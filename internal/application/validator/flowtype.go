@@ -0,0 +1,330 @@
+package validator
+
+import (
+	"fmt"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+)
+
+// nullType は null リテラルの型を表す
+var nullType = &ast.TypeExpr{Name: "null", Nullable: true}
+
+// primitiveKinds はプリミティブ型名 → 互換性を判定するための種類
+var primitiveKinds = map[string]string{
+	"string": "string", "String": "string", "uuid": "string", "UUID": "string",
+	"int": "int", "Int": "int", "integer": "int", "Integer": "int",
+	"float": "float", "Float": "float", "double": "float", "Double": "float",
+	"bool": "bool", "Bool": "bool", "boolean": "bool", "Boolean": "bool",
+}
+
+// ValidateFlowTypes はフローの呼び出しを依存先の provides のメソッドと照合し、
+// return の値をフローと同じ名前の provides のメソッドの戻り値型と照合する
+func (v *Validator) ValidateFlowTypes(spec *ast.SpecFile) error {
+	v.errors = &errors.MultiError{}
+
+	for i := range spec.Components {
+		comp := &spec.Components[i]
+		scopes := v.newFlowScopes(spec, comp)
+		for j := range comp.Body.Flows {
+			flow := &comp.Body.Flows[j]
+			scope := scopes[flow.Name]
+			env := newTypeEnv(scope, flow.Steps)
+			v.checkCalls(flow.Steps, env)
+			if scope.method != nil {
+				v.checkReturns(flow.Steps, scope.method, env)
+			}
+		}
+	}
+
+	return v.errors.ErrorOrNil()
+}
+
+// typeEnv はフロー内の変数の型を推論する
+type typeEnv struct {
+	scope *flowScope
+	vars  map[string]*ast.TypeExpr // 変数 → 型（代入ごとに型が食い違う変数は nil）
+}
+
+// binding は変数への代入（for のループ変数は反復対象の要素の代入として扱う）
+type binding struct {
+	name    string
+	value   ast.Expr
+	element bool
+}
+
+// newTypeEnv は代入から変数の型を推論する
+// 変数の型はフロー全体で1つとし、代入ごとに型が食い違う変数や推論できない代入がある変数は型を持たない
+func newTypeEnv(scope *flowScope, steps []ast.Step) *typeEnv {
+	env := &typeEnv{scope: scope, vars: make(map[string]*ast.TypeExpr)}
+	var bindings []binding
+	var collect func([]ast.Step)
+	collect = func(steps []ast.Step) {
+		for _, step := range steps {
+			switch s := step.(type) {
+			case *ast.AssignStep:
+				bindings = append(bindings, binding{name: s.Variable, value: s.Value})
+			case *ast.IfStep:
+				collect(s.Then)
+				collect(s.Else)
+			case *ast.ForStep:
+				bindings = append(bindings, binding{name: s.Variable, value: s.Iterable, element: true})
+				collect(s.Body)
+			case *ast.WhileStep:
+				collect(s.Body)
+			}
+		}
+	}
+	collect(steps)
+
+	// 変数から変数への代入を伝播させるため、変化がなくなるまで繰り返す
+	for changed, round := true, 0; changed && round <= len(bindings); round++ {
+		changed = false
+		next := make(map[string]*ast.TypeExpr)
+		conflict := make(map[string]bool)
+		for _, b := range bindings {
+			t := env.typeOf(b.value)
+			if b.element {
+				t = elementType(t)
+			}
+			prev, seen := next[b.name]
+			switch {
+			case conflict[b.name]:
+			case t == nil || t == nullType:
+				conflict[b.name] = true
+			case seen && !sameType(prev, t):
+				conflict[b.name] = true
+			default:
+				next[b.name] = t
+			}
+		}
+		for name := range conflict {
+			delete(next, name)
+		}
+		for name, t := range next {
+			if prev, ok := env.vars[name]; !ok || !sameType(prev, t) {
+				changed = true
+			}
+		}
+		if len(next) != len(env.vars) {
+			changed = true
+		}
+		env.vars = next
+	}
+	return env
+}
+
+// typeOf は式の型を推論する（推論できない場合は nil）
+func (env *typeEnv) typeOf(expr ast.Expr) *ast.TypeExpr {
+	switch e := expr.(type) {
+	case *ast.LiteralExpr:
+		switch e.Value.(type) {
+		case string:
+			return &ast.TypeExpr{Name: "string"}
+		case int, int64:
+			return &ast.TypeExpr{Name: "int"}
+		case float64:
+			return &ast.TypeExpr{Name: "float"}
+		case bool:
+			return &ast.TypeExpr{Name: "bool"}
+		case nil:
+			return nullType
+		}
+	case *ast.VariableExpr:
+		if t, ok := env.scope.params[e.Name]; ok {
+			return t
+		}
+		return env.vars[e.Name]
+	case *ast.UnaryExpr:
+		if e.Op == "!" {
+			return &ast.TypeExpr{Name: "bool"}
+		}
+		return env.typeOf(e.Operand)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case "==", "!=", "<", "<=", ">", ">=", "&&", "||":
+			return &ast.TypeExpr{Name: "bool"}
+		}
+		left, right := env.typeOf(e.Left), env.typeOf(e.Right)
+		if left == nil || right == nil {
+			return nil
+		}
+		lk, rk := env.primitiveKind(left), env.primitiveKind(right)
+		switch {
+		case lk == "string" && rk == "string" && e.Op == "+":
+			return &ast.TypeExpr{Name: "string"}
+		case lk == "int" && rk == "int":
+			return &ast.TypeExpr{Name: "int"}
+		case (lk == "int" || lk == "float") && (rk == "int" || rk == "float"):
+			return &ast.TypeExpr{Name: "float"}
+		}
+	case *ast.TernaryExpr:
+		then, els := env.typeOf(e.Then), env.typeOf(e.Else)
+		if then != nil && els != nil && sameType(then, els) {
+			return then
+		}
+	case *ast.NullishExpr:
+		left := env.typeOf(e.Left)
+		if left == nil || left == nullType {
+			return nil
+		}
+		if e.ThrowErr == nil {
+			if right := env.typeOf(e.Right); right == nil || !sameType(left, right) {
+				return nil
+			}
+		}
+		nonNull := *left
+		nonNull.Nullable = false
+		return &nonNull
+	case *ast.CallExpr:
+		method, dep, _, _ := env.scope.callTarget(e)
+		if method == nil || method.ReturnType == nil || method.ReturnType.Name == "void" {
+			return nil
+		}
+		return env.scope.qualify(method.ReturnType, dep.prefix)
+	case *ast.FieldExpr:
+		object := env.typeOf(e.Object)
+		if object == nil {
+			return nil
+		}
+		decl := env.structDecl(object)
+		if decl == nil {
+			return nil
+		}
+		if field := fieldByName(decl, e.Field); field != nil {
+			prefix := ""
+			if alias, _, ok := ast.SplitQualifiedName(env.resolve(object).Name); ok {
+				prefix = alias + "."
+			}
+			return env.scope.qualify(&field.Type, prefix)
+		}
+	}
+	return nil
+}
+
+// elementType は配列の要素の型を返す（配列でなければ nil）
+func elementType(t *ast.TypeExpr) *ast.TypeExpr {
+	if t == nil || !t.Array {
+		return nil
+	}
+	elem := *t
+	elem.Array = false
+	return &elem
+}
+
+// resolve は型エイリアスを元の型まで辿る
+func (env *typeEnv) resolve(t *ast.TypeExpr) *ast.TypeExpr {
+	for depth := 0; depth < 10; depth++ {
+		decl, ok := env.scope.types[t.Name]
+		if !ok || decl.Kind != ast.TypeKindAlias || decl.BaseType == nil || t.Array {
+			return t
+		}
+		base := *decl.BaseType
+		base.Nullable = base.Nullable || t.Nullable
+		t = &base
+	}
+	return t
+}
+
+// primitiveKind はプリミティブ型の種類を返す（プリミティブ型でなければ空文字列）
+func (env *typeEnv) primitiveKind(t *ast.TypeExpr) string {
+	t = env.resolve(t)
+	if t.Array {
+		return ""
+	}
+	return primitiveKinds[t.Name]
+}
+
+// assignable は actual 型の値を declared 型として扱えるかどうかを返す
+// 明らかに矛盾する組み合わせ（プリミティブ型どうしの不一致、プリミティブ型と構造体・列挙型、
+// 異なる構造体、null 非許容への null）だけを false にする
+func (env *typeEnv) assignable(actual, declared *ast.TypeExpr) bool {
+	if declared.Name == "any" || declared.Name == "object" || declared.Name == "Any" || declared.Name == "Object" {
+		return true
+	}
+	declared = env.resolve(declared)
+	if actual == nullType {
+		return declared.Nullable
+	}
+	actual = env.resolve(actual)
+	if actual.Array != declared.Array {
+		// 要素型が分かる場合だけ、配列と非配列の食い違いを矛盾とする
+		return !env.isKnownType(actual) || !env.isKnownType(declared)
+	}
+
+	ak, dk := primitiveKinds[actual.Name], primitiveKinds[declared.Name]
+	actualDecl, actualIsType := env.scope.types[actual.Name]
+	declaredDecl, declaredIsType := env.scope.types[declared.Name]
+	switch {
+	case ak != "" && dk != "":
+		return ak == dk || (ak == "int" && dk == "float")
+	case ak != "" && declaredIsType:
+		return false
+	case actualIsType && dk != "":
+		return false
+	case actualIsType && declaredIsType:
+		return actualDecl == declaredDecl
+	}
+	return true
+}
+
+func (env *typeEnv) isKnownType(t *ast.TypeExpr) bool {
+	_, declared := env.scope.types[t.Name]
+	return declared || primitiveKinds[t.Name] != ""
+}
+
+// sameType は2つの型が同じ型かどうかを返す
+func sameType(a, b *ast.TypeExpr) bool {
+	return a.Name == b.Name && a.Array == b.Array
+}
+
+// typeString は型を .pact の表記にする
+func typeString(t *ast.TypeExpr) string {
+	if t == nullType {
+		return "null"
+	}
+	s := t.Name
+	if t.Array {
+		s += "[]"
+	}
+	if t.Nullable {
+		s += "?"
+	}
+	return s
+}
+
+// checkReturns は return の値をメソッドの戻り値型と照合する
+func (v *Validator) checkReturns(steps []ast.Step, method *ast.MethodDecl, env *typeEnv) {
+	void := method.ReturnType == nil || method.ReturnType.Name == "void"
+	for _, step := range steps {
+		switch s := step.(type) {
+		case *ast.ReturnStep:
+			switch {
+			case void && s.Value != nil:
+				v.addTypeMismatch(s.Pos, method.Name, "returns a value but the method has no return type")
+			case !void && s.Value == nil:
+				v.addTypeMismatch(s.Pos, method.Name, fmt.Sprintf("returns no value but the method returns %s", typeString(method.ReturnType)))
+			case !void:
+				if t := env.typeOf(s.Value); t != nil && !env.assignable(t, method.ReturnType) {
+					v.addTypeMismatch(s.Pos, method.Name, fmt.Sprintf("returns %s but the method returns %s", typeString(t), typeString(method.ReturnType)))
+				}
+			}
+		case *ast.IfStep:
+			v.checkReturns(s.Then, method, env)
+			v.checkReturns(s.Else, method, env)
+		case *ast.ForStep:
+			v.checkReturns(s.Body, method, env)
+		case *ast.WhileStep:
+			v.checkReturns(s.Body, method, env)
+		}
+	}
+}
+
+func (v *Validator) addTypeMismatch(pos ast.Position, name, message string) {
+	v.errors.Add(&errors.ValidationError{
+		Pos:     pos,
+		Type:    errors.CodeTypeMismatch,
+		Name:    name,
+		Message: message,
+	})
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"pact/internal/domain/ast"
 	"pact/internal/domain/errors"
+	"pact/internal/domain/statechart"
 )
 
 // validDurationUnits は有効な期間単位
@@ -49,10 +50,12 @@ func (v *Validator) validateDuplicateStates(states *ast.StatesDecl) {
 
 // validateStateReferences はStates内の状態参照を検証する
 func (v *Validator) validateStateReferences(states *ast.StatesDecl) {
-	// 定義された状態名を収集
+	// 定義された状態名を収集（入れ子の状態・並行状態・リージョンも含める）
 	definedStates := make(map[string]bool)
-	for _, state := range states.States {
-		definedStates[state.Name] = true
+	for _, state := range statechart.New(states).States {
+		if state.Declared {
+			definedStates[state.Name] = true
+		}
 	}
 
 	// initial状態を検証
@@ -0,0 +1,342 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+	"pact/internal/domain/statechart"
+)
+
+// checkStateMachines は states ブロックの構造上の問題を警告する
+// 到達できない状態・デッドロック・終了状態からの遷移・非決定的な遷移・initial の欠落・完了できない並行状態
+func (v *Validator) checkStateMachines(spec *ast.SpecFile) {
+	for _, comp := range spec.Components {
+		for i := range comp.Body.States {
+			decl := &comp.Body.States[i]
+			chart := statechart.New(decl)
+
+			v.checkMissingInitial(chart)
+			reached := reachableStates(chart)
+			v.checkUnreachableStates(chart, reached)
+			v.checkDeadlocks(chart, reached)
+			v.checkFinalTransitions(chart)
+			v.checkNondeterminism(chart)
+			v.checkParallelCompletion(chart, reached)
+		}
+	}
+}
+
+func (v *Validator) warn(pos ast.Position, code, message string) {
+	v.warnings.Add(&errors.Warning{Pos: pos, Code: code, Message: message})
+}
+
+// checkMissingInitial は子を持つのに initial のない states ブロック・複合状態・リージョンを検出する
+func (v *Validator) checkMissingInitial(chart *statechart.Chart) {
+	for _, s := range append([]*statechart.State{chart.Root}, chart.States...) {
+		if len(s.Children) == 0 || s.Initial != nil || s.Kind == statechart.KindParallel {
+			continue
+		}
+		switch {
+		case s.IsRoot():
+			v.warn(s.Pos, errors.CodeMissingInitial, fmt.Sprintf("states '%s' has no initial state", s.Name))
+		case s.Kind == statechart.KindRegion:
+			v.warn(s.Pos, errors.CodeMissingInitial, fmt.Sprintf("region '%s' has no initial state (defaults to '%s')", s.Name, s.DefaultChild().Name))
+		default:
+			v.warn(s.Pos, errors.CodeMissingInitial, fmt.Sprintf("compound state '%s' has no initial state (defaults to '%s')", s.Name, s.DefaultChild().Name))
+		}
+	}
+}
+
+// reachableStates は initial から遷移を辿って入りうる状態を返す
+// 遷移の条件・ガードは常に成り立つものとして扱う
+func reachableStates(chart *statechart.Chart) map[*statechart.State]bool {
+	reached := make(map[*statechart.State]bool)
+	if chart.Root.Initial == nil {
+		return reached
+	}
+
+	var enterTree func(s *statechart.State)
+	enterTree = func(s *statechart.State) {
+		reached[s] = true
+		if s.Kind == statechart.KindParallel {
+			for _, region := range s.Children {
+				enterTree(region)
+			}
+		} else if child := s.DefaultChild(); child != nil {
+			enterTree(child)
+		}
+	}
+	// enter は遷移先とその祖先に入る（並行状態の祖先では他のリージョンにも入る）
+	enter := func(target *statechart.State) {
+		for a := target.Parent; a != nil && !a.IsRoot(); a = a.Parent {
+			reached[a] = true
+			if a.Kind != statechart.KindParallel {
+				continue
+			}
+			for _, region := range a.Children {
+				if region != target && !region.IsAncestorOf(target) {
+					enterTree(region)
+				}
+			}
+		}
+		enterTree(target)
+	}
+
+	enter(chart.Root.Initial)
+	taken := make(map[*statechart.Transition]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, t := range chart.Transitions {
+			if !taken[t] && reached[t.Source] {
+				taken[t] = true
+				changed = true
+				enter(t.Target)
+			}
+		}
+	}
+	return reached
+}
+
+// checkUnreachableStates は到達できない状態を報告する（到達できない状態の子孫は親の警告にまとめる）
+func (v *Validator) checkUnreachableStates(chart *statechart.Chart, reached map[*statechart.State]bool) {
+	if chart.Root.Initial == nil {
+		return
+	}
+	for _, s := range chart.States {
+		if reached[s] || !(s.Parent.IsRoot() || reached[s.Parent]) {
+			continue
+		}
+		v.warn(s.Pos, errors.CodeUnreachableState, fmt.Sprintf("state '%s' is unreachable from initial state '%s'", s.Name, chart.Root.Initial.Name))
+	}
+}
+
+// checkDeadlocks は抜ける遷移が自身にも祖先にもない、終了状態以外の末端の状態を報告する
+func (v *Validator) checkDeadlocks(chart *statechart.Chart, reached map[*statechart.State]bool) {
+	for _, s := range chart.States {
+		if !s.IsAtomic() || !reached[s] || s.Kind != statechart.KindAtomic {
+			continue
+		}
+		if hasExit(s, nil) {
+			continue
+		}
+		v.warn(s.Pos, errors.CodeDeadlockState, fmt.Sprintf("state '%s' is not final but has no outgoing transitions", s.Name))
+	}
+}
+
+// hasExit は s または until より下の祖先から出る遷移があるかどうかを返す
+func hasExit(s, until *statechart.State) bool {
+	for a := s; a != nil && a != until && !a.IsRoot(); a = a.Parent {
+		if len(a.Transitions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFinalTransitions は終了状態から出る遷移を報告する
+func (v *Validator) checkFinalTransitions(chart *statechart.Chart) {
+	for _, s := range chart.States {
+		if s.Kind != statechart.KindFinal {
+			continue
+		}
+		for _, t := range s.Transitions {
+			v.warn(t.Pos, errors.CodeFinalTransition, fmt.Sprintf("final state '%s' has an outgoing transition to '%s'", s.Name, t.Target.Name))
+		}
+	}
+}
+
+// checkNondeterminism は同じ遷移元・同じトリガーで、ガードがないか重なりうる遷移の組を報告する
+func (v *Validator) checkNondeterminism(chart *statechart.Chart) {
+	for _, s := range chart.States {
+		for i, a := range s.Transitions {
+			for _, b := range s.Transitions[:i] {
+				if triggerKey(a) == "" || triggerKey(a) != triggerKey(b) || !guardsOverlap(a.Guard, b.Guard) {
+					continue
+				}
+				reason := "overlapping guards"
+				if a.Guard == nil || b.Guard == nil {
+					reason = "no guard"
+				}
+				v.warn(a.Pos, errors.CodeNondeterministic, fmt.Sprintf(
+					"transitions from '%s' %s are nondeterministic (%s; conflicts with the transition to '%s' at %s)",
+					s.Name, triggerKey(a), reason, b.Target.Name, b.Pos.String()))
+				break
+			}
+		}
+	}
+}
+
+// triggerKey は遷移のトリガーを "on E" / "after 5m0s" の形にする（条件で待つ遷移は ""）
+func triggerKey(t *statechart.Transition) string {
+	switch {
+	case t.Event != "":
+		return "on '" + t.Event + "'"
+	case t.After > 0:
+		return "after " + t.After.String()
+	}
+	return ""
+}
+
+// guardsOverlap は2つのガードが同時に成り立ちうるかどうかを返す
+// ガードがない・同じ式・同じ名前の数値比較で範囲が重なる場合に true（それ以外は判定しない）
+func guardsOverlap(a, b ast.Expr) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	if exprKey(a) == exprKey(b) {
+		return true
+	}
+	ra, okA := comparisonRange(a)
+	rb, okB := comparisonRange(b)
+	return okA && okB && ra.name == rb.name && ra.overlaps(rb)
+}
+
+// valueRange は "name op 数値" の比較が成り立つ範囲
+type valueRange struct {
+	name           string
+	lo, hi         float64
+	hasLo, hasHi   bool
+	openLo, openHi bool // 端点を含まない
+}
+
+func (r valueRange) overlaps(o valueRange) bool {
+	lo, hasLo, openLo := r.lo, r.hasLo, r.openLo
+	if o.hasLo && (!hasLo || o.lo > lo || (o.lo == lo && o.openLo)) {
+		lo, hasLo, openLo = o.lo, true, o.openLo
+	}
+	hi, hasHi, openHi := r.hi, r.hasHi, r.openHi
+	if o.hasHi && (!hasHi || o.hi < hi || (o.hi == hi && o.openHi)) {
+		hi, hasHi, openHi = o.hi, true, o.openHi
+	}
+	if !hasLo || !hasHi {
+		return true
+	}
+	return lo < hi || (lo == hi && !openLo && !openHi)
+}
+
+// comparisonRange は "name op 数値" または "数値 op name" の比較を範囲にする
+func comparisonRange(expr ast.Expr) (valueRange, bool) {
+	bin, ok := expr.(*ast.BinaryExpr)
+	if !ok {
+		return valueRange{}, false
+	}
+	op := bin.Op
+	name, value := exprKey(bin.Left), numberValue(bin.Right)
+	if value == nil {
+		// 数値が左辺にある場合は向きを入れ替える
+		name, value = exprKey(bin.Right), numberValue(bin.Left)
+		op = map[string]string{"<": ">", ">": "<", "<=": ">=", ">=": "<=", "==": "=="}[op]
+	}
+	if value == nil || numberValue(bin.Left) != nil && numberValue(bin.Right) != nil {
+		return valueRange{}, false
+	}
+
+	r := valueRange{name: name}
+	switch op {
+	case "<", "<=":
+		r.hi, r.hasHi, r.openHi = *value, true, op == "<"
+	case ">", ">=":
+		r.lo, r.hasLo, r.openLo = *value, true, op == ">"
+	case "==":
+		r.lo, r.hasLo, r.hi, r.hasHi = *value, true, *value, true
+	default:
+		return valueRange{}, false
+	}
+	return r, true
+}
+
+func numberValue(expr ast.Expr) *float64 {
+	lit, ok := expr.(*ast.LiteralExpr)
+	if !ok {
+		return nil
+	}
+	var f float64
+	switch n := lit.Value.(type) {
+	case int64:
+		f = float64(n)
+	case int:
+		f = float64(n)
+	case float64:
+		f = n
+	default:
+		return nil
+	}
+	return &f
+}
+
+// exprKey は式を位置を除いて比較できる文字列にする
+func exprKey(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case nil:
+		return ""
+	case *ast.LiteralExpr:
+		return fmt.Sprintf("%#v", e.Value)
+	case *ast.VariableExpr:
+		return e.Name
+	case *ast.FieldExpr:
+		return exprKey(e.Object) + "." + e.Field
+	case *ast.CallExpr:
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = exprKey(arg)
+		}
+		call := e.Method + "(" + strings.Join(args, ", ") + ")"
+		if e.Object != nil {
+			call = exprKey(e.Object) + "." + call
+		}
+		return call
+	case *ast.BinaryExpr:
+		return "(" + exprKey(e.Left) + " " + e.Op + " " + exprKey(e.Right) + ")"
+	case *ast.UnaryExpr:
+		return e.Op + exprKey(e.Operand)
+	case *ast.TernaryExpr:
+		return "(" + exprKey(e.Condition) + " ? " + exprKey(e.Then) + " : " + exprKey(e.Else) + ")"
+	case *ast.NullishExpr:
+		return "(" + exprKey(e.Left) + " ?? " + exprKey(e.Right) + ")"
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+// checkParallelCompletion は完了に頼る並行状態で、末端の状態に到達できないリージョンを報告する
+// リージョンは外に出る遷移のない状態に入ったときに完了し、イベントや時間で抜ける遷移のない並行状態は
+// すべてのリージョンが完了しないと抜けられない
+func (v *Validator) checkParallelCompletion(chart *statechart.Chart, reached map[*statechart.State]bool) {
+	for _, p := range chart.States {
+		if p.Kind != statechart.KindParallel || !reached[p] || exitsOnTrigger(p) {
+			continue
+		}
+		for _, region := range p.Children {
+			if regionCanComplete(chart, region, reached) {
+				continue
+			}
+			v.warn(region.Pos, errors.CodeParallelIncompletion, fmt.Sprintf(
+				"parallel state '%s' can never complete: region '%s' never reaches a state without outgoing transitions", p.Name, region.Name))
+		}
+	}
+}
+
+// exitsOnTrigger は状態またはその祖先からイベント・時間で出る遷移があるかどうかを返す
+func exitsOnTrigger(s *statechart.State) bool {
+	for a := s; a != nil && !a.IsRoot(); a = a.Parent {
+		for _, t := range a.Transitions {
+			if !t.Eventless() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func regionCanComplete(chart *statechart.Chart, region *statechart.State, reached map[*statechart.State]bool) bool {
+	if len(region.Children) == 0 {
+		return true
+	}
+	for _, s := range chart.States {
+		if region.IsAncestorOf(s) && s.IsAtomic() && reached[s] && !hasExit(s, region) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package validator
+
+import (
+	"fmt"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+)
+
+// raise はフローが例外を送出しうる箇所
+type raise struct {
+	err string
+	pos ast.Position
+	via string // 呼び出し先から伝播する場合は "Target.Method"
+}
+
+// throwAnalysis はフローが送出しうる例外を、呼び出し先のメソッドのフローまで辿って求める
+type throwAnalysis struct {
+	v        *Validator
+	spec     *ast.SpecFile // 検証する仕様
+	memo     map[*ast.MethodDecl][]string
+	visiting map[*ast.MethodDecl]bool
+	scopes   map[*ast.ComponentDecl]map[string]*flowScope // 呼び出し先のコンポーネントのフローのスコープ
+}
+
+func newThrowAnalysis(v *Validator, spec *ast.SpecFile) *throwAnalysis {
+	return &throwAnalysis{
+		v:        v,
+		spec:     spec,
+		memo:     make(map[*ast.MethodDecl][]string),
+		visiting: make(map[*ast.MethodDecl]bool),
+		scopes:   make(map[*ast.ComponentDecl]map[string]*flowScope),
+	}
+}
+
+// ValidateThrows は provides のメソッドを実装するフローが、宣言していない例外を送出しうる箇所を検出する
+func (v *Validator) ValidateThrows(spec *ast.SpecFile) error {
+	v.errors = &errors.MultiError{}
+
+	a := newThrowAnalysis(v, spec)
+	a.eachImplementation(func(scope *flowScope, flow *ast.FlowDecl, raises []raise) {
+		declared := make(nameSet)
+		for _, name := range scope.method.Throws {
+			declared[name] = true
+		}
+		for _, r := range raises {
+			if declared[r.err] {
+				continue
+			}
+			message := fmt.Sprintf("flow throws '%s' but the method does not declare it", r.err)
+			if r.via != "" {
+				message = fmt.Sprintf("'%s' propagates from '%s' but the method does not declare it", r.err, r.via)
+			}
+			v.errors.Add(&errors.ValidationError{
+				Pos:     r.pos,
+				Type:    errors.CodeUndeclaredThrow,
+				Name:    flow.Name,
+				Message: message,
+			})
+		}
+	})
+
+	return v.errors.ErrorOrNil()
+}
+
+// checkUnraisedThrows は provides のメソッドが宣言しているのに、フローのどの経路でも送出されない例外を検出する
+func (v *Validator) checkUnraisedThrows(spec *ast.SpecFile) {
+	a := newThrowAnalysis(v, spec)
+	a.eachImplementation(func(scope *flowScope, flow *ast.FlowDecl, raises []raise) {
+		raised := make(nameSet)
+		for _, r := range raises {
+			raised[r.err] = true
+		}
+		for _, name := range scope.method.Throws {
+			if !raised[name] {
+				v.warn(scope.method.Pos, errors.CodeUnraisedThrow, fmt.Sprintf("method '%s' declares '%s' but flow '%s' never throws it", scope.method.Name, name, flow.Name))
+			}
+		}
+	})
+}
+
+// eachImplementation は provides のメソッドを実装するフローごとに、送出しうる例外を渡して fn を呼ぶ
+func (a *throwAnalysis) eachImplementation(fn func(*flowScope, *ast.FlowDecl, []raise)) {
+	for i := range a.spec.Components {
+		comp := &a.spec.Components[i]
+		scopes := a.v.newFlowScopes(a.spec, comp)
+		for j := range comp.Body.Flows {
+			flow := &comp.Body.Flows[j]
+			scope := scopes[flow.Name]
+			if scope.method == nil {
+				continue
+			}
+			a.visiting[scope.method] = true
+			raises := a.flowRaises(scope, flow.Steps)
+			delete(a.visiting, scope.method)
+			fn(scope, flow, raises)
+		}
+	}
+}
+
+// flowRaises はフローが送出しうる例外を、例外ごとに最初の箇所で返す
+func (a *throwAnalysis) flowRaises(scope *flowScope, steps []ast.Step) []raise {
+	var raises []raise
+	seen := make(nameSet)
+	add := func(r raise) {
+		if !seen[r.err] {
+			seen[r.err] = true
+			raises = append(raises, r)
+		}
+	}
+
+	var walkExpr func(ast.Expr)
+	walkExpr = func(expr ast.Expr) {
+		switch e := expr.(type) {
+		case *ast.CallExpr:
+			if e.Object != nil {
+				walkExpr(e.Object)
+			}
+			for _, arg := range e.Args {
+				walkExpr(arg)
+			}
+			method, dep, target, _ := scope.callTarget(e)
+			if method == nil {
+				return
+			}
+			for _, err := range a.methodRaises(dep, method) {
+				add(raise{err: err, pos: e.Pos, via: target + "." + method.Name})
+			}
+		case *ast.NullishExpr:
+			walkExpr(e.Left)
+			walkExpr(e.Right)
+			if e.ThrowErr != nil {
+				add(raise{err: *e.ThrowErr, pos: e.Pos})
+			}
+		case *ast.FieldExpr:
+			walkExpr(e.Object)
+		case *ast.BinaryExpr:
+			walkExpr(e.Left)
+			walkExpr(e.Right)
+		case *ast.UnaryExpr:
+			walkExpr(e.Operand)
+		case *ast.TernaryExpr:
+			walkExpr(e.Condition)
+			walkExpr(e.Then)
+			walkExpr(e.Else)
+		}
+	}
+
+	var walk func([]ast.Step)
+	walk = func(steps []ast.Step) {
+		for _, step := range steps {
+			switch s := step.(type) {
+			case *ast.AssignStep:
+				walkExpr(s.Value)
+			case *ast.CallStep:
+				walkExpr(s.Expr)
+			case *ast.ReturnStep:
+				walkExpr(s.Value)
+			case *ast.ThrowStep:
+				add(raise{err: s.Error, pos: s.Pos})
+			case *ast.IfStep:
+				walkExpr(s.Condition)
+				walk(s.Then)
+				walk(s.Else)
+			case *ast.ForStep:
+				walkExpr(s.Iterable)
+				walk(s.Body)
+			case *ast.WhileStep:
+				walkExpr(s.Condition)
+				walk(s.Body)
+			}
+		}
+	}
+	walk(steps)
+	return raises
+}
+
+// methodRaises は呼び出し先のメソッドが送出しうる例外を返す
+// 宣言された例外に加え、メソッドを実装するフローがあればそのフローが実際に送出しうる例外も含める
+func (a *throwAnalysis) methodRaises(dep *dependency, method *ast.MethodDecl) []string {
+	if errs, ok := a.memo[method]; ok {
+		return errs
+	}
+
+	errs := append([]string(nil), method.Throws...)
+	if a.visiting[method] {
+		// 再帰呼び出しでは宣言だけを使う
+		return errs
+	}
+	scopes, ok := a.calleeScopes(dep)
+	if !ok {
+		// 呼び出し先の仕様の import を解決できなければ宣言だけを使う
+		a.memo[method] = errs
+		return errs
+	}
+	seen := make(nameSet)
+	for _, err := range errs {
+		seen[err] = true
+	}
+	for i := range dep.comp.Body.Flows {
+		flow := &dep.comp.Body.Flows[i]
+		if flow.Name != method.Name {
+			continue
+		}
+		scope := scopes[flow.Name]
+		a.visiting[method] = true
+		for _, r := range a.flowRaises(scope, flow.Steps) {
+			if !seen[r.err] {
+				seen[r.err] = true
+				errs = append(errs, r.err)
+			}
+		}
+		delete(a.visiting, method)
+	}
+
+	a.memo[method] = errs
+	return errs
+}
+
+// calleeScopes は呼び出し先のコンポーネントのフローのスコープを返す
+// import 先の仕様のスコープは、その仕様自身の import で型と依存先を解決する
+// その仕様の import が登録されていなければ false を返す
+func (a *throwAnalysis) calleeScopes(dep *dependency) (map[string]*flowScope, bool) {
+	if scopes, ok := a.scopes[dep.comp]; ok {
+		return scopes, scopes != nil
+	}
+	v := a.v
+	if dep.spec != a.spec {
+		imports, ok := a.v.specImports[dep.spec]
+		if !ok && len(dep.spec.Imports) > 0 {
+			a.scopes[dep.comp] = nil
+			return nil, false
+		}
+		v = &Validator{imports: imports, specImports: a.v.specImports}
+	}
+	scopes := v.newFlowScopes(dep.spec, dep.comp)
+	a.scopes[dep.comp] = scopes
+	return scopes, true
+}
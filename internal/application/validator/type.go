@@ -139,12 +139,7 @@ func (v *Validator) validateFieldTypeReferences(comp *ast.ComponentDecl, defined
 		for _, field := range typ.Fields {
 			typeName := field.Type.Name
 			if !definedTypes[typeName] && !builtinTypes[typeName] {
-				v.errors.Add(&errors.ValidationError{
-					Pos:     field.Pos,
-					Type:    "undefined",
-					Name:    typeName,
-					Message: "type is not defined",
-				})
+				v.addUndefined(field.Pos, typeName, "type is not defined")
 			}
 		}
 	}
@@ -167,24 +162,14 @@ func (v *Validator) validateMethodTypes(iface *ast.InterfaceDecl, definedTypes m
 		for _, param := range method.Params {
 			typeName := param.Type.Name
 			if !definedTypes[typeName] && !builtinTypes[typeName] {
-				v.errors.Add(&errors.ValidationError{
-					Pos:     param.Pos,
-					Type:    "undefined",
-					Name:    typeName,
-					Message: "parameter type is not defined",
-				})
+				v.addUndefined(param.Pos, typeName, "parameter type is not defined")
 			}
 		}
 		// 戻り値型
 		if method.ReturnType != nil {
 			typeName := method.ReturnType.Name
 			if !definedTypes[typeName] && !builtinTypes[typeName] {
-				v.errors.Add(&errors.ValidationError{
-					Pos:     method.Pos,
-					Type:    "undefined",
-					Name:    typeName,
-					Message: "return type is not defined",
-				})
+				v.addUndefined(method.Pos, typeName, "return type is not defined")
 			}
 		}
 	}
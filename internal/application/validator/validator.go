@@ -9,6 +9,10 @@ import (
 type Validator struct {
 	errors   *errors.MultiError
 	warnings *errors.WarningList
+	imports  map[string]*ast.SpecFile // import 文のパス → import 先の仕様
+	aliases  map[string]bool          // import のエイリアス → import 先が解決済みか
+	// import 先の仕様 → その仕様の import 文のパス → import 先の仕様（呼び出し先のフローを辿るときに使う）
+	specImports map[*ast.SpecFile]map[string]*ast.SpecFile
 }
 
 // NewValidator は新しいValidatorを作成する
@@ -24,6 +28,19 @@ func (v *Validator) GetWarnings() *errors.WarningList {
 	return v.warnings
 }
 
+// SetImports は import 先の仕様を登録する
+// キーは import 文に書かれたパスで、登録した仕様の宣言は参照解決の対象になる
+func (v *Validator) SetImports(imports map[string]*ast.SpecFile) {
+	v.imports = imports
+}
+
+// SetSpecImports は import 先の仕様それぞれの import 先を登録する
+// 呼び出し先のフローが送出しうる例外を辿るとき、呼び出し先の仕様の import とエイリアスの解決に使う
+// 登録されていない仕様のフローは辿らず、メソッドが宣言した例外だけを使う
+func (v *Validator) SetSpecImports(imports map[*ast.SpecFile]map[string]*ast.SpecFile) {
+	v.specImports = imports
+}
+
 // Validate はSpecFileを検証する
 func (v *Validator) Validate(spec *ast.SpecFile) error {
 	v.errors = &errors.MultiError{}
@@ -119,6 +136,26 @@ func (v *Validator) ValidateAll(spec *ast.SpecFile) error {
 		}
 	}
 
+	if err := v.ValidateFlowTypes(spec); err != nil {
+		if me, ok := err.(*errors.MultiError); ok {
+			for _, e := range me.Errors {
+				multiErr.Add(e)
+			}
+		} else {
+			multiErr.Add(err)
+		}
+	}
+
+	if err := v.ValidateThrows(spec); err != nil {
+		if me, ok := err.(*errors.MultiError); ok {
+			for _, e := range me.Errors {
+				multiErr.Add(e)
+			}
+		} else {
+			multiErr.Add(err)
+		}
+	}
+
 	// 警告を収集
 	v.CollectWarnings(spec)
 
@@ -133,12 +170,8 @@ func (v *Validator) ValidateReferences(spec *ast.SpecFile) error {
 	definedTypes := make(map[string]bool)
 	definedComponents := make(map[string]bool)
 
-	for _, comp := range spec.Components {
-		definedComponents[comp.Name] = true
-		for _, typ := range comp.Body.Types {
-			definedTypes[typ.Name] = true
-		}
-	}
+	collectDeclarations(spec, definedComponents, definedTypes)
+	v.collectImportedDeclarations(spec, definedComponents, definedTypes)
 
 	// 参照を検証
 	for _, comp := range spec.Components {
@@ -179,7 +212,7 @@ func (v *Validator) ValidateEmptyDeclarations(spec *ast.SpecFile) error {
 	return v.errors.ErrorOrNil()
 }
 
-// CollectWarnings は警告を収集する（L-005, L-007）
+// CollectWarnings は警告を収集する（L-005, L-007, 状態機械・フローの変数・例外の検査）
 func (v *Validator) CollectWarnings(spec *ast.SpecFile) {
 	v.warnings = &errors.WarningList{}
 
@@ -191,6 +224,15 @@ func (v *Validator) CollectWarnings(spec *ast.SpecFile) {
 
 	// @deprecated アノテーションの使用チェック
 	v.checkDeprecatedUsage(spec)
+
+	// 状態機械の到達性・デッドロック・非決定性
+	v.checkStateMachines(spec)
+
+	// フローの変数の未代入・未使用・隠蔽
+	v.checkFlowVariables(spec)
+
+	// 宣言されているのに送出されない例外
+	v.checkUnraisedThrows(spec)
 }
 
 // hasAnnotation はアノテーションリストに指定のアノテーションがあるかを返す
@@ -202,3 +244,68 @@ func hasAnnotation(annotations []ast.AnnotationDecl, name string) bool {
 	}
 	return false
 }
+
+// collectDeclarations は仕様で宣言されたコンポーネント名と型名を収集する
+func collectDeclarations(spec *ast.SpecFile, components, types map[string]bool) {
+	for _, comp := range spec.Components {
+		components[comp.Name] = true
+		for _, typ := range comp.Body.Types {
+			types[typ.Name] = true
+		}
+	}
+	for _, typ := range spec.Types {
+		types[typ.Name] = true
+	}
+}
+
+// collectImportedDeclarations は import 先で宣言されたコンポーネント名と型名を収集する
+// エイリアス付きの import の宣言は "alias.Name" の修飾名でのみ参照できる
+func (v *Validator) collectImportedDeclarations(spec *ast.SpecFile, components, types map[string]bool) {
+	v.aliases = make(map[string]bool)
+	for _, imp := range spec.Imports {
+		imported, resolved := v.imports[imp.Path]
+		if imp.Alias != nil {
+			v.aliases[*imp.Alias] = resolved
+		}
+		if !resolved {
+			continue
+		}
+		if imp.Alias == nil {
+			collectDeclarations(imported, components, types)
+			continue
+		}
+
+		importedComponents := make(map[string]bool)
+		importedTypes := make(map[string]bool)
+		collectDeclarations(imported, importedComponents, importedTypes)
+		for name := range importedComponents {
+			components[*imp.Alias+"."+name] = true
+		}
+		for name := range importedTypes {
+			types[*imp.Alias+"."+name] = true
+		}
+	}
+}
+
+// addUndefined は未定義参照のエラーを追加する
+// 修飾名はエイリアスと名前のどちらが解決できないかを区別して報告する
+func (v *Validator) addUndefined(pos ast.Position, name, message string) {
+	if alias, member, ok := ast.SplitQualifiedName(name); ok {
+		resolved, known := v.aliases[alias]
+		switch {
+		case !known:
+			message = "unknown import alias '" + alias + "'"
+		case !resolved:
+			// import 先が読めない場合は import のエラーとして報告済み
+			return
+		default:
+			message = "'" + member + "' is not declared in import '" + alias + "'"
+		}
+	}
+	v.errors.Add(&errors.ValidationError{
+		Pos:     pos,
+		Type:    "undefined",
+		Name:    name,
+		Message: message,
+	})
+}
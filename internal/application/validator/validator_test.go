@@ -0,0 +1,894 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+)
+
+// =============================================================================
+// VA001-VA003: import を考慮した検証
+// =============================================================================
+
+func importingSpec(path string) *ast.SpecFile {
+	return &ast.SpecFile{
+		Imports: []ast.ImportDecl{{Pos: ast.Position{Line: 1, Column: 1}, Path: path}},
+		Components: []ast.ComponentDecl{
+			{
+				Name: "OrderService",
+				Body: ast.ComponentBody{
+					Relations: []ast.RelationDecl{
+						{Pos: ast.Position{Line: 3, Column: 3}, Kind: ast.RelationDependsOn, Target: "PaymentService"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func paymentSpec() *ast.SpecFile {
+	return &ast.SpecFile{
+		Components: []ast.ComponentDecl{{Name: "PaymentService"}},
+	}
+}
+
+// VA001: import 先のコンポーネントへの参照は未定義にならない
+func TestValidator_ValidateReferences_Imported(t *testing.T) {
+	spec := importingSpec("./payment.pact")
+
+	v := NewValidator()
+	if err := v.ValidateReferences(spec); err == nil {
+		t.Fatal("expected undefined reference without imports")
+	}
+
+	v.SetImports(map[string]*ast.SpecFile{"./payment.pact": paymentSpec()})
+	if err := v.ValidateReferences(spec); err != nil {
+		t.Errorf("unexpected error with imports: %v", err)
+	}
+}
+
+// VA002: 参照されている import は未使用警告にならない
+func TestValidator_CollectWarnings_UsedImport(t *testing.T) {
+	spec := importingSpec("./payment.pact")
+
+	v := NewValidator()
+	v.SetImports(map[string]*ast.SpecFile{"./payment.pact": paymentSpec()})
+	v.CollectWarnings(spec)
+
+	for _, w := range v.GetWarnings().Warnings {
+		if w.Code == "unused-import" {
+			t.Errorf("unexpected warning: %v", w)
+		}
+	}
+}
+
+// VA003: 参照されていない import は警告になる
+func TestValidator_CollectWarnings_UnusedImport(t *testing.T) {
+	spec := importingSpec("./other.pact")
+	spec.Components[0].Body.Relations = nil
+
+	v := NewValidator()
+	v.SetImports(map[string]*ast.SpecFile{"./other.pact": paymentSpec()})
+	v.CollectWarnings(spec)
+
+	found := false
+	for _, w := range v.GetWarnings().Warnings {
+		if w.Code == "unused-import" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected unused-import warning")
+	}
+}
+
+// VA004: ValidateAll は検証エラーをまとめて返す
+func TestValidator_ValidateAll_Collects(t *testing.T) {
+	spec := &ast.SpecFile{
+		Components: []ast.ComponentDecl{
+			{
+				Name: "Svc",
+				Body: ast.ComponentBody{
+					Types: []ast.TypeDecl{
+						{Name: "User", Kind: ast.TypeKindStruct},
+						{Name: "User", Kind: ast.TypeKindStruct},
+					},
+					Relations: []ast.RelationDecl{{Kind: ast.RelationDependsOn, Target: "Missing"}},
+				},
+			},
+		},
+	}
+
+	err := NewValidator().ValidateAll(spec)
+	me, ok := err.(*errors.MultiError)
+	if !ok {
+		t.Fatalf("expected MultiError, got %T", err)
+	}
+
+	types := make(map[string]bool)
+	for _, e := range me.Errors {
+		if ve, ok := e.(*errors.ValidationError); ok {
+			types[ve.Type] = true
+		}
+	}
+	if !types["duplicate"] || !types["undefined"] {
+		t.Errorf("expected duplicate and undefined errors, got %v", me.Errors)
+	}
+}
+
+// =============================================================================
+// VA005-VA007: エイリアス付き import の修飾名
+// =============================================================================
+
+func aliasedSpec(target string) *ast.SpecFile {
+	spec := importingSpec("./billing.pact")
+	alias := "billing"
+	spec.Imports[0].Alias = &alias
+	spec.Components[0].Body.Relations[0].Target = target
+	return spec
+}
+
+func undefinedMessages(err error) []string {
+	var messages []string
+	if me, ok := err.(*errors.MultiError); ok {
+		for _, e := range me.Errors {
+			if ve, ok := e.(*errors.ValidationError); ok && ve.Type == "undefined" {
+				messages = append(messages, ve.Message)
+			}
+		}
+	}
+	return messages
+}
+
+// VA005: 修飾名はエイリアス経由で import 先の宣言に解決される
+func TestValidator_ValidateReferences_Qualified(t *testing.T) {
+	v := NewValidator()
+	v.SetImports(map[string]*ast.SpecFile{"./billing.pact": paymentSpec()})
+
+	if err := v.ValidateReferences(aliasedSpec("billing.PaymentService")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// エイリアス付きの import は修飾なしでは参照できない
+	if err := v.ValidateReferences(aliasedSpec("PaymentService")); err == nil {
+		t.Error("expected undefined error for unqualified reference")
+	}
+}
+
+// VA006: 未知のエイリアスと未宣言の名前を区別して報告する
+func TestValidator_ValidateReferences_QualifiedErrors(t *testing.T) {
+	v := NewValidator()
+	v.SetImports(map[string]*ast.SpecFile{"./billing.pact": paymentSpec()})
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"shipping.PaymentService", "unknown import alias 'shipping'"},
+		{"billing.Refund", "'Refund' is not declared in import 'billing'"},
+	}
+	for _, tt := range tests {
+		messages := undefinedMessages(v.ValidateReferences(aliasedSpec(tt.target)))
+		if len(messages) != 1 || messages[0] != tt.want {
+			t.Errorf("%s: expected [%s], got %v", tt.target, tt.want, messages)
+		}
+	}
+}
+
+// VA007: エイリアス付き import は修飾名で参照されていれば使用済み
+func TestValidator_CollectWarnings_QualifiedImport(t *testing.T) {
+	v := NewValidator()
+	v.SetImports(map[string]*ast.SpecFile{"./billing.pact": paymentSpec()})
+	v.CollectWarnings(aliasedSpec("billing.PaymentService"))
+
+	for _, w := range v.GetWarnings().Warnings {
+		if w.Code == "unused-import" {
+			t.Errorf("unexpected warning: %v", w)
+		}
+	}
+}
+
+// =============================================================================
+// VS001-VS006: 状態機械の検査
+// =============================================================================
+
+func at(line int) ast.Position {
+	return ast.Position{Line: line, Column: 5}
+}
+
+func transition(line int, from, to, event string, guard ast.Expr) ast.TransitionDecl {
+	return ast.TransitionDecl{Pos: at(line), From: from, To: to, Trigger: &ast.EventTrigger{Event: event}, Guard: guard}
+}
+
+// stateWarnings は states ブロックを検査し、"code line" の一覧を返す
+func stateWarnings(states ast.StatesDecl) []string {
+	v := NewValidator()
+	v.CollectWarnings(&ast.SpecFile{Components: []ast.ComponentDecl{{
+		Name: "Order",
+		Body: ast.ComponentBody{States: []ast.StatesDecl{states}},
+	}}})
+	var found []string
+	for _, w := range v.GetWarnings().Warnings {
+		found = append(found, fmt.Sprintf("%s %d", w.Code, w.Pos.Line))
+	}
+	return found
+}
+
+func assertWarnings(t *testing.T, found []string, want ...string) {
+	t.Helper()
+	if strings.Join(found, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected warnings:\ngot:\n  %s\nwant:\n  %s", strings.Join(found, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+// VS001: 到達できない状態とデッドロック
+func TestValidator_States_UnreachableAndDeadlock(t *testing.T) {
+	found := stateWarnings(ast.StatesDecl{
+		Pos:     at(1),
+		Name:    "OrderState",
+		Initial: "Pending",
+		Finals:  []string{"Done"},
+		States: []ast.StateDecl{
+			{Pos: at(2), Name: "Pending"},
+			{Pos: at(3), Name: "Stuck"},
+			{Pos: at(4), Name: "Orphan"},
+		},
+		Transitions: []ast.TransitionDecl{
+			transition(5, "Pending", "Done", "ship", nil),
+			transition(6, "Pending", "Stuck", "hold", nil),
+			transition(7, "Orphan", "Done", "ship", nil),
+		},
+	})
+	assertWarnings(t, found,
+		errors.CodeUnreachableState+" 4",
+		errors.CodeDeadlockState+" 3",
+	)
+}
+
+// VS002: 終了状態から出る遷移と、initial のない複合状態
+func TestValidator_States_FinalAndInitial(t *testing.T) {
+	found := stateWarnings(ast.StatesDecl{
+		Pos:     at(1),
+		Name:    "Job",
+		Initial: "Active",
+		Finals:  []string{"Done"},
+		States: []ast.StateDecl{{
+			Pos:         at(2),
+			Name:        "Active",
+			States:      []ast.StateDecl{{Pos: at(3), Name: "Running"}, {Pos: at(4), Name: "Paused"}},
+			Transitions: []ast.TransitionDecl{transition(5, "Running", "Paused", "pause", nil), transition(6, "Paused", "Running", "resume", nil)},
+		}},
+		Transitions: []ast.TransitionDecl{
+			transition(7, "Active", "Done", "finish", nil),
+			transition(8, "Done", "Active", "restart", nil),
+		},
+	})
+	assertWarnings(t, found,
+		errors.CodeMissingInitial+" 2",
+		errors.CodeFinalTransition+" 8",
+	)
+}
+
+// VS003: 同じ遷移元・イベントでガードがない、または範囲が重なる遷移
+func TestValidator_States_Nondeterministic(t *testing.T) {
+	retries := func(op string, n int64) ast.Expr {
+		return &ast.BinaryExpr{Left: &ast.VariableExpr{Name: "retries"}, Op: op, Right: &ast.LiteralExpr{Value: n}}
+	}
+	paid := &ast.FieldExpr{Object: &ast.VariableExpr{Name: "order"}, Field: "paid"}
+
+	found := stateWarnings(ast.StatesDecl{
+		Pos:     at(1),
+		Name:    "Payment",
+		Initial: "Waiting",
+		Finals:  []string{"Paid", "Failed", "Retry"},
+		Transitions: []ast.TransitionDecl{
+			transition(2, "Waiting", "Paid", "pay", paid),
+			transition(3, "Waiting", "Failed", "pay", &ast.UnaryExpr{Op: "!", Operand: paid}),
+			transition(4, "Waiting", "Retry", "fail", retries("<", 3)),
+			transition(5, "Waiting", "Failed", "fail", retries(">=", 3)),
+			transition(6, "Waiting", "Failed", "fail", retries(">", 1)),
+			transition(7, "Waiting", "Paid", "cancel", nil),
+			transition(8, "Waiting", "Failed", "cancel", nil),
+		},
+	})
+	assertWarnings(t, found,
+		errors.CodeNondeterministic+" 6",
+		errors.CodeNondeterministic+" 8",
+	)
+}
+
+// VS004: 並行状態はイベントで抜けられなければ全リージョンの完了を待つ
+func TestValidator_States_ParallelCompletion(t *testing.T) {
+	decl := func(exit ast.Trigger) ast.StatesDecl {
+		return ast.StatesDecl{
+			Pos:     at(1),
+			Name:    "Checkout",
+			Initial: "Processing",
+			Finals:  []string{"Done"},
+			Parallels: []ast.ParallelDecl{{
+				Pos:  at(2),
+				Name: "Processing",
+				Regions: []ast.RegionDecl{
+					{Pos: at(3), Name: "Payment", Initial: "Authorizing", Transitions: []ast.TransitionDecl{transition(4, "Authorizing", "Charged", "charged", nil)}},
+					{Pos: at(5), Name: "Polling", Initial: "Ping", Transitions: []ast.TransitionDecl{transition(6, "Ping", "Pong", "tick", nil), transition(7, "Pong", "Ping", "tick", nil)}},
+				},
+			}},
+			Transitions: []ast.TransitionDecl{{Pos: at(8), From: "Processing", To: "Done", Trigger: exit}},
+		}
+	}
+
+	found := stateWarnings(decl(&ast.WhenTrigger{Condition: &ast.VariableExpr{Name: "complete"}}))
+	assertWarnings(t, found, errors.CodeParallelIncompletion+" 5")
+
+	if found := stateWarnings(decl(&ast.EventTrigger{Event: "cancel"})); len(found) != 0 {
+		t.Errorf("expected no warnings when the parallel state exits on an event: %v", found)
+	}
+}
+
+// VS005: 問題のない状態機械は警告しない
+func TestValidator_States_Clean(t *testing.T) {
+	running := "Running"
+	found := stateWarnings(ast.StatesDecl{
+		Pos:     at(1),
+		Name:    "Job",
+		Initial: "Active",
+		Finals:  []string{"Done"},
+		States: []ast.StateDecl{{
+			Pos:         at(2),
+			Name:        "Active",
+			Initial:     &running,
+			States:      []ast.StateDecl{{Pos: at(3), Name: "Running"}, {Pos: at(4), Name: "Paused"}},
+			Transitions: []ast.TransitionDecl{transition(5, "Running", "Paused", "pause", nil)},
+		}},
+		Transitions: []ast.TransitionDecl{
+			transition(6, "Active", "Done", "finish", nil),
+			{Pos: at(7), From: "Active", To: "Done", Trigger: &ast.AfterTrigger{Duration: ast.Duration{Value: 1, Unit: "h"}}},
+		},
+	})
+	assertWarnings(t, found)
+}
+
+// VS006: 並行状態・リージョン・入れ子の状態への遷移は定義済みとして扱う
+func TestValidator_States_ParallelReferences(t *testing.T) {
+	authorizing := "Authorizing"
+	spec := &ast.SpecFile{Components: []ast.ComponentDecl{{
+		Name: "Checkout",
+		Body: ast.ComponentBody{States: []ast.StatesDecl{{
+			Pos:     at(1),
+			Name:    "CheckoutState",
+			Initial: "Cart",
+			Finals:  []string{"Done"},
+			States: []ast.StateDecl{
+				{Pos: at(2), Name: "Cart"},
+				{Pos: at(3), Name: "Done"},
+				{Pos: at(4), Name: "Review", Initial: &authorizing, States: []ast.StateDecl{{Pos: at(5), Name: "Authorizing"}}},
+			},
+			Parallels: []ast.ParallelDecl{{
+				Pos:  at(6),
+				Name: "Processing",
+				Regions: []ast.RegionDecl{
+					{Pos: at(7), Name: "Payment", Initial: "Charging", States: []ast.StateDecl{{Pos: at(8), Name: "Charging"}}},
+				},
+			}},
+			Transitions: []ast.TransitionDecl{
+				transition(9, "Cart", "Processing", "submit", nil),
+				transition(10, "Processing", "Authorizing", "hold", nil),
+				transition(11, "Charging", "Done", "charged", nil),
+				transition(12, "Cart", "Missing", "oops", nil),
+			},
+		}}},
+	}}}
+
+	err := NewValidator().ValidateAll(spec)
+	me, ok := err.(*errors.MultiError)
+	if !ok || len(me.Errors) != 1 {
+		t.Fatalf("expected only the undefined target, got %v", err)
+	}
+	if ve, ok := me.Errors[0].(*errors.ValidationError); !ok || ve.Name != "Missing" {
+		t.Errorf("expected 'Missing' to be reported, got %v", me.Errors[0])
+	}
+}
+
+// =============================================================================
+// VD001-VD006: フローの変数と戻り値型の検査
+// =============================================================================
+
+func variable(name string) *ast.VariableExpr {
+	return &ast.VariableExpr{Name: name}
+}
+
+func assign(line int, name string, value ast.Expr) *ast.AssignStep {
+	return &ast.AssignStep{Pos: at(line), Variable: name, Value: value}
+}
+
+func selfCall(method string, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{Object: variable("self"), Method: method, Args: args}
+}
+
+// flowSpec はメソッドとフローを持つコンポーネント1つの仕様を作る
+func flowSpec(methods []ast.MethodDecl, flow ast.FlowDecl) *ast.SpecFile {
+	return &ast.SpecFile{Components: []ast.ComponentDecl{{
+		Name: "AuthService",
+		Body: ast.ComponentBody{
+			Types: []ast.TypeDecl{
+				{Name: "User", Kind: ast.TypeKindStruct, Fields: []ast.FieldDecl{{Name: "email", Type: ast.TypeExpr{Name: "string"}}}},
+				{Name: "Token", Kind: ast.TypeKindAlias, BaseType: &ast.TypeExpr{Name: "string"}},
+			},
+			Provides: []ast.InterfaceDecl{{Name: "AuthAPI", Methods: methods}},
+			Flows:    []ast.FlowDecl{flow},
+		},
+	}}}
+}
+
+func flowWarnings(spec *ast.SpecFile) []string {
+	v := NewValidator()
+	v.CollectWarnings(spec)
+	var found []string
+	for _, w := range v.GetWarnings().Warnings {
+		switch w.Code {
+		case errors.CodeUnassignedVariable, errors.CodeUnusedAssignment, errors.CodeShadowedVariable:
+			found = append(found, fmt.Sprintf("%s %d", w.Code, w.Pos.Line))
+		}
+	}
+	return found
+}
+
+func typeMismatches(spec *ast.SpecFile) []string {
+	var found []string
+	if me, ok := NewValidator().ValidateFlowTypes(spec).(*errors.MultiError); ok {
+		for _, e := range me.Errors {
+			if ve, ok := e.(*errors.ValidationError); ok {
+				found = append(found, fmt.Sprintf("%d %s", ve.Pos.Line, ve.Message))
+			}
+		}
+	}
+	return found
+}
+
+var loginMethod = ast.MethodDecl{
+	Name: "Login",
+	Params: []ast.ParamDecl{
+		{Name: "email", Type: ast.TypeExpr{Name: "string"}},
+		{Name: "password", Type: ast.TypeExpr{Name: "string"}},
+	},
+	ReturnType: &ast.TypeExpr{Name: "Token"},
+}
+
+// VD001: パラメータは代入済みとして扱い、代入前の読み出しを報告する
+func TestValidator_Flow_ReadBeforeAssign(t *testing.T) {
+	found := flowWarnings(flowSpec([]ast.MethodDecl{loginMethod}, ast.FlowDecl{
+		Name: "Login",
+		Steps: []ast.Step{
+			assign(1, "user", selfCall("find", variable("email"))),
+			&ast.IfStep{Condition: variable("user"), Then: []ast.Step{
+				assign(3, "token", selfCall("issue", variable("user"), variable("password"))),
+			}},
+			&ast.CallStep{Pos: at(4), Expr: selfCall("log", &ast.VariableExpr{Pos: at(4), Name: "token"})},
+			&ast.CallStep{Pos: at(5), Expr: selfCall("audit", &ast.VariableExpr{Pos: at(5), Name: "session"})},
+			&ast.ReturnStep{Pos: at(6), Value: variable("token")},
+		},
+	}))
+	assertWarnings(t, found,
+		errors.CodeUnassignedVariable+" 4",
+		errors.CodeUnassignedVariable+" 5",
+	)
+}
+
+// VD002: 両方の分岐で代入された変数と、return で終わる分岐の後は代入済みとして扱う
+func TestValidator_Flow_BranchAssignment(t *testing.T) {
+	found := flowWarnings(flowSpec([]ast.MethodDecl{loginMethod}, ast.FlowDecl{
+		Name: "Login",
+		Steps: []ast.Step{
+			&ast.IfStep{Condition: variable("email"),
+				Then: []ast.Step{assign(2, "token", selfCall("issue", variable("email")))},
+				Else: []ast.Step{assign(3, "token", selfCall("guest"))},
+			},
+			&ast.IfStep{Condition: variable("password"),
+				Then: []ast.Step{assign(5, "user", selfCall("find", variable("email")))},
+				Else: []ast.Step{&ast.ThrowStep{Error: "AuthError"}},
+			},
+			&ast.CallStep{Expr: selfCall("log", variable("user"))},
+			&ast.ReturnStep{Value: variable("token")},
+		},
+	}))
+	assertWarnings(t, found)
+}
+
+// VD003: 読まれない代入を報告する（ループの次の繰り返しで読まれる代入は報告しない）
+func TestValidator_Flow_UnusedAssignment(t *testing.T) {
+	found := flowWarnings(flowSpec(nil, ast.FlowDecl{
+		Name: "Process",
+		Steps: []ast.Step{
+			assign(1, "count", &ast.LiteralExpr{Value: int64(0)}),
+			assign(2, "unused", selfCall("load")),
+			&ast.WhileStep{Condition: variable("pending"), Body: []ast.Step{
+				assign(4, "count", &ast.BinaryExpr{Left: variable("count"), Op: "+", Right: &ast.LiteralExpr{Value: int64(1)}}),
+			}},
+			assign(5, "result", selfCall("finish")),
+			assign(6, "result", selfCall("finish", variable("count"))),
+			&ast.ReturnStep{Value: variable("result")},
+		},
+	}))
+	assertWarnings(t, found,
+		errors.CodeUnusedAssignment+" 2",
+		errors.CodeUnusedAssignment+" 5",
+	)
+}
+
+// VD004: 外側の変数・パラメータ・外側のループ変数を隠すループ変数を報告する
+func TestValidator_Flow_ShadowedLoopVariable(t *testing.T) {
+	found := flowWarnings(flowSpec([]ast.MethodDecl{loginMethod}, ast.FlowDecl{
+		Name: "Login",
+		Steps: []ast.Step{
+			assign(1, "item", selfCall("first")),
+			&ast.CallStep{Expr: selfCall("log", variable("item"))},
+			&ast.ForStep{Pos: at(3), Variable: "item", Iterable: selfCall("items"), Body: []ast.Step{
+				&ast.ForStep{Pos: at(4), Variable: "item", Iterable: variable("item"), Body: []ast.Step{
+					&ast.CallStep{Expr: selfCall("log", variable("item"))},
+				}},
+			}},
+			&ast.ForStep{Pos: at(6), Variable: "email", Iterable: selfCall("emails"), Body: []ast.Step{
+				&ast.CallStep{Expr: selfCall("log", variable("email"))},
+			}},
+			&ast.ForStep{Pos: at(8), Variable: "tag", Iterable: selfCall("tags"), Body: []ast.Step{
+				&ast.CallStep{Expr: selfCall("log", variable("tag"), variable("password"))},
+			}},
+			&ast.ReturnStep{Value: selfCall("issue")},
+		},
+	}))
+	assertWarnings(t, found,
+		errors.CodeShadowedVariable+" 3",
+		errors.CodeShadowedVariable+" 4",
+		errors.CodeShadowedVariable+" 6",
+	)
+}
+
+// VD005: 対応するメソッドのないフローでは、代入されない名前を暗黙の入力として扱う
+func TestValidator_Flow_ImplicitInputs(t *testing.T) {
+	found := flowWarnings(flowSpec(nil, ast.FlowDecl{
+		Name: "Handle",
+		Steps: []ast.Step{
+			assign(1, "user", &ast.CallExpr{Object: variable("UserRepository"), Method: "find", Args: []ast.Expr{variable("request")}}),
+			&ast.ReturnStep{Value: &ast.FieldExpr{Object: variable("user"), Field: "email"}},
+		},
+	}))
+	assertWarnings(t, found)
+}
+
+// VD006: return の値の型がメソッドの戻り値型と食い違う
+func TestValidator_Flow_ReturnType(t *testing.T) {
+	method := func(ret *ast.TypeExpr) []ast.MethodDecl {
+		m := loginMethod
+		m.ReturnType = ret
+		return []ast.MethodDecl{m}
+	}
+	flow := func(steps ...ast.Step) ast.FlowDecl {
+		return ast.FlowDecl{Name: "Login", Steps: steps}
+	}
+	ret := func(line int, value ast.Expr) ast.Step {
+		return &ast.ReturnStep{Pos: at(line), Value: value}
+	}
+
+	tests := []struct {
+		name string
+		spec *ast.SpecFile
+		want []string
+	}{
+		{"alias of string accepts parameter", flowSpec(method(&ast.TypeExpr{Name: "Token"}), flow(ret(1, variable("email")))), nil},
+		{"int literal to alias of string", flowSpec(method(&ast.TypeExpr{Name: "Token"}), flow(ret(1, &ast.LiteralExpr{Value: int64(1)}))),
+			[]string{"1 returns int but the method returns Token"}},
+		{"inferred variable", flowSpec(method(&ast.TypeExpr{Name: "User"}), flow(
+			assign(1, "ok", &ast.BinaryExpr{Left: variable("email"), Op: "==", Right: variable("password")}),
+			ret(2, variable("ok")))),
+			[]string{"2 returns bool but the method returns User"}},
+		{"int to float", flowSpec(method(&ast.TypeExpr{Name: "float"}), flow(ret(1, &ast.LiteralExpr{Value: int64(1)}))), nil},
+		{"null to non-nullable", flowSpec(method(&ast.TypeExpr{Name: "User"}), flow(ret(1, &ast.LiteralExpr{Value: nil}))),
+			[]string{"1 returns null but the method returns User"}},
+		{"null to nullable", flowSpec(method(&ast.TypeExpr{Name: "User", Nullable: true}), flow(ret(1, &ast.LiteralExpr{Value: nil}))), nil},
+		{"value from void", flowSpec(method(&ast.TypeExpr{Name: "void"}), flow(ret(1, variable("email")))),
+			[]string{"1 returns a value but the method has no return type"}},
+		{"missing value", flowSpec(method(&ast.TypeExpr{Name: "User"}), flow(ret(1, nil))),
+			[]string{"1 returns no value but the method returns User"}},
+		{"unknown call result", flowSpec(method(&ast.TypeExpr{Name: "User"}), flow(ret(1, selfCall("find")))), nil},
+		{"variable with conflicting assignments", flowSpec(method(&ast.TypeExpr{Name: "User"}), flow(
+			assign(1, "x", &ast.LiteralExpr{Value: "a"}),
+			assign(2, "x", &ast.LiteralExpr{Value: true}),
+			ret(3, variable("x")))), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if found := typeMismatches(tt.spec); strings.Join(found, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// VC001-VC004: フローの呼び出しとフィールドアクセスの検査
+// =============================================================================
+
+func call(object, method string, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{Pos: at(0), Object: variable(object), Method: method, Args: args}
+}
+
+// callSpec は UserRepository に依存する AuthService の仕様を作る
+func callSpec(alias *string, steps ...ast.Step) *ast.SpecFile {
+	spec := flowSpec([]ast.MethodDecl{loginMethod}, ast.FlowDecl{Name: "Login", Steps: steps})
+	spec.Components[0].Body.Relations = []ast.RelationDecl{
+		{Kind: ast.RelationDependsOn, Target: "UserRepository", Alias: alias},
+		{Kind: ast.RelationDependsOn, Target: "Mailer"},
+	}
+	spec.Components = append(spec.Components, ast.ComponentDecl{
+		Name: "UserRepository",
+		Body: ast.ComponentBody{
+			Types: []ast.TypeDecl{{Name: "StoredUser", Kind: ast.TypeKindStruct, Fields: []ast.FieldDecl{
+				{Name: "passwordHash", Type: ast.TypeExpr{Name: "string"}},
+				{Name: "profile", Type: ast.TypeExpr{Name: "User"}},
+			}}},
+			Provides: []ast.InterfaceDecl{{Name: "UserRepo", Methods: []ast.MethodDecl{
+				{Name: "FindByEmail", Params: []ast.ParamDecl{{Name: "email", Type: ast.TypeExpr{Name: "string"}}}, ReturnType: &ast.TypeExpr{Name: "StoredUser", Nullable: true}},
+				{Name: "Count", ReturnType: &ast.TypeExpr{Name: "int"}},
+			}}},
+		},
+	})
+	return spec
+}
+
+func flowErrors(spec *ast.SpecFile) []string {
+	var found []string
+	if me, ok := NewValidator().ValidateFlowTypes(spec).(*errors.MultiError); ok {
+		for _, e := range me.Errors {
+			if ve, ok := e.(*errors.ValidationError); ok {
+				found = append(found, fmt.Sprintf("%s %s: %s", ve.Type, ve.Name, ve.Message))
+			}
+		}
+	}
+	return found
+}
+
+// VC001: 依存先（エイリアスを含む）のメソッドを解決し、先頭の大文字・小文字は区別しない
+func TestValidator_FlowCall_Resolved(t *testing.T) {
+	repo := "repo"
+	for _, spec := range []*ast.SpecFile{
+		callSpec(nil,
+			assign(1, "user", call("UserRepository", "findByEmail", variable("email"))),
+			&ast.ReturnStep{Value: &ast.FieldExpr{Object: variable("user"), Field: "passwordHash"}}),
+		callSpec(&repo,
+			assign(1, "user", call("repo", "FindByEmail", &ast.LiteralExpr{Value: "a@example.com"})),
+			&ast.CallStep{Expr: call("Mailer", "send", variable("user"))},
+			&ast.ReturnStep{Value: &ast.FieldExpr{Object: &ast.FieldExpr{Object: variable("user"), Field: "profile"}, Field: "email"}}),
+	} {
+		if found := flowErrors(spec); len(found) != 0 {
+			t.Errorf("unexpected errors: %v", found)
+		}
+	}
+}
+
+// VC002: provides にないメソッドと引数の数の食い違い
+func TestValidator_FlowCall_UnknownMethodAndArity(t *testing.T) {
+	repo := "repo"
+	found := flowErrors(callSpec(&repo,
+		&ast.CallStep{Expr: call("repo", "FindByName", variable("email"))},
+		&ast.CallStep{Expr: call("UserRepository", "FindByEmail")},
+		&ast.CallStep{Expr: call("UserRepository", "Count", variable("email"))},
+		&ast.ReturnStep{Value: variable("email")},
+	))
+	want := []string{
+		"unknown-method repo.FindByName: 'repo' does not provide method 'FindByName' (provides: Count, FindByEmail)",
+		"argument-count UserRepository.FindByEmail: expects 1 argument(s), got 0",
+		"argument-count UserRepository.Count: expects 0 argument(s), got 1",
+	}
+	if strings.Join(found, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n  %s\nwant:\n  %s", strings.Join(found, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+// VC003: 引数の型の食い違い
+func TestValidator_FlowCall_ArgumentType(t *testing.T) {
+	found := flowErrors(callSpec(nil,
+		assign(1, "count", call("UserRepository", "Count")),
+		&ast.CallStep{Expr: call("UserRepository", "FindByEmail", variable("count"))},
+		&ast.ReturnStep{Value: variable("email")},
+	))
+	want := []string{"type-mismatch UserRepository.FindByEmail: argument 1 (email) is int but the parameter is string"}
+	if strings.Join(found, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %v, want %v", found, want)
+	}
+}
+
+// VC004: 戻り値型から推論した変数のフィールドアクセスと戻り値
+func TestValidator_FlowCall_FieldAccess(t *testing.T) {
+	found := flowErrors(callSpec(nil,
+		assign(1, "user", call("UserRepository", "FindByEmail", variable("email"))),
+		assign(2, "hash", &ast.FieldExpr{Pos: at(2), Object: variable("user"), Field: "password"}),
+		&ast.CallStep{Expr: call("Mailer", "send", variable("hash"))},
+		&ast.ReturnStep{Value: &ast.FieldExpr{Object: variable("user"), Field: "profile"}},
+	))
+	want := []string{
+		"unknown-field user.password: type 'StoredUser' has no field 'password'",
+		"type-mismatch Login: returns User but the method returns Token",
+	}
+	if strings.Join(found, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n  %s\nwant:\n  %s", strings.Join(found, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+// =============================================================================
+// VT001-VT004: 宣言された例外と送出される例外の照合
+// =============================================================================
+
+// throwSpec は Checkout → Payment → Gateway と呼び出す仕様を作る
+// Gateway.Charge は DeclinedError を宣言し、フローでは宣言していない TimeoutError も送出する
+func throwSpec(checkoutThrows []string, checkoutSteps ...ast.Step) *ast.SpecFile {
+	component := func(name, method string, throws []string, deps []string, steps ...ast.Step) ast.ComponentDecl {
+		comp := ast.ComponentDecl{Name: name, Body: ast.ComponentBody{
+			Provides: []ast.InterfaceDecl{{Name: name + "API", Methods: []ast.MethodDecl{
+				{Pos: at(100), Name: method, Throws: throws},
+			}}},
+			Flows: []ast.FlowDecl{{Name: method, Steps: steps}},
+		}}
+		for _, dep := range deps {
+			comp.Body.Relations = append(comp.Body.Relations, ast.RelationDecl{Kind: ast.RelationDependsOn, Target: dep})
+		}
+		return comp
+	}
+	return &ast.SpecFile{Components: []ast.ComponentDecl{
+		component("Checkout", "Pay", checkoutThrows, []string{"Payment"}, checkoutSteps...),
+		component("Payment", "Charge", nil, []string{"Gateway"},
+			&ast.CallStep{Expr: call("Gateway", "charge")},
+		),
+		component("Gateway", "Charge", []string{"DeclinedError"}, nil,
+			&ast.IfStep{Condition: variable("slow"), Then: []ast.Step{&ast.ThrowStep{Pos: at(50), Error: "TimeoutError"}}},
+		),
+	}}
+}
+
+func throwDiagnostics(spec *ast.SpecFile) []string {
+	var found []string
+	v := NewValidator()
+	if me, ok := v.ValidateThrows(spec).(*errors.MultiError); ok {
+		for _, e := range me.Errors {
+			if ve, ok := e.(*errors.ValidationError); ok {
+				found = append(found, fmt.Sprintf("%s %s %d: %s", ve.Type, ve.Name, ve.Pos.Line, ve.Message))
+			}
+		}
+	}
+	v.CollectWarnings(spec)
+	for _, w := range v.GetWarnings().Warnings {
+		if w.Code == errors.CodeUnraisedThrow {
+			found = append(found, fmt.Sprintf("%s %d: %s", w.Code, w.Pos.Line, w.Message))
+		}
+	}
+	return found
+}
+
+// VT001: 直接の throw と ?? throw、依存先から伝播する例外
+func TestValidator_Throws_Undeclared(t *testing.T) {
+	invalid := "InvalidCart"
+	found := throwDiagnostics(throwSpec([]string{"InvalidCart", "DeclinedError"},
+		assign(1, "cart", &ast.NullishExpr{Pos: at(1), Left: call("self", "load"), ThrowErr: &invalid}),
+		&ast.CallStep{Pos: at(2), Expr: &ast.CallExpr{Pos: at(2), Object: variable("Payment"), Method: "charge", Args: nil}},
+		&ast.IfStep{Condition: variable("cart"), Then: []ast.Step{&ast.ThrowStep{Pos: at(3), Error: "EmptyCart"}}},
+	))
+	want := []string{
+		"undeclared-throw Pay 2: 'TimeoutError' propagates from 'Payment.Charge' but the method does not declare it",
+		"undeclared-throw Pay 3: flow throws 'EmptyCart' but the method does not declare it",
+		"undeclared-throw Charge 0: 'DeclinedError' propagates from 'Gateway.Charge' but the method does not declare it",
+		"undeclared-throw Charge 0: 'TimeoutError' propagates from 'Gateway.Charge' but the method does not declare it",
+		"undeclared-throw Charge 50: flow throws 'TimeoutError' but the method does not declare it",
+		"unraised-throw 100: method 'Charge' declares 'DeclinedError' but flow 'Charge' never throws it",
+	}
+	if strings.Join(found, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n  %s\nwant:\n  %s", strings.Join(found, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+// VT002: 宣言されているのにどの経路でも送出されない例外
+func TestValidator_Throws_Unraised(t *testing.T) {
+	found := throwDiagnostics(throwSpec([]string{"DeclinedError", "TimeoutError", "FraudError"},
+		&ast.CallStep{Expr: call("Payment", "Charge")},
+	))
+	var unraised []string
+	for _, f := range found {
+		if strings.HasPrefix(f, errors.CodeUnraisedThrow) {
+			unraised = append(unraised, f)
+		}
+	}
+	want := []string{
+		"unraised-throw 100: method 'Pay' declares 'FraudError' but flow 'Pay' never throws it",
+		"unraised-throw 100: method 'Charge' declares 'DeclinedError' but flow 'Charge' never throws it",
+	}
+	if strings.Join(unraised, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %v, want %v", unraised, want)
+	}
+}
+
+// VT003: 相互に呼び出すメソッドでも解析が終わる
+func TestValidator_Throws_Recursive(t *testing.T) {
+	spec := &ast.SpecFile{Components: []ast.ComponentDecl{{
+		Name: "Tree",
+		Body: ast.ComponentBody{
+			Provides: []ast.InterfaceDecl{{Name: "TreeAPI", Methods: []ast.MethodDecl{
+				{Name: "Walk", Throws: []string{"DepthError"}},
+				{Name: "Visit", Throws: []string{"DepthError"}},
+			}}},
+			Flows: []ast.FlowDecl{
+				{Name: "Walk", Steps: []ast.Step{&ast.CallStep{Expr: call("self", "visit")}}},
+				{Name: "Visit", Steps: []ast.Step{
+					&ast.CallStep{Expr: call("self", "walk")},
+					&ast.ThrowStep{Error: "DepthError"},
+				}},
+			},
+		},
+	}}}
+	if found := throwDiagnostics(spec); len(found) != 0 {
+		t.Errorf("unexpected diagnostics: %v", found)
+	}
+}
+
+// VT004: import 先のフローの呼び出しは、その仕様自身の import で解決する
+func TestValidator_Throws_ImportedFlow(t *testing.T) {
+	component := func(name string, rel *ast.RelationDecl, steps ...ast.Step) ast.ComponentDecl {
+		comp := ast.ComponentDecl{Name: name, Body: ast.ComponentBody{
+			Provides: []ast.InterfaceDecl{{Name: name + "API", Methods: []ast.MethodDecl{{Pos: at(100), Name: "Charge"}}}},
+			Flows:    []ast.FlowDecl{{Name: "Charge", Steps: steps}},
+		}}
+		if rel != nil {
+			comp.Body.Relations = []ast.RelationDecl{*rel}
+		}
+		return comp
+	}
+	gw, other, gateway := "gw", "other", "gateway"
+
+	// billing/payment.pact の "./gateway.pact" は billing/gateway.pact を指す
+	billingGateway := &ast.SpecFile{Components: []ast.ComponentDecl{
+		component("Gateway", nil, &ast.ThrowStep{Error: "TimeoutError"}),
+	}}
+	rootGateway := &ast.SpecFile{Components: []ast.ComponentDecl{
+		component("Gateway", nil, &ast.ThrowStep{Error: "FraudError"}),
+	}}
+	billing := &ast.SpecFile{
+		Imports: []ast.ImportDecl{{Path: "./gateway.pact", Alias: &gw}},
+		Components: []ast.ComponentDecl{component("Payment",
+			&ast.RelationDecl{Kind: ast.RelationDependsOn, Target: "gw.Gateway", Alias: &gateway},
+			&ast.CallStep{Expr: call("gateway", "charge")},
+		)},
+	}
+	spec := &ast.SpecFile{
+		Imports: []ast.ImportDecl{{Path: "./billing/payment.pact"}, {Path: "./gateway.pact", Alias: &other}},
+		Components: []ast.ComponentDecl{component("Checkout",
+			&ast.RelationDecl{Kind: ast.RelationDependsOn, Target: "Payment"},
+			&ast.CallStep{Expr: &ast.CallExpr{Pos: at(2), Object: variable("Payment"), Method: "charge"}},
+		)},
+	}
+	validate := func(specImports map[*ast.SpecFile]map[string]*ast.SpecFile) []string {
+		v := NewValidator()
+		v.SetImports(map[string]*ast.SpecFile{"./billing/payment.pact": billing, "./gateway.pact": rootGateway})
+		v.SetSpecImports(specImports)
+		var found []string
+		if me, ok := v.ValidateThrows(spec).(*errors.MultiError); ok {
+			for _, e := range me.Errors {
+				found = append(found, e.(*errors.ValidationError).Message)
+			}
+		}
+		return found
+	}
+
+	found := validate(map[*ast.SpecFile]map[string]*ast.SpecFile{
+		billing:        {"./gateway.pact": billingGateway},
+		billingGateway: {},
+	})
+	want := "'TimeoutError' propagates from 'Payment.Charge' but the method does not declare it"
+	if strings.Join(found, "|") != want {
+		t.Errorf("got %v, want %s", found, want)
+	}
+
+	// import 先の仕様の import が分からなければ、呼び出し先が宣言した例外だけを使う
+	if found := validate(nil); len(found) != 0 {
+		t.Errorf("unexpected diagnostics: %v", found)
+	}
+}
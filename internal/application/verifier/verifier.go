@@ -0,0 +1,428 @@
+// Package verifier compares .pact specs with specs derived from source code.
+package verifier
+
+import (
+	"fmt"
+	"strings"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+)
+
+// Verifier は仕様と、ソースコードから導出した仕様を突き合わせる
+type Verifier struct {
+	errors *errors.MultiError
+	source string              // 表示用のソースファイル
+	names  map[string][]string // コード側のコンポーネント名 → 仕様側のコンポーネント名
+}
+
+// NewVerifier は新しい Verifier を作成する
+func NewVerifier() *Verifier {
+	return &Verifier{errors: &errors.MultiError{}}
+}
+
+// SetComponentNames はコード側のコンポーネント名に対応する仕様側の名前を登録する
+// 依存先の照合で、ファイル名から導出した名前と仕様で付けた名前を同一視する
+func (v *Verifier) SetComponentNames(names map[string][]string) {
+	v.names = names
+}
+
+// Verify は spec の宣言と code（source から導出した仕様）の宣言を比較する
+// 型・フィールド（可視性を含む）・enum の値・provides のメソッド・依存先について、
+// コードにない宣言・仕様にない宣言・食い違う宣言を報告する
+func (v *Verifier) Verify(spec, code *ast.SpecFile, source string) error {
+	v.errors = &errors.MultiError{}
+	v.source = source
+
+	if len(spec.Components) == 0 {
+		return nil
+	}
+	anchor := spec.Components[0].Pos
+
+	var codeComp ast.ComponentDecl
+	if len(code.Components) > 0 {
+		codeComp = code.Components[0]
+	}
+
+	// 一つの仕様ファイルの全コンポーネントを一つのソースファイルと突き合わせる
+	specComponents := make(map[string]bool)
+	var types []ast.TypeDecl
+	var provides []ast.InterfaceDecl
+	var relations []ast.RelationDecl
+	for _, comp := range spec.Components {
+		specComponents[comp.Name] = true
+		types = append(types, comp.Body.Types...)
+		provides = append(provides, comp.Body.Provides...)
+		relations = append(relations, comp.Body.Relations...)
+	}
+
+	v.verifyTypes(types, codeComp.Body.Types, specComponents, anchor)
+	v.verifyInterfaces(provides, codeComp.Body.Provides, anchor)
+	v.verifyRelations(relations, codeComp.Body.Relations, anchor)
+
+	if v.errors.HasErrors() {
+		return v.errors
+	}
+	return nil
+}
+
+func (v *Verifier) verifyTypes(types, codeTypes []ast.TypeDecl, components map[string]bool, anchor ast.Position) {
+	actual := make(map[string]*ast.TypeDecl)
+	for i := range codeTypes {
+		actual[codeTypes[i].Name] = &codeTypes[i]
+	}
+
+	declared := make(map[string]bool)
+	for i := range types {
+		typ := &types[i]
+		declared[typ.Name] = true
+		code, ok := actual[typ.Name]
+		if !ok {
+			v.missing(typ.Pos, typ.Name, "type")
+			continue
+		}
+		if typ.Kind != code.Kind {
+			v.mismatch(typ.Pos, typ.Name, "kind", kindName(typ.Kind), kindName(code.Kind))
+			continue
+		}
+		switch typ.Kind {
+		case ast.TypeKindStruct:
+			v.verifyFields(typ, code)
+		case ast.TypeKindEnum:
+			v.verifyEnum(typ, code)
+		case ast.TypeKindAlias:
+			if typ.BaseType != nil && code.BaseType != nil && !sameType(*typ.BaseType, *code.BaseType) {
+				v.mismatch(typ.Pos, typ.Name, "type", formatType(typ.BaseType), formatType(code.BaseType))
+			}
+		}
+	}
+
+	for _, code := range codeTypes {
+		// コンポーネント自身を表す struct は型として宣言しなくてよい
+		if declared[code.Name] || (code.Kind == ast.TypeKindStruct && components[code.Name]) {
+			continue
+		}
+		v.extra(anchor, code.Name, "type")
+	}
+}
+
+func (v *Verifier) verifyFields(typ, code *ast.TypeDecl) {
+	actual := make(map[string]*ast.FieldDecl)
+	for i := range code.Fields {
+		actual[code.Fields[i].Name] = &code.Fields[i]
+	}
+
+	declared := make(map[string]bool)
+	for _, field := range typ.Fields {
+		name := typ.Name + "." + field.Name
+		declared[field.Name] = true
+		codeField, ok := actual[field.Name]
+		if !ok {
+			v.missing(field.Pos, name, "field")
+			continue
+		}
+		if !sameVisibility(field.Visibility, codeField.Visibility) {
+			v.mismatch(field.Pos, name, "visibility", string(field.Visibility), string(codeField.Visibility))
+		}
+		if !sameType(field.Type, codeField.Type) {
+			v.mismatch(field.Pos, name, "type", formatType(&field.Type), formatType(&codeField.Type))
+		}
+	}
+
+	for _, field := range code.Fields {
+		if !declared[field.Name] {
+			v.extra(typ.Pos, typ.Name+"."+field.Name, "field")
+		}
+	}
+}
+
+func (v *Verifier) verifyEnum(typ, code *ast.TypeDecl) {
+	actual := make(map[string]bool)
+	for _, value := range code.Values {
+		actual[value] = true
+	}
+	declared := make(map[string]bool)
+	for _, value := range typ.Values {
+		declared[value] = true
+		if !actual[value] {
+			v.missing(typ.Pos, typ.Name+"."+value, "enum value")
+		}
+	}
+	for _, value := range code.Values {
+		if !declared[value] {
+			v.extra(typ.Pos, typ.Name+"."+value, "enum value")
+		}
+	}
+}
+
+func (v *Verifier) verifyInterfaces(ifaces, codeIfaces []ast.InterfaceDecl, anchor ast.Position) {
+	actual := make(map[string]*ast.InterfaceDecl)
+	for i := range codeIfaces {
+		actual[codeIfaces[i].Name] = &codeIfaces[i]
+	}
+
+	declared := make(map[string]bool)
+	for i := range ifaces {
+		iface := &ifaces[i]
+		declared[iface.Name] = true
+		code, ok := actual[iface.Name]
+		if !ok {
+			v.missing(iface.Pos, iface.Name, "interface")
+			continue
+		}
+		v.verifyMethods(iface, code)
+	}
+
+	for _, code := range codeIfaces {
+		if !declared[code.Name] {
+			v.extra(anchor, code.Name, "interface")
+		}
+	}
+}
+
+func (v *Verifier) verifyMethods(iface, code *ast.InterfaceDecl) {
+	actual := make(map[string]*ast.MethodDecl)
+	for i := range code.Methods {
+		actual[code.Methods[i].Name] = &code.Methods[i]
+	}
+
+	declared := make(map[string]bool)
+	for i := range iface.Methods {
+		method := &iface.Methods[i]
+		name := iface.Name + "." + method.Name
+		declared[method.Name] = true
+		codeMethod, ok := actual[method.Name]
+		if !ok {
+			v.missing(method.Pos, name, "method")
+			continue
+		}
+		v.verifySignature(name, method, codeMethod)
+	}
+
+	for _, method := range code.Methods {
+		if !declared[method.Name] {
+			v.extra(iface.Pos, iface.Name+"."+method.Name, "method")
+		}
+	}
+}
+
+// verifySignature はメソッドの引数・戻り値・async・エラーの有無を比較する（引数名は比較しない）
+func (v *Verifier) verifySignature(name string, method, code *ast.MethodDecl) {
+	if len(method.Params) != len(code.Params) {
+		v.mismatch(method.Pos, name, "parameters", formatParams(method.Params), formatParams(code.Params))
+	} else {
+		for i, param := range method.Params {
+			if !sameType(param.Type, code.Params[i].Type) {
+				v.mismatch(param.Pos, name+"("+param.Name+")", "parameter type", formatType(&param.Type), formatType(&code.Params[i].Type))
+			}
+		}
+	}
+
+	if !sameReturn(method.ReturnType, code.ReturnType) {
+		v.mismatch(method.Pos, name, "return type", formatType(method.ReturnType), formatType(code.ReturnType))
+	}
+	if method.Async != code.Async {
+		v.mismatch(method.Pos, name, "async", fmt.Sprint(method.Async), fmt.Sprint(code.Async))
+	}
+	if (len(method.Throws) > 0) != (len(code.Throws) > 0) {
+		v.mismatch(method.Pos, name, "error return", throwsText(method.Throws), throwsText(code.Throws))
+	}
+}
+
+// verifyRelations は依存先（depends on / contains / aggregates）を種類を区別せずに比較する
+func (v *Verifier) verifyRelations(relations, codeRelations []ast.RelationDecl, anchor ast.Position) {
+	var actual []string
+	for _, rel := range codeRelations {
+		if isDependency(rel.Kind) {
+			actual = append(actual, rel.Target)
+		}
+	}
+
+	matched := make(map[string]bool)
+	for _, rel := range relations {
+		if !isDependency(rel.Kind) {
+			continue
+		}
+		found := false
+		for _, target := range actual {
+			if v.sameComponent(rel.Target, target) {
+				matched[target] = true
+				found = true
+			}
+		}
+		if !found {
+			v.missing(rel.Pos, rel.Target, "dependency")
+		}
+	}
+
+	for _, target := range actual {
+		if !matched[target] {
+			v.extra(anchor, target, "dependency")
+		}
+	}
+}
+
+// sameComponent は仕様側の依存先がコード側の依存先を指すかどうかを返す
+func (v *Verifier) sameComponent(specTarget, codeTarget string) bool {
+	if _, member, ok := ast.SplitQualifiedName(specTarget); ok {
+		specTarget = member
+	}
+	if specTarget == codeTarget {
+		return true
+	}
+	for _, name := range v.names[codeTarget] {
+		if name == specTarget {
+			return true
+		}
+	}
+	return false
+}
+
+func isDependency(kind ast.RelationKind) bool {
+	return kind == ast.RelationDependsOn || kind == ast.RelationContains || kind == ast.RelationAggregates
+}
+
+func (v *Verifier) missing(pos ast.Position, name, what string) {
+	v.errors.Add(&errors.ValidationError{
+		Pos:     pos,
+		Type:    errors.CodeDriftMissing,
+		Name:    name,
+		Message: fmt.Sprintf("%s is declared in spec but not found in %s", what, v.source),
+	})
+}
+
+func (v *Verifier) extra(pos ast.Position, name, what string) {
+	v.errors.Add(&errors.ValidationError{
+		Pos:     pos,
+		Type:    errors.CodeDriftExtra,
+		Name:    name,
+		Message: fmt.Sprintf("%s is found in %s but not declared in spec", what, v.source),
+	})
+}
+
+func (v *Verifier) mismatch(pos ast.Position, name, what, spec, code string) {
+	v.errors.Add(&errors.ValidationError{
+		Pos:     pos,
+		Type:    errors.CodeDriftMismatch,
+		Name:    name,
+		Message: fmt.Sprintf("%s mismatch: spec %s, code %s", what, spec, code),
+	})
+}
+
+// builtinAliases は同じ組み込み型を表す別名
+var builtinAliases = map[string]string{
+	"String": "string",
+	"Int":    "int", "integer": "int", "Integer": "int",
+	"Float": "float", "double": "float", "Double": "float",
+	"Bool": "bool", "boolean": "bool", "Boolean": "bool",
+	"Any": "any", "object": "any", "Object": "any",
+	"map":      "Map",
+	"DateTime": "datetime", "time": "datetime", "Time": "datetime", "date": "datetime", "Date": "datetime",
+	"Bytes": "bytes",
+	"UUID":  "string", "uuid": "string",
+	"void": "", "Void": "",
+}
+
+// canonicalName は型名を比較用の名前にする
+func canonicalName(name string) string {
+	if _, member, ok := ast.SplitQualifiedName(name); ok {
+		name = member
+	}
+	if alias, ok := builtinAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// sameType は二つの型が同じかどうかを返す
+// コード側の any はコードから型を特定できなかったことを表し、どの型とも一致する
+func sameType(spec, code ast.TypeExpr) bool {
+	codeName := canonicalName(code.Name)
+	if codeName == "any" && !code.Array {
+		return true
+	}
+	if spec.Array != code.Array || spec.Nullable != code.Nullable {
+		return false
+	}
+	if codeName == "any" {
+		return true
+	}
+	if canonicalName(spec.Name) != codeName {
+		return false
+	}
+	// 型引数を省略した Map などは型引数によらず一致する
+	if len(spec.TypeParams) == 0 {
+		return true
+	}
+	if len(spec.TypeParams) != len(code.TypeParams) {
+		return false
+	}
+	for i := range spec.TypeParams {
+		if !sameType(spec.TypeParams[i], code.TypeParams[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameReturn(spec, code *ast.TypeExpr) bool {
+	specVoid := spec == nil || canonicalName(spec.Name) == ""
+	codeVoid := code == nil || canonicalName(code.Name) == ""
+	if specVoid || codeVoid {
+		return specVoid == codeVoid
+	}
+	return sameType(*spec, *code)
+}
+
+func sameVisibility(spec, code ast.Visibility) bool {
+	return (spec == ast.VisibilityPublic || spec == "") == (code == ast.VisibilityPublic || code == "")
+}
+
+func kindName(kind ast.TypeKind) string {
+	switch kind {
+	case ast.TypeKindEnum:
+		return "enum"
+	case ast.TypeKindAlias:
+		return "alias"
+	}
+	return "type"
+}
+
+func formatType(t *ast.TypeExpr) string {
+	if t == nil {
+		return "void"
+	}
+	text := t.Name
+	if len(t.TypeParams) > 0 {
+		params := make([]string, len(t.TypeParams))
+		for i := range t.TypeParams {
+			params[i] = formatType(&t.TypeParams[i])
+		}
+		text += "<" + strings.Join(params, ", ") + ">"
+	}
+	if t.Nullable && !t.Array {
+		text += "?"
+	}
+	if t.Array {
+		text += "[]"
+		if t.Nullable {
+			text += "?"
+		}
+	}
+	return text
+}
+
+func formatParams(params []ast.ParamDecl) string {
+	parts := make([]string, len(params))
+	for i := range params {
+		parts[i] = formatType(&params[i].Type)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func throwsText(throws []string) string {
+	if len(throws) == 0 {
+		return "no error"
+	}
+	return "throws " + strings.Join(throws, ", ")
+}
@@ -0,0 +1,186 @@
+package verifier
+
+import (
+	"strings"
+	"testing"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+)
+
+func specOf(name string, body ast.ComponentBody) *ast.SpecFile {
+	return &ast.SpecFile{Components: []ast.ComponentDecl{{Name: name, Body: body}}}
+}
+
+func structType(name string, fields ...ast.FieldDecl) ast.TypeDecl {
+	return ast.TypeDecl{Name: name, Kind: ast.TypeKindStruct, Fields: fields}
+}
+
+func field(name string, typ ast.TypeExpr, visibility ast.Visibility) ast.FieldDecl {
+	return ast.FieldDecl{Name: name, Type: typ, Visibility: visibility}
+}
+
+func named(name string) ast.TypeExpr {
+	return ast.TypeExpr{Name: name}
+}
+
+func param(name, typ string) ast.ParamDecl {
+	return ast.ParamDecl{Name: name, Type: named(typ)}
+}
+
+// verify は仕様とコード側の仕様を比較し、"code name" の一覧を返す
+func verify(t *testing.T, v *Verifier, spec, code *ast.SpecFile) []string {
+	t.Helper()
+	err := v.Verify(spec, code, "src/x.go")
+	if err == nil {
+		return nil
+	}
+	var found []string
+	for _, e := range err.(*errors.MultiError).Errors {
+		ve := e.(*errors.ValidationError)
+		found = append(found, ve.Type+" "+ve.Name)
+	}
+	return found
+}
+
+func assertDrift(t *testing.T, found []string, want ...string) {
+	t.Helper()
+	if strings.Join(found, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected drift:\ngot:\n  %s\nwant:\n  %s", strings.Join(found, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+// =============================================================================
+// VF001-VF006: 仕様とコードの突き合わせ
+// =============================================================================
+
+// VF001: 一致していれば差分はない
+func TestVerify_Match(t *testing.T) {
+	ret := ast.TypeExpr{Name: "User", Nullable: true}
+	body := ast.ComponentBody{
+		Types: []ast.TypeDecl{structType("User",
+			field("id", named("string"), ast.VisibilityPublic),
+			field("tags", ast.TypeExpr{Name: "string", Array: true}, ast.VisibilityPrivate),
+		)},
+		Provides: []ast.InterfaceDecl{{Name: "Api", Methods: []ast.MethodDecl{
+			{Name: "Get", Params: []ast.ParamDecl{param("id", "string")}, ReturnType: &ret, Async: true, Throws: []string{"Error"}},
+		}}},
+	}
+	assertDrift(t, verify(t, NewVerifier(), specOf("A", body), specOf("A", body)))
+}
+
+// VF002: 型とフィールドの不足・過剰・食い違い
+func TestVerify_Fields(t *testing.T) {
+	spec := specOf("A", ast.ComponentBody{Types: []ast.TypeDecl{
+		structType("User",
+			field("id", named("string"), ast.VisibilityPublic),
+			field("age", named("int"), ast.VisibilityPublic),
+			field("email", named("String"), ast.VisibilityPublic),
+		),
+		structType("Extra"),
+	}})
+	code := specOf("A", ast.ComponentBody{Types: []ast.TypeDecl{
+		structType("User",
+			field("id", named("int"), ast.VisibilityPublic),
+			field("email", named("string"), ast.VisibilityPrivate),
+			field("name", named("string"), ast.VisibilityPublic),
+		),
+		structType("Hidden"),
+	}})
+	assertDrift(t, verify(t, NewVerifier(), spec, code),
+		"drift-mismatch User.id",
+		"drift-missing User.age",
+		"drift-mismatch User.email",
+		"drift-extra User.name",
+		"drift-missing Extra",
+		"drift-extra Hidden",
+	)
+}
+
+// VF003: enum の値と型の種類
+func TestVerify_Enum(t *testing.T) {
+	base := named("int")
+	spec := specOf("A", ast.ComponentBody{Types: []ast.TypeDecl{
+		{Name: "Status", Kind: ast.TypeKindEnum, Values: []string{"Active", "Closed"}},
+		{Name: "Code", Kind: ast.TypeKindAlias, BaseType: &base},
+	}})
+	code := specOf("A", ast.ComponentBody{Types: []ast.TypeDecl{
+		{Name: "Status", Kind: ast.TypeKindEnum, Values: []string{"Active", "Pending"}},
+		{Name: "Code", Kind: ast.TypeKindEnum, Values: []string{"X"}},
+	}})
+	assertDrift(t, verify(t, NewVerifier(), spec, code),
+		"drift-missing Status.Closed",
+		"drift-extra Status.Pending",
+		"drift-mismatch Code",
+	)
+}
+
+// VF004: メソッドの引数・戻り値・async・エラー
+func TestVerify_Methods(t *testing.T) {
+	str := named("string")
+	nullableStr := ast.TypeExpr{Name: "string", Nullable: true}
+	anyType := named("any")
+	spec := specOf("A", ast.ComponentBody{Provides: []ast.InterfaceDecl{{Name: "Api", Methods: []ast.MethodDecl{
+		{Name: "Get", Params: []ast.ParamDecl{param("id", "string")}, ReturnType: &str},
+		{Name: "Put", Params: []ast.ParamDecl{param("id", "string"), param("v", "int")}, Throws: []string{"Error"}},
+		{Name: "Del", Params: []ast.ParamDecl{param("id", "string")}, Async: true},
+		{Name: "Gone"},
+	}}}})
+	code := specOf("A", ast.ComponentBody{Provides: []ast.InterfaceDecl{{Name: "Api", Methods: []ast.MethodDecl{
+		{Name: "Get", Params: []ast.ParamDecl{param("key", "int")}, ReturnType: &nullableStr, Throws: []string{"Error"}},
+		{Name: "Put", Params: []ast.ParamDecl{param("id", "string")}},
+		{Name: "Del", Params: []ast.ParamDecl{param("id", "string")}},
+		{Name: "New", ReturnType: &anyType},
+	}}}})
+	assertDrift(t, verify(t, NewVerifier(), spec, code),
+		"drift-mismatch Api.Get(id)",
+		"drift-mismatch Api.Get",
+		"drift-mismatch Api.Get",
+		"drift-mismatch Api.Put",
+		"drift-mismatch Api.Put",
+		"drift-mismatch Api.Del",
+		"drift-missing Api.Gone",
+		"drift-extra Api.New",
+	)
+}
+
+// VF005: 依存先は種類を区別せず、コンポーネント名の対応表で照合する
+func TestVerify_Dependencies(t *testing.T) {
+	spec := specOf("A", ast.ComponentBody{Relations: []ast.RelationDecl{
+		{Kind: ast.RelationDependsOn, Target: "PaymentGateway"},
+		{Kind: ast.RelationAggregates, Target: "Cache"},
+		{Kind: ast.RelationDependsOn, Target: "Mailer"},
+		{Kind: ast.RelationExtends, Target: "Base"},
+	}})
+	code := specOf("A", ast.ComponentBody{Relations: []ast.RelationDecl{
+		{Kind: ast.RelationDependsOn, Target: "Gateway"},
+		{Kind: ast.RelationContains, Target: "Cache"},
+		{Kind: ast.RelationDependsOn, Target: "Clock"},
+	}})
+	v := NewVerifier()
+	v.SetComponentNames(map[string][]string{"Gateway": {"PaymentGateway"}})
+	assertDrift(t, verify(t, v, spec, code),
+		"drift-missing Mailer",
+		"drift-extra Clock",
+	)
+}
+
+// VF006: コードから型を特定できない any とコンポーネント自身の struct
+func TestVerify_AnyAndComponentStruct(t *testing.T) {
+	spec := specOf("Service", ast.ComponentBody{Types: []ast.TypeDecl{
+		structType("Config",
+			field("handler", named("Handler"), ast.VisibilityPublic),
+			field("items", ast.TypeExpr{Name: "Item", Array: true}, ast.VisibilityPublic),
+			field("meta", named("Map"), ast.VisibilityPublic),
+		),
+	}})
+	code := specOf("Service", ast.ComponentBody{Types: []ast.TypeDecl{
+		structType("Service", field("repo", named("any"), ast.VisibilityPrivate)),
+		structType("Config",
+			field("handler", named("any"), ast.VisibilityPublic),
+			field("items", ast.TypeExpr{Name: "any", Array: true}, ast.VisibilityPublic),
+			field("meta", ast.TypeExpr{Name: "Map", TypeParams: []ast.TypeExpr{named("string"), named("int")}}, ast.VisibilityPublic),
+		),
+	}})
+	assertDrift(t, verify(t, NewVerifier(), spec, code))
+}
@@ -1,5 +1,7 @@
 package ast
 
+import "strings"
+
 // SpecFile は .pact ファイル全体を表す
 type SpecFile struct {
 	Path        string
@@ -9,6 +11,14 @@ type SpecFile struct {
 	Interfaces  []InterfaceDecl  // ファイルレベルのインターフェース
 	Types       []TypeDecl       // ファイルレベルの型定義
 	Annotations []AnnotationDecl // ファイルレベルのアノテーション
+	Comments    []Comment        // ソース中のコメント（出現順）
+}
+
+// Comment はソース中のコメントを表す
+// Text は "//" や "/* */" を含む元の文字列
+type Comment struct {
+	Pos  Position
+	Text string
 }
 
 // ImportDecl は import 文を表す
@@ -18,12 +28,23 @@ type ImportDecl struct {
 	Alias *string
 }
 
+// SplitQualifiedName は "alias.Name" 形式の修飾名をエイリアスと名前に分割する
+// 修飾名でなければ ok は false になる
+func SplitQualifiedName(name string) (qualifier, member string, ok bool) {
+	i := strings.IndexByte(name, '.')
+	if i <= 0 || i == len(name)-1 {
+		return "", name, false
+	}
+	return name[:i], name[i+1:], true
+}
+
 // ComponentDecl は component 宣言を表す
 type ComponentDecl struct {
 	Pos         Position
 	Name        string
 	Annotations []AnnotationDecl
 	Body        ComponentBody
+	End         Position // 閉じ括弧 } の位置
 }
 
 // ComponentBody は component の中身を表す
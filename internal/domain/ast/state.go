@@ -10,6 +10,7 @@ type StatesDecl struct {
 	States      []StateDecl
 	Transitions []TransitionDecl
 	Parallels   []ParallelDecl
+	End         Position // 閉じ括弧 } の位置
 }
 
 // StateDecl は状態定義を表す
@@ -23,6 +24,7 @@ type StateDecl struct {
 	Initial     *string
 	States      []StateDecl
 	Transitions []TransitionDecl
+	End         Position // 閉じ括弧 } の位置
 }
 
 // TransitionDecl は状態遷移定義を表す
@@ -80,6 +82,7 @@ type ParallelDecl struct {
 	Name        string
 	Annotations []AnnotationDecl
 	Regions     []RegionDecl
+	End         Position // 閉じ括弧 } の位置
 }
 
 // RegionDecl はリージョンを表す
@@ -89,4 +92,5 @@ type RegionDecl struct {
 	Initial     string
 	States      []StateDecl
 	Transitions []TransitionDecl
+	End         Position // 閉じ括弧 } の位置
 }
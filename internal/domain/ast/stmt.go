@@ -6,6 +6,7 @@ type FlowDecl struct {
 	Name        string
 	Annotations []AnnotationDecl
 	Steps       []Step
+	End         Position // 閉じ括弧 } の位置
 }
 
 // Step はフローのステップを表すインターフェース
@@ -63,6 +64,8 @@ type IfStep struct {
 	Then        []Step
 	Else        []Step
 	Annotations []AnnotationDecl
+	ThenEnd     Position // then ブロックの閉じ括弧 } の位置
+	ElseEnd     Position // else ブロックの閉じ括弧 } の位置
 }
 
 func (s *IfStep) stepNode()        {}
@@ -75,6 +78,7 @@ type ForStep struct {
 	Iterable    Expr
 	Body        []Step
 	Annotations []AnnotationDecl
+	End         Position // 閉じ括弧 } の位置
 }
 
 func (s *ForStep) stepNode()        {}
@@ -86,6 +90,7 @@ type WhileStep struct {
 	Condition   Expr
 	Body        []Step
 	Annotations []AnnotationDecl
+	End         Position // 閉じ括弧 } の位置
 }
 
 func (s *WhileStep) stepNode()        {}
@@ -9,6 +9,8 @@ type TypeDecl struct {
 	Fields      []FieldDecl // struct の場合
 	Values      []string    // enum の場合
 	BaseType    *TypeExpr   // alias の場合
+	ValuePos    []Position  // enum の値の位置（Values と同じ順）
+	End         Position    // struct・enum の閉じ括弧 } の位置
 }
 
 type TypeKind string
@@ -72,6 +74,7 @@ type InterfaceDecl struct {
 	Name        string
 	Annotations []AnnotationDecl
 	Methods     []MethodDecl
+	End         Position // 閉じ括弧 } の位置
 }
 
 // MethodDecl はメソッド定義を表す
@@ -10,6 +10,26 @@ type Config struct {
 	Language   string   `yaml:"language"`
 	Diagrams   []string `yaml:"diagrams"`
 	Exclude    []string `yaml:"exclude"`
+	// CoverageThreshold は check --missing で要求するカバレッジ（0〜100 の百分率）
+	CoverageThreshold float64 `yaml:"coverage_threshold"`
+	// Codegen は pact codegen の設定
+	Codegen CodegenConfig `yaml:"codegen,omitempty"`
+	// Rules は check --rules で検査する依存関係のルール
+	Rules []Rule `yaml:"rules,omitempty"`
+}
+
+// CodegenConfig はコード生成の設定を表す
+type CodegenConfig struct {
+	TypeScript TypeScriptConfig `yaml:"typescript,omitempty"`
+}
+
+// TypeScriptConfig は TypeScript の生成設定を表す
+type TypeScriptConfig struct {
+	// Types は .pact の型名から TypeScript の型への対応表（既定の対応を上書きする）
+	// 例: datetime: Date
+	Types map[string]string `yaml:"types,omitempty"`
+	// Declaration が true なら .ts ではなく .d.ts を出力する
+	Declaration bool `yaml:"declaration,omitempty"`
 }
 
 // Default はデフォルト設定を返す
@@ -35,17 +55,7 @@ func (c *Config) DiagramEnabled(diagramType string) bool {
 }
 
 // IsExcluded は指定したパスが除外対象かどうかを返す
+// パス自身だけでなく親ディレクトリがパターンに一致する場合も除外する
 func (c *Config) IsExcluded(path string) bool {
-	for _, pattern := range c.Exclude {
-		matched, err := filepath.Match(pattern, path)
-		if err == nil && matched {
-			return true
-		}
-		// ディレクトリ名でのマッチも試みる
-		matched, err = filepath.Match(pattern, filepath.Base(path))
-		if err == nil && matched {
-			return true
-		}
-	}
-	return false
+	return MatchAny(c.Exclude, filepath.ToSlash(path))
 }
@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // =============================================================================
 // DC001-DC005: デフォルト設定
@@ -142,3 +145,116 @@ func TestConfig_IsExcluded(t *testing.T) {
 		})
 	}
 }
+
+// =============================================================================
+// DC010-DC011: パターンマッチ
+// =============================================================================
+
+// DC010: "**" は0個以上のディレクトリに一致する
+func TestMatchPattern_DoubleStar(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"**/*.pact", "a.pact", true},
+		{"**/*.pact", "a/b/c.pact", true},
+		{"specs/**/*.pact", "specs/billing/invoice.pact", true},
+		{"specs/**/*.pact", "docs/invoice.pact", false},
+		{"specs/**", "specs/a/b.pact", true},
+		{"*.pact", "a/b.pact", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchPattern(tt.pattern, tt.path); got != tt.expected {
+			t.Errorf("MatchPattern(%q, %q) = %v, expected %v", tt.pattern, tt.path, got, tt.expected)
+		}
+	}
+}
+
+// DC011: 親ディレクトリが除外パターンに一致すれば配下も除外する
+func TestConfig_IsExcluded_Parent(t *testing.T) {
+	cfg := &Config{Exclude: []string{"drafts", "legacy/**/*_old.pact"}}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"drafts/order.pact", true},
+		{"billing/drafts/order.pact", true},
+		{"legacy/v1/invoice_old.pact", true},
+		{"legacy/v1/invoice.pact", false},
+		{"billing/order.pact", false},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.IsExcluded(tt.path); got != tt.expected {
+			t.Errorf("IsExcluded(%q) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
+
+// =============================================================================
+// DC012-DC013: 依存関係のルール
+// =============================================================================
+
+// DC012: アノテーションの指定を名前と値に分ける
+func TestSelector_AnnotationPattern(t *testing.T) {
+	tests := []struct {
+		annotation string
+		name       string
+		value      string // 空なら値なし
+		valid      bool
+	}{
+		{"layer", "layer", "", true},
+		{`@layer("api")`, "layer", "api", true},
+		{"layer(domain)", "layer", "domain", true},
+		{`layer("api"`, "", "", false},
+		{"@", "", "", false},
+	}
+
+	for _, tt := range tests {
+		name, value, err := Selector{Annotation: tt.annotation}.AnnotationPattern()
+		if (err == nil) != tt.valid {
+			t.Errorf("AnnotationPattern(%q) error = %v, expected valid %v", tt.annotation, err, tt.valid)
+			continue
+		}
+		if !tt.valid {
+			continue
+		}
+		got := ""
+		if value != nil {
+			got = *value
+		}
+		if name != tt.name || got != tt.value {
+			t.Errorf("AnnotationPattern(%q) = %q, %q, expected %q, %q", tt.annotation, name, got, tt.name, tt.value)
+		}
+	}
+}
+
+// DC013: ルールの書式の検査
+func TestConfig_ValidateRules(t *testing.T) {
+	valid := Rule{Name: "r", Forbid: []Selector{{Name: "*Repository"}}}
+	tests := []struct {
+		name  string
+		rules []Rule
+		err   string // 空なら成功
+	}{
+		{"valid", []Rule{valid}, ""},
+		{"no name", []Rule{{Forbid: valid.Forbid}}, "name is required"},
+		{"duplicate", []Rule{valid, valid}, "duplicate name"},
+		{"no condition", []Rule{{Name: "r"}}, "one of forbid, allow or allow_from is required"},
+		{"bad pattern", []Rule{{Name: "r", Allow: []Selector{{Name: "["}}}}, "invalid name pattern"},
+		{"bad relation", []Rule{{Name: "r", Forbid: valid.Forbid, Relations: []string{"uses"}}}, "unknown relation"},
+	}
+
+	for _, tt := range tests {
+		err := (&Config{Rules: tt.rules}).ValidateRules()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.err, err)
+		}
+	}
+}
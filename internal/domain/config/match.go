@@ -0,0 +1,67 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchPattern はスラッシュ区切りのパスがパターンに一致するかを返す
+// 各要素は path.Match で比較し、"**" は0個以上のディレクトリに一致する
+func MatchPattern(pattern, name string) bool {
+	return matchSegments(splitPattern(pattern), strings.Split(name, "/"))
+}
+
+// MatchAny はパスまたはその親ディレクトリがいずれかのパターンに一致するかを返す
+// "/" を含まないパターンはファイル名・ディレクトリ名だけでも比較する
+func MatchAny(patterns []string, name string) bool {
+	segments := strings.Split(strings.TrimPrefix(name, "./"), "/")
+	for _, pattern := range patterns {
+		pats := splitPattern(pattern)
+		if len(pats) == 0 {
+			continue
+		}
+		for i := 1; i <= len(segments); i++ {
+			if matchSegments(pats, segments[:i]) {
+				return true
+			}
+			if len(pats) == 1 && matchSegments(pats, segments[i-1:i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitPattern はパターンを要素に分割する（先頭の "./" と末尾の "/" は無視する）
+func splitPattern(pattern string) []string {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(pattern), "./"), "/")
+	if pattern == "" {
+		return nil
+	}
+	return strings.Split(pattern, "/")
+}
+
+func matchSegments(pats, segments []string) bool {
+	for len(pats) > 0 {
+		if pats[0] == "**" {
+			pats = pats[1:]
+			if len(pats) == 0 {
+				return true
+			}
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pats, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pats[0], segments[0]); err != nil || !matched {
+			return false
+		}
+		pats, segments = pats[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
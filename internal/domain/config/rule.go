@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Rule は依存関係のルールを表す
+// From・To・Relations に一致する関係を対象に、Forbid・Allow・AllowFrom で違反を判定する
+type Rule struct {
+	Name string `yaml:"name"`
+	// Message は違反の報告に添える説明
+	Message string `yaml:"message,omitempty"`
+	// From・To は対象とする関係の依存元・依存先（省略時はすべて）
+	From Selector `yaml:"from,omitempty"`
+	To   Selector `yaml:"to,omitempty"`
+	// Relations は対象とする関係の種類（depends_on・extends など。省略時はすべて）
+	Relations []string `yaml:"relations,omitempty"`
+	// Forbid はいずれかに一致する依存先を違反とする
+	Forbid []Selector `yaml:"forbid,omitempty"`
+	// Allow はどれにも一致しない依存先を違反とする
+	Allow []Selector `yaml:"allow,omitempty"`
+	// AllowFrom はどれにも一致しない依存元を違反とする
+	AllowFrom []Selector `yaml:"allow_from,omitempty"`
+}
+
+// Selector はコンポーネントの選び方を表す（指定した条件をすべて満たすものに一致する）
+type Selector struct {
+	// Annotation はアノテーション（例: layer, @layer("api")）
+	Annotation string `yaml:"annotation,omitempty"`
+	// Path は宣言した仕様の pact_root からのパスのパターン（exclude と同じ形式）
+	Path string `yaml:"path,omitempty"`
+	// Name はコンポーネント名のパターン（path.Match の形式）
+	Name string `yaml:"name,omitempty"`
+	// TargetType は depends on の対象の種類（例: external）。依存先にだけ一致する
+	TargetType string `yaml:"target_type,omitempty"`
+}
+
+// IsZero は条件が1つもない（すべてに一致する）かどうかを返す
+func (s Selector) IsZero() bool {
+	return s == Selector{}
+}
+
+// AnnotationPattern は Annotation をアノテーション名と引数の値に分ける（引数がなければ value は nil）
+func (s Selector) AnnotationPattern() (name string, value *string, err error) {
+	text := strings.TrimPrefix(strings.TrimSpace(s.Annotation), "@")
+	open := strings.IndexByte(text, '(')
+	if open < 0 {
+		name = text
+	} else {
+		if !strings.HasSuffix(text, ")") {
+			return "", nil, fmt.Errorf("invalid annotation %q", s.Annotation)
+		}
+		name = text[:open]
+		v := strings.Trim(strings.TrimSpace(text[open+1:len(text)-1]), `"`)
+		value = &v
+	}
+	if name == "" || strings.ContainsAny(name, " ()\"") {
+		return "", nil, fmt.Errorf("invalid annotation %q", s.Annotation)
+	}
+	return name, value, nil
+}
+
+// validate は Selector の書式を検査する
+func (s Selector) validate() error {
+	if s.Annotation != "" {
+		if _, _, err := s.AnnotationPattern(); err != nil {
+			return err
+		}
+	}
+	if _, err := path.Match(s.Name, ""); err != nil {
+		return fmt.Errorf("invalid name pattern %q", s.Name)
+	}
+	for _, segment := range splitPattern(s.Path) {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid path pattern %q", s.Path)
+		}
+	}
+	return nil
+}
+
+// ValidateRules はルールの書式を検査する
+func (c *Config) ValidateRules() error {
+	seen := make(map[string]bool)
+	for i, rule := range c.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rules[%d]: name is required", i)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("rule %q: duplicate name", rule.Name)
+		}
+		seen[rule.Name] = true
+		if len(rule.Forbid) == 0 && len(rule.Allow) == 0 && len(rule.AllowFrom) == 0 {
+			return fmt.Errorf("rule %q: one of forbid, allow or allow_from is required", rule.Name)
+		}
+		selectors := append([]Selector{rule.From, rule.To}, rule.Forbid...)
+		selectors = append(selectors, rule.Allow...)
+		selectors = append(selectors, rule.AllowFrom...)
+		for _, s := range selectors {
+			if err := s.validate(); err != nil {
+				return fmt.Errorf("rule %q: %v", rule.Name, err)
+			}
+		}
+		for _, kind := range rule.Relations {
+			if !relationKinds[kind] {
+				return fmt.Errorf("rule %q: unknown relation %q", rule.Name, kind)
+			}
+		}
+	}
+	return nil
+}
+
+// relationKinds は Relations に書ける関係の種類
+var relationKinds = map[string]bool{
+	"depends_on": true,
+	"extends":    true,
+	"implements": true,
+	"contains":   true,
+	"aggregates": true,
+}
@@ -21,6 +21,8 @@ type Node struct {
 	Attributes  []Attribute
 	Methods     []Method
 	Annotations []common.Annotation
+	Highlighted bool          // 強調表示する（依存の循環など）
+	Change      common.Change // 差分の図での変更
 }
 
 // Attribute はクラスの属性
@@ -28,6 +30,7 @@ type Attribute struct {
 	Name       string
 	Type       string
 	Visibility Visibility
+	Change     common.Change // 差分の図での変更
 }
 
 // Method はクラスのメソッド
@@ -37,7 +40,8 @@ type Method struct {
 	ReturnType string
 	Visibility Visibility
 	Async      bool
-	Throws     []string // 例外リスト
+	Throws     []string      // 例外リスト
+	Change     common.Change // 差分の図での変更
 }
 
 // Param はメソッドのパラメータ
@@ -58,12 +62,14 @@ const (
 
 // Edge はクラス図のエッジ
 type Edge struct {
-	From       string
-	To         string
-	Type       EdgeType
-	Label      string
-	Decoration Decoration
-	LineStyle  LineStyle
+	From        string
+	To          string
+	Type        EdgeType
+	Label       string
+	Decoration  Decoration
+	LineStyle   LineStyle
+	Highlighted bool          // 強調表示する（依存の循環など）
+	Change      common.Change // 差分の図での変更
 }
 
 // EdgeType はエッジの種類
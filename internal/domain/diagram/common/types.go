@@ -38,3 +38,12 @@ const (
 	NotePositionBottom NotePosition = "bottom"
 	NotePositionOver   NotePosition = "over" // シーケンス図用
 )
+
+// Change は2つの版を比較した図（差分の図）での要素の変更（変更がなければ空）
+type Change string
+
+const (
+	ChangeAdded   Change = "added"
+	ChangeRemoved Change = "removed"
+	ChangeChanged Change = "changed"
+)
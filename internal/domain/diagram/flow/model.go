@@ -20,6 +20,7 @@ type Node struct {
 	Label    string
 	Shape    NodeShape
 	Swimlane string
+	Change   common.Change // 差分の図での変更
 }
 
 // NodeShape はノードの形状
@@ -36,9 +37,10 @@ const (
 
 // Edge はフローチャートのエッジ
 type Edge struct {
-	From  string
-	To    string
-	Label string
+	From   string
+	To     string
+	Label  string
+	Change common.Change // 差分の図での変更
 }
 
 // Swimlane はスイムレーン
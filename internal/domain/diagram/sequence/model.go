@@ -15,9 +15,10 @@ func (d *Diagram) Type() common.DiagramType {
 
 // Participant は参加者
 type Participant struct {
-	ID   string
-	Name string
-	Type ParticipantType
+	ID     string
+	Name   string
+	Type   ParticipantType
+	Change common.Change // 差分の図での変更
 }
 
 // ParticipantType は参加者の種類
@@ -42,6 +43,7 @@ type MessageEvent struct {
 	To          string
 	Label       string
 	MessageType MessageType
+	Change      common.Change // 差分の図での変更
 }
 
 func (e *MessageEvent) eventNode() {}
@@ -59,9 +61,10 @@ const (
 type FragmentEvent struct {
 	Type      FragmentType
 	Label     string
-	Events    []Event // メイン(then)部分のイベント
-	AltLabel  string  // alt の else 部分用ラベル
-	AltEvents []Event // alt の else 部分のイベント
+	Events    []Event       // メイン(then)部分のイベント
+	AltLabel  string        // alt の else 部分用ラベル
+	AltEvents []Event       // alt の else 部分のイベント
+	Change    common.Change // 差分の図での変更
 }
 
 func (e *FragmentEvent) eventNode() {}
@@ -85,9 +88,10 @@ func (e *ActivationEvent) eventNode() {}
 
 // NoteEvent はシーケンス図内の注釈イベント
 type NoteEvent struct {
-	Participant string   // 注釈を付ける参加者（空の場合は全体）
-	Text        string   // 注釈テキスト
-	NoteType    NoteType // 注釈の種類
+	Participant string        // 注釈を付ける参加者（空の場合は全体）
+	Text        string        // 注釈テキスト
+	NoteType    NoteType      // 注釈の種類
+	Change      common.Change // 差分の図での変更
 }
 
 func (e *NoteEvent) eventNode() {}
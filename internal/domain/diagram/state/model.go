@@ -23,6 +23,8 @@ type State struct {
 	Children    []State  // 階層状態の場合
 	Regions     []Region // 並行状態の場合
 	Annotations []common.Annotation
+	Highlighted bool          // シミュレーションで通った状態
+	Change      common.Change // 差分の図での変更
 }
 
 // StateType は状態の種類
@@ -45,11 +47,13 @@ type Region struct {
 
 // Transition は状態遷移
 type Transition struct {
-	From    string
-	To      string
-	Trigger Trigger
-	Guard   string
-	Actions []string
+	From        string
+	To          string
+	Trigger     Trigger
+	Guard       string
+	Actions     []string
+	Highlighted bool          // シミュレーションで実行された遷移
+	Change      common.Change // 差分の図での変更
 }
 
 // Trigger はトリガー
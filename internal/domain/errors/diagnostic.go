@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"fmt"
+
+	"pact/internal/domain/ast"
+)
+
+// Severity は診断の重大度
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// 診断のルールコード（Warning は自身の Code を使う）
+const (
+	CodeSyntax           = "syntax-error"
+	CodeSemantic         = "semantic-error"
+	CodeImport           = "import-error"
+	CodeImportCycle      = "import-cycle"
+	CodeConfig           = "config-error"
+	CodeEmptyDeclaration = "empty-declaration"
+	CodeMissingComponent = "missing-component"
+	CodeDependencyCycle  = "dependency-cycle" // コンポーネントの関係の循環
+	CodeDependencyRule   = "dependency-rule"  // 設定の依存関係のルールへの違反
+	CodeMissingSpec      = "missing-spec"
+	CodeOrphanedSpec     = "orphaned-spec"
+	CodeCoverage         = "coverage-threshold"
+	CodeDriftMissing     = "drift-missing"  // 仕様にあってコードにない
+	CodeDriftExtra       = "drift-extra"    // コードにあって仕様にない
+	CodeDriftMismatch    = "drift-mismatch" // 仕様とコードで食い違う
+
+	// 状態機械の検査
+	CodeUnreachableState     = "unreachable-state"           // initial から到達できない状態
+	CodeDeadlockState        = "deadlock-state"              // 抜ける遷移のない終了状態以外の状態
+	CodeFinalTransition      = "final-transition"            // 終了状態から出る遷移
+	CodeNondeterministic     = "nondeterministic-transition" // 同じ遷移元・イベントでガードがない・重なる遷移
+	CodeMissingInitial       = "missing-initial"             // initial のない複合状態・リージョン
+	CodeParallelIncompletion = "parallel-never-completes"    // 完了できないリージョンを持つ並行状態
+
+	// フローの検査
+	CodeUnassignedVariable = "unassigned-variable" // 代入される前に読まれる変数
+	CodeUnusedAssignment   = "unused-assignment"   // 一度も読まれない代入
+	CodeShadowedVariable   = "shadowed-variable"   // 外側の変数を隠すループ変数
+	CodeTypeMismatch       = "type-mismatch"       // 宣言された型と食い違う値
+	CodeUnknownMethod      = "unknown-method"      // 依存先が provides しないメソッドの呼び出し
+	CodeArgumentCount      = "argument-count"      // メソッドのパラメータと数の合わない引数
+	CodeUnknownField       = "unknown-field"       // 型にないフィールドへのアクセス
+	CodeUndeclaredThrow    = "undeclared-throw"    // メソッドが宣言していない例外の送出
+	CodeUnraisedThrow      = "unraised-throw"      // どの経路でも送出されない宣言済みの例外
+)
+
+// Diagnostic はファイル位置・ルールコード・重大度を持つ診断結果を表す
+type Diagnostic struct {
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// NewDiagnostic はエラーを file に紐づく診断結果に変換する
+func NewDiagnostic(file string, err error) Diagnostic {
+	d := Diagnostic{File: file, Severity: SeverityError, Message: err.Error()}
+
+	var pos ast.Position
+	switch e := err.(type) {
+	case *ParseError:
+		pos, d.Code, d.Message = e.Pos, CodeSyntax, e.Message
+	case *SemanticError:
+		pos, d.Code, d.Message = e.Pos, CodeSemantic, e.Message
+	case *ImportError:
+		pos, d.Code = e.Pos, CodeImport
+		d.Message = fmt.Sprintf("import %q: %s", e.Path, e.Message)
+		if e.Cause != nil {
+			d.Message += fmt.Sprintf(" (caused by: %v)", e.Cause)
+		}
+	case *CycleError:
+		d.Code = CodeImportCycle
+	case *ConfigError:
+		d.Code, d.Message = CodeConfig, e.Message
+		if d.File == "" {
+			d.File = e.Path
+		}
+	case *ValidationError:
+		pos, d.Code = e.Pos, e.Type
+		d.Message = fmt.Sprintf("'%s': %s", e.Name, e.Message)
+		// 空宣言の検出は Type "warning" で報告される
+		if e.Type == "warning" {
+			d.Code, d.Severity = CodeEmptyDeclaration, SeverityWarning
+		}
+	case *Warning:
+		pos, d.Code, d.Severity, d.Message = e.Pos, e.Code, SeverityWarning, e.Message
+	default:
+		d.Code = CodeSemantic
+	}
+
+	if pos.File != "" && d.File == "" {
+		d.File = pos.File
+	}
+	d.Line, d.Column = pos.Line, pos.Column
+	return d
+}
+
+// String は "file:line:col: severity[code]: message" 形式の文字列を返す
+func (d Diagnostic) String() string {
+	loc := d.File
+	if d.Line > 0 {
+		loc = fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	}
+	if loc == "" {
+		return fmt.Sprintf("%s[%s]: %s", d.Severity, d.Code, d.Message)
+	}
+	return fmt.Sprintf("%s: %s[%s]: %s", loc, d.Severity, d.Code, d.Message)
+}
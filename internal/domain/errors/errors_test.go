@@ -168,3 +168,57 @@ func TestConfigError_Error_NoPath(t *testing.T) {
 		t.Errorf("expected just message, got %q", result)
 	}
 }
+
+// =============================================================================
+// DE007-DE009: Diagnostic Tests
+// =============================================================================
+
+// DE007: エラー種別ごとのコードと重大度
+func TestNewDiagnostic_Codes(t *testing.T) {
+	pos := ast.Position{Line: 3, Column: 7}
+	tests := []struct {
+		err      error
+		code     string
+		severity Severity
+	}{
+		{&ParseError{Pos: pos, Message: "unexpected token"}, CodeSyntax, SeverityError},
+		{&SemanticError{Pos: pos, Message: "bad"}, CodeSemantic, SeverityError},
+		{&ImportError{Pos: pos, Path: "./x.pact", Message: "file not found"}, CodeImport, SeverityError},
+		{&CycleError{Cycle: []string{"a.pact"}}, CodeImportCycle, SeverityError},
+		{&ValidationError{Pos: pos, Type: "undefined", Name: "Foo", Message: "not defined"}, "undefined", SeverityError},
+		{&ValidationError{Pos: pos, Type: "warning", Name: "E", Message: "empty enum declaration"}, CodeEmptyDeclaration, SeverityWarning},
+		{&Warning{Pos: pos, Code: "unused-type", Message: "unused"}, "unused-type", SeverityWarning},
+	}
+
+	for _, tt := range tests {
+		d := NewDiagnostic("a.pact", tt.err)
+		if d.Code != tt.code || d.Severity != tt.severity {
+			t.Errorf("%T: expected %s/%s, got %s/%s", tt.err, tt.code, tt.severity, d.Code, d.Severity)
+		}
+		if d.File != "a.pact" {
+			t.Errorf("%T: expected file a.pact, got %q", tt.err, d.File)
+		}
+	}
+}
+
+// DE008: 位置とメッセージ（位置の重複を含まない）
+func TestNewDiagnostic_Position(t *testing.T) {
+	d := NewDiagnostic("a.pact", &ParseError{Pos: ast.Position{Line: 3, Column: 7}, Message: "unexpected token"})
+	if d.Line != 3 || d.Column != 7 {
+		t.Errorf("expected 3:7, got %d:%d", d.Line, d.Column)
+	}
+	if d.Message != "unexpected token" {
+		t.Errorf("unexpected message %q", d.Message)
+	}
+	if d.String() != "a.pact:3:7: error[syntax-error]: unexpected token" {
+		t.Errorf("unexpected string %q", d.String())
+	}
+}
+
+// DE009: 位置情報のない診断
+func TestDiagnostic_String_NoPosition(t *testing.T) {
+	d := NewDiagnostic("", &ConfigError{Message: "project root not found"})
+	if d.String() != "error[config-error]: project root not found" {
+		t.Errorf("unexpected string %q", d.String())
+	}
+}
@@ -0,0 +1,303 @@
+package statechart
+
+import (
+	"time"
+
+	"pact/internal/domain/ast"
+)
+
+// Kind は状態の種類を表す
+type Kind string
+
+const (
+	KindAtomic   Kind = "atomic"
+	KindCompound Kind = "compound"
+	KindParallel Kind = "parallel"
+	KindRegion   Kind = "region"
+	KindFinal    Kind = "final"
+)
+
+// State は状態階層の中の一つの状態を表す
+// 並行状態の子はリージョン、リージョンの子はその中の状態になる
+type State struct {
+	Pos         ast.Position
+	Name        string
+	Kind        Kind
+	Parent      *State // 最上位の状態ではルート
+	Children    []*State
+	Initial     *State // 複合状態・リージョン・ルートの初期状態
+	Entry       []string
+	Exit        []string
+	Transitions []*Transition // この状態を遷移元とする遷移（宣言順）
+	Depth       int           // ルートの子が 1
+	Declared    bool          // state・parallel・region で宣言されたか（遷移などから暗黙に作られた状態は false）
+}
+
+// IsRoot はルート（states ブロック自体）かどうかを返す
+func (s *State) IsRoot() bool {
+	return s.Parent == nil
+}
+
+// IsAtomic は子を持たない状態かどうかを返す
+func (s *State) IsAtomic() bool {
+	return len(s.Children) == 0
+}
+
+// DefaultChild は状態に入ったときに既定で入る子を返す
+// initial がなければ最初の子を使う（子がなければ nil）
+func (s *State) DefaultChild() *State {
+	if s.Initial != nil {
+		return s.Initial
+	}
+	if len(s.Children) > 0 && s.Kind != KindParallel {
+		return s.Children[0]
+	}
+	return nil
+}
+
+// IsAncestorOf は s が other の真の祖先かどうかを返す
+func (s *State) IsAncestorOf(other *State) bool {
+	for p := other.Parent; p != nil; p = p.Parent {
+		if p == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Path はルートの子から s までの状態名を "." で連結した文字列を返す
+func (s *State) Path() string {
+	if s.Parent == nil || s.Parent.IsRoot() {
+		return s.Name
+	}
+	return s.Parent.Path() + "." + s.Name
+}
+
+// Transition は解決済みの状態遷移を表す
+type Transition struct {
+	Pos       ast.Position
+	Source    *State
+	Target    *State
+	Event     string        // on E
+	After     time.Duration // after 5m
+	Condition ast.Expr      // when cond（トリガーとしての条件）
+	Guard     ast.Expr      // on E when guard
+	Actions   []string
+}
+
+// Eventless はイベントにも時間にもよらない遷移（when またはトリガーなし）かどうかを返す
+func (t *Transition) Eventless() bool {
+	return t.Event == "" && t.After == 0
+}
+
+// Domain は遷移で抜けて入り直す範囲の根になる状態を返す
+// 遷移元と遷移先の共通の真の祖先のうち、並行状態でない最も近いもの（自己遷移では親）
+func (t *Transition) Domain() *State {
+	for a := t.Source.Parent; a != nil; a = a.Parent {
+		if a.Kind != KindParallel && (a.IsRoot() || a.IsAncestorOf(t.Target)) {
+			return a
+		}
+	}
+	return nil
+}
+
+// Chart は states ブロックを状態の木と遷移に解決したもの
+type Chart struct {
+	Name        string
+	Root        *State
+	States      []*State      // ルートを除く全状態（文書順）
+	Transitions []*Transition // 全遷移（最上位の遷移、入れ子のブロックの遷移の順）
+	byName      map[string]*State
+}
+
+// State は名前で状態を探す（見つからなければ nil）
+func (c *Chart) State(name string) *State {
+	return c.byName[name]
+}
+
+// Events は遷移のイベント名を初出順に返す
+func (c *Chart) Events() []string {
+	var events []string
+	seen := make(map[string]bool)
+	for _, t := range c.Transitions {
+		if t.Event != "" && !seen[t.Event] {
+			seen[t.Event] = true
+			events = append(events, t.Event)
+		}
+	}
+	return events
+}
+
+// Actions は entry・exit・遷移のアクション名を初出順に返す
+func (c *Chart) Actions() []string {
+	var actions []string
+	seen := make(map[string]bool)
+	add := func(names []string) {
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				actions = append(actions, name)
+			}
+		}
+	}
+	for _, s := range c.States {
+		add(s.Entry)
+		add(s.Exit)
+	}
+	for _, t := range c.Transitions {
+		add(t.Actions)
+	}
+	return actions
+}
+
+// Duration は .pact の期間を time.Duration にする
+func Duration(d ast.Duration) time.Duration {
+	unit := map[string]time.Duration{
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+		"d":  24 * time.Hour,
+	}[d.Unit]
+	return time.Duration(d.Value) * unit
+}
+
+// New は states ブロックから状態の木を組み立てる
+//
+// 宣言のない状態名（遷移や initial・final にだけ現れる名前）は、それが書かれた
+// ブロックの子として暗黙に作る。同名の状態が複数ある場合は最初の宣言を使う
+func New(decl *ast.StatesDecl) *Chart {
+	c := &Chart{
+		Name:   decl.Name,
+		Root:   &State{Pos: decl.Pos, Name: decl.Name, Kind: KindCompound, Declared: true},
+		byName: make(map[string]*State),
+	}
+	b := &builder{chart: c}
+
+	// 1. 宣言された状態で木を作る
+	for i := range decl.States {
+		b.declare(c.Root, &decl.States[i])
+	}
+	for i := range decl.Parallels {
+		b.declareParallel(c.Root, &decl.Parallels[i])
+	}
+
+	// 2. 初期状態・終了状態・遷移の名前を解決する
+	for _, name := range decl.Finals {
+		s := b.resolve(c.Root, name, decl.Pos)
+		if s.IsAtomic() && s.Kind != KindParallel {
+			s.Kind = KindFinal
+		}
+	}
+	if decl.Initial != "" {
+		c.Root.Initial = b.resolve(c.Root, decl.Initial, decl.Pos)
+	}
+	for _, pending := range b.pending {
+		pending()
+	}
+	for i := range decl.Transitions {
+		b.transition(c.Root, &decl.Transitions[i])
+	}
+	for _, scoped := range b.scoped {
+		scoped()
+	}
+
+	c.States = nil
+	b.collect(c.Root)
+	return c
+}
+
+type builder struct {
+	chart   *Chart
+	pending []func() // 入れ子の initial の解決
+	scoped  []func() // 入れ子の遷移の解決
+}
+
+func (b *builder) add(parent *State, s *State) *State {
+	s.Parent = parent
+	s.Depth = parent.Depth + 1
+	parent.Children = append(parent.Children, s)
+	if _, exists := b.chart.byName[s.Name]; !exists {
+		b.chart.byName[s.Name] = s
+	}
+	return s
+}
+
+func (b *builder) declare(parent *State, decl *ast.StateDecl) {
+	if existing := b.chart.byName[decl.Name]; existing != nil && existing.Parent == parent {
+		return
+	}
+	s := b.add(parent, &State{Pos: decl.Pos, Name: decl.Name, Kind: KindAtomic, Entry: decl.Entry, Exit: decl.Exit, Declared: true})
+	for i := range decl.States {
+		b.declare(s, &decl.States[i])
+	}
+	if len(s.Children) > 0 || decl.Initial != nil || len(decl.Transitions) > 0 {
+		s.Kind = KindCompound
+	}
+	if decl.Initial != nil {
+		initial := *decl.Initial
+		b.pending = append(b.pending, func() { s.Initial = b.resolve(s, initial, decl.Pos) })
+	}
+	for i := range decl.Transitions {
+		trans := &decl.Transitions[i]
+		b.scoped = append(b.scoped, func() { b.transition(s, trans) })
+	}
+}
+
+func (b *builder) declareParallel(parent *State, decl *ast.ParallelDecl) {
+	p := b.add(parent, &State{Pos: decl.Pos, Name: decl.Name, Kind: KindParallel, Declared: true})
+	for i := range decl.Regions {
+		region := &decl.Regions[i]
+		r := b.add(p, &State{Pos: region.Pos, Name: region.Name, Kind: KindRegion, Declared: true})
+		for j := range region.States {
+			b.declare(r, &region.States[j])
+		}
+		if region.Initial != "" {
+			initial := region.Initial
+			b.pending = append(b.pending, func() { r.Initial = b.resolve(r, initial, region.Pos) })
+		}
+		for j := range region.Transitions {
+			trans := &region.Transitions[j]
+			b.scoped = append(b.scoped, func() { b.transition(r, trans) })
+		}
+	}
+}
+
+// resolve は名前の状態を返す（なければ scope の子として暗黙に作る）
+func (b *builder) resolve(scope *State, name string, pos ast.Position) *State {
+	if s := b.chart.byName[name]; s != nil {
+		return s
+	}
+	if scope.Kind == KindAtomic {
+		scope.Kind = KindCompound
+	}
+	return b.add(scope, &State{Pos: pos, Name: name, Kind: KindAtomic})
+}
+
+func (b *builder) transition(scope *State, decl *ast.TransitionDecl) {
+	t := &Transition{
+		Pos:     decl.Pos,
+		Source:  b.resolve(scope, decl.From, decl.Pos),
+		Target:  b.resolve(scope, decl.To, decl.Pos),
+		Guard:   decl.Guard,
+		Actions: decl.Actions,
+	}
+	switch trigger := decl.Trigger.(type) {
+	case *ast.EventTrigger:
+		t.Event = trigger.Event
+	case *ast.AfterTrigger:
+		t.After = Duration(trigger.Duration)
+	case *ast.WhenTrigger:
+		t.Condition = trigger.Condition
+	}
+	t.Source.Transitions = append(t.Source.Transitions, t)
+	b.chart.Transitions = append(b.chart.Transitions, t)
+}
+
+// collect は状態を文書順（深さ優先）に並べる
+func (b *builder) collect(s *State) {
+	for _, child := range s.Children {
+		b.chart.States = append(b.chart.States, child)
+		b.collect(child)
+	}
+}
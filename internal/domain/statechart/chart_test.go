@@ -0,0 +1,143 @@
+package statechart
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pact/internal/domain/ast"
+)
+
+func on(from, to, event string) ast.TransitionDecl {
+	return ast.TransitionDecl{From: from, To: to, Trigger: &ast.EventTrigger{Event: event}}
+}
+
+func names(states []*State) string {
+	var parts []string
+	for _, s := range states {
+		parts = append(parts, s.Path()+":"+string(s.Kind))
+	}
+	return strings.Join(parts, " ")
+}
+
+// =============================================================================
+// ST001-ST004: 状態の木の組み立て
+// =============================================================================
+
+// ST001: 宣言のない状態は遷移が書かれたブロックの子として作られる
+func TestNew_ImplicitStates(t *testing.T) {
+	chart := New(&ast.StatesDecl{
+		Name:        "Order",
+		Initial:     "Pending",
+		Finals:      []string{"Done"},
+		States:      []ast.StateDecl{{Name: "Pending", Entry: []string{"create"}}},
+		Transitions: []ast.TransitionDecl{on("Pending", "Paid", "pay"), on("Paid", "Done", "ship")},
+	})
+
+	if got := names(chart.States); got != "Pending:atomic Done:final Paid:atomic" {
+		t.Errorf("unexpected states: %s", got)
+	}
+	if chart.Root.Initial != chart.State("Pending") {
+		t.Errorf("expected root initial to be Pending")
+	}
+	if chart.State("Paid").Declared || !chart.State("Pending").Declared {
+		t.Errorf("unexpected Declared flags")
+	}
+	if len(chart.State("Pending").Transitions) != 1 {
+		t.Errorf("expected Pending to own its transition")
+	}
+	if got := strings.Join(chart.Events(), ","); got != "pay,ship" {
+		t.Errorf("unexpected events: %s", got)
+	}
+}
+
+// ST002: 階層状態と入れ子の遷移
+func TestNew_Hierarchy(t *testing.T) {
+	running := "Running"
+	chart := New(&ast.StatesDecl{
+		Name:    "Job",
+		Initial: "Idle",
+		States: []ast.StateDecl{
+			{Name: "Idle"},
+			{
+				Name:        "Active",
+				Initial:     &running,
+				Exit:        []string{"cleanup"},
+				States:      []ast.StateDecl{{Name: "Running"}, {Name: "Paused"}},
+				Transitions: []ast.TransitionDecl{on("Running", "Paused", "pause")},
+			},
+		},
+		Transitions: []ast.TransitionDecl{
+			on("Idle", "Active", "start"),
+			{From: "Active", To: "Idle", Trigger: &ast.AfterTrigger{Duration: ast.Duration{Value: 5, Unit: "m"}}},
+		},
+	})
+
+	if got := names(chart.States); got != "Idle:atomic Active:compound Active.Running:atomic Active.Paused:atomic" {
+		t.Errorf("unexpected states: %s", got)
+	}
+	active := chart.State("Active")
+	if active.Initial != chart.State("Running") || active.DefaultChild() != chart.State("Running") {
+		t.Errorf("expected Active to start in Running")
+	}
+	if !active.IsAncestorOf(chart.State("Paused")) || active.IsAncestorOf(active) {
+		t.Errorf("unexpected IsAncestorOf result")
+	}
+	if after := active.Transitions[0].After; after != 5*time.Minute {
+		t.Errorf("unexpected after duration: %v", after)
+	}
+	if got := strings.Join(chart.Actions(), ","); got != "cleanup" {
+		t.Errorf("unexpected actions: %s", got)
+	}
+}
+
+// ST003: 並行状態の子はリージョンになる
+func TestNew_Parallel(t *testing.T) {
+	chart := New(&ast.StatesDecl{
+		Name:    "Checkout",
+		Initial: "Processing",
+		Parallels: []ast.ParallelDecl{{
+			Name: "Processing",
+			Regions: []ast.RegionDecl{
+				{Name: "Payment", Initial: "Authorizing", Transitions: []ast.TransitionDecl{on("Authorizing", "Charged", "charged")}},
+				{Name: "Shipping", Initial: "Packing"},
+			},
+		}},
+	})
+
+	if got := names(chart.States); got != "Processing:parallel Processing.Payment:region Processing.Payment.Authorizing:atomic Processing.Payment.Charged:atomic Processing.Shipping:region Processing.Shipping.Packing:atomic" {
+		t.Errorf("unexpected states: %s", got)
+	}
+	if chart.State("Processing").DefaultChild() != nil {
+		t.Errorf("parallel states have no default child")
+	}
+}
+
+// ST004: 遷移のドメイン
+func TestTransition_Domain(t *testing.T) {
+	running := "Running"
+	chart := New(&ast.StatesDecl{
+		Name:    "Job",
+		Initial: "Active",
+		States: []ast.StateDecl{{
+			Name:        "Active",
+			Initial:     &running,
+			States:      []ast.StateDecl{{Name: "Running"}, {Name: "Paused"}},
+			Transitions: []ast.TransitionDecl{on("Running", "Paused", "pause"), on("Active", "Active", "reset"), on("Active", "Paused", "hold")},
+		}},
+		Parallels: []ast.ParallelDecl{{
+			Name: "Both",
+			Regions: []ast.RegionDecl{
+				{Name: "Left", Initial: "L1", Transitions: []ast.TransitionDecl{on("L1", "R1", "cross")}},
+				{Name: "Right", Initial: "R1"},
+			},
+		}},
+	})
+
+	tests := map[string]string{"pause": "Active", "reset": "Job", "hold": "Job", "cross": "Job"}
+	for _, tr := range chart.Transitions {
+		if got := tr.Domain().Name; got != tests[tr.Event] {
+			t.Errorf("domain of %s = %s, want %s", tr.Event, got, tests[tr.Event])
+		}
+	}
+}
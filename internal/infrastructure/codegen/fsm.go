@@ -0,0 +1,722 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"time"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/statechart"
+	"pact/internal/infrastructure/formatter"
+)
+
+// HasStates は仕様に states ブロックがあるかどうかを返す
+func HasStates(spec *ast.SpecFile) bool {
+	for _, comp := range spec.Components {
+		if len(comp.Body.States) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// FSM は仕様の states ブロックごとに型安全な Go のステートマシンを生成する
+//
+//	状態      → <Name>State 型の定数
+//	イベント  → <Name>Event 型の定数、Fire(event) で処理する
+//	when      → <Name>Guards に注入する述語関数
+//	アクション → <Name>Actions インターフェースのメソッド
+//	after     → <Name>Clock で時刻を注入し、Tick() で期限切れの遷移を処理する
+//
+// 階層状態・並行状態に対応し、1つのトリガーは run-to-completion で処理する
+// （有効な遷移を全て実行した後、イベントのない遷移を安定するまで辿る）
+func FSM(spec *ast.SpecFile, opts GoOptions) ([]byte, error) {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "model"
+	}
+
+	var body bytes.Buffer
+	timers := false
+	for _, comp := range spec.Components {
+		for i := range comp.Body.States {
+			m := newFSMModel(statechart.New(&comp.Body.States[i]))
+			timers = timers || m.Timers
+			if err := fsmTemplate.Execute(&body, m); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var out strings.Builder
+	if opts.Source != "" {
+		fmt.Fprintf(&out, "// Code generated by pact codegen fsm from %s. DO NOT EDIT.\n\n", opts.Source)
+	} else {
+		out.WriteString("// Code generated by pact codegen fsm. DO NOT EDIT.\n\n")
+	}
+	fmt.Fprintf(&out, "package %s\n", pkg)
+	if timers {
+		out.WriteString("\nimport \"time\"\n")
+	}
+	out.Write(body.Bytes())
+
+	src, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated Go code is invalid: %w", err)
+	}
+	return src, nil
+}
+
+// fsmModel はテンプレートに渡すステートマシンの情報
+type fsmModel struct {
+	Name        string // states ブロックの名前
+	Prefix      string // 公開する型名の接頭辞（末尾の State を除いた名前）
+	Private     string // 非公開の型名・変数名の接頭辞
+	States      []fsmConst
+	Events      []fsmConst
+	Actions     []fsmMember
+	Guards      []fsmMember
+	Nodes       []fsmNode
+	Transitions []fsmTransition
+	Order       []string // 状態の定数名（文書順）
+	Timers      bool
+}
+
+type fsmConst struct {
+	Const string
+	Value string
+}
+
+type fsmMember struct {
+	Name string
+	Doc  string
+}
+
+type fsmNode struct {
+	Key      string // 状態の定数名（ルートは ""）
+	Parent   string
+	Initial  string
+	Children []string
+	Parallel bool
+	Final    bool
+	Entry    []string
+	Exit     []string
+}
+
+type fsmTransition struct {
+	Doc     string
+	Source  string
+	Target  string
+	Event   string
+	After   string
+	Guard   string
+	Actions []string
+}
+
+func newFSMModel(chart *statechart.Chart) *fsmModel {
+	name := exported(chart.Name)
+	prefix := name
+	if trimmed := strings.TrimSuffix(name, "State"); trimmed != "" {
+		prefix = trimmed
+	}
+	m := &fsmModel{Name: chart.Name, Prefix: prefix, Private: unexported(prefix)}
+
+	consts := map[*statechart.State]string{chart.Root: `""`}
+	for _, s := range chart.States {
+		c := fsmConst{Const: prefix + "State" + exported(s.Name), Value: s.Name}
+		consts[s] = c.Const
+		m.States = append(m.States, c)
+		m.Order = append(m.Order, c.Const)
+	}
+	events := make(map[string]string)
+	for _, event := range chart.Events() {
+		c := fsmConst{Const: prefix + "Event" + exported(event), Value: event}
+		events[event] = c.Const
+		m.Events = append(m.Events, c)
+	}
+
+	// アクションのメソッドには、どこで呼ばれるかを説明として付ける
+	uses := make(map[string][]string)
+	var actions []string
+	use := func(names []string, where string) []string {
+		var methods []string
+		for _, name := range names {
+			method := exported(name)
+			if _, ok := uses[method]; !ok {
+				actions = append(actions, method)
+			}
+			uses[method] = append(uses[method], where)
+			methods = append(methods, method)
+		}
+		return methods
+	}
+
+	for _, s := range append([]*statechart.State{chart.Root}, chart.States...) {
+		node := fsmNode{
+			Key:      consts[s],
+			Parallel: s.Kind == statechart.KindParallel,
+			Final:    s.Kind == statechart.KindFinal,
+			Entry:    use(s.Entry, "on entry to "+s.Name),
+			Exit:     use(s.Exit, "on exit from "+s.Name),
+		}
+		if s.Parent != nil && !s.Parent.IsRoot() {
+			node.Parent = consts[s.Parent]
+		}
+		if initial := s.DefaultChild(); initial != nil {
+			node.Initial = consts[initial]
+		}
+		for _, child := range s.Children {
+			node.Children = append(node.Children, consts[child])
+		}
+		m.Nodes = append(m.Nodes, node)
+	}
+
+	guards := &fsmGuards{fields: make(map[string]string)}
+	for _, t := range chart.Transitions {
+		text := transitionText(t)
+		ft := fsmTransition{
+			Doc:    text,
+			Source: consts[t.Source],
+			Target: consts[t.Target],
+			Event:  events[t.Event],
+		}
+		if t.After > 0 {
+			m.Timers = true
+			ft.After = goDuration(t.After)
+		}
+		var conds []string
+		for _, expr := range []ast.Expr{t.Condition, t.Guard} {
+			if expr != nil {
+				conds = append(conds, guards.predicate(expr, t))
+			}
+		}
+		ft.Guard = strings.Join(conds, " && ")
+		ft.Actions = use(t.Actions, "on "+text)
+		m.Transitions = append(m.Transitions, ft)
+	}
+	m.Guards = guards.members
+
+	for _, method := range actions {
+		m.Actions = append(m.Actions, fsmMember{Name: method, Doc: strings.Join(uses[method], ", ")})
+	}
+	return m
+}
+
+// transitionText は遷移を "From -> To on E" の形で表す
+func transitionText(t *statechart.Transition) string {
+	text := t.Source.Name + " -> " + t.Target.Name
+	switch {
+	case t.Event != "":
+		text += " on " + t.Event
+	case t.After > 0:
+		text += " after " + pactDuration(t.After)
+	case t.Condition != nil:
+		text += " when " + formatter.Expr(t.Condition)
+	}
+	return text
+}
+
+// goDuration は期間を Go の式にする（例: 5 * time.Minute）
+func goDuration(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			if d == u.unit {
+				return u.name
+			}
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("%d", int64(d))
+}
+
+// pactDuration は期間を .pact の表記にする（例: 5m、1500ms）
+func pactDuration(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d%s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("%dms", d/time.Millisecond)
+}
+
+// fsmGuards はガード式を Guards の述語関数の組み合わせに変換する
+type fsmGuards struct {
+	members []fsmMember
+	fields  map[string]string // フィールド名 → 元の式
+}
+
+// predicate はガード式を Go の式にする
+// 名前・メソッド呼び出し・フィールド参照はそれぞれ1つの述語になり、! と && と || はそのまま組み合わせる
+// それ以外の式（比較など）は遷移ごとに1つの述語にする
+func (g *fsmGuards) predicate(expr ast.Expr, t *statechart.Transition) string {
+	switch e := expr.(type) {
+	case *ast.LiteralExpr:
+		if b, ok := e.Value.(bool); ok {
+			return fmt.Sprintf("%t", b)
+		}
+	case *ast.UnaryExpr:
+		if e.Op == "!" {
+			return "!" + g.predicate(e.Operand, t)
+		}
+	case *ast.BinaryExpr:
+		if e.Op == "&&" || e.Op == "||" {
+			return "(" + g.predicate(e.Left, t) + " " + e.Op + " " + g.predicate(e.Right, t) + ")"
+		}
+	}
+	if name := predicateName(expr); name != "" {
+		return g.field(name, formatter.Expr(expr))
+	}
+	return g.field(exported(t.Source.Name)+"To"+exported(t.Target.Name)+"Guard", formatter.Expr(expr))
+}
+
+func (g *fsmGuards) field(name, text string) string {
+	// 同じ名前で異なる式の述語には番号を付けて区別する
+	base := name
+	for n := 2; ; n++ {
+		existing, exists := g.fields[name]
+		if !exists {
+			g.fields[name] = text
+			g.members = append(g.members, fsmMember{Name: name, Doc: text})
+			break
+		}
+		if existing == text {
+			break
+		}
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	return "m.holds(m.guards." + name + ")"
+}
+
+// predicateName は名前・引数のない呼び出し・フィールド参照から述語の名前を作る
+// 例: isPaid → IsPaid、order.isPaid() → OrderIsPaid
+func predicateName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.VariableExpr:
+		return exported(e.Name)
+	case *ast.FieldExpr:
+		if obj := predicateName(e.Object); obj != "" {
+			return obj + exported(e.Field)
+		}
+	case *ast.CallExpr:
+		if len(e.Args) > 0 {
+			return ""
+		}
+		if e.Object == nil {
+			return exported(e.Method)
+		}
+		if obj := predicateName(e.Object); obj != "" {
+			return obj + exported(e.Method)
+		}
+	}
+	return ""
+}
+
+var fsmTemplate = template.Must(template.New("fsm").Parse(`
+// {{.Prefix}}State is a state of the {{.Name}} state machine.
+type {{.Prefix}}State string
+
+// States of the {{.Name}} state machine.
+const (
+{{- range .States}}
+	{{.Const}} {{$.Prefix}}State = {{printf "%q" .Value}}
+{{- end}}
+)
+
+// {{.Prefix}}Event is an event accepted by the {{.Name}} state machine.
+type {{.Prefix}}Event string
+{{if .Events}}
+// Events of the {{.Name}} state machine.
+const (
+{{- range .Events}}
+	{{.Const}} {{$.Prefix}}Event = {{printf "%q" .Value}}
+{{- end}}
+)
+{{end}}
+{{- if .Actions}}
+// {{.Prefix}}Actions receives the entry, exit and transition actions of the {{.Name}} state machine.
+type {{.Prefix}}Actions interface {
+{{- range .Actions}}
+	// {{.Name}} runs {{.Doc}}.
+	{{.Name}}()
+{{- end}}
+}
+{{end}}
+{{- if .Guards}}
+// {{.Prefix}}Guards holds the predicates that guard transitions of the {{.Name}} state machine.
+// A nil predicate never holds.
+type {{.Prefix}}Guards struct {
+{{- range .Guards}}
+	// {{.Name}} reports whether {{.Doc}} holds.
+	{{.Name}} func() bool
+{{- end}}
+}
+{{end}}
+{{- if .Timers}}
+// {{.Prefix}}Clock tells the {{.Name}} state machine the current time for after transitions.
+type {{.Prefix}}Clock interface {
+	Now() time.Time
+}
+{{end}}
+// {{.Prefix}}Machine runs the {{.Name}} state machine.
+//
+// Fire{{if .Timers}} and Tick{{end}} process one trigger with run-to-completion semantics: every
+// enabled transition is taken, then eventless transitions are followed until
+// the configuration is stable. A {{.Prefix}}Machine is not safe for concurrent use.
+type {{.Prefix}}Machine struct {
+{{- if .Actions}}
+	actions {{.Prefix}}Actions
+{{- end}}
+{{- if .Guards}}
+	guards  {{.Prefix}}Guards
+{{- end}}
+{{- if .Timers}}
+	clock   {{.Prefix}}Clock
+	entered map[{{.Prefix}}State]time.Time
+{{- end}}
+	active  map[{{.Prefix}}State]bool
+	started bool
+}
+
+// New{{.Prefix}}Machine creates a {{.Name}} state machine. Call Start to enter its initial configuration.
+{{- if .Timers}}
+// A nil clock uses the system time.
+{{- end}}
+func New{{.Prefix}}Machine({{if .Actions}}actions {{.Prefix}}Actions, {{end}}{{if .Guards}}guards {{.Prefix}}Guards, {{end}}{{if .Timers}}clock {{.Prefix}}Clock{{end}}) *{{.Prefix}}Machine {
+	return &{{.Prefix}}Machine{
+{{- if .Actions}}
+		actions: actions,
+{{- end}}
+{{- if .Guards}}
+		guards:  guards,
+{{- end}}
+{{- if .Timers}}
+		clock:   clock,
+		entered: make(map[{{.Prefix}}State]time.Time),
+{{- end}}
+		active:  make(map[{{.Prefix}}State]bool),
+	}
+}
+
+type {{.Private}}Node struct {
+	parent   {{.Prefix}}State
+	initial  {{.Prefix}}State
+	children []{{.Prefix}}State
+	parallel bool
+	final    bool
+	entry    func(m *{{.Prefix}}Machine)
+	exit     func(m *{{.Prefix}}Machine)
+}
+
+// {{.Private}}Nodes describes the state hierarchy. The root is the empty state.
+var {{.Private}}Nodes = map[{{.Prefix}}State]{{.Private}}Node{
+{{- range .Nodes}}
+	{{.Key}}: {
+		{{- if .Parent}}parent: {{.Parent}}, {{end}}
+		{{- if .Initial}}initial: {{.Initial}}, {{end}}
+		{{- if .Children}}children: []{{$.Prefix}}State{ {{- range $i, $c := .Children}}{{if $i}}, {{end}}{{$c}}{{end -}} }, {{end}}
+		{{- if .Parallel}}parallel: true, {{end}}
+		{{- if .Final}}final: true, {{end}}
+		{{- if .Entry}}entry: func(m *{{$.Prefix}}Machine) { {{- range .Entry}} m.actions.{{.}}();{{end}} }, {{end}}
+		{{- if .Exit}}exit: func(m *{{$.Prefix}}Machine) { {{- range .Exit}} m.actions.{{.}}();{{end}} }, {{end -}}
+	},
+{{- end}}
+}
+
+// {{.Private}}Order lists the states in document order.
+var {{.Private}}Order = []{{.Prefix}}State{ {{- range $i, $s := .Order}}{{if $i}}, {{end}}{{$s}}{{end -}} }
+
+type {{.Private}}Transition struct {
+	source {{.Prefix}}State
+	target {{.Prefix}}State
+	event  {{.Prefix}}Event
+{{- if .Timers}}
+	after  time.Duration
+{{- end}}
+	guard  func(m *{{.Prefix}}Machine) bool
+	action func(m *{{.Prefix}}Machine)
+}
+
+// {{.Private}}Transitions lists the transitions in priority order.
+var {{.Private}}Transitions = []{{.Private}}Transition{
+{{- range .Transitions}}
+	// {{.Doc}}
+	{
+		source: {{.Source}}, target: {{.Target}},
+		{{- if .Event}} event: {{.Event}},{{end}}
+		{{- if .After}} after: {{.After}},{{end}}
+		{{- if .Guard}}
+		guard: func(m *{{$.Prefix}}Machine) bool { return {{.Guard}} },
+		{{- end}}
+		{{- if .Actions}}
+		action: func(m *{{$.Prefix}}Machine) { {{- range .Actions}} m.actions.{{.}}();{{end}} },
+		{{- end}}
+	},
+{{- end}}
+}
+
+// {{.Private}}MaxSteps bounds the eventless transitions followed by one trigger.
+const {{.Private}}MaxSteps = 100
+
+// Start enters the initial configuration. Fire{{if .Timers}} and Tick{{end}} start the machine
+// implicitly; calling Start again has no effect.
+func (m *{{.Prefix}}Machine) Start() {
+	if m.started {
+		return
+	}
+	m.started = true
+	if initial := {{.Private}}Nodes[""].initial; initial != "" {
+		m.enterTree(initial)
+	}
+	m.settle()
+}
+
+// Fire dispatches ev and reports whether it caused any transition.
+func (m *{{.Prefix}}Machine) Fire(ev {{.Prefix}}Event) bool {
+	m.Start()
+	fired := m.step(func(t *{{.Private}}Transition) bool { return t.event == ev })
+	if fired {
+		m.settle()
+	}
+	return fired
+}
+{{if .Timers}}
+// Tick takes the after transitions whose delay has elapsed and reports whether any was taken.
+func (m *{{.Prefix}}Machine) Tick() bool {
+	m.Start()
+	fired := false
+	for i := 0; i < {{.Private}}MaxSteps && m.step(m.expired); i++ {
+		fired = true
+		m.settle()
+	}
+	return fired
+}
+
+// NextDeadline returns when the earliest pending after transition becomes due.
+func (m *{{.Prefix}}Machine) NextDeadline() (time.Time, bool) {
+	var next time.Time
+	found := false
+	for i := range {{.Private}}Transitions {
+		t := &{{.Private}}Transitions[i]
+		if t.after > 0 && m.active[t.source] {
+			if due := m.entered[t.source].Add(t.after); !found || due.Before(next) {
+				next, found = due, true
+			}
+		}
+	}
+	return next, found
+}
+
+func (m *{{.Prefix}}Machine) expired(t *{{.Private}}Transition) bool {
+	return t.after > 0 && !m.now().Before(m.entered[t.source].Add(t.after))
+}
+
+func (m *{{.Prefix}}Machine) now() time.Time {
+	if m.clock == nil {
+		return time.Now()
+	}
+	return m.clock.Now()
+}
+{{end}}
+// State returns the active atomic states in document order.
+func (m *{{.Prefix}}Machine) State() []{{.Prefix}}State {
+	var states []{{.Prefix}}State
+	for _, s := range {{.Private}}Order {
+		if m.active[s] && !m.hasActiveChild(s) {
+			states = append(states, s)
+		}
+	}
+	return states
+}
+
+// Is reports whether s is active. A compound state is active while any of its descendants is.
+func (m *{{.Prefix}}Machine) Is(s {{.Prefix}}State) bool {
+	return m.active[s]
+}
+
+// Done reports whether the machine has reached a top-level final state.
+func (m *{{.Prefix}}Machine) Done() bool {
+	for _, s := range {{.Private}}Order {
+		if m.active[s] && {{.Private}}Nodes[s].final && {{.Private}}Nodes[s].parent == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *{{.Prefix}}Machine) settle() {
+	for i := 0; i < {{.Private}}MaxSteps && m.step(func(t *{{.Private}}Transition) bool {
+		return t.event == ""{{if .Timers}} && t.after == 0{{end}}
+	}); i++ {
+	}
+}
+
+// step takes the transitions selected by match: for each active atomic state, the first
+// enabled transition of the state or its nearest ancestor, unless it conflicts with a
+// transition already selected. It exits the affected states deepest first, runs the
+// transition actions and then enters the targets.
+func (m *{{.Prefix}}Machine) step(match func(t *{{.Private}}Transition) bool) bool {
+	var selected []*{{.Private}}Transition
+	exiting := make(map[{{.Prefix}}State]bool)
+	for _, leaf := range m.State() {
+	search:
+		for s := leaf; s != ""; s = {{.Private}}Nodes[s].parent {
+			for i := range {{.Private}}Transitions {
+				t := &{{.Private}}Transitions[i]
+				if t.source != s || !match(t) || (t.guard != nil && !t.guard(m)) {
+					continue
+				}
+				exits := m.exitSet(t)
+				for _, x := range exits {
+					if exiting[x] {
+						break search
+					}
+				}
+				for _, x := range exits {
+					exiting[x] = true
+				}
+				selected = append(selected, t)
+				break search
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return false
+	}
+
+	for i := len({{.Private}}Order) - 1; i >= 0; i-- {
+		if s := {{.Private}}Order[i]; exiting[s] {
+			m.exitState(s)
+		}
+	}
+	for _, t := range selected {
+		if t.action != nil {
+			t.action(m)
+		}
+	}
+	for _, t := range selected {
+		m.enter(t)
+	}
+	return true
+}
+
+// domain returns the nearest non-parallel state that contains both ends of t.
+func (m *{{.Prefix}}Machine) domain(t *{{.Private}}Transition) {{.Prefix}}State {
+	for a := {{.Private}}Nodes[t.source].parent; ; a = {{.Private}}Nodes[a].parent {
+		if a == "" || (!{{.Private}}Nodes[a].parallel && {{.Private}}IsAncestor(a, t.target)) {
+			return a
+		}
+	}
+}
+
+func (m *{{.Prefix}}Machine) exitSet(t *{{.Private}}Transition) []{{.Prefix}}State {
+	domain := m.domain(t)
+	var states []{{.Prefix}}State
+	for _, s := range {{.Private}}Order {
+		if m.active[s] && {{.Private}}IsAncestor(domain, s) {
+			states = append(states, s)
+		}
+	}
+	return states
+}
+
+func (m *{{.Prefix}}Machine) enter(t *{{.Private}}Transition) {
+	domain := m.domain(t)
+	var path []{{.Prefix}}State
+	for s := t.target; s != domain; s = {{.Private}}Nodes[s].parent {
+		path = append(path, s)
+	}
+	for i := len(path) - 1; i > 0; i-- {
+		s := path[i]
+		m.enterState(s)
+		if {{.Private}}Nodes[s].parallel {
+			for _, region := range {{.Private}}Nodes[s].children {
+				if region != path[i-1] {
+					m.enterTree(region)
+				}
+			}
+		}
+	}
+	m.enterTree(t.target)
+}
+
+// enterTree enters s and its default descendants.
+func (m *{{.Prefix}}Machine) enterTree(s {{.Prefix}}State) {
+	if m.active[s] {
+		return
+	}
+	m.enterState(s)
+	node := {{.Private}}Nodes[s]
+	if node.parallel {
+		for _, region := range node.children {
+			m.enterTree(region)
+		}
+	} else if node.initial != "" {
+		m.enterTree(node.initial)
+	}
+}
+
+func (m *{{.Prefix}}Machine) enterState(s {{.Prefix}}State) {
+	if m.active[s] {
+		return
+	}
+	m.active[s] = true
+{{- if .Timers}}
+	m.entered[s] = m.now()
+{{- end}}
+	if entry := {{.Private}}Nodes[s].entry; entry != nil {
+		entry(m)
+	}
+}
+
+func (m *{{.Prefix}}Machine) exitState(s {{.Prefix}}State) {
+	if exit := {{.Private}}Nodes[s].exit; exit != nil {
+		exit(m)
+	}
+	delete(m.active, s)
+}
+
+func (m *{{.Prefix}}Machine) hasActiveChild(s {{.Prefix}}State) bool {
+	for _, child := range {{.Private}}Nodes[s].children {
+		if m.active[child] {
+			return true
+		}
+	}
+	return false
+}
+{{if .Guards}}
+func (m *{{.Prefix}}Machine) holds(predicate func() bool) bool {
+	return predicate != nil && predicate()
+}
+{{end}}
+// {{.Private}}IsAncestor reports whether a is a proper ancestor of s.
+func {{.Private}}IsAncestor(a, s {{.Prefix}}State) bool {
+	for s != "" {
+		s = {{.Private}}Nodes[s].parent
+		if s == a {
+			return true
+		}
+	}
+	return false
+}
+`))
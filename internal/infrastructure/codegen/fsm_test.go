@@ -0,0 +1,147 @@
+package codegen
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+	"time"
+
+	pactParser "pact/internal/infrastructure/parser"
+)
+
+// generateFSM は仕様からステートマシンを生成し、gofmt 済みで型検査を通ることを確認する
+func generateFSM(t *testing.T, src string) string {
+	t.Helper()
+	spec, err := pactParser.NewParser(pactParser.NewLexer(src)).Parse()
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	out, err := FSM(spec, GoOptions{Package: "model", Source: "spec.pact"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	formatted, err := format.Source(out)
+	if err != nil || string(formatted) != string(out) {
+		t.Errorf("output is not gofmt-clean:\n%s", out)
+	}
+	typeCheck(t, string(out), "")
+	return string(out)
+}
+
+// =============================================================================
+// CF001-CF004: ステートマシンの生成
+// =============================================================================
+
+// CF001: 状態とイベントは型付きの定数になり、アクションはインターフェースのメソッドになる
+func TestFSM_Basic(t *testing.T) {
+	code := generateFSM(t, `component Order {
+  states OrderState {
+    initial Pending
+    final Delivered
+    state Pending {
+      entry [createOrder]
+    }
+    Pending -> Shipped on ship do [notify]
+    Shipped -> Delivered on deliver
+  }
+}`)
+	assertContains(t, code,
+		"// Code generated by pact codegen fsm from spec.pact. DO NOT EDIT.",
+		"type OrderState string",
+		`OrderStatePending   OrderState = "Pending"`,
+		"type OrderEvent string",
+		`OrderEventShip    OrderEvent = "ship"`,
+		"type OrderActions interface {",
+		"// CreateOrder runs on entry to Pending.",
+		"// Notify runs on Pending -> Shipped on ship.",
+		"func NewOrderMachine(actions OrderActions) *OrderMachine {",
+		"func (m *OrderMachine) Fire(ev OrderEvent) bool {",
+		"OrderStateDelivered: {final: true},",
+	)
+	for _, absent := range []string{"Guards", "Clock", "import"} {
+		if strings.Contains(code, absent) {
+			t.Errorf("unexpected %q without guards or timers:\n%s", absent, code)
+		}
+	}
+}
+
+// CF002: ガードは注入する述語関数の組み合わせになる
+func TestFSM_Guards(t *testing.T) {
+	code := generateFSM(t, `component Order {
+  states Payment {
+    initial Waiting
+    Waiting -> Paid on pay when order.isPaid() && !expired
+    Waiting -> Failed on pay when retries >= 3
+    Waiting -> Retry when !expired || retries < 3
+  }
+}`)
+	assertContains(t, code,
+		"type PaymentGuards struct {",
+		"// OrderIsPaid reports whether order.isPaid() holds.\n\tOrderIsPaid func() bool",
+		"// Expired reports whether expired holds.",
+		"// WaitingToFailedGuard reports whether retries >= 3 holds.",
+		"// WaitingToRetryGuard reports whether retries < 3 holds.",
+		"return (m.holds(m.guards.OrderIsPaid) && !m.holds(m.guards.Expired))",
+		"func NewPaymentMachine(guards PaymentGuards) *PaymentMachine {",
+	)
+}
+
+// CF003: after は注入する時計で判定し、階層状態と並行状態は状態の木になる
+func TestFSM_TimersAndHierarchy(t *testing.T) {
+	code := generateFSM(t, `component Job {
+  states Job {
+    initial Active
+    state Active {
+      initial Running
+      state Running {}
+      state Paused {}
+      Running -> Paused on pause
+    }
+    parallel Checkout {
+      region Payment {
+        initial Authorizing
+        state Authorizing {}
+      }
+      region Shipping {
+        initial Packing
+        state Packing {}
+      }
+    }
+    Active -> Checkout after 90s
+  }
+}`)
+	assertContains(t, code,
+		"import \"time\"",
+		"type JobClock interface {",
+		"func NewJobMachine(clock JobClock) *JobMachine {",
+		"// Active -> Checkout after 90s",
+		"after: 90 * time.Second,",
+		"JobStateActive:      {initial: JobStateRunning, children: []JobState{JobStateRunning, JobStatePaused}},",
+		"JobStateRunning:     {parent: JobStateActive},",
+		"JobStateCheckout:    {children: []JobState{JobStatePayment, JobStateShipping}, parallel: true},",
+		"func (m *JobMachine) Tick() bool {",
+		"func (m *JobMachine) NextDeadline() (time.Time, bool) {",
+	)
+}
+
+// CF004: 期間の表記
+func TestFSM_Durations(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		goExpr   string
+		pactText string
+	}{
+		{5 * time.Minute, "5 * time.Minute", "5m"},
+		{time.Hour, "time.Hour", "1h"},
+		{48 * time.Hour, "48 * time.Hour", "2d"},
+		{1500 * time.Millisecond, "1500 * time.Millisecond", "1500ms"},
+	}
+	for _, tt := range tests {
+		if got := goDuration(tt.d); got != tt.goExpr {
+			t.Errorf("goDuration(%v) = %q, want %q", tt.d, got, tt.goExpr)
+		}
+		if got := pactDuration(tt.d); got != tt.pactText {
+			t.Errorf("pactDuration(%v) = %q, want %q", tt.d, got, tt.pactText)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+// Package codegen generates source code from .pact specs.
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"pact/internal/domain/ast"
+)
+
+// GoOptions は Go コード生成の設定
+type GoOptions struct {
+	Package string // 出力するパッケージ名
+	Source  string // 生成元の仕様ファイル（ヘッダーのコメント用）
+}
+
+// Go は仕様から Go のコードを生成する
+//
+//	type      → struct（public のフィールドは大文字始まり、それ以外は小文字始まり）
+//	enum      → string を基底型とする型と定数、String() メソッド
+//	T? / T[]  → *T / []T
+//	provides  → interface（async は context.Context を受け取り、throws は error を返す）
+//	throws X  → 番兵エラー ErrX
+//	depends on → コンポーネントのコンストラクタ New<Component> の引数
+//
+// 出力は gofmt 済みで、同じ入力からは常に同じ内容を生成する
+func Go(spec *ast.SpecFile, opts GoOptions) ([]byte, error) {
+	g := &goWriter{imports: make(map[string]bool)}
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "model"
+	}
+
+	var body strings.Builder
+	g.out = &body
+	g.sentinels(spec)
+	for _, typ := range spec.Types {
+		g.typeDecl(typ)
+	}
+	for _, iface := range spec.Interfaces {
+		g.interfaceDecl(iface)
+	}
+	for i := range spec.Components {
+		g.component(&spec.Components[i], spec)
+	}
+
+	var out strings.Builder
+	if opts.Source != "" {
+		fmt.Fprintf(&out, "// Code generated by pact codegen go from %s. DO NOT EDIT.\n\n", opts.Source)
+	} else {
+		out.WriteString("// Code generated by pact codegen go. DO NOT EDIT.\n\n")
+	}
+	fmt.Fprintf(&out, "package %s\n", pkg)
+	if len(g.imports) > 0 {
+		var paths []string
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		out.WriteString("\nimport (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		out.WriteString(")\n")
+	}
+	out.WriteString(body.String())
+
+	src, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated Go code is invalid: %w", err)
+	}
+	return src, nil
+}
+
+type goWriter struct {
+	out     *strings.Builder
+	imports map[string]bool
+}
+
+func (g *goWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(g.out, format, args...)
+}
+
+// sentinels は throws に書かれたエラーを番兵エラーとして宣言する
+func (g *goWriter) sentinels(spec *ast.SpecFile) {
+	seen := make(map[string]bool)
+	var names []string
+	collect := func(ifaces []ast.InterfaceDecl) {
+		for _, iface := range ifaces {
+			for _, method := range iface.Methods {
+				for _, name := range method.Throws {
+					if !seen[name] && sentinelName(name) != "" {
+						seen[name] = true
+						names = append(names, name)
+					}
+				}
+			}
+		}
+	}
+	collect(spec.Interfaces)
+	for _, comp := range spec.Components {
+		collect(comp.Body.Provides)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	g.imports["errors"] = true
+	g.printf("\n// Errors declared by throws in the spec.\nvar (\n")
+	for _, name := range names {
+		g.printf("\t%s = errors.New(%q)\n", sentinelName(name), errorMessage(name))
+	}
+	g.printf(")\n")
+}
+
+func (g *goWriter) component(comp *ast.ComponentDecl, spec *ast.SpecFile) {
+	self := -1
+	taken := false
+	for i, typ := range comp.Body.Types {
+		// コンポーネントと同名の struct にはコンストラクタで依存先を持たせる
+		if typ.Name == comp.Name && typ.Kind == ast.TypeKindStruct {
+			self = i
+			continue
+		}
+		taken = taken || typ.Name == comp.Name
+		g.typeDecl(typ)
+	}
+	for _, iface := range comp.Body.Provides {
+		taken = taken || iface.Name == comp.Name
+		g.interfaceDecl(iface)
+	}
+
+	name := exported(comp.Name)
+	structName := name
+	// 同名の interface などがあれば実装側の struct に Impl を付ける
+	if taken {
+		structName += "Impl"
+	}
+
+	deps := dependencies(comp, spec)
+	g.printf("\n// %s is the %s component.\ntype %s struct {\n", structName, comp.Name, structName)
+	if self >= 0 {
+		g.fields(comp.Body.Types[self].Fields)
+	}
+	for _, dep := range deps {
+		g.printf("\t%s %s\n", dep.field, dep.typ)
+	}
+	g.printf("}\n")
+
+	params := make([]string, len(deps))
+	inits := make([]string, len(deps))
+	for i, dep := range deps {
+		params[i] = dep.field + " " + dep.typ
+		inits[i] = dep.field + ": " + dep.field
+	}
+	g.printf("\n// New%s returns a new %s with its dependencies.\n", name, structName)
+	g.printf("func New%s(%s) *%s {\n\treturn &%s{%s}\n}\n", name, strings.Join(params, ", "), structName, structName, strings.Join(inits, ", "))
+}
+
+type goDependency struct {
+	field string
+	typ   string
+}
+
+// dependencies は depends on の依存先をコンストラクタの引数にする
+// 依存先と同名の interface があればその interface、同じ仕様のコンポーネントならそのポインタを受け取る
+func dependencies(comp *ast.ComponentDecl, spec *ast.SpecFile) []goDependency {
+	seen := make(map[string]bool)
+	var deps []goDependency
+	for _, rel := range comp.Body.Relations {
+		if rel.Kind != ast.RelationDependsOn {
+			continue
+		}
+		target := memberName(rel.Target)
+		typ := exported(target)
+		if isLocalComponent(spec, target) {
+			typ = "*" + typ
+		}
+		field := unexported(target)
+		if rel.Alias != nil {
+			field = unexported(*rel.Alias)
+		}
+		field = safeIdent(field)
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		deps = append(deps, goDependency{field: field, typ: typ})
+	}
+	return deps
+}
+
+// isLocalComponent は name が spec 内の、同名の interface を持たないコンポーネントかどうかを返す
+func isLocalComponent(spec *ast.SpecFile, name string) bool {
+	for _, iface := range spec.Interfaces {
+		if iface.Name == name {
+			return false
+		}
+	}
+	for _, comp := range spec.Components {
+		for _, iface := range comp.Body.Provides {
+			if iface.Name == name {
+				return false
+			}
+		}
+	}
+	for _, comp := range spec.Components {
+		if comp.Name == name {
+			for _, typ := range comp.Body.Types {
+				if typ.Name == name && typ.Kind != ast.TypeKindStruct {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (g *goWriter) typeDecl(typ ast.TypeDecl) {
+	name := exported(typ.Name)
+	switch typ.Kind {
+	case ast.TypeKindEnum:
+		g.printf("\n// %s is the %s enum.\ntype %s string\n\n", name, typ.Name, name)
+		if len(typ.Values) > 0 {
+			g.printf("const (\n")
+			for _, value := range typ.Values {
+				g.printf("\t%s %s = %q\n", name+exported(value), name, value)
+			}
+			g.printf(")\n")
+		}
+		receiver := strings.ToLower(name[:1])
+		g.printf("\n// String returns the name of the %s value.\n", name)
+		g.printf("func (%s %s) String() string {\n\treturn string(%s)\n}\n", receiver, name, receiver)
+	case ast.TypeKindAlias:
+		base := "any"
+		if typ.BaseType != nil {
+			base = g.typeExpr(*typ.BaseType)
+		}
+		g.printf("\n// %s is an alias of %s.\ntype %s = %s\n", name, base, name, base)
+	default:
+		g.printf("\n// %s is the %s type.\ntype %s struct {\n", name, typ.Name, name)
+		g.fields(typ.Fields)
+		g.printf("}\n")
+	}
+}
+
+func (g *goWriter) fields(fields []ast.FieldDecl) {
+	for _, field := range fields {
+		name := unexported(field.Name)
+		if field.Visibility == ast.VisibilityPublic || field.Visibility == "" {
+			name = exported(field.Name)
+		}
+		g.printf("\t%s %s\n", safeIdent(name), g.typeExpr(field.Type))
+	}
+}
+
+func (g *goWriter) interfaceDecl(iface ast.InterfaceDecl) {
+	name := exported(iface.Name)
+	g.printf("\n// %s is the %s interface.\ntype %s interface {\n", name, iface.Name, name)
+	for i, method := range iface.Methods {
+		if i > 0 {
+			g.printf("\n")
+		}
+		g.method(method)
+	}
+	g.printf("}\n")
+}
+
+// method はメソッドのシグネチャを出力する
+// async は先頭の context.Context、throws は末尾の error として表す
+func (g *goWriter) method(method ast.MethodDecl) {
+	name := exported(method.Name)
+	var doc []string
+	if method.Async {
+		doc = append(doc, fmt.Sprintf("%s is asynchronous: it may block and honours cancellation of ctx.", name))
+	}
+	var sentinels []string
+	for _, t := range method.Throws {
+		if s := sentinelName(t); s != "" {
+			sentinels = append(sentinels, s)
+		}
+	}
+	if len(sentinels) > 0 {
+		doc = append(doc, fmt.Sprintf("It may return %s.", strings.Join(sentinels, ", ")))
+	}
+	for _, line := range doc {
+		g.printf("\t// %s\n", line)
+	}
+
+	var params []string
+	if method.Async {
+		g.imports["context"] = true
+		params = append(params, "ctx context.Context")
+	}
+	for _, p := range method.Params {
+		params = append(params, safeIdent(unexported(p.Name))+" "+g.typeExpr(p.Type))
+	}
+
+	var results []string
+	if method.ReturnType != nil && !isVoid(method.ReturnType.Name) {
+		results = append(results, g.typeExpr(*method.ReturnType))
+	}
+	if len(method.Throws) > 0 {
+		results = append(results, "error")
+	}
+
+	g.printf("\t%s(%s)", name, strings.Join(params, ", "))
+	switch len(results) {
+	case 0:
+	case 1:
+		g.printf(" %s", results[0])
+	default:
+		g.printf(" (%s)", strings.Join(results, ", "))
+	}
+	g.printf("\n")
+}
+
+// typeExpr は .pact の型を Go の型にする
+func (g *goWriter) typeExpr(t ast.TypeExpr) string {
+	var base string
+	switch t.Name {
+	case "string", "String", "uuid", "UUID":
+		base = "string"
+	case "int", "Int", "integer", "Integer":
+		base = "int"
+	case "float", "Float", "double", "Double":
+		base = "float64"
+	case "bool", "Bool", "boolean", "Boolean":
+		base = "bool"
+	case "bytes", "Bytes":
+		base = "[]byte"
+	case "datetime", "DateTime", "date", "Date", "time", "Time":
+		g.imports["time"] = true
+		base = "time.Time"
+	case "any", "Any", "object", "Object", "void", "Void":
+		base = "any"
+	case "map", "Map":
+		key, value := "string", "any"
+		if len(t.TypeParams) == 2 {
+			key, value = g.typeExpr(t.TypeParams[0]), g.typeExpr(t.TypeParams[1])
+		}
+		base = "map[" + key + "]" + value
+	case "array", "Array":
+		base = "[]any"
+		if len(t.TypeParams) == 1 {
+			base = "[]" + g.typeExpr(t.TypeParams[0])
+		}
+	default:
+		base = exported(memberName(t.Name))
+		if len(t.TypeParams) > 0 {
+			params := make([]string, len(t.TypeParams))
+			for i, p := range t.TypeParams {
+				params[i] = g.typeExpr(p)
+			}
+			base += "[" + strings.Join(params, ", ") + "]"
+		}
+	}
+
+	if t.Array {
+		return "[]" + base
+	}
+	// スライス・マップ・any はそれ自体が nil を取れるのでポインタにしない
+	if t.Nullable && !strings.HasPrefix(base, "[]") && !strings.HasPrefix(base, "map[") && base != "any" {
+		return "*" + base
+	}
+	return base
+}
+
+func isVoid(name string) bool {
+	return name == "void" || name == "Void"
+}
+
+// sentinelName は throws のエラー名から番兵エラーの名前を作る（汎用の Error は対象外）
+// 例: NotFoundError → ErrNotFound、InvalidCredentials → ErrInvalidCredentials
+func sentinelName(name string) string {
+	name = memberName(name)
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(name, "Exception"), "Error")
+	if trimmed == "" {
+		return ""
+	}
+	return "Err" + exported(trimmed)
+}
+
+// errorMessage はエラー名を小文字の単語列にする（例: InvalidCredentials → "invalid credentials"）
+func errorMessage(name string) string {
+	name = strings.TrimPrefix(sentinelName(name), "Err")
+	return strings.Join(splitWords(name), " ")
+}
+
+// splitWords は CamelCase を小文字の単語に分割する
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		boundary := i == len(runes) || runes[i] == '_' ||
+			(unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))))
+		if !boundary {
+			continue
+		}
+		if word := strings.Trim(string(runes[start:i]), "_"); word != "" {
+			words = append(words, strings.ToLower(word))
+		}
+		start = i
+	}
+	return words
+}
+
+// memberName は修飾名のメンバー部分を返す（例: auth.User → User）
+func memberName(name string) string {
+	if _, member, ok := ast.SplitQualifiedName(name); ok {
+		return member
+	}
+	return name
+}
+
+// initialisms は Go で全て大文字で書く略語
+var initialisms = map[string]bool{
+	"id": true, "url": true, "uri": true, "api": true, "http": true, "json": true,
+	"uuid": true, "sql": true, "ip": true, "html": true, "xml": true,
+}
+
+// exported は名前を大文字始まりにする
+// 先頭の単語が略語なら全て大文字にする（例: id → ID、urlPath → URLPath）
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	n := 0
+	for n < len(runes) && unicode.IsLower(runes[n]) {
+		n++
+	}
+	if initialisms[string(runes[:n])] {
+		return strings.ToUpper(string(runes[:n])) + string(runes[n:])
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// unexported は名前を小文字始まりにする
+// 先頭の大文字の連なりは略語として扱う（例: ID → id、URLPath → urlPath）
+func unexported(name string) string {
+	runes := []rune(name)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// safeIdent は Go の予約語と重なる名前の末尾に "_" を付ける
+func safeIdent(name string) string {
+	if token.Lookup(name).IsKeyword() {
+		return name + "_"
+	}
+	return name
+}
@@ -0,0 +1,210 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	pactParser "pact/internal/infrastructure/parser"
+)
+
+// generateGo は仕様から Go のコードを生成し、gofmt 済みであることを確認する
+func generateGo(t *testing.T, src string) string {
+	t.Helper()
+	spec, err := pactParser.NewParser(pactParser.NewLexer(src)).Parse()
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	out, err := Go(spec, GoOptions{Package: "model", Source: "spec.pact"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	formatted, err := format.Source(out)
+	if err != nil || string(formatted) != string(out) {
+		t.Errorf("output is not gofmt-clean:\n%s", out)
+	}
+	again, _ := Go(spec, GoOptions{Package: "model", Source: "spec.pact"})
+	if string(again) != string(out) {
+		t.Errorf("output is not deterministic")
+	}
+	return string(out)
+}
+
+// typeCheck は生成したコードを型検査する（extra は不足する宣言の補完）
+func typeCheck(t *testing.T, code, extra string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{"gen.go": code, "extra.go": "package model\n" + extra} {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatalf("generated code does not parse: %v\n%s", err, code)
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("model", fset, files, nil); err != nil {
+		t.Errorf("generated code does not type-check: %v\n%s", err, code)
+	}
+}
+
+func assertContains(t *testing.T, text string, wants ...string) {
+	t.Helper()
+	for _, want := range wants {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+// =============================================================================
+// CG001-CG006: Go コード生成
+// =============================================================================
+
+// CG001: struct のフィールドは可視性で大文字・小文字始まりになり、nullable はポインタになる
+func TestGo_Struct(t *testing.T) {
+	code := generateGo(t, `component A {
+  type User {
+    +id: string
+    -passwordHash: string
+    +nickname: string?
+    +tags: string[]
+    +friends: User[]
+    +meta: Map<string, int>
+    +avatar: bytes
+    +createdAt: datetime
+    +extra: any?
+    #range: string
+  }
+}`)
+	assertContains(t, code,
+		"// Code generated by pact codegen go from spec.pact. DO NOT EDIT.",
+		"package model",
+		"\"time\"",
+		"type User struct {",
+		"ID           string",
+		"passwordHash string",
+		"Nickname     *string",
+		"Tags         []string",
+		"Friends      []User",
+		"Meta         map[string]int",
+		"Avatar       []byte",
+		"CreatedAt    time.Time",
+		"Extra        any",
+		"range_       string",
+	)
+	typeCheck(t, code, "")
+}
+
+// CG002: enum は string 型の定数と String() になる
+func TestGo_Enum(t *testing.T) {
+	code := generateGo(t, `component A {
+  enum Status { Active Closed }
+  type Code = int
+}`)
+	assertContains(t, code,
+		"type Status string",
+		`StatusActive Status = "Active"`,
+		`StatusClosed Status = "Closed"`,
+		"func (s Status) String() string {",
+		"type Code = int",
+	)
+	typeCheck(t, code, "")
+}
+
+// CG003: provides は interface になり、async は context、throws は error と番兵エラーになる
+func TestGo_Interface(t *testing.T) {
+	code := generateGo(t, `component Auth {
+  type User { +id: string }
+  provides AuthService {
+    async Login(email: string, password: string) -> string throws InvalidCredentials, UserNotFoundError
+    Logout(token: string)
+    Find(id: string) -> User? throws Error
+    Count() -> int
+  }
+}`)
+	assertContains(t, code,
+		`ErrInvalidCredentials = errors.New("invalid credentials")`,
+		`ErrUserNotFound       = errors.New("user not found")`,
+		"type AuthService interface {",
+		"// Login is asynchronous",
+		"// It may return ErrInvalidCredentials, ErrUserNotFound.",
+		"Login(ctx context.Context, email string, password string) (string, error)",
+		"Logout(token string)\n",
+		"Find(id string) (*User, error)",
+		"Count() int",
+	)
+	if strings.Contains(code, "ErrError") {
+		t.Errorf("generic Error should not become a sentinel:\n%s", code)
+	}
+	typeCheck(t, code, "")
+}
+
+// CG004: コンポーネントは depends on を引数に取るコンストラクタを持つ
+func TestGo_Constructor(t *testing.T) {
+	code := generateGo(t, `component OrderService {
+  type OrderService {
+    -retries: int
+  }
+  depends on PaymentGateway
+  depends on Clock as clock
+  depends on Cache
+}
+
+component Cache {}`)
+	assertContains(t, code,
+		"type OrderService struct {\n\tretries        int\n\tpaymentGateway PaymentGateway\n\tclock          Clock\n\tcache          *Cache\n}",
+		"func NewOrderService(paymentGateway PaymentGateway, clock Clock, cache *Cache) *OrderService {",
+		"return &OrderService{paymentGateway: paymentGateway, clock: clock, cache: cache}",
+		"func NewCache() *Cache {",
+	)
+	typeCheck(t, code, "type PaymentGateway interface{}\ntype Clock interface{}")
+}
+
+// CG005: コンポーネントと同名の interface がある場合は実装の struct に Impl を付ける
+func TestGo_ComponentNamedLikeInterface(t *testing.T) {
+	code := generateGo(t, `component UserRepository {
+  provides UserRepository {
+    Save(name: string) throws Error
+  }
+}
+
+component Service {
+  depends on UserRepository
+}`)
+	assertContains(t, code,
+		"type UserRepository interface {",
+		"type UserRepositoryImpl struct {",
+		"func NewUserRepository() *UserRepositoryImpl {",
+		"func NewService(userRepository UserRepository) *Service {",
+	)
+	typeCheck(t, code, "")
+}
+
+// CG006: 名前の変換
+func TestGo_Names(t *testing.T) {
+	tests := []struct{ in, exported, unexported string }{
+		{"id", "ID", "id"},
+		{"urlPath", "URLPath", "urlPath"},
+		{"idle", "Idle", "idle"},
+		{"ID", "ID", "id"},
+		{"URLPath", "URLPath", "urlPath"},
+		{"userName", "UserName", "userName"},
+	}
+	for _, tt := range tests {
+		if got := exported(tt.in); got != tt.exported {
+			t.Errorf("exported(%q) = %q, want %q", tt.in, got, tt.exported)
+		}
+		if got := unexported(tt.in); got != tt.unexported {
+			t.Errorf("unexported(%q) = %q, want %q", tt.in, got, tt.unexported)
+		}
+	}
+	if got := errorMessage("HTTPTimeoutError"); got != "http timeout" {
+		t.Errorf("unexpected error message: %q", got)
+	}
+}
@@ -0,0 +1,289 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"pact/internal/domain/ast"
+)
+
+// TSOptions は TypeScript コード生成の設定
+type TSOptions struct {
+	Source      string                   // 生成元の仕様ファイル（ヘッダーのコメント用）
+	Declaration bool                     // true なら .d.ts 向けに enum を文字列リテラルの union で出力する
+	Types       map[string]string        // .pact の型名から TypeScript の型への対応表（既定の対応を上書きする）
+	Imports     map[string]*ast.SpecFile // import のパスごとの仕様（型の import 文の生成に使う）
+}
+
+// defaultTSTypes は組み込みの型名と TypeScript の型の既定の対応表
+var defaultTSTypes = map[string]string{
+	"string": "string", "String": "string", "uuid": "string", "UUID": "string",
+	"int": "number", "Int": "number", "integer": "number", "Integer": "number",
+	"float": "number", "Float": "number", "double": "number", "Double": "number",
+	"bool": "boolean", "Bool": "boolean", "boolean": "boolean", "Boolean": "boolean",
+	"bytes": "string", "Bytes": "string",
+	"datetime": "string", "DateTime": "string", "date": "string", "Date": "string", "time": "string", "Time": "string",
+	"any": "unknown", "Any": "unknown", "object": "unknown", "Object": "unknown",
+	"void": "void", "Void": "void",
+}
+
+// TypeScript は仕様から TypeScript のコードを生成する
+//
+//	type      → interface（public のフィールドのみ）
+//	enum      → enum（Declaration のときは文字列リテラルの union）
+//	T? / T[]  → T | null / T[]
+//	List<T>   → List<T>
+//	provides / requires → interface（async は Promise<T> を返し、throws は @throws に書く）
+//
+// 組み込みの型名は opts.Types で別の型に対応付けられる（例: datetime → Date）
+func TypeScript(spec *ast.SpecFile, opts TSOptions) ([]byte, error) {
+	w := &tsWriter{opts: opts, refs: make(map[string]bool), local: make(map[string]bool)}
+	for _, name := range declaredNames(spec) {
+		w.local[name] = true
+	}
+
+	var body strings.Builder
+	w.out = &body
+	for _, typ := range spec.Types {
+		w.typeDecl(typ)
+	}
+	for _, iface := range spec.Interfaces {
+		w.interfaceDecl(iface)
+	}
+	for _, comp := range spec.Components {
+		for _, typ := range comp.Body.Types {
+			w.typeDecl(typ)
+		}
+		for _, iface := range comp.Body.Provides {
+			w.interfaceDecl(iface)
+		}
+		for _, iface := range comp.Body.Requires {
+			w.interfaceDecl(iface)
+		}
+	}
+
+	var out strings.Builder
+	if opts.Source != "" {
+		fmt.Fprintf(&out, "// Code generated by pact codegen ts from %s. DO NOT EDIT.\n", opts.Source)
+	} else {
+		out.WriteString("// Code generated by pact codegen ts. DO NOT EDIT.\n")
+	}
+	if imports := w.imports(spec); len(imports) > 0 {
+		out.WriteString("\n")
+		for _, line := range imports {
+			out.WriteString(line + "\n")
+		}
+	}
+	out.WriteString(body.String())
+	return []byte(out.String()), nil
+}
+
+type tsWriter struct {
+	out   *strings.Builder
+	opts  TSOptions
+	refs  map[string]bool // 参照された型名
+	local map[string]bool // このファイルで宣言された型名
+}
+
+func (w *tsWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(w.out, format, args...)
+}
+
+// declaredNames は仕様で宣言された型と interface の名前を出現順に返す
+func declaredNames(spec *ast.SpecFile) []string {
+	var names []string
+	add := func(types []ast.TypeDecl, ifaces ...[]ast.InterfaceDecl) {
+		for _, typ := range types {
+			names = append(names, typ.Name)
+		}
+		for _, list := range ifaces {
+			for _, iface := range list {
+				names = append(names, iface.Name)
+			}
+		}
+	}
+	add(spec.Types, spec.Interfaces)
+	for _, comp := range spec.Components {
+		add(comp.Body.Types, comp.Body.Provides, comp.Body.Requires)
+	}
+	return names
+}
+
+// imports は参照された他ファイルの型の import 文を返す
+// エイリアス付きの import は名前空間として、それ以外は使われた名前だけを取り込む
+func (w *tsWriter) imports(spec *ast.SpecFile) []string {
+	var lines []string
+	for _, imp := range spec.Imports {
+		module := strings.TrimSuffix(imp.Path, ".pact")
+		if !strings.HasPrefix(module, ".") {
+			module = "./" + module
+		}
+		if imp.Alias != nil {
+			if w.refs[*imp.Alias+"."] {
+				lines = append(lines, fmt.Sprintf("import type * as %s from %q;", *imp.Alias, module))
+			}
+			continue
+		}
+		imported := w.opts.Imports[imp.Path]
+		if imported == nil {
+			continue
+		}
+		var names []string
+		for _, name := range declaredNames(imported) {
+			if w.refs[name] && !w.local[name] {
+				names = append(names, name)
+				w.local[name] = true
+			}
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			lines = append(lines, fmt.Sprintf("import type { %s } from %q;", strings.Join(names, ", "), module))
+		}
+	}
+	return lines
+}
+
+func (w *tsWriter) typeDecl(typ ast.TypeDecl) {
+	switch typ.Kind {
+	case ast.TypeKindEnum:
+		if w.opts.Declaration {
+			values := make([]string, len(typ.Values))
+			for i, value := range typ.Values {
+				values[i] = fmt.Sprintf("%q", value)
+			}
+			if len(values) == 0 {
+				values = []string{"never"}
+			}
+			w.printf("\nexport type %s = %s;\n", typ.Name, strings.Join(values, " | "))
+			return
+		}
+		w.printf("\nexport enum %s {\n", typ.Name)
+		for _, value := range typ.Values {
+			w.printf("  %s = %q,\n", value, value)
+		}
+		w.printf("}\n")
+	case ast.TypeKindAlias:
+		base := "unknown"
+		if typ.BaseType != nil {
+			base = w.typeExpr(*typ.BaseType)
+		}
+		w.printf("\nexport type %s = %s;\n", typ.Name, base)
+	default:
+		// private・protected のフィールドは外部に公開される形に含めない
+		w.printf("\nexport interface %s {\n", typ.Name)
+		for _, field := range typ.Fields {
+			if field.Visibility == ast.VisibilityPublic || field.Visibility == "" {
+				w.printf("  %s: %s;\n", field.Name, w.typeExpr(field.Type))
+			}
+		}
+		w.printf("}\n")
+	}
+}
+
+func (w *tsWriter) interfaceDecl(iface ast.InterfaceDecl) {
+	w.printf("\nexport interface %s {\n", iface.Name)
+	for _, method := range iface.Methods {
+		w.method(method)
+	}
+	w.printf("}\n")
+}
+
+// method はメソッドのシグネチャを出力する
+// async は Promise で包み、throws は JSDoc の @throws として残す
+func (w *tsWriter) method(method ast.MethodDecl) {
+	if len(method.Throws) > 0 {
+		w.printf("  /**\n")
+		for _, name := range method.Throws {
+			w.printf("   * @throws {%s}\n", name)
+		}
+		w.printf("   */\n")
+	}
+
+	params := make([]string, len(method.Params))
+	for i, p := range method.Params {
+		params[i] = tsIdent(p.Name) + ": " + w.typeExpr(p.Type)
+	}
+	result := "void"
+	if method.ReturnType != nil {
+		result = w.typeExpr(*method.ReturnType)
+	}
+	if method.Async {
+		result = "Promise<" + result + ">"
+	}
+	w.printf("  %s(%s): %s;\n", method.Name, strings.Join(params, ", "), result)
+}
+
+// typeExpr は .pact の型を TypeScript の型にする
+func (w *tsWriter) typeExpr(t ast.TypeExpr) string {
+	base, ok := w.opts.Types[t.Name]
+	if !ok {
+		base, ok = defaultTSTypes[t.Name]
+	}
+	if !ok {
+		switch t.Name {
+		case "map", "Map":
+			key, value := "string", "unknown"
+			if len(t.TypeParams) == 2 {
+				key, value = w.typeExpr(t.TypeParams[0]), w.typeExpr(t.TypeParams[1])
+			}
+			base = "Record<" + key + ", " + value + ">"
+		case "array", "Array":
+			elem := "unknown"
+			if len(t.TypeParams) == 1 {
+				elem = w.typeExpr(t.TypeParams[0])
+			}
+			base = arrayOf(elem)
+		default:
+			if qualifier, _, ok := ast.SplitQualifiedName(t.Name); ok {
+				w.refs[qualifier+"."] = true
+			} else {
+				w.refs[t.Name] = true
+			}
+			base = t.Name
+			if len(t.TypeParams) > 0 {
+				params := make([]string, len(t.TypeParams))
+				for i, p := range t.TypeParams {
+					params[i] = w.typeExpr(p)
+				}
+				base += "<" + strings.Join(params, ", ") + ">"
+			}
+		}
+	}
+
+	if t.Array {
+		base = arrayOf(base)
+	}
+	if t.Nullable && base != "unknown" && base != "any" {
+		return base + " | null"
+	}
+	return base
+}
+
+// arrayOf は要素型の配列型を返す（union は括弧で囲む）
+func arrayOf(elem string) string {
+	if strings.Contains(elem, "|") || strings.Contains(elem, "&") {
+		return "(" + elem + ")[]"
+	}
+	return elem + "[]"
+}
+
+// tsReserved は引数名に使えない TypeScript の予約語
+var tsReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"let": true, "static": true, "yield": true, "await": true, "implements": true,
+	"interface": true, "package": true, "private": true, "protected": true, "public": true,
+}
+
+// tsIdent は予約語と衝突する引数名の末尾に _ を付ける
+func tsIdent(name string) string {
+	if tsReserved[name] {
+		return name + "_"
+	}
+	return name
+}
@@ -0,0 +1,144 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"pact/internal/domain/ast"
+	pactParser "pact/internal/infrastructure/parser"
+)
+
+// generateTS は仕様から TypeScript のコードを生成し、出力が決定的であることを確認する
+func generateTS(t *testing.T, src string, opts TSOptions) string {
+	t.Helper()
+	spec, err := pactParser.NewParser(pactParser.NewLexer(src)).Parse()
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	out, err := TypeScript(spec, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, _ := TypeScript(spec, opts)
+	if string(again) != string(out) {
+		t.Errorf("output is not deterministic")
+	}
+	return string(out)
+}
+
+// =============================================================================
+// CT001-CT005: TypeScript コード生成
+// =============================================================================
+
+// CT001: struct は public のフィールドだけを持つ interface になる
+func TestTypeScript_Struct(t *testing.T) {
+	code := generateTS(t, `component A {
+  type User {
+    +id: string
+    -passwordHash: string
+    +nickname: string?
+    +tags: string[]
+    +friends: User[]?
+    +meta: Map<string, int>
+    +page: Page<User>
+    +createdAt: datetime
+    +extra: any?
+  }
+}`, TSOptions{Source: "spec.pact"})
+	assertContains(t, code,
+		"// Code generated by pact codegen ts from spec.pact. DO NOT EDIT.",
+		"export interface User {",
+		"  id: string;\n",
+		"  nickname: string | null;\n",
+		"  tags: string[];\n",
+		"  friends: User[] | null;\n",
+		"  meta: Record<string, number>;\n",
+		"  page: Page<User>;\n",
+		"  createdAt: string;\n",
+		"  extra: unknown;\n",
+	)
+	if strings.Contains(code, "passwordHash") {
+		t.Errorf("private field should not be exported:\n%s", code)
+	}
+}
+
+// CT002: enum は .ts では enum、.d.ts では文字列リテラルの union になる
+func TestTypeScript_Enum(t *testing.T) {
+	src := `component A {
+  enum Status { Active Closed }
+  type Code = int
+}`
+	assertContains(t, generateTS(t, src, TSOptions{}),
+		"export enum Status {\n  Active = \"Active\",\n  Closed = \"Closed\",\n}",
+		"export type Code = number;",
+	)
+	assertContains(t, generateTS(t, src, TSOptions{Declaration: true}),
+		`export type Status = "Active" | "Closed";`,
+	)
+}
+
+// CT003: provides と requires は interface になり、async は Promise、throws は @throws になる
+func TestTypeScript_Interface(t *testing.T) {
+	code := generateTS(t, `component Auth {
+  provides AuthService {
+    async Login(email: string, password: string) -> string throws InvalidCredentials
+    Logout(token: string)
+    async Ping()
+  }
+  requires Clock {
+    Now() -> datetime
+  }
+}`, TSOptions{})
+	assertContains(t, code,
+		"export interface AuthService {",
+		"  /**\n   * @throws {InvalidCredentials}\n   */\n  Login(email: string, password: string): Promise<string>;",
+		"  Logout(token: string): void;",
+		"  Ping(): Promise<void>;",
+		"export interface Clock {\n  Now(): string;\n}",
+	)
+}
+
+// CT004: 型の対応表で組み込みの型名を上書きできる
+func TestTypeScript_TypeTable(t *testing.T) {
+	code := generateTS(t, `component A {
+  type Event {
+    +at: datetime
+    +amount: Money?
+    +slots: datetime?[]
+  }
+}`, TSOptions{Types: map[string]string{"datetime": "Date", "Money": "string | number"}})
+	assertContains(t, code,
+		"  at: Date;\n",
+		"  amount: string | number | null;\n",
+	)
+	if got := arrayOf("Date | null"); got != "(Date | null)[]" {
+		t.Errorf("unexpected array of union: %s", got)
+	}
+}
+
+// CT005: 他のファイルの型は import type で取り込む
+func TestTypeScript_Imports(t *testing.T) {
+	alias := "m"
+	spec := &ast.SpecFile{
+		Imports: []ast.ImportDecl{{Path: "./model.pact"}, {Path: "shared.pact", Alias: &alias}},
+		Components: []ast.ComponentDecl{{Name: "Api", Body: ast.ComponentBody{
+			Types: []ast.TypeDecl{{Name: "Order", Kind: ast.TypeKindStruct, Fields: []ast.FieldDecl{
+				{Name: "user", Type: ast.TypeExpr{Name: "User"}},
+				{Name: "money", Type: ast.TypeExpr{Name: "m.Money"}},
+			}}},
+		}}},
+	}
+	model := &ast.SpecFile{Types: []ast.TypeDecl{{Name: "User"}, {Name: "Unused"}}}
+	out, err := TypeScript(spec, TSOptions{Imports: map[string]*ast.SpecFile{"./model.pact": model}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertContains(t, string(out),
+		`import type { User } from "./model";`,
+		`import type * as m from "./shared";`,
+		"  money: m.Money;\n",
+	)
+	if strings.Contains(string(out), "Unused") {
+		t.Errorf("unused type should not be imported:\n%s", out)
+	}
+}
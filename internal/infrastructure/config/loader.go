@@ -35,6 +35,9 @@ func (l *Loader) Load(path string) (*domainConfig.Config, error) {
 	if err := yaml.Unmarshal(data, cfg); err != nil {
 		return nil, &errors.ConfigError{Path: path, Message: "invalid YAML: " + err.Error()}
 	}
+	if err := cfg.ValidateRules(); err != nil {
+		return nil, &errors.ConfigError{Path: path, Message: err.Error()}
+	}
 
 	return cfg, nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	domainConfig "pact/internal/domain/config"
@@ -233,3 +234,99 @@ func TestLoader_FindProjectRoot_Nested(t *testing.T) {
 		t.Errorf("expected root %q, got %q", tmpDir, root)
 	}
 }
+
+// =============================================================================
+// CL010: codegen
+// =============================================================================
+
+// CL010: TypeScript の型の対応表
+func TestLoader_Load_CodegenTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".pactconfig")
+
+	content := `language: typescript
+codegen:
+  typescript:
+    declaration: true
+    types:
+      datetime: Date
+      Money: string
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := cfg.Codegen.TypeScript
+	if !ts.Declaration {
+		t.Error("expected declaration to be true")
+	}
+	if ts.Types["datetime"] != "Date" || ts.Types["Money"] != "string" {
+		t.Errorf("unexpected type table: %v", ts.Types)
+	}
+}
+
+// =============================================================================
+// CL011-CL012: rules
+// =============================================================================
+
+// CL011: 依存関係のルール
+func TestLoader_Load_Rules(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".pactconfig")
+
+	content := `rules:
+  - name: domain-independence
+    from: { annotation: 'layer("domain")' }
+    forbid:
+      - annotation: 'layer("infrastructure")'
+  - name: external-via-api
+    to: { target_type: external }
+    allow_from:
+      - annotation: 'layer("api")'
+      - path: gateways/**
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(cfg.Rules))
+	}
+	if cfg.Rules[0].From.Annotation != `layer("domain")` || len(cfg.Rules[0].Forbid) != 1 {
+		t.Errorf("unexpected rule: %+v", cfg.Rules[0])
+	}
+	if cfg.Rules[1].To.TargetType != "external" || cfg.Rules[1].AllowFrom[1].Path != "gateways/**" {
+		t.Errorf("unexpected rule: %+v", cfg.Rules[1])
+	}
+}
+
+// CL012: 不正なルールは ConfigError
+func TestLoader_Load_InvalidRule(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".pactconfig")
+
+	content := `rules:
+  - name: empty
+    from: { name: "*Service" }
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewLoader().Load(configPath)
+	if _, ok := err.(*errors.ConfigError); !ok {
+		t.Fatalf("expected ConfigError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "empty") {
+		t.Errorf("error should name the rule: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package formatter
+
+import (
+	"sort"
+
+	"pact/internal/domain/ast"
+)
+
+// posKey はコメントを付ける宣言の位置
+type posKey struct {
+	line, column int
+}
+
+func keyOf(pos ast.Position) posKey {
+	return posKey{pos.Line, pos.Column}
+}
+
+func (k posKey) before(other posKey) bool {
+	if k.line != other.line {
+		return k.line < other.line
+	}
+	return k.column < other.column
+}
+
+// commentMap はコメントを出力先の宣言・ステップに対応づける
+// 同じ行で宣言より後ろにあるコメントは行末コメント、それ以外は次の宣言の前置コメントになる
+type commentMap struct {
+	lead  map[posKey][]ast.Comment
+	trail map[posKey][]ast.Comment
+	rem   []ast.Comment // 後ろに宣言がないコメント
+}
+
+func attachComments(spec *ast.SpecFile) *commentMap {
+	m := &commentMap{
+		lead:  make(map[posKey][]ast.Comment),
+		trail: make(map[posKey][]ast.Comment),
+	}
+	if len(spec.Comments) == 0 {
+		return m
+	}
+
+	anchors := collectAnchors(spec)
+	sort.Slice(anchors, func(i, j int) bool { return anchors[i].before(anchors[j]) })
+
+	for _, c := range spec.Comments {
+		key := keyOf(c.Pos)
+
+		// 同じ行で直前にある宣言の行末コメント
+		var owner *posKey
+		for i := range anchors {
+			a := anchors[i]
+			if a.line == key.line && a.column < key.column {
+				owner = &anchors[i]
+			}
+		}
+		if owner != nil {
+			m.trail[*owner] = append(m.trail[*owner], c)
+			continue
+		}
+
+		// 後ろにある最初の宣言の前置コメント
+		i := sort.Search(len(anchors), func(i int) bool { return key.before(anchors[i]) })
+		if i < len(anchors) {
+			m.lead[anchors[i]] = append(m.lead[anchors[i]], c)
+		} else {
+			m.rem = append(m.rem, c)
+		}
+	}
+	return m
+}
+
+// leading は pos の前置コメントを返し、出力済みとして取り除く
+func (m *commentMap) leading(pos ast.Position) []ast.Comment {
+	k := keyOf(pos)
+	comments := m.lead[k]
+	delete(m.lead, k)
+	return comments
+}
+
+// hasLeading は pos に出力していない前置コメントがあるかどうかを返す
+func (m *commentMap) hasLeading(pos ast.Position) bool {
+	return len(m.lead[keyOf(pos)]) > 0
+}
+
+// trailing は pos の行末コメントを返し、出力済みとして取り除く
+func (m *commentMap) trailing(pos ast.Position) []ast.Comment {
+	k := keyOf(pos)
+	comments := m.trail[k]
+	delete(m.trail, k)
+	return comments
+}
+
+// rest はまだ出力していないコメントを位置順に返す
+func (m *commentMap) rest() []ast.Comment {
+	rest := m.rem
+	for _, comments := range m.lead {
+		rest = append(rest, comments...)
+	}
+	for _, comments := range m.trail {
+		rest = append(rest, comments...)
+	}
+	m.rem, m.lead, m.trail = nil, map[posKey][]ast.Comment{}, map[posKey][]ast.Comment{}
+
+	sort.SliceStable(rest, func(i, j int) bool { return keyOf(rest[i].Pos).before(keyOf(rest[j].Pos)) })
+	return rest
+}
+
+// collectAnchors は整形時に1行目を出力する宣言・ステップ・enum の値と、ブロックの閉じ括弧の位置を集める
+// 閉じ括弧の位置に付いたコメントは、ブロック末尾のコメントとして } の前（行末コメントは後ろ）に出力する
+func collectAnchors(spec *ast.SpecFile) []posKey {
+	var anchors []posKey
+	add := func(pos ast.Position) {
+		if pos.IsValid() {
+			anchors = append(anchors, keyOf(pos))
+		}
+	}
+	addAnnotations := func(annotations []ast.AnnotationDecl) {
+		for _, ann := range annotations {
+			add(ann.Pos)
+		}
+	}
+
+	var addSteps func(steps []ast.Step)
+	addSteps = func(steps []ast.Step) {
+		for _, step := range steps {
+			add(step.GetPos())
+			switch s := step.(type) {
+			case *ast.AssignStep:
+				addAnnotations(s.Annotations)
+			case *ast.CallStep:
+				addAnnotations(s.Annotations)
+			case *ast.ReturnStep:
+				addAnnotations(s.Annotations)
+			case *ast.ThrowStep:
+				addAnnotations(s.Annotations)
+			case *ast.IfStep:
+				addAnnotations(s.Annotations)
+				addSteps(s.Then)
+				add(s.ThenEnd)
+				addSteps(s.Else)
+				add(s.ElseEnd)
+			case *ast.ForStep:
+				addAnnotations(s.Annotations)
+				addSteps(s.Body)
+				add(s.End)
+			case *ast.WhileStep:
+				addAnnotations(s.Annotations)
+				addSteps(s.Body)
+				add(s.End)
+			}
+		}
+	}
+
+	var addStates func(states []ast.StateDecl)
+	addTransitions := func(transitions []ast.TransitionDecl) {
+		for _, t := range transitions {
+			add(t.Pos)
+		}
+	}
+	addStates = func(states []ast.StateDecl) {
+		for _, s := range states {
+			add(s.Pos)
+			addAnnotations(s.Annotations)
+			addStates(s.States)
+			addTransitions(s.Transitions)
+			add(s.End)
+		}
+	}
+
+	for _, imp := range spec.Imports {
+		add(imp.Pos)
+	}
+	for _, comp := range spec.Components {
+		add(comp.Pos)
+		addAnnotations(comp.Annotations)
+		for _, typ := range comp.Body.Types {
+			add(typ.Pos)
+			addAnnotations(typ.Annotations)
+			for _, field := range typ.Fields {
+				add(field.Pos)
+				addAnnotations(field.Annotations)
+			}
+			for _, pos := range typ.ValuePos {
+				add(pos)
+			}
+			add(typ.End)
+		}
+		for _, rel := range comp.Body.Relations {
+			add(rel.Pos)
+			addAnnotations(rel.Annotations)
+		}
+		for _, ifaces := range [][]ast.InterfaceDecl{comp.Body.Provides, comp.Body.Requires} {
+			for _, iface := range ifaces {
+				add(iface.Pos)
+				addAnnotations(iface.Annotations)
+				for _, method := range iface.Methods {
+					add(method.Pos)
+					addAnnotations(method.Annotations)
+				}
+				add(iface.End)
+			}
+		}
+		for _, flow := range comp.Body.Flows {
+			add(flow.Pos)
+			addAnnotations(flow.Annotations)
+			addSteps(flow.Steps)
+			add(flow.End)
+		}
+		for _, states := range comp.Body.States {
+			add(states.Pos)
+			addAnnotations(states.Annotations)
+			addStates(states.States)
+			addTransitions(states.Transitions)
+			for _, par := range states.Parallels {
+				add(par.Pos)
+				addAnnotations(par.Annotations)
+				for _, region := range par.Regions {
+					add(region.Pos)
+					addStates(region.States)
+					addTransitions(region.Transitions)
+					add(region.End)
+				}
+				add(par.End)
+			}
+			add(states.End)
+		}
+		add(comp.End)
+	}
+	return anchors
+}
@@ -0,0 +1,51 @@
+package formatter
+
+import (
+	"strings"
+
+	"pact/internal/domain/ast"
+)
+
+// Declaration は1つの宣言を正規化したソースで返す（エディタのホバー表示などに使う）
+// 対応していない宣言は空文字列を返す
+func Declaration(decl interface{}) string {
+	p := &printer{comments: &commentMap{}}
+
+	switch d := decl.(type) {
+	case *ast.TypeDecl:
+		p.typeDecl(d)
+	case *ast.FieldDecl:
+		p.annotations(d.Annotations)
+		p.raw(visibilitySymbol(d.Visibility) + d.Name + ": " + formatType(d.Type))
+	case *ast.MethodDecl:
+		p.method(d)
+	case *ast.RelationDecl:
+		p.relation(d)
+	case *ast.FlowDecl:
+		p.flow(d)
+	case *ast.StatesDecl:
+		p.states(d)
+	case *ast.StateDecl:
+		p.state(d)
+	default:
+		return ""
+	}
+	return strings.TrimSuffix(p.buf.String(), "\n")
+}
+
+// Interface は provides / requires の宣言を正規化したソースで返す
+func Interface(keyword string, iface *ast.InterfaceDecl) string {
+	p := &printer{comments: &commentMap{}}
+	p.iface(keyword, iface)
+	return strings.TrimSuffix(p.buf.String(), "\n")
+}
+
+// Type は型を .pact の表記で返す（仕様の差分の表示などに使う）
+func Type(t ast.TypeExpr) string {
+	return formatType(t)
+}
+
+// Expr は式を正規化したソースで返す（ガード条件の表示などに使う）
+func Expr(expr ast.Expr) string {
+	return formatExpr(expr)
+}
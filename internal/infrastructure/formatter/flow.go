@@ -0,0 +1,222 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pact/internal/domain/ast"
+)
+
+// =============================================================================
+// Flow
+// =============================================================================
+
+func (p *printer) flow(flow *ast.FlowDecl) {
+	p.annotations(flow.Annotations)
+	p.block(flow.Pos, flow.End, "flow "+flow.Name, flow.Steps)
+}
+
+// block はステップの並びを { } で囲んで出力する
+func (p *printer) block(pos, end ast.Position, header string, steps []ast.Step) {
+	if len(steps) == 0 {
+		p.empty(pos, end, header)
+		return
+	}
+	p.open(pos, header)
+	p.steps(steps)
+	p.close(end)
+}
+
+func (p *printer) steps(steps []ast.Step) {
+	for _, step := range steps {
+		p.step(step)
+	}
+}
+
+func (p *printer) step(step ast.Step) {
+	switch s := step.(type) {
+	case *ast.AssignStep:
+		p.annotations(s.Annotations)
+		p.line(s.Pos, s.Variable+" = "+formatExpr(s.Value))
+
+	case *ast.CallStep:
+		p.annotations(s.Annotations)
+		text := formatExpr(s.Expr)
+		if s.Await {
+			text = "await " + text
+		}
+		p.line(s.Pos, text)
+
+	case *ast.ReturnStep:
+		p.annotations(s.Annotations)
+		text := "return"
+		if s.Value != nil {
+			text += " " + formatExpr(s.Value)
+		}
+		p.line(s.Pos, text)
+
+	case *ast.ThrowStep:
+		p.annotations(s.Annotations)
+		p.line(s.Pos, "throw "+s.Error)
+
+	case *ast.IfStep:
+		p.annotations(s.Annotations)
+		header := "if " + formatExpr(s.Condition)
+		if s.Else == nil {
+			p.block(s.Pos, s.ThenEnd, header, s.Then)
+			return
+		}
+		p.open(s.Pos, header)
+		p.steps(s.Then)
+		if len(s.Else) == 0 && !p.comments.hasLeading(s.ElseEnd) {
+			p.closeWith(s.ThenEnd, "} else {}"+p.trailingText(s.ElseEnd))
+			return
+		}
+		p.closeWith(s.ThenEnd, "} else {")
+		p.depth++
+		p.opened = true
+		p.steps(s.Else)
+		p.close(s.ElseEnd)
+
+	case *ast.ForStep:
+		p.annotations(s.Annotations)
+		p.block(s.Pos, s.End, "for "+s.Variable+" in "+formatExpr(s.Iterable), s.Body)
+
+	case *ast.WhileStep:
+		p.annotations(s.Annotations)
+		p.block(s.Pos, s.End, "while "+formatExpr(s.Condition), s.Body)
+	}
+}
+
+// =============================================================================
+// Expression
+// =============================================================================
+
+// 式の結合の強さ（パーサーの curPrecedence に対応する）
+const (
+	precTernary = 0
+	precNullish = 1
+	precUnary   = 7
+	precPrimary = 9
+)
+
+var binaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, ">": 4, "<=": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+func precedence(expr ast.Expr) int {
+	switch e := expr.(type) {
+	case *ast.TernaryExpr:
+		return precTernary
+	case *ast.NullishExpr:
+		return precNullish
+	case *ast.BinaryExpr:
+		return binaryPrecedence[e.Op]
+	case *ast.UnaryExpr:
+		return precUnary
+	default:
+		return precPrimary
+	}
+}
+
+// formatExpr は式を必要最小限の括弧で文字列にする
+func formatExpr(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case nil:
+		return ""
+
+	case *ast.LiteralExpr:
+		return formatLiteral(e.Value)
+
+	case *ast.VariableExpr:
+		return e.Name
+
+	case *ast.FieldExpr:
+		return formatObject(e.Object) + "." + e.Field
+
+	case *ast.CallExpr:
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = formatExpr(arg)
+		}
+		call := e.Method + "(" + strings.Join(args, ", ") + ")"
+		if e.Object == nil {
+			return call
+		}
+		return formatObject(e.Object) + "." + call
+
+	case *ast.UnaryExpr:
+		// 単項演算子の被演算子はパーサー上一次式なので、それ以外は括弧で囲む
+		operand := formatExpr(e.Operand)
+		switch e.Operand.(type) {
+		case *ast.VariableExpr, *ast.LiteralExpr, *ast.UnaryExpr:
+		default:
+			operand = "(" + operand + ")"
+		}
+		return e.Op + operand
+
+	case *ast.BinaryExpr:
+		prec := binaryPrecedence[e.Op]
+		return formatOperand(e.Left, prec, false) + " " + e.Op + " " + formatOperand(e.Right, prec, true)
+
+	case *ast.NullishExpr:
+		left := formatOperand(e.Left, precNullish, false)
+		if e.ThrowErr != nil {
+			return left + " ?? throw " + *e.ThrowErr
+		}
+		return left + " ?? " + formatOperand(e.Right, precNullish, true)
+
+	case *ast.TernaryExpr:
+		cond := formatOperand(e.Condition, precNullish, false)
+		return cond + " ? " + formatExpr(e.Then) + " : " + formatExpr(e.Else)
+
+	default:
+		return fmt.Sprintf("%v", expr)
+	}
+}
+
+// formatOperand は演算子の被演算子を出力する
+// 左結合なので、右辺は同じ強さでも括弧が必要になる
+func formatOperand(expr ast.Expr, prec int, right bool) string {
+	p := precedence(expr)
+	if p < prec || (right && p == prec) {
+		return "(" + formatExpr(expr) + ")"
+	}
+	return formatExpr(expr)
+}
+
+// formatObject はフィールド参照・呼び出しの対象を出力する
+// パーサーは "!a.b" を (!a).b と読むので、単項式は括弧なしで書ける
+func formatObject(expr ast.Expr) string {
+	if precedence(expr) < precUnary {
+		return "(" + formatExpr(expr) + ")"
+	}
+	return formatExpr(expr)
+}
+
+func formatLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return quote(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEn") {
+			s += ".0"
+		}
+		return s
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
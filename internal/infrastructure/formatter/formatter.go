@@ -0,0 +1,390 @@
+// Package formatter prints .pact ASTs back to canonical source.
+package formatter
+
+import (
+	"bytes"
+	"strings"
+
+	"pact/internal/domain/ast"
+	"pact/internal/infrastructure/parser"
+)
+
+// 整形のレイアウト設定
+const (
+	indentUnit = "  " // インデント1段分
+	maxWidth   = 80   // throws を折り返す行幅
+)
+
+// Format はソースをパースし、正規化した .pact ソースを返す
+// 構文エラーがある場合は整形せずにエラーを返す
+func Format(src []byte) ([]byte, error) {
+	spec, err := parser.ParseString(string(src))
+	if err != nil {
+		return nil, err
+	}
+	return Print(spec), nil
+}
+
+// Print は AST を正規化した .pact ソースに変換する
+// spec.Comments のコメントは最も近い宣言・ステップに付けて出力する
+func Print(spec *ast.SpecFile) []byte {
+	p := &printer{comments: attachComments(spec)}
+	p.file(spec)
+	return p.buf.Bytes()
+}
+
+// printer は正規化したソースを組み立てる
+type printer struct {
+	buf      bytes.Buffer
+	depth    int
+	opened   bool // 直前の行がブロックの開始行か
+	comments *commentMap
+}
+
+// line はインデント付きで1行を出力する
+// pos に付いたコメントは、前置コメントを直前の行に、行末コメントを行の末尾に出力する
+func (p *printer) line(pos ast.Position, text string) {
+	for _, c := range p.comments.leading(pos) {
+		p.raw(c.Text)
+	}
+	p.raw(text + p.trailingText(pos))
+}
+
+// trailingText は pos の行末コメントを行の末尾に付ける形で返す
+func (p *printer) trailingText(pos ast.Position) string {
+	text := ""
+	for _, c := range p.comments.trailing(pos) {
+		text += " " + c.Text
+	}
+	return text
+}
+
+// raw はインデント付きで1行を出力する
+func (p *printer) raw(text string) {
+	if text != "" {
+		p.buf.WriteString(strings.Repeat(indentUnit, p.depth))
+		p.buf.WriteString(text)
+	}
+	p.buf.WriteByte('\n')
+	p.opened = false
+}
+
+// blank は空行を出力する（連続した空行やブロック先頭の空行は出力しない）
+func (p *printer) blank() {
+	b := p.buf.Bytes()
+	if len(b) == 0 || bytes.HasSuffix(b, []byte("\n\n")) || p.opened {
+		return
+	}
+	p.buf.WriteByte('\n')
+}
+
+// open はブロックを開始する
+func (p *printer) open(pos ast.Position, header string) {
+	p.line(pos, header+" {")
+	p.depth++
+	p.opened = true
+}
+
+// close はブロックを終了する
+// end（閉じ括弧の位置）の前置コメントはブロックの中に、行末コメントは } の後ろに出力する
+func (p *printer) close(end ast.Position) {
+	p.closeWith(end, "}")
+}
+
+// closeWith は閉じ括弧の行を text にしてブロックを終了する（"} else {" など）
+func (p *printer) closeWith(end ast.Position, text string) {
+	for _, c := range p.comments.leading(end) {
+		p.raw(c.Text)
+	}
+	p.depth--
+	p.raw(text + p.trailingText(end))
+}
+
+// empty は本体のないブロックを "header {}" の1行で出力する
+// ブロック内にコメントがあれば、コメントだけを本体にしたブロックにする
+func (p *printer) empty(pos, end ast.Position, header string) {
+	if p.comments.hasLeading(end) {
+		p.open(pos, header)
+		p.close(end)
+		return
+	}
+	p.line(pos, header+" {}"+p.trailingText(end))
+}
+
+func (p *printer) file(spec *ast.SpecFile) {
+	for _, imp := range spec.Imports {
+		text := "import " + quote(imp.Path)
+		if imp.Alias != nil {
+			text += " as " + *imp.Alias
+		}
+		p.line(imp.Pos, text)
+	}
+
+	for i := range spec.Components {
+		p.blank()
+		p.component(&spec.Components[i])
+	}
+
+	// どの宣言にも付かなかった末尾のコメント
+	if rest := p.comments.rest(); len(rest) > 0 {
+		p.blank()
+		for _, c := range rest {
+			p.raw(c.Text)
+		}
+	}
+}
+
+func (p *printer) annotations(annotations []ast.AnnotationDecl) {
+	for _, ann := range annotations {
+		p.line(ann.Pos, formatAnnotation(ann))
+	}
+}
+
+// section は本体の1区分を出力する
+// 複数行になる要素の前後には空行を入れ、1行の要素は詰めて並べる
+func (p *printer) section(n int, multiline func(i int) bool, item func(i int)) {
+	if n == 0 {
+		return
+	}
+	p.blank()
+	for i := 0; i < n; i++ {
+		if i > 0 && (multiline(i) || multiline(i-1)) {
+			p.blank()
+		}
+		item(i)
+	}
+}
+
+func (p *printer) component(comp *ast.ComponentDecl) {
+	p.annotations(comp.Annotations)
+
+	body := &comp.Body
+	if isEmptyBody(body) {
+		p.empty(comp.Pos, comp.End, "component "+comp.Name)
+		return
+	}
+
+	// 本体は 型 → 関係 → provides → requires → flow → states の順にまとめる
+	p.open(comp.Pos, "component "+comp.Name)
+	p.section(len(body.Types),
+		func(i int) bool { return body.Types[i].Kind != ast.TypeKindAlias },
+		func(i int) { p.typeDecl(&body.Types[i]) })
+	p.section(len(body.Relations),
+		func(i int) bool { return false },
+		func(i int) { p.relation(&body.Relations[i]) })
+	p.section(len(body.Provides),
+		func(i int) bool { return true },
+		func(i int) { p.iface("provides", &body.Provides[i]) })
+	p.section(len(body.Requires),
+		func(i int) bool { return true },
+		func(i int) { p.iface("requires", &body.Requires[i]) })
+	p.section(len(body.Flows),
+		func(i int) bool { return true },
+		func(i int) { p.flow(&body.Flows[i]) })
+	p.section(len(body.States),
+		func(i int) bool { return true },
+		func(i int) { p.states(&body.States[i]) })
+	p.close(comp.End)
+}
+
+func isEmptyBody(body *ast.ComponentBody) bool {
+	return len(body.Types) == 0 && len(body.Relations) == 0 && len(body.Provides) == 0 &&
+		len(body.Requires) == 0 && len(body.Flows) == 0 && len(body.States) == 0
+}
+
+func (p *printer) typeDecl(typ *ast.TypeDecl) {
+	p.annotations(typ.Annotations)
+
+	switch typ.Kind {
+	case ast.TypeKindAlias:
+		text := "type " + typ.Name + " ="
+		if typ.BaseType != nil {
+			text += " " + formatType(*typ.BaseType)
+		}
+		p.line(typ.Pos, text)
+
+	case ast.TypeKindEnum:
+		if len(typ.Values) == 0 {
+			p.empty(typ.Pos, typ.End, "enum "+typ.Name)
+			return
+		}
+		p.open(typ.Pos, "enum "+typ.Name)
+		for i, v := range typ.Values {
+			var pos ast.Position
+			if i < len(typ.ValuePos) {
+				pos = typ.ValuePos[i]
+			}
+			p.line(pos, v)
+		}
+		p.close(typ.End)
+
+	default:
+		if len(typ.Fields) == 0 {
+			p.empty(typ.Pos, typ.End, "type "+typ.Name)
+			return
+		}
+		p.open(typ.Pos, "type "+typ.Name)
+		for _, field := range typ.Fields {
+			p.annotations(field.Annotations)
+			p.line(field.Pos, visibilitySymbol(field.Visibility)+field.Name+": "+formatType(field.Type))
+		}
+		p.close(typ.End)
+	}
+}
+
+func (p *printer) relation(rel *ast.RelationDecl) {
+	p.annotations(rel.Annotations)
+
+	var text string
+	switch rel.Kind {
+	case ast.RelationDependsOn:
+		text = "depends on " + rel.Target
+		if rel.TargetType != nil {
+			text += ": " + *rel.TargetType
+		}
+		if rel.Alias != nil {
+			text += " as " + *rel.Alias
+		}
+	default:
+		text = string(rel.Kind) + " " + rel.Target
+	}
+	p.line(rel.Pos, text)
+}
+
+func (p *printer) iface(keyword string, iface *ast.InterfaceDecl) {
+	p.annotations(iface.Annotations)
+
+	if len(iface.Methods) == 0 {
+		p.empty(iface.Pos, iface.End, keyword+" "+iface.Name)
+		return
+	}
+	p.open(iface.Pos, keyword+" "+iface.Name)
+	for i := range iface.Methods {
+		p.method(&iface.Methods[i])
+	}
+	p.close(iface.End)
+}
+
+// method はメソッドを出力する
+// 行幅を超える場合は throws を次の行に送り、それでも収まらなければ1つずつ折り返す
+func (p *printer) method(m *ast.MethodDecl) {
+	p.annotations(m.Annotations)
+
+	sig := m.Name + "("
+	for i, param := range m.Params {
+		if i > 0 {
+			sig += ", "
+		}
+		sig += param.Name + ": " + formatType(param.Type)
+	}
+	sig += ")"
+	if m.Async {
+		sig = "async " + sig
+	}
+	if m.ReturnType != nil {
+		sig += " -> " + formatType(*m.ReturnType)
+	}
+	if len(m.Throws) == 0 {
+		p.line(m.Pos, sig)
+		return
+	}
+
+	throws := "throws " + strings.Join(m.Throws, ", ")
+	indent := len(indentUnit) * p.depth
+	if indent+len(sig)+1+len(throws) <= maxWidth {
+		p.line(m.Pos, sig+" "+throws)
+		return
+	}
+
+	p.line(m.Pos, sig)
+	p.depth++
+	defer func() { p.depth-- }()
+	if indent+len(indentUnit)+len(throws) <= maxWidth {
+		p.raw(throws)
+		return
+	}
+	p.raw("throws " + m.Throws[0] + ",")
+	p.depth++
+	for i, name := range m.Throws[1:] {
+		if i < len(m.Throws)-2 {
+			name += ","
+		}
+		p.raw(name)
+	}
+	p.depth--
+}
+
+func formatAnnotation(ann ast.AnnotationDecl) string {
+	text := "@" + ann.Name
+	if len(ann.Args) == 0 {
+		return text
+	}
+	args := make([]string, len(ann.Args))
+	for i, arg := range ann.Args {
+		args[i] = quote(arg.Value)
+		if arg.Key != nil {
+			args[i] = *arg.Key + ": " + args[i]
+		}
+	}
+	return text + "(" + strings.Join(args, ", ") + ")"
+}
+
+func formatType(t ast.TypeExpr) string {
+	text := t.Name
+	if len(t.TypeParams) > 0 {
+		params := make([]string, len(t.TypeParams))
+		for i, tp := range t.TypeParams {
+			params[i] = formatType(tp)
+		}
+		text += "<" + strings.Join(params, ", ") + ">"
+	}
+	if t.Nullable && !t.Array {
+		text += "?"
+	}
+	if t.Array {
+		text += "[]"
+		if t.Nullable {
+			text += "?"
+		}
+	}
+	return text
+}
+
+func visibilitySymbol(v ast.Visibility) string {
+	switch v {
+	case ast.VisibilityPublic:
+		return "+"
+	case ast.VisibilityPrivate:
+		return "-"
+	case ast.VisibilityProtected:
+		return "#"
+	case ast.VisibilityPackage:
+		return "~"
+	default:
+		return ""
+	}
+}
+
+// quote は文字列をレキサーが読めるエスケープでクォートする
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,406 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func format(t *testing.T, src string) string {
+	t.Helper()
+	out, err := Format([]byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := Format(out)
+	if err != nil {
+		t.Fatalf("formatted output does not parse: %v\n%s", err, out)
+	}
+	if string(again) != string(out) {
+		t.Errorf("format is not idempotent:\nfirst:\n%s\nsecond:\n%s", out, again)
+	}
+	return string(out)
+}
+
+func assertFormat(t *testing.T, src, want string) {
+	t.Helper()
+	if got := format(t, src); got != want {
+		t.Errorf("unexpected output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// =============================================================================
+// FM001-FM005: 宣言
+// =============================================================================
+
+// FM001: インデントと記号まわりの空白
+func TestFormat_Spacing(t *testing.T) {
+	src := `component   Order{
+type Item{+id:string
+ tags:string[]?
+}
+provides API{Get(id:string)->Item?}
+}
+`
+	want := `component Order {
+  type Item {
+    +id: string
+    tags: string[]?
+  }
+
+  provides API {
+    Get(id: string) -> Item?
+  }
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM002: 本体の区分をまとめる
+func TestFormat_GroupsSections(t *testing.T) {
+	src := `component Order {
+  flow Run { return null }
+  depends on Repo
+  type Id = string
+  provides API { Get() }
+  depends on Logger: service as log
+  type Name = string
+}
+`
+	want := `component Order {
+  type Id = string
+  type Name = string
+
+  depends on Repo
+  depends on Logger: service as log
+
+  provides API {
+    Get()
+  }
+
+  flow Run {
+    return null
+  }
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM003: 長い throws の折り返し
+func TestFormat_WrapsThrows(t *testing.T) {
+	src := `component Order {
+  provides API {
+    Short() throws A, B
+    async CreateOrder(request: CreateOrderRequest, context: Context) -> Order throws ValidationError, NotFound
+  }
+}
+`
+	want := `component Order {
+  provides API {
+    Short() throws A, B
+    async CreateOrder(request: CreateOrderRequest, context: Context) -> Order
+      throws ValidationError, NotFound
+  }
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM004: 1行に収まらない throws は1つずつ折り返す
+func TestFormat_WrapsThrowsPerLine(t *testing.T) {
+	names := []string{
+		"VeryLongValidationErrorName", "AnotherVeryLongErrorName",
+		"YetAnotherVeryLongErrorName", "FinalVeryLongErrorName",
+	}
+	src := "component C {\n  provides API {\n    Run() throws " + strings.Join(names, ", ") + "\n  }\n}\n"
+	want := `component C {
+  provides API {
+    Run()
+      throws VeryLongValidationErrorName,
+        AnotherVeryLongErrorName,
+        YetAnotherVeryLongErrorName,
+        FinalVeryLongErrorName
+  }
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM005: import とアノテーション
+func TestFormat_ImportsAndAnnotations(t *testing.T) {
+	src := `import   "./a.pact"  as a
+import "./b.pact"
+@description( "Order \"service\"" ) @owner(team:"core")
+component Order {}
+component Empty { }
+`
+	want := `import "./a.pact" as a
+import "./b.pact"
+
+@description("Order \"service\"")
+@owner(team: "core")
+component Order {}
+
+component Empty {}
+`
+	assertFormat(t, src, want)
+}
+
+// =============================================================================
+// FM010-FM012: flow と states
+// =============================================================================
+
+// FM010: ステップと式の括弧
+func TestFormat_Flow(t *testing.T) {
+	src := `component C {
+  flow Run {
+    x = (a + b) * c
+    y = a - (b - c)
+    z = ((a))
+    if !(a && b) { throw Failed } else { await repo.save(x) }
+    for i in items { n = n + 1 }
+    while n > 0 { n = n - 1 }
+    v = a ?? throw NotFound
+    return ok ? 1 : 2.5
+  }
+}
+`
+	want := `component C {
+  flow Run {
+    x = (a + b) * c
+    y = a - (b - c)
+    z = a
+    if !(a && b) {
+      throw Failed
+    } else {
+      await repo.save(x)
+    }
+    for i in items {
+      n = n + 1
+    }
+    while n > 0 {
+      n = n - 1
+    }
+    v = a ?? throw NotFound
+    return ok ? 1 : 2.5
+  }
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM011: 状態機械
+func TestFormat_States(t *testing.T) {
+	src := `component C {
+  states Life {
+    Idle -> Active on start when ready do [init,log]
+    initial Idle
+    state Idle {}
+    state Active { entry [begin] initial Sub state Sub {} Sub -> Sub on tick }
+    final Done
+    Active -> Done after 5m
+  }
+}
+`
+	want := `component C {
+  states Life {
+    initial Idle
+    final Done
+
+    state Idle {}
+
+    state Active {
+      entry [begin]
+      initial Sub
+
+      state Sub {}
+
+      Sub -> Sub on tick
+    }
+
+    Idle -> Active on start when ready do [init, log]
+    Active -> Done after 5m
+  }
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM012: 並行状態
+func TestFormat_Parallel(t *testing.T) {
+	src := `component C {
+  states Life {
+    initial Running
+    parallel Running { region A { initial A1 state A1 {} } region B {} }
+  }
+}
+`
+	want := `component C {
+  states Life {
+    initial Running
+
+    parallel Running {
+      region A {
+        initial A1
+
+        state A1 {}
+      }
+
+      region B {}
+    }
+  }
+}
+`
+	assertFormat(t, src, want)
+}
+
+// =============================================================================
+// FM020-FM024: コメント
+// =============================================================================
+
+// FM020: 前置コメントと行末コメントを保持する
+func TestFormat_PreservesComments(t *testing.T) {
+	src := `// file header
+component Order { // the order
+  // the item
+  type Item {
+    id: string   // identifier
+  }
+  flow Run {
+    /* check first */
+    x = 1
+  }
+}
+`
+	want := `// file header
+component Order { // the order
+  // the item
+  type Item {
+    id: string // identifier
+  }
+
+  flow Run {
+    /* check first */
+    x = 1
+  }
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM021: 末尾のコメントを保持する
+func TestFormat_TrailingFileComments(t *testing.T) {
+	src := `component Order {}
+// end of file
+`
+	want := `component Order {}
+
+// end of file
+`
+	assertFormat(t, src, want)
+}
+
+// FM022: 並べ替えた宣言にコメントが付いていく
+func TestFormat_CommentsFollowDeclarations(t *testing.T) {
+	src := `component C {
+  // runs things
+  flow Run {}
+  // the id
+  type Id = string
+}
+`
+	want := `component C {
+  // the id
+  type Id = string
+
+  // runs things
+  flow Run {}
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM023: enum の値のコメントは値に付く
+func TestFormat_EnumValueComments(t *testing.T) {
+	src := `component Order {
+  enum Status {
+    // awaiting payment
+    PENDING
+    PAID   // settled
+  }
+  flow Pay {}
+}
+`
+	want := `component Order {
+  enum Status {
+    // awaiting payment
+    PENDING
+    PAID // settled
+  }
+
+  flow Pay {}
+}
+`
+	assertFormat(t, src, want)
+}
+
+// FM024: ブロック末尾のコメントは閉じ括弧の前に、閉じ括弧の行末コメントは後ろに残す
+func TestFormat_BlockEndComments(t *testing.T) {
+	src := `component Order {
+  enum Status {
+    PENDING
+    // more to come
+  }
+  flow Pay {
+    if paid {
+      return
+      // already done
+    } else {
+      // nothing to do
+    }
+    // TODO: handle refunds
+  } // end of flow
+  states Lifecycle {
+    state Pending {
+      // not decided yet
+    }
+  }
+  // end of component
+}
+`
+	want := `component Order {
+  enum Status {
+    PENDING
+    // more to come
+  }
+
+  flow Pay {
+    if paid {
+      return
+      // already done
+    } else {
+      // nothing to do
+    }
+    // TODO: handle refunds
+  } // end of flow
+
+  states Lifecycle {
+    state Pending {
+      // not decided yet
+    }
+  }
+  // end of component
+}
+`
+	assertFormat(t, src, want)
+}
+
+// =============================================================================
+// FM030: エラー
+// =============================================================================
+
+// FM030: 構文エラーは整形しない
+func TestFormat_SyntaxError(t *testing.T) {
+	if _, err := Format([]byte("component {")); err == nil {
+		t.Error("expected syntax error")
+	}
+}
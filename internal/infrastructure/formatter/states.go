@@ -0,0 +1,146 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+
+	"pact/internal/domain/ast"
+)
+
+// =============================================================================
+// States
+// =============================================================================
+
+// states は状態機械を出力する
+// initial/final → state → parallel → 遷移 の順にまとめる
+func (p *printer) states(states *ast.StatesDecl) {
+	p.annotations(states.Annotations)
+
+	if states.Initial == "" && len(states.Finals) == 0 && len(states.States) == 0 &&
+		len(states.Parallels) == 0 && len(states.Transitions) == 0 {
+		p.empty(states.Pos, states.End, "states "+states.Name)
+		return
+	}
+
+	p.open(states.Pos, "states "+states.Name)
+	if states.Initial != "" {
+		p.raw("initial " + states.Initial)
+	}
+	for _, final := range states.Finals {
+		p.raw("final " + final)
+	}
+	p.stateList(states.States)
+	for i := range states.Parallels {
+		p.blank()
+		p.parallel(&states.Parallels[i])
+	}
+	p.transitions(states.Transitions)
+	p.close(states.End)
+}
+
+func (p *printer) stateList(states []ast.StateDecl) {
+	for i := range states {
+		if i == 0 || !isSimpleState(&states[i]) || !isSimpleState(&states[i-1]) {
+			p.blank()
+		}
+		p.state(&states[i])
+	}
+}
+
+// isSimpleState は本体が1行に収まる状態かどうかを返す
+func isSimpleState(s *ast.StateDecl) bool {
+	return s.Initial == nil && len(s.States) == 0 && len(s.Transitions) == 0 &&
+		len(s.Entry)+len(s.Exit) == 0
+}
+
+func (p *printer) state(s *ast.StateDecl) {
+	p.annotations(s.Annotations)
+
+	if isSimpleState(s) {
+		p.empty(s.Pos, s.End, "state "+s.Name)
+		return
+	}
+
+	p.open(s.Pos, "state "+s.Name)
+	if len(s.Entry) > 0 {
+		p.raw("entry " + formatActions(s.Entry))
+	}
+	if len(s.Exit) > 0 {
+		p.raw("exit " + formatActions(s.Exit))
+	}
+	if s.Initial != nil {
+		p.raw("initial " + *s.Initial)
+	}
+	p.stateList(s.States)
+	p.transitions(s.Transitions)
+	p.close(s.End)
+}
+
+func (p *printer) parallel(par *ast.ParallelDecl) {
+	p.annotations(par.Annotations)
+
+	if len(par.Regions) == 0 {
+		p.empty(par.Pos, par.End, "parallel "+par.Name)
+		return
+	}
+
+	p.open(par.Pos, "parallel "+par.Name)
+	for i := range par.Regions {
+		if i > 0 {
+			p.blank()
+		}
+		p.region(&par.Regions[i])
+	}
+	p.close(par.End)
+}
+
+func (p *printer) region(r *ast.RegionDecl) {
+	if r.Initial == "" && len(r.States) == 0 && len(r.Transitions) == 0 {
+		p.empty(r.Pos, r.End, "region "+r.Name)
+		return
+	}
+
+	p.open(r.Pos, "region "+r.Name)
+	if r.Initial != "" {
+		p.raw("initial " + r.Initial)
+	}
+	p.stateList(r.States)
+	p.transitions(r.Transitions)
+	p.close(r.End)
+}
+
+func (p *printer) transitions(transitions []ast.TransitionDecl) {
+	if len(transitions) == 0 {
+		return
+	}
+	p.blank()
+	for i := range transitions {
+		p.line(transitions[i].Pos, formatTransition(&transitions[i]))
+	}
+}
+
+func formatTransition(t *ast.TransitionDecl) string {
+	text := t.From + " -> " + t.To
+
+	switch trigger := t.Trigger.(type) {
+	case *ast.EventTrigger:
+		text += " on " + trigger.Event
+	case *ast.AfterTrigger:
+		text += " after " + strconv.Itoa(trigger.Duration.Value) + trigger.Duration.Unit
+	case *ast.WhenTrigger:
+		text += " when " + formatExpr(trigger.Condition)
+	}
+
+	// ガードはトリガーがある場合のみ書ける
+	if t.Guard != nil && t.Trigger != nil {
+		text += " when " + formatExpr(t.Guard)
+	}
+	if len(t.Actions) > 0 {
+		text += " do " + formatActions(t.Actions)
+	}
+	return text
+}
+
+func formatActions(actions []string) string {
+	return "[" + strings.Join(actions, ", ") + "]"
+}
@@ -0,0 +1,260 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"pact/internal/domain/ast"
+)
+
+// document はエディタで開かれている .pact ファイル
+type document struct {
+	uri      string
+	path     string
+	text     string
+	lines    []int         // 各行の先頭のバイトオフセット
+	analyzed bool          // text をパース済みか
+	spec     *ast.SpecFile // パース結果（構文エラー時は途中までの結果）
+	parseErr error         // 構文エラー
+	index    *symbolIndex  // spec から作った宣言の索引
+}
+
+func newDocument(uri, text string) *document {
+	d := &document{uri: uri, path: uriToPath(uri)}
+	d.setText(text)
+	return d
+}
+
+func (d *document) setText(text string) {
+	d.text = text
+	d.analyzed = false
+	d.lines = []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			d.lines = append(d.lines, i+1)
+		}
+	}
+}
+
+// lineText は 0 始まりの行の内容を返す（改行を含まない）
+func (d *document) lineText(line int) string {
+	if line < 0 || line >= len(d.lines) {
+		return ""
+	}
+	end := len(d.text)
+	if line+1 < len(d.lines) {
+		end = d.lines[line+1] - 1
+	}
+	return strings.TrimSuffix(d.text[d.lines[line]:end], "\r")
+}
+
+// offsetAt は LSP の位置をバイトオフセットに変換する
+func (d *document) offsetAt(pos Position) int {
+	if pos.Line < 0 {
+		return 0
+	}
+	if pos.Line >= len(d.lines) {
+		return len(d.text)
+	}
+	start := d.lines[pos.Line]
+	line := d.lineText(pos.Line)
+	units := 0
+	for i, r := range line {
+		if units >= pos.Character {
+			return start + i
+		}
+		units += utf16Len(r)
+	}
+	return start + len(line)
+}
+
+// positionAt はバイトオフセットを LSP の位置に変換する
+func (d *document) positionAt(offset int) Position {
+	if offset > len(d.text) {
+		offset = len(d.text)
+	}
+	line := 0
+	for line+1 < len(d.lines) && d.lines[line+1] <= offset {
+		line++
+	}
+	units := 0
+	for _, r := range d.text[d.lines[line]:offset] {
+		units += utf16Len(r)
+	}
+	return Position{Line: line, Character: units}
+}
+
+// astOffset は AST の位置（1 始まりの行・バイト単位の列）をバイトオフセットに変換する
+func (d *document) astOffset(pos ast.Position) int {
+	if pos.Line < 1 {
+		return 0
+	}
+	if pos.Line > len(d.lines) {
+		return len(d.text)
+	}
+	offset := d.lines[pos.Line-1] + pos.Column - 1
+	if offset < 0 {
+		return 0
+	}
+	if offset > len(d.text) {
+		return len(d.text)
+	}
+	return offset
+}
+
+// tokenRange は AST の位置から始まる単語の範囲を返す（単語でなければ1文字分）
+func (d *document) tokenRange(pos ast.Position) Range {
+	start := d.astOffset(pos)
+	end := start
+	for end < len(d.text) && isWordByte(d.text[end]) {
+		end++
+	}
+	if end == start && end < len(d.text) && d.text[end] != '\n' {
+		_, size := utf8.DecodeRuneInString(d.text[end:])
+		end += size
+	}
+	return Range{Start: d.positionAt(start), End: d.positionAt(end)}
+}
+
+// nameRange は宣言の位置以降で最初に現れる name の範囲を返す
+// 宣言の位置はキーワードを指すため、名前そのものの位置を探す
+func (d *document) nameRange(pos ast.Position, name string) Range {
+	start := d.astOffset(pos)
+	if i := indexWord(d.text[start:], name); i >= 0 {
+		start += i
+		return Range{Start: d.positionAt(start), End: d.positionAt(start + len(name))}
+	}
+	return d.tokenRange(pos)
+}
+
+// blockEnd は宣言の位置以降の最初の { に対応する } の直後のオフセットを返す
+// ブロックを持たない宣言は行末を返す
+func (d *document) blockEnd(pos ast.Position) int {
+	i := d.astOffset(pos)
+	for i < len(d.text) && d.text[i] != '{' && d.text[i] != '\n' {
+		i++
+	}
+	if i >= len(d.text) || d.text[i] != '{' {
+		return i
+	}
+	depth := 0
+	for ; i < len(d.text); i++ {
+		switch d.text[i] {
+		case '"':
+			i = skipString(d.text, i)
+		case '/':
+			i = skipComment(d.text, i)
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(d.text)
+}
+
+// declRange は宣言全体（ブロックを含む）の範囲を返す
+func (d *document) declRange(pos ast.Position) Range {
+	return Range{Start: d.positionAt(d.astOffset(pos)), End: d.positionAt(d.blockEnd(pos))}
+}
+
+// wordAt は位置にある識別子（"alias.Name" の修飾名を含む）とその範囲を返す
+func (d *document) wordAt(pos Position) (string, Range) {
+	offset := d.offsetAt(pos)
+	start, end := offset, offset
+	for start > 0 && (isWordByte(d.text[start-1]) || d.text[start-1] == '.') {
+		start--
+	}
+	for end < len(d.text) && isWordByte(d.text[end]) {
+		end++
+	}
+	word := strings.Trim(d.text[start:end], ".")
+	if word == "" {
+		return "", Range{}
+	}
+	start = strings.Index(d.text[start:end], word) + start
+	return word, Range{Start: d.positionAt(start), End: d.positionAt(start + len(word))}
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// indexWord は s の中で word が単語として現れる最初の位置を返す
+func indexWord(s, word string) int {
+	for offset := 0; ; {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return -1
+		}
+		i += offset
+		end := i + len(word)
+		if (i == 0 || !isWordByte(s[i-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return i
+		}
+		offset = i + 1
+	}
+}
+
+// skipString は i の " から始まる文字列の終わりのオフセットを返す
+func skipString(s string, i int) int {
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"', '\n':
+			return i
+		}
+	}
+	return len(s)
+}
+
+// skipComment は i の / から始まるコメントの終わりのオフセットを返す（コメントでなければ i）
+func skipComment(s string, i int) int {
+	if i+1 >= len(s) {
+		return i
+	}
+	switch s[i+1] {
+	case '/':
+		if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(s)
+	case '*':
+		if end := strings.Index(s[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 1
+		}
+		return len(s)
+	}
+	return i
+}
+
+// uriToPath は file:// URI をファイルパスに変換する
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// pathToURI はファイルパスを file:// URI に変換する
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// utf16Len は文字の UTF-16 での長さを返す
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
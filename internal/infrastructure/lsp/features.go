@@ -0,0 +1,512 @@
+package lsp
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"pact/internal/application/validator"
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+	"pact/internal/infrastructure/formatter"
+)
+
+// =============================================================================
+// 診断
+// =============================================================================
+
+// publishDiagnostics は文書を検証し、診断をクライアントに送る
+func (s *Server) publishDiagnostics(doc *document) {
+	s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Diagnostics: s.diagnostics(doc),
+	})
+}
+
+// diagnostics は pact validate と同じ検証を行う
+// 構文エラーがある場合は構文エラーのみを返す
+func (s *Server) diagnostics(doc *document) []Diagnostic {
+	s.analyze(doc)
+
+	var errs []error
+	if doc.parseErr != nil {
+		errs = flattenErrors(doc.parseErr)
+	} else {
+		imports := make(map[string]*ast.SpecFile)
+		importedDocs := s.imports(doc)
+		for i, imported := range importedDocs {
+			imp := doc.spec.Imports[i]
+			switch {
+			case imported == nil:
+				errs = append(errs, &errors.ImportError{Pos: imp.Pos, Path: imp.Path, Message: "file not found"})
+			case imported.parseErr != nil:
+				errs = append(errs, &errors.ImportError{Pos: imp.Pos, Path: imp.Path, Message: "failed to parse", Cause: imported.parseErr})
+			default:
+				imports[imp.Path] = imported.spec
+			}
+		}
+
+		v := validator.NewValidator()
+		v.SetImports(imports)
+		v.SetSpecImports(s.specImports(importedDocs))
+		errs = append(errs, flattenErrors(v.ValidateAll(doc.spec))...)
+		for _, w := range v.GetWarnings().Warnings {
+			errs = append(errs, w)
+		}
+	}
+
+	diags := []Diagnostic{}
+	for _, err := range errs {
+		d := errors.NewDiagnostic(doc.path, err)
+		severity := severityError
+		if d.Severity == errors.SeverityWarning {
+			severity = severityWarning
+		}
+		diags = append(diags, Diagnostic{
+			Range:    doc.tokenRange(ast.Position{Line: d.Line, Column: d.Column}),
+			Severity: severity,
+			Code:     d.Code,
+			Source:   "pact",
+			Message:  d.Message,
+		})
+	}
+	return diags
+}
+
+// specImports は import 先の文書それぞれの import を推移的に解決し、仕様ごとに import 文のパスをキーにした表を返す
+// 読めない・パースできない import は表に含めない
+func (s *Server) specImports(docs []*document) map[*ast.SpecFile]map[string]*ast.SpecFile {
+	maps := make(map[*ast.SpecFile]map[string]*ast.SpecFile)
+	opened := make(map[string]*document) // 同じファイルは同じ仕様として扱う
+	var visit func(doc *document)
+	visit = func(doc *document) {
+		if _, ok := maps[doc.spec]; ok || doc.path == "" {
+			return
+		}
+		resolved := make(map[string]*ast.SpecFile)
+		maps[doc.spec] = resolved
+		for _, imp := range doc.spec.Imports {
+			path := filepath.Join(filepath.Dir(doc.path), imp.Path)
+			imported, ok := opened[path]
+			if !ok {
+				imported = s.openFile(path)
+				opened[path] = imported
+			}
+			if imported != nil && imported.parseErr == nil {
+				resolved[imp.Path] = imported.spec
+				visit(imported)
+			}
+		}
+	}
+	for _, doc := range docs {
+		if doc != nil && doc.parseErr == nil {
+			opened[doc.path] = doc
+			visit(doc)
+		}
+	}
+	return maps
+}
+
+// flattenErrors は MultiError を個々のエラーに展開する
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if me, ok := err.(*errors.MultiError); ok {
+		var errs []error
+		for _, e := range me.Errors {
+			errs = append(errs, flattenErrors(e)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
+
+// =============================================================================
+// 定義ジャンプ・ホバー
+// =============================================================================
+
+// target は参照先の宣言とその文書
+type target struct {
+	doc *document
+	sym *symbol
+}
+
+func (s *Server) definition(doc *document, pos Position) *Location {
+	t := s.resolve(doc, pos)
+	if t == nil {
+		return nil
+	}
+	return &Location{URI: t.doc.uri, Range: t.sym.nameRange}
+}
+
+func (s *Server) hover(doc *document, pos Position) *Hover {
+	t := s.resolve(doc, pos)
+	if t == nil {
+		return nil
+	}
+	_, r := doc.wordAt(pos)
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: "```pact\n" + hoverText(t.sym) + "\n```"},
+		Range:    &r,
+	}
+}
+
+// hoverText は宣言のシグネチャを返す
+func hoverText(sym *symbol) string {
+	switch decl := sym.decl.(type) {
+	case *ast.ComponentDecl:
+		lines := []string{"component " + decl.Name}
+		for _, rel := range decl.Body.Relations {
+			lines = append(lines, "  "+formatter.Declaration(&rel))
+		}
+		for _, iface := range decl.Body.Provides {
+			lines = append(lines, "  provides "+iface.Name)
+		}
+		for _, iface := range decl.Body.Requires {
+			lines = append(lines, "  requires "+iface.Name)
+		}
+		return strings.Join(lines, "\n")
+	case *ast.InterfaceDecl:
+		return formatter.Interface(sym.keyword, decl)
+	case *ast.FieldDecl:
+		return sym.parent.name + "." + formatter.Declaration(decl)
+	case *ast.MethodDecl:
+		return sym.parent.name + "." + formatter.Declaration(decl)
+	case *ast.FlowDecl:
+		return "flow " + decl.Name
+	case *ast.StatesDecl:
+		return "states " + decl.Name
+	case *ast.ParallelDecl:
+		return "parallel " + decl.Name
+	case *ast.RegionDecl:
+		return "region " + decl.Name
+	case *ast.TypeDecl:
+		if sym.keyword == "value" {
+			return decl.Name + "." + sym.name
+		}
+		return formatter.Declaration(decl)
+	default:
+		return formatter.Declaration(decl)
+	}
+}
+
+// resolve は位置にある名前の宣言を探す
+func (s *Server) resolve(doc *document, pos Position) *target {
+	s.analyze(doc)
+	word, _ := doc.wordAt(pos)
+	if word == "" {
+		return nil
+	}
+
+	// 宣言の名前そのもの
+	for _, sym := range doc.index.all {
+		if contains(sym.nameRange, pos) && sym.name == word {
+			return &target{doc, sym}
+		}
+	}
+
+	scope := doc.index.enclosing(pos)
+	if qualifier, member, ok := ast.SplitQualifiedName(word); ok {
+		return s.resolveQualified(doc, scope, qualifier, member)
+	}
+	if t := pickSymbol(doc, doc.index.lookup(word), scope); t != nil {
+		return t
+	}
+
+	// depends on のエイリアスは依存先のコンポーネントを指す
+	if comp := enclosingComponent(scope); comp != nil {
+		for _, rel := range comp.decl.(*ast.ComponentDecl).Body.Relations {
+			if rel.Alias != nil && *rel.Alias == word {
+				return s.resolveName(doc, rel.Target)
+			}
+		}
+	}
+
+	// エイリアスなしで import したファイルの宣言
+	for i, imported := range s.imports(doc) {
+		if imported != nil && doc.spec.Imports[i].Alias == nil {
+			if t := pickSymbol(imported, imported.index.lookup(word), nil); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// resolveQualified は "qualifier.member" を解決する
+// qualifier は import のエイリアス、依存先のエイリアス・コンポーネント名、または変数名
+func (s *Server) resolveQualified(doc *document, scope *symbol, qualifier, member string) *target {
+	for i, imported := range s.imports(doc) {
+		if imported != nil && doc.spec.Imports[i].Alias != nil && *doc.spec.Imports[i].Alias == qualifier {
+			return pickSymbol(imported, imported.index.lookup(member), nil)
+		}
+	}
+
+	// 依存先のメソッド呼び出し
+	name := qualifier
+	if comp := enclosingComponent(scope); comp != nil {
+		for _, rel := range comp.decl.(*ast.ComponentDecl).Body.Relations {
+			if rel.Alias != nil && *rel.Alias == qualifier {
+				name = rel.Target
+			}
+		}
+	}
+	if t := s.resolveName(doc, name); t != nil {
+		for _, sym := range t.doc.index.all {
+			if sym.name == member && sym.keyword == "method" && isAncestor(t.sym, sym) {
+				return &target{t.doc, sym}
+			}
+		}
+	}
+
+	// 解決できなければメンバー名で探す
+	return pickSymbol(doc, doc.index.lookup(member), scope)
+}
+
+// resolveName は名前（修飾名を含む）のコンポーネント・型・インターフェースを探す
+func (s *Server) resolveName(doc *document, name string) *target {
+	if qualifier, member, ok := ast.SplitQualifiedName(name); ok {
+		for i, imported := range s.imports(doc) {
+			if imported != nil && doc.spec.Imports[i].Alias != nil && *doc.spec.Imports[i].Alias == qualifier {
+				return pickSymbol(imported, imported.index.lookup(member), nil)
+			}
+		}
+		return nil
+	}
+	if t := pickSymbol(doc, doc.index.lookup(name), nil); t != nil {
+		return t
+	}
+	for i, imported := range s.imports(doc) {
+		if imported != nil && doc.spec.Imports[i].Alias == nil {
+			if t := pickSymbol(imported, imported.index.lookup(name), nil); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// pickSymbol は候補から、位置を含む宣言の中にあるものを優先して1つ選ぶ
+func pickSymbol(doc *document, candidates []*symbol, scope *symbol) *target {
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if scope != nil {
+			inA, inB := isAncestor(a.parent, scope), isAncestor(b.parent, scope)
+			if inA != inB {
+				return inA
+			}
+		}
+		return keywordPriority[a.keyword] < keywordPriority[b.keyword]
+	})
+	return &target{doc, candidates[0]}
+}
+
+func enclosingComponent(sym *symbol) *symbol {
+	for ; sym != nil; sym = sym.parent {
+		if sym.keyword == "component" {
+			return sym
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// 補完
+// =============================================================================
+
+// 文脈ごとのキーワード
+var (
+	topKeywords       = []string{"import", "component"}
+	componentKeywords = []string{
+		"type", "enum", "depends on", "extends", "implements", "contains", "aggregates",
+		"provides", "requires", "flow", "states",
+	}
+	interfaceKeywords = []string{"async", "throws"}
+	flowKeywords      = []string{"if", "else", "for", "in", "while", "return", "throw", "await", "true", "false", "null"}
+	statesKeywords    = []string{
+		"initial", "final", "state", "parallel", "region", "entry", "exit", "on", "after", "when", "do",
+	}
+)
+
+// visibilityModifiers はフィールドの可視性
+var visibilityModifiers = []CompletionItem{
+	{Label: "+", Kind: completionKindOperator, Detail: "public"},
+	{Label: "-", Kind: completionKindOperator, Detail: "private"},
+	{Label: "#", Kind: completionKindOperator, Detail: "protected"},
+	{Label: "~", Kind: completionKindOperator, Detail: "package"},
+}
+
+// builtinTypes は補完候補に出す組み込み型
+var builtinTypes = []string{
+	"string", "int", "float", "bool", "datetime", "date", "time", "uuid", "bytes", "any", "void",
+}
+
+var (
+	dependsOnPattern = regexp.MustCompile(`\bdepends\s+on\s+[\w.]*$`)
+	relationPattern  = regexp.MustCompile(`\b(extends|implements|contains|aggregates)\s+[\w.]*$`)
+	typePattern      = regexp.MustCompile(`(:|->)\s*[\w.]*$|<\s*[\w.]*$`)
+	fieldPattern     = regexp.MustCompile(`^\s*[+\-#~]?\w*$`)
+)
+
+func (s *Server) completion(doc *document, pos Position) *CompletionList {
+	s.analyze(doc)
+	offset := doc.offsetAt(pos)
+	prefix := doc.text[doc.lines[pos.Line]:offset]
+	header := enclosingHeader(doc.text, offset)
+
+	var items []CompletionItem
+	switch {
+	case dependsOnPattern.MatchString(prefix):
+		items = s.declarationItems(doc, "component")
+	case relationPattern.MatchString(prefix):
+		items = s.declarationItems(doc, "component", "type", "enum")
+	case typePattern.MatchString(prefix) && !strings.Contains(prefix, "depends"):
+		items = s.declarationItems(doc, "type", "enum", "component")
+		for _, name := range builtinTypes {
+			items = append(items, CompletionItem{Label: name, Kind: completionKindTypeParam, Detail: "builtin"})
+		}
+	case header == "type" && fieldPattern.MatchString(prefix):
+		items = append(items, visibilityModifiers...)
+	default:
+		var keywords []string
+		switch header {
+		case "":
+			keywords = topKeywords
+		case "component":
+			keywords = componentKeywords
+		case "provides", "requires":
+			keywords = interfaceKeywords
+		case "flow", "if", "else", "for", "while":
+			keywords = flowKeywords
+		case "states", "state", "parallel", "region":
+			keywords = statesKeywords
+		}
+		for _, kw := range keywords {
+			items = append(items, CompletionItem{Label: kw, Kind: completionKindKeyword})
+		}
+		if header != "" {
+			items = append(items, s.declarationItems(doc, "component", "type", "enum", "provides", "requires")...)
+		}
+	}
+
+	if items == nil {
+		items = []CompletionItem{}
+	}
+	return &CompletionList{Items: items}
+}
+
+// declarationItems は文書と import 先の宣言のうち keywords に一致するものを補完候補にする
+// エイリアス付きで import した宣言は "alias.Name" で候補にする
+func (s *Server) declarationItems(doc *document, keywords ...string) []CompletionItem {
+	want := make(map[string]bool)
+	for _, kw := range keywords {
+		want[kw] = true
+	}
+
+	var items []CompletionItem
+	seen := make(map[string]bool)
+	addFrom := func(d *document, prefix string) {
+		for _, sym := range d.index.all {
+			label := prefix + sym.name
+			if !want[sym.keyword] || seen[label] {
+				continue
+			}
+			seen[label] = true
+			items = append(items, CompletionItem{Label: label, Kind: completionKind(sym.keyword), Detail: sym.keyword})
+		}
+	}
+
+	addFrom(doc, "")
+	for i, imported := range s.imports(doc) {
+		if imported == nil {
+			continue
+		}
+		prefix := ""
+		if alias := doc.spec.Imports[i].Alias; alias != nil {
+			prefix = *alias + "."
+		}
+		addFrom(imported, prefix)
+	}
+	return items
+}
+
+func completionKind(keyword string) int {
+	switch keyword {
+	case "component":
+		return completionKindClass
+	case "enum":
+		return completionKindEnum
+	case "provides", "requires":
+		return completionKindInterface
+	default:
+		return completionKindStruct
+	}
+}
+
+// enclosingHeader は offset を囲むブロックの先頭キーワードを返す（ブロック外なら空文字列）
+// 入力途中で構文が壊れていても使えるよう、AST ではなくテキストの括弧から判断する
+func enclosingHeader(text string, offset int) string {
+	// 文字列とコメントを除いた { の位置を積む
+	var opens []int
+	for i := 0; i < offset && i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			i = skipString(text, i)
+		case '/':
+			i = skipComment(text, i)
+		case '{':
+			opens = append(opens, i)
+		case '}':
+			if len(opens) > 0 {
+				opens = opens[:len(opens)-1]
+			}
+		}
+	}
+	if len(opens) == 0 {
+		return ""
+	}
+
+	open := opens[len(opens)-1]
+	lineStart := strings.LastIndexByte(text[:open], '\n') + 1
+	fields := strings.Fields(text[lineStart:open])
+	if len(fields) == 0 {
+		return ""
+	}
+	// "} else {" のように閉じ括弧から始まる行は最初の単語を使う
+	if fields[0] == "}" && len(fields) > 1 {
+		return fields[1]
+	}
+	if fields[0] == "async" {
+		return "provides"
+	}
+	return fields[0]
+}
+
+// =============================================================================
+// アウトライン
+// =============================================================================
+
+func (s *Server) documentSymbols(doc *document) []DocumentSymbol {
+	s.analyze(doc)
+	return toDocumentSymbols(doc.index.roots)
+}
+
+func toDocumentSymbols(symbols []*symbol) []DocumentSymbol {
+	result := []DocumentSymbol{}
+	for _, sym := range symbols {
+		result = append(result, DocumentSymbol{
+			Name:           sym.name,
+			Detail:         sym.keyword,
+			Kind:           sym.kind,
+			Range:          sym.fullRange,
+			SelectionRange: sym.nameRange,
+			Children:       toDocumentSymbols(sym.children),
+		})
+	}
+	return result
+}
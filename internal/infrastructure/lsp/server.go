@@ -0,0 +1,235 @@
+// Package lsp implements a Language Server Protocol server for .pact files.
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Server は stdio で動作する .pact の言語サーバー
+// リクエストは受け取った順に1つずつ処理する
+type Server struct {
+	transport *transport
+	version   string
+	docs      map[string]*document // URI → 開かれている文書
+	shutdown  bool
+}
+
+// NewServer は in からリクエストを読み、out に応答を書く言語サーバーを作成する
+func NewServer(in io.Reader, out io.Writer, version string) *Server {
+	return &Server{
+		transport: newTransport(in, out),
+		version:   version,
+		docs:      make(map[string]*document),
+	}
+}
+
+// Run は exit 通知を受け取るか入力が終わるまでリクエストを処理する
+func (s *Server) Run() error {
+	for {
+		req, err := s.transport.read()
+		if err == io.EOF {
+			return nil
+		}
+		if rerr, ok := err.(*responseError); ok {
+			if err := s.transport.write(&response{JSONRPC: "2.0", Error: rerr}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit received before shutdown")
+			}
+			return nil
+		}
+
+		result, rerr := s.handle(req)
+		if req.ID == nil {
+			continue // 通知には応答しない
+		}
+		if err := s.reply(req.ID, result, rerr); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, rerr *responseError) error {
+	resp := &response{JSONRPC: "2.0", ID: id, Error: rerr}
+	if rerr == nil {
+		body, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		raw := json.RawMessage(body)
+		resp.Result = &raw
+	}
+	return s.transport.write(resp)
+}
+
+// notify はクライアントに通知を送る
+func (s *Server) notify(method string, params interface{}) {
+	_ = s.transport.write(&notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// handle はメソッドごとの処理に振り分ける
+func (s *Server) handle(req *request) (interface{}, *responseError) {
+	if s.shutdown && req.Method != "shutdown" {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shutting down"}
+	}
+
+	switch req.Method {
+	case "initialize":
+		return s.initialize(), nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		doc := newDocument(params.TextDocument.URI, params.TextDocument.Text)
+		s.docs[doc.uri] = doc
+		s.publishDiagnostics(doc)
+		return nil, nil
+
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		doc, ok := s.docs[params.TextDocument.URI]
+		if !ok || len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// 全文同期なので最後の変更が最新の内容になる
+		doc.setText(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		s.publishDiagnostics(doc)
+		return nil, nil
+
+	case "textDocument/didSave":
+		var params DidSaveTextDocumentParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if doc, ok := s.docs[params.TextDocument.URI]; ok {
+			s.publishDiagnostics(doc)
+		}
+		return nil, nil
+
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+		return nil, nil
+
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if doc, ok := s.docs[params.TextDocument.URI]; ok {
+			return s.definition(doc, params.Position), nil
+		}
+		return nil, nil
+
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if doc, ok := s.docs[params.TextDocument.URI]; ok {
+			return s.hover(doc, params.Position), nil
+		}
+		return nil, nil
+
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if doc, ok := s.docs[params.TextDocument.URI]; ok {
+			return s.completion(doc, params.Position), nil
+		}
+		return &CompletionList{Items: []CompletionItem{}}, nil
+
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if doc, ok := s.docs[params.TextDocument.URI]; ok {
+			return s.documentSymbols(doc), nil
+		}
+		return []DocumentSymbol{}, nil
+	}
+
+	if req.ID == nil {
+		return nil, nil // 未対応の通知は無視する
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+func (s *Server) initialize() *InitializeResult {
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+				Save:      true,
+			},
+			DefinitionProvider: true,
+			HoverProvider:      true,
+			CompletionProvider: CompletionOptions{
+				TriggerCharacters: []string{" ", ".", ":"},
+			},
+			DocumentSymbolProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: "pact", Version: s.version},
+	}
+}
+
+func decodeParams(req *request, v interface{}) *responseError {
+	if err := json.Unmarshal(req.Params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// openFile はファイルパスの文書を返す
+// エディタで開かれていればその内容を、開かれていなければディスクの内容を使う
+func (s *Server) openFile(path string) *document {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = filepath.Clean(path)
+	}
+	for _, doc := range s.docs {
+		if doc.path == abs {
+			s.analyze(doc)
+			return doc
+		}
+	}
+
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return nil
+	}
+	doc := newDocument(pathToURI(abs), string(content))
+	s.analyze(doc)
+	return doc
+}
@@ -0,0 +1,421 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const orderSpec = `import "./payment.pact" as pay
+
+component OrderService {
+  type Order {
+    +id: string
+    -total: float
+    status: Status
+  }
+
+  enum Status {
+    PENDING
+    DONE
+  }
+
+  depends on pay.PaymentService as payment
+  depends on Repo
+
+  provides OrderAPI {
+    Create(order: Order) -> Order throws InvalidOrder
+  }
+
+  flow Create {
+    payment.Charge(order.id)
+    return order
+  }
+
+  states Lifecycle {
+    initial Pending
+    final Done
+
+    state Pending {}
+    state Done {}
+
+    Pending -> Done on finish
+  }
+}
+
+component Repo {}
+`
+
+const paymentSpec = `component PaymentService {
+  provides PaymentAPI {
+    Charge(order: string) -> bool
+  }
+}
+`
+
+// newTestServer は出力を捨てるサーバーを作成する
+func newTestServer() *Server {
+	return NewServer(strings.NewReader(""), io.Discard, "test")
+}
+
+// openTestDocument は dir に仕様を書き込み、サーバーで開く
+func openTestDocument(t *testing.T, s *Server, dir, name, text string) *document {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc := newDocument(pathToURI(path), text)
+	s.docs[doc.uri] = doc
+	return doc
+}
+
+// positionOf は text 中で marker が n 番目（0 始まり）に現れる位置を返す
+func positionOf(t *testing.T, text, marker string, n int) Position {
+	t.Helper()
+	offset := -1
+	for i := 0; i <= n; i++ {
+		next := strings.Index(text[offset+1:], marker)
+		if next < 0 {
+			t.Fatalf("marker %q #%d not found", marker, n)
+		}
+		offset += next + 1
+	}
+	before := text[:offset]
+	line := strings.Count(before, "\n")
+	return Position{Line: line, Character: offset - (strings.LastIndex(before, "\n") + 1)}
+}
+
+func setupOrder(t *testing.T) (*Server, *document) {
+	t.Helper()
+	dir := t.TempDir()
+	s := newTestServer()
+	openTestDocument(t, s, dir, "payment.pact", paymentSpec)
+	doc := openTestDocument(t, s, dir, "order.pact", orderSpec)
+	return s, doc
+}
+
+// =============================================================================
+// LS001-LS002: プロトコル
+// =============================================================================
+
+func frame(t *testing.T, msg interface{}) string {
+	t.Helper()
+	body, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+// readMessages は出力されたメッセージを全て読む
+func readMessages(t *testing.T, out *bytes.Buffer) []map[string]json.RawMessage {
+	t.Helper()
+	tr := newTransport(out, io.Discard)
+	var msgs []map[string]json.RawMessage
+	for {
+		header, err := tr.r.ReadString('\n')
+		if err == io.EOF {
+			return msgs
+		}
+		var length int
+		if _, err := fmt.Sscanf(header, "Content-Length: %d", &length); err != nil {
+			t.Fatalf("invalid header %q", header)
+		}
+		_, _ = tr.r.ReadString('\n')
+		body := make([]byte, length)
+		if _, err := io.ReadFull(tr.r, body); err != nil {
+			t.Fatal(err)
+		}
+		var msg map[string]json.RawMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+// LS001: initialize から exit までのセッション
+func TestServer_Session(t *testing.T) {
+	uri := "file:///tmp/broken.pact"
+	in := frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "initialized", "params": map[string]interface{}{}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "languageId": "pact", "version": 1, "text": "component {"},
+		}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "workspace/unknown", "params": map[string]interface{}{}}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "id": 3, "method": "shutdown"}) +
+		frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "exit"})
+
+	var out bytes.Buffer
+	if err := NewServer(strings.NewReader(in), &out, "test").Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msgs := readMessages(t, &out)
+	if len(msgs) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(msgs))
+	}
+
+	var init InitializeResult
+	if err := json.Unmarshal(msgs[0]["result"], &init); err != nil {
+		t.Fatal(err)
+	}
+	caps := init.Capabilities
+	if !caps.DefinitionProvider || !caps.HoverProvider || !caps.DocumentSymbolProvider || caps.TextDocumentSync.Change != textDocumentSyncFull {
+		t.Errorf("unexpected capabilities %+v", caps)
+	}
+
+	var diags PublishDiagnosticsParams
+	if err := json.Unmarshal(msgs[1]["params"], &diags); err != nil {
+		t.Fatal(err)
+	}
+	if diags.URI != uri || len(diags.Diagnostics) == 0 || diags.Diagnostics[0].Code != "syntax-error" {
+		t.Errorf("expected syntax diagnostics, got %+v", diags)
+	}
+
+	var rerr responseError
+	if err := json.Unmarshal(msgs[2]["error"], &rerr); err != nil || rerr.Code != codeMethodNotFound {
+		t.Errorf("expected method not found, got %s", msgs[2]["error"])
+	}
+	if string(msgs[3]["result"]) != "null" {
+		t.Errorf("expected null shutdown result, got %s", msgs[3]["result"])
+	}
+}
+
+// LS002: shutdown 前の exit はエラー
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	in := frame(t, map[string]interface{}{"jsonrpc": "2.0", "method": "exit"})
+	if err := NewServer(strings.NewReader(in), io.Discard, "test").Run(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+// =============================================================================
+// LS010-LS012: 診断
+// =============================================================================
+
+// LS010: import を解決した上で検証する
+func TestServer_Diagnostics_Valid(t *testing.T) {
+	s, doc := setupOrder(t)
+	for _, d := range s.diagnostics(doc) {
+		if d.Severity == severityError {
+			t.Errorf("unexpected error %+v", d)
+		}
+	}
+}
+
+// LS011: 意味エラーの位置とルールコード
+func TestServer_Diagnostics_Semantic(t *testing.T) {
+	s := newTestServer()
+	text := "component A {\n  type T {\n    x: Missing\n  }\n}\n"
+	doc := openTestDocument(t, s, t.TempDir(), "a.pact", text)
+
+	diags := s.diagnostics(doc)
+	if len(diags) == 0 {
+		t.Fatal("expected diagnostics")
+	}
+	d := diags[0]
+	if d.Severity != severityError || d.Source != "pact" || !strings.Contains(d.Message, "Missing") {
+		t.Errorf("unexpected diagnostic %+v", d)
+	}
+	if d.Range.Start.Line != 2 {
+		t.Errorf("expected diagnostic on line 2, got %+v", d.Range)
+	}
+}
+
+// LS012: 見つからない import
+func TestServer_Diagnostics_MissingImport(t *testing.T) {
+	s := newTestServer()
+	doc := openTestDocument(t, s, t.TempDir(), "a.pact", "import \"./none.pact\"\ncomponent A {}\n")
+
+	diags := s.diagnostics(doc)
+	if len(diags) == 0 || diags[0].Code != "import-error" {
+		t.Fatalf("expected import error, got %+v", diags)
+	}
+}
+
+// =============================================================================
+// LS020-LS024: 定義ジャンプ
+// =============================================================================
+
+func assertDefinition(t *testing.T, loc *Location, uri string, want Position) {
+	t.Helper()
+	if loc == nil {
+		t.Fatal("expected definition")
+	}
+	if loc.URI != uri || loc.Range.Start != want {
+		t.Errorf("expected %s %+v, got %s %+v", uri, want, loc.URI, loc.Range.Start)
+	}
+}
+
+// LS020: 型の参照から型宣言へ
+func TestServer_Definition_Type(t *testing.T) {
+	s, doc := setupOrder(t)
+	loc := s.definition(doc, positionOf(t, orderSpec, "Order)", 0))
+	assertDefinition(t, loc, doc.uri, positionOf(t, orderSpec, "Order {", 0))
+}
+
+// LS021: エイリアス付き import の宣言へ
+func TestServer_Definition_ImportAlias(t *testing.T) {
+	s, doc := setupOrder(t)
+	pos := positionOf(t, orderSpec, "PaymentService as", 0)
+	loc := s.definition(doc, pos)
+	payment := s.openFile(filepath.Join(filepath.Dir(doc.path), "payment.pact"))
+	assertDefinition(t, loc, payment.uri, positionOf(t, paymentSpec, "PaymentService", 0))
+}
+
+// LS022: 依存先エイリアス経由のメソッド呼び出しへ
+func TestServer_Definition_DependencyMethod(t *testing.T) {
+	s, doc := setupOrder(t)
+	loc := s.definition(doc, positionOf(t, orderSpec, "Charge", 0))
+	payment := s.openFile(filepath.Join(filepath.Dir(doc.path), "payment.pact"))
+	assertDefinition(t, loc, payment.uri, positionOf(t, paymentSpec, "Charge", 0))
+}
+
+// LS023: 遷移の状態名から状態宣言へ
+func TestServer_Definition_State(t *testing.T) {
+	s, doc := setupOrder(t)
+	loc := s.definition(doc, positionOf(t, orderSpec, "Pending -> Done", 0))
+	assertDefinition(t, loc, doc.uri, positionOf(t, orderSpec, "Pending {}", 0))
+}
+
+// LS024: depends on のコンポーネントへ
+func TestServer_Definition_Component(t *testing.T) {
+	s, doc := setupOrder(t)
+	loc := s.definition(doc, positionOf(t, orderSpec, "Repo", 0))
+	assertDefinition(t, loc, doc.uri, positionOf(t, orderSpec, "Repo {}", 0))
+}
+
+// =============================================================================
+// LS030-LS031: ホバー
+// =============================================================================
+
+// LS030: メソッドのシグネチャ
+func TestServer_Hover_Method(t *testing.T) {
+	s, doc := setupOrder(t)
+	h := s.hover(doc, positionOf(t, orderSpec, "Create(", 0))
+	if h == nil {
+		t.Fatal("expected hover")
+	}
+	if !strings.Contains(h.Contents.Value, "OrderAPI.Create(order: Order) -> Order throws InvalidOrder") {
+		t.Errorf("unexpected hover:\n%s", h.Contents.Value)
+	}
+}
+
+// LS031: フィールドと型
+func TestServer_Hover_FieldAndType(t *testing.T) {
+	s, doc := setupOrder(t)
+	h := s.hover(doc, positionOf(t, orderSpec, "total", 0))
+	if h == nil || !strings.Contains(h.Contents.Value, "Order.-total: float") {
+		t.Errorf("unexpected field hover %+v", h)
+	}
+
+	h = s.hover(doc, positionOf(t, orderSpec, "Status\n", 0))
+	if h == nil || !strings.Contains(h.Contents.Value, "enum Status {\n  PENDING\n  DONE\n}") {
+		t.Errorf("unexpected type hover %+v", h)
+	}
+}
+
+// =============================================================================
+// LS040-LS042: 補完
+// =============================================================================
+
+func labels(list *CompletionList) map[string]bool {
+	m := make(map[string]bool)
+	for _, item := range list.Items {
+		m[item.Label] = true
+	}
+	return m
+}
+
+// LS040: depends on の対象
+func TestServer_Completion_DependsOn(t *testing.T) {
+	s, doc := setupOrder(t)
+	s.analyze(doc)
+
+	// 入力途中の構文エラーでも直前の宣言から候補を出す
+	text := strings.Replace(orderSpec, "depends on Repo", "depends on ", 1)
+	doc.setText(text)
+
+	pos := positionOf(t, text, "depends on \n", 0)
+	pos.Character += len("depends on ")
+	got := labels(s.completion(doc, pos))
+	for _, want := range []string{"OrderService", "Repo", "pay.PaymentService"} {
+		if !got[want] {
+			t.Errorf("expected completion %q, got %v", want, got)
+		}
+	}
+	if got["component"] || got["Order"] {
+		t.Errorf("unexpected completions %v", got)
+	}
+}
+
+// LS041: 型本体の可視性
+func TestServer_Completion_Visibility(t *testing.T) {
+	s, doc := setupOrder(t)
+	pos := positionOf(t, orderSpec, "    status", 0)
+	pos.Character = 4
+	got := labels(s.completion(doc, pos))
+	for _, want := range []string{"+", "-", "#", "~"} {
+		if !got[want] {
+			t.Errorf("expected visibility %q, got %v", want, got)
+		}
+	}
+}
+
+// LS042: コンポーネント本体のキーワードと宣言名
+func TestServer_Completion_Keywords(t *testing.T) {
+	s, doc := setupOrder(t)
+	pos := positionOf(t, orderSpec, "  depends on Repo", 0)
+	pos.Character = 2
+	got := labels(s.completion(doc, pos))
+	for _, want := range []string{"provides", "depends on", "states", "Order", "OrderService"} {
+		if !got[want] {
+			t.Errorf("expected completion %q", want)
+		}
+	}
+	if got["import"] {
+		t.Error("top-level keyword offered inside component")
+	}
+}
+
+// =============================================================================
+// LS050: アウトライン
+// =============================================================================
+
+// LS050: 宣言の階層
+func TestServer_DocumentSymbols(t *testing.T) {
+	s, doc := setupOrder(t)
+	symbols := s.documentSymbols(doc)
+	if len(symbols) != 2 || symbols[0].Name != "OrderService" || symbols[1].Name != "Repo" {
+		t.Fatalf("unexpected roots %+v", symbols)
+	}
+
+	children := make(map[string]DocumentSymbol)
+	for _, c := range symbols[0].Children {
+		children[c.Name] = c
+	}
+	if children["Order"].Kind != symbolKindStruct || len(children["Order"].Children) != 3 {
+		t.Errorf("unexpected Order symbol %+v", children["Order"])
+	}
+	if children["OrderAPI"].Kind != symbolKindInterface || children["OrderAPI"].Children[0].Name != "Create" {
+		t.Errorf("unexpected OrderAPI symbol %+v", children["OrderAPI"])
+	}
+	if children["Lifecycle"].Children[0].Name != "Pending" {
+		t.Errorf("unexpected Lifecycle symbol %+v", children["Lifecycle"])
+	}
+
+	comp := symbols[0]
+	if comp.Range.Start.Line != 2 || comp.Range.End.Line != 35 {
+		t.Errorf("unexpected component range %+v", comp.Range)
+	}
+	if comp.SelectionRange.Start != (Position{Line: 2, Character: 10}) {
+		t.Errorf("unexpected selection range %+v", comp.SelectionRange)
+	}
+}
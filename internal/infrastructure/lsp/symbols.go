@@ -0,0 +1,215 @@
+package lsp
+
+import (
+	"path/filepath"
+
+	"pact/internal/domain/ast"
+	"pact/internal/infrastructure/parser"
+)
+
+// symbol は文書内の宣言
+type symbol struct {
+	name      string
+	kind      int    // DocumentSymbol の種類
+	keyword   string // 宣言のキーワード（"component", "type", "field" など）
+	decl      interface{}
+	nameRange Range // 名前の範囲
+	fullRange Range // 本体を含む宣言全体の範囲
+	parent    *symbol
+	children  []*symbol
+}
+
+// symbolIndex は文書の宣言を木構造と一覧で保持する
+type symbolIndex struct {
+	roots []*symbol
+	all   []*symbol
+}
+
+// 定義ジャンプで優先する宣言の順（小さいほど優先）
+var keywordPriority = map[string]int{
+	"component": 0, "type": 0, "enum": 0, "provides": 0, "requires": 0,
+	"states": 1, "state": 1, "parallel": 1, "region": 1,
+	"method": 2, "field": 3, "flow": 4, "value": 5,
+}
+
+// analyze は文書をパースし、宣言の索引を作る（内容が変わっていなければ何もしない）
+func (s *Server) analyze(doc *document) {
+	if doc.analyzed {
+		return
+	}
+	doc.analyzed = true
+
+	spec, err := parser.NewParser(parser.NewLexer(doc.text)).Parse()
+	doc.parseErr = err
+	// 入力途中の構文エラーでは、補完や定義ジャンプに直前の正しい AST を使い続ける
+	if err != nil && doc.spec != nil {
+		return
+	}
+	if spec == nil {
+		spec = &ast.SpecFile{}
+	}
+	spec.Path = doc.path
+	doc.spec = spec
+	doc.index = buildIndex(doc, spec)
+}
+
+// imports は文書の import 先の文書を import 文ごとに返す（読めないファイルは nil）
+func (s *Server) imports(doc *document) []*document {
+	docs := make([]*document, len(doc.spec.Imports))
+	if doc.path == "" {
+		return docs
+	}
+	for i, imp := range doc.spec.Imports {
+		docs[i] = s.openFile(filepath.Join(filepath.Dir(doc.path), imp.Path))
+	}
+	return docs
+}
+
+func buildIndex(doc *document, spec *ast.SpecFile) *symbolIndex {
+	idx := &symbolIndex{}
+	add := func(parent *symbol, sym *symbol) *symbol {
+		sym.parent = parent
+		if parent == nil {
+			idx.roots = append(idx.roots, sym)
+		} else {
+			parent.children = append(parent.children, sym)
+		}
+		idx.all = append(idx.all, sym)
+		return sym
+	}
+	newSymbol := func(pos ast.Position, name, keyword string, kind int, decl interface{}) *symbol {
+		return &symbol{
+			name:      name,
+			kind:      kind,
+			keyword:   keyword,
+			decl:      decl,
+			nameRange: doc.nameRange(pos, name),
+			fullRange: doc.declRange(pos),
+		}
+	}
+
+	var addStates func(parent *symbol, states []ast.StateDecl)
+	addStates = func(parent *symbol, states []ast.StateDecl) {
+		for i := range states {
+			st := &states[i]
+			sym := add(parent, newSymbol(st.Pos, st.Name, "state", symbolKindObject, st))
+			addStates(sym, st.States)
+		}
+	}
+
+	for i := range spec.Components {
+		comp := &spec.Components[i]
+		compSym := add(nil, newSymbol(comp.Pos, comp.Name, "component", symbolKindClass, comp))
+
+		for j := range comp.Body.Types {
+			typ := &comp.Body.Types[j]
+			switch typ.Kind {
+			case ast.TypeKindEnum:
+				typSym := add(compSym, newSymbol(typ.Pos, typ.Name, "enum", symbolKindEnum, typ))
+				for _, value := range typ.Values {
+					sym := newSymbol(typ.Pos, value, "value", symbolKindEnumMember, typ)
+					sym.nameRange = doc.nameRange(doc.astPosition(typSym.nameRange.End), value)
+					sym.fullRange = sym.nameRange
+					add(typSym, sym)
+				}
+			case ast.TypeKindAlias:
+				add(compSym, newSymbol(typ.Pos, typ.Name, "type", symbolKindTypeParam, typ))
+			default:
+				typSym := add(compSym, newSymbol(typ.Pos, typ.Name, "type", symbolKindStruct, typ))
+				for k := range typ.Fields {
+					field := &typ.Fields[k]
+					add(typSym, newSymbol(field.Pos, field.Name, "field", symbolKindField, field))
+				}
+			}
+		}
+
+		for _, group := range []struct {
+			keyword string
+			ifaces  []ast.InterfaceDecl
+		}{{"provides", comp.Body.Provides}, {"requires", comp.Body.Requires}} {
+			for j := range group.ifaces {
+				iface := &group.ifaces[j]
+				ifaceSym := add(compSym, newSymbol(iface.Pos, iface.Name, group.keyword, symbolKindInterface, iface))
+				for k := range iface.Methods {
+					method := &iface.Methods[k]
+					add(ifaceSym, newSymbol(method.Pos, method.Name, "method", symbolKindMethod, method))
+				}
+			}
+		}
+
+		for j := range comp.Body.Flows {
+			flow := &comp.Body.Flows[j]
+			add(compSym, newSymbol(flow.Pos, flow.Name, "flow", symbolKindFunction, flow))
+		}
+
+		for j := range comp.Body.States {
+			states := &comp.Body.States[j]
+			statesSym := add(compSym, newSymbol(states.Pos, states.Name, "states", symbolKindNamespace, states))
+			addStates(statesSym, states.States)
+			for k := range states.Parallels {
+				par := &states.Parallels[k]
+				parSym := add(statesSym, newSymbol(par.Pos, par.Name, "parallel", symbolKindNamespace, par))
+				for l := range par.Regions {
+					region := &par.Regions[l]
+					regionSym := add(parSym, newSymbol(region.Pos, region.Name, "region", symbolKindNamespace, region))
+					addStates(regionSym, region.States)
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// astPosition は LSP の位置を AST の位置に変換する
+func (d *document) astPosition(pos Position) ast.Position {
+	offset := d.offsetAt(pos)
+	line := pos.Line
+	if line >= len(d.lines) {
+		line = len(d.lines) - 1
+	}
+	return ast.Position{Line: line + 1, Column: offset - d.lines[line] + 1}
+}
+
+// lookup は name の宣言を優先順に返す
+func (idx *symbolIndex) lookup(name string) []*symbol {
+	var found []*symbol
+	for _, sym := range idx.all {
+		if sym.name == name {
+			found = append(found, sym)
+		}
+	}
+	return found
+}
+
+// enclosing は位置を含む最も内側の宣言を返す
+func (idx *symbolIndex) enclosing(pos Position) *symbol {
+	var inner *symbol
+	for _, sym := range idx.all {
+		if contains(sym.fullRange, pos) {
+			inner = sym
+		}
+	}
+	return inner
+}
+
+// contains は範囲が位置を含むかどうかを返す
+func contains(r Range, pos Position) bool {
+	return !before(pos, r.Start) && !before(r.End, pos)
+}
+
+func before(a, b Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Character < b.Character
+}
+
+// isAncestor は a が b 自身または b の祖先かどうかを返す
+func isAncestor(a, b *symbol) bool {
+	for ; b != nil; b = b.parent {
+		if a == b {
+			return true
+		}
+	}
+	return false
+}
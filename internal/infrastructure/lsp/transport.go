@@ -0,0 +1,65 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+)
+
+// transport は Content-Length ヘッダ付きの JSON-RPC メッセージを読み書きする
+type transport struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func newTransport(r io.Reader, w io.Writer) *transport {
+	return &transport{r: bufio.NewReader(r), w: w}
+}
+
+// read は次のメッセージを読み込む
+// ストリームが終了した場合は io.EOF を返す
+func (t *transport) read() (*request, error) {
+	header, err := textproto.NewReader(t.r).ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length: %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(t.r, body); err != nil {
+		return nil, err
+	}
+
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, &responseError{Code: codeParseError, Message: err.Error()}
+	}
+	return &req, nil
+}
+
+// write はメッセージを書き込む
+func (t *transport) write(msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(t.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = t.w.Write(body)
+	return err
+}
+
+func (e *responseError) Error() string {
+	return e.Message
+}
@@ -1,5 +1,11 @@
 package parser
 
+import (
+	"strings"
+
+	"pact/internal/domain/ast"
+)
+
 // Lexer は字句解析器
 type Lexer struct {
 	input       string
@@ -10,7 +16,8 @@ type Lexer struct {
 	column      int
 	tokenLine   int
 	tokenColumn int
-	err         error // レキシングエラー
+	err         error         // レキシングエラー
+	comments    []ast.Comment // 読み飛ばしたコメント（整形時に使う）
 }
 
 // キーワードマップ
@@ -269,14 +276,17 @@ func (l *Lexer) skipWhitespaceAndComments() {
 		// コメントをスキップ
 		if l.ch == '/' && l.peekChar() == '/' {
 			// 行コメント
+			start, line, column := l.pos, l.line, l.column
 			for l.ch != '\n' && l.ch != 0 {
 				l.readChar()
 			}
+			l.addComment(start, line, column)
 			continue
 		}
 
 		if l.ch == '/' && l.peekChar() == '*' {
 			// ブロックコメント
+			start := l.pos
 			commentLine := l.line
 			commentColumn := l.column
 			l.readChar() // consume '/'
@@ -301,6 +311,7 @@ func (l *Lexer) skipWhitespaceAndComments() {
 				l.readChar()
 			}
 			if closed {
+				l.addComment(start, commentLine, commentColumn)
 				continue
 			}
 			break
@@ -310,6 +321,20 @@ func (l *Lexer) skipWhitespaceAndComments() {
 	}
 }
 
+// addComment は start から現在位置までをコメントとして記録する
+func (l *Lexer) addComment(start, line, column int) {
+	text := strings.TrimRight(l.input[start:l.pos], " \t\r")
+	l.comments = append(l.comments, ast.Comment{
+		Pos:  ast.Position{Line: line, Column: column, Offset: start},
+		Text: text,
+	})
+}
+
+// Comments は読み飛ばしたコメントを出現順に返す
+func (l *Lexer) Comments() []ast.Comment {
+	return l.comments
+}
+
 // lexerError はレキシングエラー
 type lexerError struct {
 	line    int
@@ -348,3 +373,9 @@ func lookupIdent(ident string) TokenType {
 	}
 	return TOKEN_IDENT
 }
+
+// IsKeyword は name が予約語かどうかを返す
+func IsKeyword(name string) bool {
+	_, ok := keywords[name]
+	return ok
+}
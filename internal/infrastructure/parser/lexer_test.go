@@ -924,6 +924,23 @@ func TestLexer_Comment_Block_Unterminated(t *testing.T) {
 	}
 }
 
+// L126: コメントの記録
+func TestLexer_Comment_Recorded(t *testing.T) {
+	l := NewLexer("foo // trailing  \n/* block\n  comment */ bar")
+	for l.NextToken().Type != TOKEN_EOF {
+	}
+	comments := l.Comments()
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(comments))
+	}
+	if comments[0].Text != "// trailing" || comments[0].Pos.Line != 1 || comments[0].Pos.Column != 5 {
+		t.Errorf("unexpected first comment %+v", comments[0])
+	}
+	if comments[1].Text != "/* block\n  comment */" || comments[1].Pos.Line != 2 || comments[1].Pos.Column != 1 {
+		t.Errorf("unexpected second comment %+v", comments[1])
+	}
+}
+
 // =============================================================================
 // 1.1.6 空白・位置情報
 // =============================================================================
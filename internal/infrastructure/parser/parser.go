@@ -127,6 +127,8 @@ func (p *Parser) ParseFile() (*ast.SpecFile, error) {
 		}
 	}
 
+	spec.Comments = p.l.Comments()
+
 	// エラーがあれば返す
 	if err := p.getErrors(); err != nil {
 		return spec, err
@@ -222,6 +224,18 @@ func (p *Parser) expectIdentifier(context string) (string, error) {
 	return name, nil
 }
 
+// parseQualifiedName は識別子を読み取り、"alias.Name" 形式の修飾名ならまとめて返す
+func (p *Parser) parseQualifiedName() string {
+	name := p.curToken.Literal
+	p.nextToken()
+	if p.curToken.Type == TOKEN_DOT && p.peekToken.Type == TOKEN_IDENT {
+		p.nextToken()
+		name += "." + p.curToken.Literal
+		p.nextToken()
+	}
+	return name
+}
+
 // isIdentLike はトークンが識別子またはキーワード（識別子として使用可能なもの）かどうかを返す
 func (p *Parser) isIdentLike() bool {
 	switch p.curToken.Type {
@@ -37,6 +37,7 @@ func (p *Parser) parseComponent(annotations []ast.AnnotationDecl) (*ast.Componen
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of component")
 	}
+	comp.End = p.curPos()
 	p.nextToken()
 
 	return comp, nil
@@ -173,6 +174,7 @@ func (p *Parser) parseInterface(annotations []ast.AnnotationDecl) (*ast.Interfac
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of interface")
 	}
+	iface.End = p.curPos()
 	p.nextToken()
 
 	return iface, nil
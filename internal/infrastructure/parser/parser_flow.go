@@ -36,6 +36,7 @@ func (p *Parser) parseFlow(annotations []ast.AnnotationDecl) (*ast.FlowDecl, err
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of flow")
 	}
+	flow.End = p.curPos()
 	p.nextToken()
 
 	return flow, nil
@@ -157,6 +158,7 @@ func (p *Parser) parseIfStep(pos ast.Position, annotations []ast.AnnotationDecl)
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' after then block")
 	}
+	step.ThenEnd = p.curPos()
 	p.nextToken()
 
 	// else
@@ -176,6 +178,7 @@ func (p *Parser) parseIfStep(pos ast.Position, annotations []ast.AnnotationDecl)
 		if p.curToken.Type != TOKEN_RBRACE {
 			return nil, p.newError("expected '}' after else block")
 		}
+		step.ElseEnd = p.curPos()
 		p.nextToken()
 	}
 
@@ -221,6 +224,7 @@ func (p *Parser) parseForStep(pos ast.Position, annotations []ast.AnnotationDecl
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' after for body")
 	}
+	step.End = p.curPos()
 	p.nextToken()
 
 	return step, nil
@@ -254,6 +258,7 @@ func (p *Parser) parseWhileStep(pos ast.Position, annotations []ast.AnnotationDe
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' after while body")
 	}
+	step.End = p.curPos()
 	p.nextToken()
 
 	return step, nil
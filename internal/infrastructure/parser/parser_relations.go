@@ -25,8 +25,7 @@ func (p *Parser) parseDependsOn(annotations []ast.AnnotationDecl) (*ast.Relation
 	if p.curToken.Type != TOKEN_IDENT {
 		return nil, p.newError("expected identifier after 'depends on'")
 	}
-	rel.Target = p.curToken.Literal
-	p.nextToken()
+	rel.Target = p.parseQualifiedName()
 
 	// : type
 	if p.curToken.Type == TOKEN_COLON {
@@ -65,8 +64,7 @@ func (p *Parser) parseSimpleRelation(kind ast.RelationKind, annotations []ast.An
 	if p.curToken.Type != TOKEN_IDENT {
 		return nil, p.newError("expected identifier")
 	}
-	rel.Target = p.curToken.Literal
-	p.nextToken()
+	rel.Target = p.parseQualifiedName()
 
 	return rel, nil
 }
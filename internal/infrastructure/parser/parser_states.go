@@ -38,6 +38,7 @@ func (p *Parser) parseStates(annotations []ast.AnnotationDecl) (*ast.StatesDecl,
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of states")
 	}
+	states.End = p.curPos()
 	p.nextToken()
 
 	return states, nil
@@ -159,6 +160,7 @@ func (p *Parser) parseStateDecl() (*ast.StateDecl, error) {
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of state")
 	}
+	state.End = p.curPos()
 	p.nextToken()
 
 	return state, nil
@@ -278,6 +280,7 @@ func (p *Parser) parseParallel() (*ast.ParallelDecl, error) {
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of parallel")
 	}
+	parallel.End = p.curPos()
 	p.nextToken()
 
 	return parallel, nil
@@ -334,6 +337,7 @@ func (p *Parser) parseRegion() (*ast.RegionDecl, error) {
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of region")
 	}
+	region.End = p.curPos()
 	p.nextToken()
 
 	return region, nil
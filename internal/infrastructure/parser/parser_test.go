@@ -194,6 +194,39 @@ func TestParser_Component_Position(t *testing.T) {
 	}
 }
 
+// P207: 閉じ括弧と enum の値の位置
+func TestParser_Component_EndPositions(t *testing.T) {
+	input := `component Foo {
+  enum Status {
+    PENDING
+    PAID
+  }
+  flow Run {
+    if x {
+      return
+    } else {
+      return
+    }
+  }
+}`
+	spec, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	comp := spec.Component
+	if comp.End.Line != 13 || comp.End.Column != 1 {
+		t.Errorf("expected component end at 13:1, got %s", comp.End)
+	}
+	enum := comp.Body.Types[0]
+	if len(enum.ValuePos) != 2 || enum.ValuePos[1].Line != 4 || enum.End.Line != 5 {
+		t.Errorf("unexpected enum positions: values %v, end %s", enum.ValuePos, enum.End)
+	}
+	step := comp.Body.Flows[0].Steps[0].(*ast.IfStep)
+	if step.ThenEnd.Line != 9 || step.ElseEnd.Line != 11 || comp.Body.Flows[0].End.Line != 12 {
+		t.Errorf("unexpected flow positions: then %s, else %s, flow %s", step.ThenEnd, step.ElseEnd, comp.Body.Flows[0].End)
+	}
+}
+
 // =============================================================================
 // 1.2.3 型定義
 // =============================================================================
@@ -481,6 +514,41 @@ func TestParser_Relation_DependsOn_Full(t *testing.T) {
 	}
 }
 
+// P205: エイリアスで修飾された depends on
+func TestParser_Relation_DependsOn_Qualified(t *testing.T) {
+	input := `component Foo { depends on billing.PaymentService: external as pay }`
+	spec, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rel := spec.Component.Body.Relations[0]
+	if rel.Target != "billing.PaymentService" {
+		t.Errorf("expected target 'billing.PaymentService', got %q", rel.Target)
+	}
+	if rel.TargetType == nil || *rel.TargetType != "external" {
+		t.Errorf("expected target type 'external'")
+	}
+	if rel.Alias == nil || *rel.Alias != "pay" {
+		t.Errorf("expected alias 'pay'")
+	}
+}
+
+// P206: エイリアスで修飾された型参照
+func TestParser_TypeExpr_Qualified(t *testing.T) {
+	input := `component Foo { type Order { invoice: billing.Invoice? items: billing.Item[] } }`
+	spec, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := spec.Component.Body.Types[0].Fields
+	if fields[0].Type.Name != "billing.Invoice" || !fields[0].Type.Nullable {
+		t.Errorf("expected nullable 'billing.Invoice', got %+v", fields[0].Type)
+	}
+	if fields[1].Type.Name != "billing.Item" || !fields[1].Type.Array {
+		t.Errorf("expected array 'billing.Item', got %+v", fields[1].Type)
+	}
+}
+
 // P044: extends
 func TestParser_Relation_Extends(t *testing.T) {
 	input := `component Foo { extends Base }`
@@ -51,6 +51,7 @@ func (p *Parser) parseTypeDecl(annotations []ast.AnnotationDecl) (*ast.TypeDecl,
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of type")
 	}
+	typ.End = p.curPos()
 	p.nextToken()
 
 	return typ, nil
@@ -78,12 +79,14 @@ func (p *Parser) parseEnumDecl(annotations []ast.AnnotationDecl) (*ast.TypeDecl,
 
 	for p.curToken.Type == TOKEN_IDENT {
 		typ.Values = append(typ.Values, p.curToken.Literal)
+		typ.ValuePos = append(typ.ValuePos, p.curPos())
 		p.nextToken()
 	}
 
 	if p.curToken.Type != TOKEN_RBRACE {
 		return nil, p.newError("expected '}' at end of enum")
 	}
+	typ.End = p.curPos()
 	p.nextToken()
 
 	return typ, nil
@@ -148,8 +151,7 @@ func (p *Parser) parseTypeExpr() (*ast.TypeExpr, error) {
 	if p.curToken.Type != TOKEN_IDENT {
 		return nil, p.newError("expected type name")
 	}
-	typeExpr.Name = p.curToken.Literal
-	p.nextToken()
+	typeExpr.Name = p.parseQualifiedName()
 
 	// ジェネリクス型パラメータ: Type<T, U>
 	if p.curToken.Type == TOKEN_LT {
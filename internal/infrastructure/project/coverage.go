@@ -0,0 +1,76 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// MissingSpec は仕様ファイルが存在しないソースファイルを表す
+type MissingSpec struct {
+	Source   string // ソースファイル（絶対パス）
+	Expected string // 期待される仕様ファイル（絶対パス）
+}
+
+// CoverageReport はソースコードに対する仕様のカバレッジを表す
+type CoverageReport struct {
+	Sources  []string      // 対象となったソースファイル
+	Covered  []string      // 仕様が存在するソースファイル
+	Missing  []MissingSpec // 仕様がないソースファイル
+	Orphaned []string      // 対応するソースファイルがない仕様ファイル
+}
+
+// Percent はカバレッジを百分率で返す
+// 対象のソースファイルがなければ 100 を返す
+func (r *CoverageReport) Percent() float64 {
+	if len(r.Sources) == 0 {
+		return 100
+	}
+	return float64(len(r.Covered)) * 100 / float64(len(r.Sources))
+}
+
+// Coverage は source_root と pact_root を突き合わせてカバレッジを計算する
+func (p *Project) Coverage() (*CoverageReport, error) {
+	sources, err := p.SourceFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CoverageReport{Sources: sources}
+	for _, src := range sources {
+		expected, err := p.PactPathFor(src)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(expected); err == nil && !info.IsDir() {
+			report.Covered = append(report.Covered, src)
+		} else {
+			report.Missing = append(report.Missing, MissingSpec{Source: src, Expected: expected})
+		}
+	}
+
+	// pact_root が未作成の場合は仕様が一つもないものとして扱う
+	if _, err := os.Stat(p.PactRoot()); err != nil {
+		return report, nil
+	}
+
+	specs, err := p.PactFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		if p.SourcePathFor(spec) == "" {
+			report.Orphaned = append(report.Orphaned, spec)
+		}
+	}
+
+	return report, nil
+}
+
+// ProjectPath はプロジェクトルートからの相対パスをスラッシュ区切りで返す
+func (p *Project) ProjectPath(path string) string {
+	rel, err := filepath.Rel(p.Root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,125 @@
+package project
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	domainConfig "pact/internal/domain/config"
+)
+
+// ExpandPatterns はファイルパターンを .pact ファイルのリストに展開する
+//
+//	dir      ディレクトリ直下の .pact ファイル
+//	dir/...  ディレクトリ以下の全ての .pact ファイル（pact_root 以外の隠しディレクトリは除く）
+//	a/**/*.pact  "**" は0個以上のディレクトリに一致する
+//
+// 展開で見つかったファイルは除外設定で絞り込み、重複を除いてパス順に並べる
+// 何にも一致しないファイル指定と dir/... はそのまま残す（呼び出し側でファイル不在として扱う）
+func (p *Project) ExpandPatterns(patterns []string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string, discovered bool) {
+		path = filepath.Clean(path)
+		if seen[path] || (discovered && p.Excludes(path)) {
+			return
+		}
+		seen[path] = true
+		files = append(files, path)
+	}
+
+	for _, pattern := range patterns {
+		var matches []string
+		isDir := false
+		switch {
+		case pattern == "..." || strings.HasSuffix(pattern, "/..."):
+			matches = p.walkPactFiles(strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/"), nil)
+		case strings.Contains(pattern, "**"):
+			matches = p.globRecursive(pattern)
+		default:
+			if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+				isDir = true
+				matches, _ = filepath.Glob(filepath.Join(pattern, "*"+PactExt))
+			} else if hasMeta(pattern) {
+				matches, _ = filepath.Glob(pattern)
+			}
+		}
+
+		if len(matches) == 0 && !isDir && !hasMeta(pattern) {
+			add(pattern, false)
+			continue
+		}
+		for _, m := range matches {
+			// 名前が .pact で終わるディレクトリ（pact_root の .pact など）は除く
+			if isRegularFile(m) {
+				add(m, true)
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return files
+}
+
+// globRecursive は "**" を含むパターンに一致する .pact ファイルを返す
+func (p *Project) globRecursive(pattern string) []string {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+
+	// メタ文字を含まない先頭のディレクトリから走査する
+	var prefix []string
+	for _, seg := range strings.Split(pattern, "/") {
+		if hasMeta(seg) {
+			break
+		}
+		prefix = append(prefix, seg)
+	}
+
+	return p.walkPactFiles(strings.Join(prefix, "/"), func(path string) bool {
+		return domainConfig.MatchPattern(pattern, filepath.ToSlash(path))
+	})
+}
+
+// walkPactFiles は root 以下の .pact ファイルのうち match を満たすものを返す
+// 隠しディレクトリは飛ばすが、pact_root（既定の .pact など）は走査する
+func (p *Project) walkPactFiles(root string, match func(path string) bool) []string {
+	if root == "" {
+		root = "."
+	}
+	pactRoot := p.PactRoot()
+	var files []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") && absPath(path) != pactRoot {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == PactExt && (match == nil || match(path)) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
+
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func hasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
@@ -0,0 +1,69 @@
+package project
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	domainConfig "pact/internal/domain/config"
+	"pact/internal/domain/errors"
+)
+
+// IgnoreFileName は除外パターンを書くファイルの名前
+const IgnoreFileName = ".pactignore"
+
+// ReadIgnoreFile は dir の .pactignore から除外パターンを読み込む
+// 空行と "#" で始まる行は無視し、ファイルがなければ空のリストを返す
+func ReadIgnoreFile(dir string) ([]string, error) {
+	path := filepath.Join(dir, IgnoreFileName)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &errors.ConfigError{Path: path, Message: "failed to read ignore file: " + err.Error()}
+	}
+	defer func() { _ = f.Close() }()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &errors.ConfigError{Path: path, Message: "failed to read ignore file: " + err.Error()}
+	}
+	return patterns, nil
+}
+
+// Excludes はパスが exclude 設定または .pactignore によって除外されるかを返す
+// .pactignore はプロジェクトルート、exclude は pact_root と source_root からの相対パスで判定する
+func (p *Project) Excludes(path string) bool {
+	if rel, ok := relativeTo(p.Root, path); ok && domainConfig.MatchAny(p.Ignore, rel) {
+		return true
+	}
+	for _, root := range []string{p.PactRoot(), p.SourceRoot()} {
+		if rel, ok := relativeTo(root, path); ok && p.Config.IsExcluded(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeTo は root 内のパスなら root からのスラッシュ区切りの相対パスを返す
+func relativeTo(root, path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
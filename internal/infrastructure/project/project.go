@@ -0,0 +1,141 @@
+// Package project provides .pactconfig based project discovery and path mapping.
+package project
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	domainConfig "pact/internal/domain/config"
+	"pact/internal/domain/errors"
+	"pact/internal/infrastructure/config"
+)
+
+// PactExt は仕様ファイルの拡張子
+const PactExt = ".pact"
+
+// Project は .pactconfig を基点としたプロジェクトを表す
+type Project struct {
+	Root   string // .pactconfig があるディレクトリ（絶対パス）
+	Config *domainConfig.Config
+	Ignore []string // .pactignore の除外パターン（Root からの相対パス）
+}
+
+// Load は startPath から上位ディレクトリを辿ってプロジェクトを読み込む
+func Load(startPath string) (*Project, error) {
+	loader := config.NewLoader()
+	root, err := loader.FindProjectRoot(startPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loader.Load(filepath.Join(root, config.ConfigFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	ignore, err := ReadIgnoreFile(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Project{Root: root, Config: cfg, Ignore: ignore}, nil
+}
+
+// LoadOrDefault は Load と同様にプロジェクトを読み込む
+// .pactconfig が見つからなければ dir を基点とする既定の設定で作成し、dir の .pactignore を読み込む
+func LoadOrDefault(dir string) *Project {
+	if p, err := Load(dir); err == nil {
+		return p
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		root = dir
+	}
+	p := New(root, nil)
+	p.Ignore, _ = ReadIgnoreFile(root)
+	return p
+}
+
+// New は設定済みのプロジェクトを作成する
+func New(root string, cfg *domainConfig.Config) *Project {
+	if cfg == nil {
+		cfg = domainConfig.Default()
+	}
+	return &Project{Root: root, Config: cfg}
+}
+
+// PactRoot は仕様ファイルのルートディレクトリを返す
+func (p *Project) PactRoot() string {
+	return p.resolve(p.Config.PactRoot)
+}
+
+// SourceRoot はソースコードのルートディレクトリを返す
+func (p *Project) SourceRoot() string {
+	return p.resolve(p.Config.SourceRoot)
+}
+
+// OutputDir は図の出力ディレクトリを返す
+func (p *Project) OutputDir() string {
+	return p.resolve(p.Config.OutputDir)
+}
+
+// PactFiles は pact_root 以下の .pact ファイルを再帰的に収集し、パス順で返す
+// exclude や .pactignore に一致するファイル・ディレクトリは除外する
+func (p *Project) PactFiles() ([]string, error) {
+	root := p.PactRoot()
+	var files []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		if p.Excludes(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && filepath.Ext(path) == PactExt {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &errors.ConfigError{Path: root, Message: "failed to read pact_root: " + err.Error()}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// RelPath は pact_root からの相対パスを返す
+func (p *Project) RelPath(pactFile string) (string, error) {
+	abs, err := filepath.Abs(pactFile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(p.PactRoot(), abs)
+}
+
+// OutputDirFor は仕様ファイルに対応する出力ディレクトリを返す
+// pact_root 内のディレクトリ構造を output_dir 以下にそのまま反映する
+func (p *Project) OutputDirFor(pactFile string) string {
+	rel, err := p.RelPath(pactFile)
+	if err != nil {
+		return p.OutputDir()
+	}
+	return filepath.Join(p.OutputDir(), filepath.Dir(rel))
+}
+
+// resolve は設定値のパスをプロジェクトルート基準の絶対パスにする
+func (p *Project) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(p.Root, path)
+}
@@ -0,0 +1,361 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	domainConfig "pact/internal/domain/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+// =============================================================================
+// PJ001-PJ004: Project
+// =============================================================================
+
+// PJ001: サブディレクトリからのプロジェクト読み込み
+func TestProject_Load_FromSubdir(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".pactconfig"), "pact_root: ./specs\noutput_dir: ./out\n")
+	sub := filepath.Join(root, "specs", "auth")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	proj, err := Load(sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if proj.PactRoot() != filepath.Join(root, "specs") {
+		t.Errorf("unexpected PactRoot %q", proj.PactRoot())
+	}
+	if proj.OutputDir() != filepath.Join(root, "out") {
+		t.Errorf("unexpected OutputDir %q", proj.OutputDir())
+	}
+}
+
+// PJ002: プロジェクト未検出
+func TestProject_Load_NotFound(t *testing.T) {
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("expected error when .pactconfig is missing")
+	}
+}
+
+// PJ003: 再帰的な収集と除外
+func TestProject_PactFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".pact", "auth", "service.pact"), "component A { }")
+	writeFile(t, filepath.Join(root, ".pact", "order", "handler.pact"), "component B { }")
+	writeFile(t, filepath.Join(root, ".pact", "order", "handler_test.pact"), "component C { }")
+	writeFile(t, filepath.Join(root, ".pact", "vendor", "lib.pact"), "component D { }")
+	writeFile(t, filepath.Join(root, ".pact", "readme.md"), "")
+
+	cfg := domainConfig.Default()
+	cfg.Exclude = []string{"*_test.pact", "vendor"}
+	proj := New(root, cfg)
+
+	files, err := proj.PactFiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		filepath.Join(root, ".pact", "auth", "service.pact"),
+		filepath.Join(root, ".pact", "order", "handler.pact"),
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("files[%d] = %q, expected %q", i, files[i], expected[i])
+		}
+	}
+}
+
+// PJ004: 出力ディレクトリのミラーリング
+func TestProject_OutputDirFor(t *testing.T) {
+	root := t.TempDir()
+	proj := New(root, nil)
+
+	got := proj.OutputDirFor(filepath.Join(root, ".pact", "auth", "service.pact"))
+	expected := filepath.Join(root, "diagrams", "auth")
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+// =============================================================================
+// PJ005-PJ008: Source mapping
+// =============================================================================
+
+// PJ005: 設定言語のソースファイル収集（テスト・隠しディレクトリは除外）
+func TestProject_SourceFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "src", "auth", "service.go"), "package auth")
+	writeFile(t, filepath.Join(root, "src", "auth", "service_test.go"), "package auth")
+	writeFile(t, filepath.Join(root, "src", "auth", "notes.md"), "")
+	writeFile(t, filepath.Join(root, "src", ".git", "hook.go"), "package git")
+
+	proj := New(root, nil)
+	files, err := proj.SourceFiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 1 || files[0] != filepath.Join(root, "src", "auth", "service.go") {
+		t.Errorf("unexpected source files: %v", files)
+	}
+}
+
+// PJ006: ソース⇔仕様のパス対応
+func TestProject_PathMapping(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src", "order", "handler.go")
+	writeFile(t, src, "package order")
+
+	proj := New(root, nil)
+	pactPath, err := proj.PactPathFor(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := filepath.Join(root, ".pact", "order", "handler.pact")
+	if pactPath != expected {
+		t.Errorf("expected %q, got %q", expected, pactPath)
+	}
+
+	if got := proj.SourcePathFor(pactPath); got != src {
+		t.Errorf("expected source %q, got %q", src, got)
+	}
+	if got := proj.SourcePathFor(filepath.Join(root, ".pact", "order", "gone.pact")); got != "" {
+		t.Errorf("expected no source for orphan spec, got %q", got)
+	}
+}
+
+// PJ007: コンポーネント名の導出
+func TestComponentName(t *testing.T) {
+	tests := map[string]string{
+		"service.go":             "Service",
+		"user_repository.go":     "UserRepository",
+		"order-handler.ts":       "OrderHandler",
+		"2fa.go":                 "Component2fa",
+		"internal/api/client.go": "Client",
+	}
+	for input, expected := range tests {
+		if got := ComponentName(input); got != expected {
+			t.Errorf("ComponentName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+// PJ008: スタブ作成（既存ファイルは上書きしない）
+func TestProject_CreateStubs(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "src", "auth", "service.go"), "package auth")
+	writeFile(t, filepath.Join(root, "src", "order", "handler.go"), "package order")
+	existing := filepath.Join(root, ".pact", "order", "handler.pact")
+	writeFile(t, existing, "component Custom { }")
+
+	proj := New(root, nil)
+	created, err := proj.CreateStubs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created) != 1 {
+		t.Fatalf("expected 1 created file, got %v", created)
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, ".pact", "auth", "service.pact"))
+	if err != nil {
+		t.Fatalf("stub not created: %v", err)
+	}
+	if string(content) != "// Source: src/auth/service.go\ncomponent Service {\n}\n" {
+		t.Errorf("unexpected stub content: %q", content)
+	}
+
+	content, _ = os.ReadFile(existing)
+	if string(content) != "component Custom { }" {
+		t.Error("existing spec should not be overwritten")
+	}
+}
+
+// =============================================================================
+// PJ009-PJ010: Coverage
+// =============================================================================
+
+// PJ009: 欠落・孤立した仕様の検出
+func TestProject_Coverage(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "src", "auth", "service.go"), "package auth")
+	writeFile(t, filepath.Join(root, "src", "order", "handler.go"), "package order")
+	writeFile(t, filepath.Join(root, "src", "order", "model.go"), "package order")
+	writeFile(t, filepath.Join(root, ".pact", "auth", "service.pact"), "component Service { }")
+	writeFile(t, filepath.Join(root, ".pact", "legacy", "old.pact"), "component Old { }")
+
+	proj := New(root, nil)
+	report, err := proj.Coverage()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Sources) != 3 || len(report.Covered) != 1 {
+		t.Errorf("expected 1/3 covered, got %d/%d", len(report.Covered), len(report.Sources))
+	}
+	if len(report.Missing) != 2 {
+		t.Fatalf("expected 2 missing specs, got %v", report.Missing)
+	}
+	if report.Missing[0].Expected != filepath.Join(root, ".pact", "order", "handler.pact") {
+		t.Errorf("unexpected expected path %q", report.Missing[0].Expected)
+	}
+	if len(report.Orphaned) != 1 || proj.ProjectPath(report.Orphaned[0]) != ".pact/legacy/old.pact" {
+		t.Errorf("unexpected orphaned specs: %v", report.Orphaned)
+	}
+
+	percent := report.Percent()
+	if percent < 33.3 || percent > 33.4 {
+		t.Errorf("expected 33.3%%, got %.2f", percent)
+	}
+}
+
+// PJ010: pact_root 未作成・ソースなし
+func TestProject_Coverage_Empty(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "src", "main.go"), "package main")
+
+	proj := New(root, nil)
+	report, err := proj.Coverage()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Missing) != 1 || report.Percent() != 0 {
+		t.Errorf("expected 0%% coverage, got %.1f%%", report.Percent())
+	}
+
+	if (&CoverageReport{}).Percent() != 100 {
+		t.Error("expected 100% coverage when there are no sources")
+	}
+}
+
+// =============================================================================
+// PJ011-PJ015: Pattern expansion
+// =============================================================================
+
+func expansionTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "specs", "order.pact"), "component A { }")
+	writeFile(t, filepath.Join(root, "specs", "billing", "invoice.pact"), "component B { }")
+	writeFile(t, filepath.Join(root, "specs", "billing", "drafts", "refund.pact"), "component C { }")
+	writeFile(t, filepath.Join(root, "specs", ".hidden", "secret.pact"), "component D { }")
+	writeFile(t, filepath.Join(root, "specs", "billing", "notes.md"), "")
+	return root
+}
+
+func assertFiles(t *testing.T, got []string, expected ...string) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("files[%d] = %q, expected %q", i, got[i], expected[i])
+		}
+	}
+}
+
+// PJ011: dir/... と ** の再帰展開（隠しディレクトリは除外、パス順）
+func TestProject_ExpandPatterns_Recursive(t *testing.T) {
+	root := expansionTree(t)
+	specs := filepath.Join(root, "specs")
+	proj := New(root, nil)
+
+	all := []string{
+		filepath.Join(specs, "billing", "drafts", "refund.pact"),
+		filepath.Join(specs, "billing", "invoice.pact"),
+		filepath.Join(specs, "order.pact"),
+	}
+	assertFiles(t, proj.ExpandPatterns([]string{specs + "/..."}), all...)
+	assertFiles(t, proj.ExpandPatterns([]string{specs + "/**/*.pact"}), all...)
+	assertFiles(t, proj.ExpandPatterns([]string{specs + "/billing/**/*.pact", specs + "/billing/..."}), all[:2]...)
+
+	// ディレクトリ指定は直下のみ
+	assertFiles(t, proj.ExpandPatterns([]string{specs}), all[2])
+}
+
+// PJ012: exclude と .pactignore で展開結果を絞り込む（明示したファイルは残す）
+func TestProject_ExpandPatterns_Excludes(t *testing.T) {
+	root := expansionTree(t)
+	specs := filepath.Join(root, "specs")
+	writeFile(t, filepath.Join(root, IgnoreFileName), "# drafts are work in progress\n\nspecs/billing/drafts\n")
+
+	cfg := domainConfig.Default()
+	cfg.PactRoot = "./specs"
+	cfg.Exclude = []string{"order.pact"}
+	proj := New(root, cfg)
+	ignore, err := ReadIgnoreFile(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	proj.Ignore = ignore
+
+	assertFiles(t, proj.ExpandPatterns([]string{specs + "/..."}), filepath.Join(specs, "billing", "invoice.pact"))
+
+	explicit := filepath.Join(specs, "order.pact")
+	assertFiles(t, proj.ExpandPatterns([]string{explicit}), explicit)
+}
+
+// PJ013: Load は .pactignore を読み込み、PactFiles にも適用する
+func TestProject_Load_Ignore(t *testing.T) {
+	root := expansionTree(t)
+	writeFile(t, filepath.Join(root, ".pactconfig"), "pact_root: ./specs\n")
+	writeFile(t, filepath.Join(root, IgnoreFileName), "**/drafts\n")
+
+	proj, err := Load(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := proj.PactFiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// pact_root 内の隠しディレクトリは PactFiles の対象（files[0]）
+	assertFiles(t, files[1:],
+		filepath.Join(root, "specs", "billing", "invoice.pact"),
+		filepath.Join(root, "specs", "order.pact"),
+	)
+}
+
+// PJ014: 名前が .pact で終わるディレクトリは展開結果に含めない
+func TestProject_ExpandPatterns_SkipsDirectories(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "order.pact"), "component A { }")
+	writeFile(t, filepath.Join(root, ".pact", "billing.pact"), "component B { }")
+	writeFile(t, filepath.Join(root, "empty", "drafts.pact", "refund.pact"), "component C { }")
+	proj := New(root, nil)
+
+	assertFiles(t, proj.ExpandPatterns([]string{root}), filepath.Join(root, "order.pact"))
+	assertFiles(t, proj.ExpandPatterns([]string{filepath.Join(root, "*.pact")}), filepath.Join(root, "order.pact"))
+
+	// .pact ファイルのないディレクトリはディレクトリ自体を返さない
+	assertFiles(t, proj.ExpandPatterns([]string{filepath.Join(root, "empty")}))
+}
+
+// PJ015: dir/... は pact_root の隠しディレクトリを走査し、何にも一致しなければそのまま残す
+func TestProject_ExpandPatterns_PactRoot(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".pact", "order.pact"), "component A { }")
+	writeFile(t, filepath.Join(root, ".git", "stale.pact"), "component B { }")
+	proj := New(root, nil)
+
+	assertFiles(t, proj.ExpandPatterns([]string{root + "/..."}), filepath.Join(root, ".pact", "order.pact"))
+
+	missing := filepath.Join(root, "missing", "...")
+	assertFiles(t, proj.ExpandPatterns([]string{missing}), missing)
+}
@@ -0,0 +1,161 @@
+package project
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"pact/internal/domain/errors"
+)
+
+// languageExtensions は言語ごとのソースファイル拡張子
+var languageExtensions = map[string][]string{
+	"go":         {".go"},
+	"typescript": {".ts", ".tsx"},
+	"javascript": {".js", ".jsx"},
+	"python":     {".py"},
+	"java":       {".java"},
+	"kotlin":     {".kt"},
+	"rust":       {".rs"},
+	"csharp":     {".cs"},
+	"ruby":       {".rb"},
+}
+
+// languageTestSuffixes は仕様の対象外とするテストファイルの接尾辞
+var languageTestSuffixes = map[string][]string{
+	"go":         {"_test.go"},
+	"typescript": {".test.ts", ".spec.ts", ".test.tsx", ".spec.tsx", ".d.ts"},
+	"javascript": {".test.js", ".spec.js", ".test.jsx", ".spec.jsx"},
+	"python":     {"_test.py"},
+	"java":       {"Test.java"},
+	"kotlin":     {"Test.kt"},
+	"rust":       {},
+	"csharp":     {"Tests.cs"},
+	"ruby":       {"_spec.rb", "_test.rb"},
+}
+
+// SourceExtensions は言語に対応するソースファイルの拡張子を返す
+func SourceExtensions(language string) []string {
+	return languageExtensions[strings.ToLower(language)]
+}
+
+// IsSourceFile はパスが設定言語のソースファイル（テストを除く）かを返す
+func (p *Project) IsSourceFile(path string) bool {
+	lang := strings.ToLower(p.Config.Language)
+	base := filepath.Base(path)
+	for _, suffix := range languageTestSuffixes[lang] {
+		if strings.HasSuffix(base, suffix) {
+			return false
+		}
+	}
+	ext := filepath.Ext(path)
+	for _, e := range languageExtensions[lang] {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceFiles は source_root 以下の設定言語のソースファイルをパス順で返す
+// exclude に一致するファイル・ディレクトリと、隠しディレクトリは除外する
+func (p *Project) SourceFiles() ([]string, error) {
+	if len(SourceExtensions(p.Config.Language)) == 0 {
+		return nil, &errors.ConfigError{Message: "unsupported language: " + p.Config.Language}
+	}
+
+	root := p.SourceRoot()
+	pactRoot := p.PactRoot()
+	var files []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			// pact_root が source_root 内にある場合や .git などは走査しない
+			if path == pactRoot || strings.HasPrefix(d.Name(), ".") || p.Excludes(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if p.Excludes(path) || !p.IsSourceFile(path) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, &errors.ConfigError{Path: root, Message: "failed to read source_root: " + err.Error()}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// PactPathFor はソースファイルに対応する仕様ファイルのパスを返す
+// 例: src/auth/service.go → .pact/auth/service.pact
+func (p *Project) PactPathFor(sourceFile string) (string, error) {
+	abs, err := filepath.Abs(sourceFile)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(p.SourceRoot(), abs)
+	if err != nil {
+		return "", err
+	}
+	rel = strings.TrimSuffix(rel, filepath.Ext(rel))
+	return filepath.Join(p.PactRoot(), rel+PactExt), nil
+}
+
+// SourcePathFor は仕様ファイルに対応する既存のソースファイルを返す
+// 対応するファイルがなければ空文字列を返す
+func (p *Project) SourcePathFor(pactFile string) string {
+	rel, err := p.RelPath(pactFile)
+	if err != nil {
+		return ""
+	}
+	stem := filepath.Join(p.SourceRoot(), strings.TrimSuffix(rel, PactExt))
+	for _, ext := range SourceExtensions(p.Config.Language) {
+		candidate := stem + ext
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// ComponentName はファイル名からコンポーネント名を導出する
+// 例: user_repository.go → UserRepository
+func ComponentName(path string) string {
+	base := filepath.Base(path)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	// パーサーの識別子はASCIIのみのため、それ以外の文字は区切りとして扱う
+	var sb strings.Builder
+	upper := true
+	for _, r := range base {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+
+	name := sb.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Component" + name
+	}
+	return name
+}
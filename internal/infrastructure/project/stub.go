@@ -0,0 +1,50 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"pact/internal/domain/errors"
+)
+
+// StubSpec はソースファイルに対応する空の仕様を返す
+func StubSpec(sourceRel, componentName string) string {
+	return fmt.Sprintf("// Source: %s\ncomponent %s {\n}\n", filepath.ToSlash(sourceRel), componentName)
+}
+
+// CreateStubs は source_root のディレクトリ構造を pact_root に反映し、
+// ソースファイルごとに空の仕様ファイルを作成する
+// 既存の仕様ファイルは上書きせず、作成したファイルのパスを返す
+func (p *Project) CreateStubs() ([]string, error) {
+	sources, err := p.SourceFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var created []string
+	for _, src := range sources {
+		pactPath, err := p.PactPathFor(src)
+		if err != nil {
+			return created, &errors.ConfigError{Path: src, Message: err.Error()}
+		}
+		if _, err := os.Stat(pactPath); err == nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(p.Root, src)
+		if err != nil {
+			rel = src
+		}
+
+		if err := os.MkdirAll(filepath.Dir(pactPath), 0755); err != nil {
+			return created, &errors.ConfigError{Path: pactPath, Message: err.Error()}
+		}
+		if err := os.WriteFile(pactPath, []byte(StubSpec(rel, ComponentName(src))), 0644); err != nil {
+			return created, &errors.ConfigError{Path: pactPath, Message: err.Error()}
+		}
+		created = append(created, pactPath)
+	}
+
+	return created, nil
+}
@@ -82,8 +82,8 @@ func (c *Canvas) OrthogonalArrowWithMid(x1, y1, midX, midY, x2, y2 int, opts ...
 	}
 }
 
-// DrawArrowHead は矢印の先端を描画する
-func (c *Canvas) DrawArrowHead(x2, y2, fromX, fromY int) {
+// DrawArrowHead は矢印の先端を描画する（opts で塗りの色などを上書きできる）
+func (c *Canvas) DrawArrowHead(x2, y2, fromX, fromY int, opts ...Option) {
 	arrowSize := 8
 	dx := float64(x2 - fromX)
 	dy := float64(y2 - fromY)
@@ -101,7 +101,7 @@ func (c *Canvas) DrawArrowHead(x2, y2, fromX, fromY int) {
 	p1y := int(ay + py*float64(arrowSize)/2)
 	p2x := int(ax - px*float64(arrowSize)/2)
 	p2y := int(ay - py*float64(arrowSize)/2)
-	c.Polygon(fmt.Sprintf("%d,%d %d,%d %d,%d", x2, y2, p1x, p1y, p2x, p2y), Fill("#000"))
+	c.Polygon(fmt.Sprintf("%d,%d %d,%d %d,%d", x2, y2, p1x, p1y, p2x, p2y), append([]Option{Fill("#000")}, opts...)...)
 }
 
 func sqrt(x float64) float64 {
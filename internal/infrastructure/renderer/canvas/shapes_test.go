@@ -6,7 +6,7 @@ import (
 )
 
 // =============================================================================
-// RS001-RS005: Shapes Tests
+// RS001-RS006: Shapes Tests
 // =============================================================================
 
 // RS001: ひし形
@@ -83,3 +83,18 @@ func TestShapes_Parallelogram(t *testing.T) {
 		t.Error("expected points attribute")
 	}
 }
+
+// RS006: 線のオプションは矢印の先端に渡さず、先端の塗りは DrawArrowHead で指定する
+func TestShapes_ArrowHeadOptions(t *testing.T) {
+	c := New()
+	c.Arrow(0, 0, 100, 0, Stroke("#e53e3e"))
+	c.DrawArrowHead(100, 50, 0, 50, Fill("#e53e3e"))
+	svg := c.String()
+
+	if strings.Count(svg, `stroke="#e53e3e"`) != 1 {
+		t.Errorf("expected only the line to be stroked:\n%s", svg)
+	}
+	if strings.Count(svg, `fill="#e53e3e"`) != 1 || strings.Count(svg, `fill="#000"`) != 1 {
+		t.Errorf("expected the arrowhead fill to be overridden:\n%s", svg)
+	}
+}
@@ -37,6 +37,18 @@ const (
 
 	// 初期状態
 	ColorInitialState = "#1a202c"
+
+	// 強調表示（シミュレーションの経路など）
+	ColorHighlight     = "#dd6b20"
+	ColorHighlightFill = "#feebc8"
+
+	// 差分の図（追加・削除・変更）
+	ColorAdded       = "#2f855a"
+	ColorAddedFill   = "#c6f6d5"
+	ColorRemoved     = "#c53030"
+	ColorRemovedFill = "#fed7d7"
+	ColorChanged     = "#b7791f"
+	ColorChangedFill = "#fefcbf"
 )
 
 // ストローク幅
@@ -224,3 +236,10 @@ func FontSize(size int) Option {
 		attrs["font-size"] = fmt.Sprintf("%d", size)
 	}
 }
+
+// TextDecoration はテキストの装飾（line-through など）を設定する
+func TextDecoration(decoration string) Option {
+	return func(attrs map[string]string) {
+		attrs["text-decoration"] = decoration
+	}
+}
@@ -249,3 +249,14 @@ func TestTemplateRegistry_ApplyToOrder(t *testing.T) {
 		t.Error("expected marker before symbol")
 	}
 }
+
+// RT024: TextDecoration オプション
+func TestOption_TextDecoration(t *testing.T) {
+	c := New()
+	c.Text(0, 0, "removed text", TextDecoration("line-through"))
+
+	svg := c.String()
+	if !strings.Contains(svg, `text-decoration="line-through"`) {
+		t.Error("expected text-decoration attribute")
+	}
+}
@@ -17,12 +17,18 @@ func NewClassRenderer() *ClassRenderer {
 
 // Render はクラス図をSVGにレンダリングする
 func (r *ClassRenderer) Render(diagram *class.Diagram, w io.Writer) error {
-	c := canvas.New()
+	return r.render(diagram, r.layout(diagram), w)
+}
 
-	// テンプレートレジストリを適用（シャドウ、フォント、カラーテーマ）
-	registry := canvas.NewBuiltinRegistry()
-	registry.ApplyTo(c)
+// classLayout はクラス図のノードの配置
+type classLayout struct {
+	positions map[string]struct{ x, y, width, height int }
+	width     int // キャンバスの幅
+	bottom    int // 最後のレイヤーの下端（レイヤー間のマージンを含む）
+}
 
+// layout はノードをレイヤーに割り当てて配置する
+func (r *ClassRenderer) layout(diagram *class.Diagram) *classLayout {
 	// 各ノードのサイズを事前計算
 	nodeSizes := make(map[string]struct{ width, height int })
 	for _, node := range diagram.Nodes {
@@ -108,6 +114,19 @@ func (r *ClassRenderer) Render(diagram *class.Diagram, w io.Writer) error {
 		y += layerHeights[i] + layerMargin
 	}
 
+	return &classLayout{positions: nodePositions, width: canvasWidth, bottom: y}
+}
+
+// render は配置済みのノードとエッジ・ノートを描画する
+func (r *ClassRenderer) render(diagram *class.Diagram, layout *classLayout, w io.Writer) error {
+	c := canvas.New()
+
+	// テンプレートレジストリを適用（シャドウ、フォント、カラーテーマ）
+	registry := canvas.NewBuiltinRegistry()
+	registry.ApplyTo(c)
+
+	nodePositions := layout.positions
+
 	// ノードを描画
 	nodeMap := make(map[string]class.Node)
 	for _, node := range diagram.Nodes {
@@ -119,8 +138,8 @@ func (r *ClassRenderer) Render(diagram *class.Diagram, w io.Writer) error {
 	}
 
 	// ノートの位置を考慮してキャンバスサイズを計算
-	totalHeight := y + 50
-	totalWidth := canvasWidth
+	totalHeight := layout.bottom + 50
+	totalWidth := layout.width
 
 	// ノートが存在する場合、その位置を考慮
 	if len(diagram.Notes) > 0 {
@@ -212,32 +231,32 @@ func (r *ClassRenderer) renderNode(c *canvas.Canvas, node class.Node, x, y, widt
 	sectionGap := 10
 
 	// ノード本体（テーマカラー＋ドロップシャドウ）
-	c.Rect(x, y, width, height,
+	c.Rect(x, y, width, height, append([]canvas.Option{
 		canvas.Fill(canvas.ColorNodeFill),
 		canvas.Stroke(canvas.ColorNodeStroke),
 		canvas.StrokeWidth(2),
 		canvas.Filter("drop-shadow"),
-	)
+	}, append(highlight(node.Highlighted), changeStyle(node.Change)...)...)...)
 
 	centerX := x + width/2
 	textY := y + padding + 12 // ベースライン調整
 
 	// ステレオタイプ
 	if node.Stereotype != "" {
-		c.Text(centerX, textY, "<<"+node.Stereotype+">>",
+		c.Text(centerX, textY, "<<"+node.Stereotype+">>", append([]canvas.Option{
 			canvas.TextAnchor("middle"),
 			canvas.Fill(canvas.ColorEdgeLabel),
 			canvas.FontStyle("italic"),
-		)
+		}, changeText(node.Change)...)...)
 		textY += lineHeight
 	}
 
 	// 名前
-	c.Text(centerX, textY, node.Name,
+	c.Text(centerX, textY, node.Name, append([]canvas.Option{
 		canvas.TextAnchor("middle"),
 		canvas.Fill(canvas.ColorNodeText),
 		canvas.FontWeight("bold"),
-	)
+	}, changeText(node.Change)...)...)
 	textY += lineHeight
 
 	// 属性セクション
@@ -250,7 +269,7 @@ func (r *ClassRenderer) renderNode(c *canvas.Canvas, node class.Node, x, y, widt
 		for _, attr := range node.Attributes {
 			vis := visibilitySymbol(attr.Visibility)
 			c.Text(x+10, textY, vis+attr.Name+": "+attr.Type,
-				canvas.Fill(canvas.ColorNodeText),
+				append([]canvas.Option{canvas.Fill(canvas.ColorNodeText)}, changeText(attr.Change)...)...,
 			)
 			textY += lineHeight
 		}
@@ -267,7 +286,7 @@ func (r *ClassRenderer) renderNode(c *canvas.Canvas, node class.Node, x, y, widt
 			vis := visibilitySymbol(class.Visibility(method.Visibility))
 			methodStr := r.formatMethod(method)
 			c.Text(x+10, textY, vis+methodStr,
-				canvas.Fill(canvas.ColorNodeText),
+				append([]canvas.Option{canvas.Fill(canvas.ColorNodeText)}, changeText(method.Change)...)...,
 			)
 			textY += lineHeight
 		}
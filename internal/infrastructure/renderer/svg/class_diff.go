@@ -0,0 +1,188 @@
+package svg
+
+import (
+	"io"
+
+	"pact/internal/domain/diagram/class"
+	"pact/internal/domain/diagram/common"
+)
+
+// ClassDiffRenderer は2つの版のクラス図の差分を1つのSVGにレンダリングする
+type ClassDiffRenderer struct {
+	renderer *ClassRenderer
+}
+
+// NewClassDiffRenderer は新しいClassDiffRendererを作成する
+func NewClassDiffRenderer() *ClassDiffRenderer {
+	return &ClassDiffRenderer{renderer: NewClassRenderer()}
+}
+
+// Render は変更前と変更後のクラス図を重ねてレンダリングする
+// 変更前からあるノードは変更前の図と同じ位置に置き、追加されたノードはその下の行に並べる
+func (r *ClassDiffRenderer) Render(old, new *class.Diagram, w io.Writer) error {
+	merged := mergeClass(old, new)
+	layout := r.renderer.layout(old)
+
+	x, rowHeight := 50, 0
+	for _, node := range merged.Nodes {
+		width, height := r.renderer.calculateNodeWidth(node), r.renderer.calculateNodeHeight(node)
+		if node.Change != common.ChangeAdded {
+			// 属性・メソッドの増減に合わせてサイズだけを変える
+			pos := layout.positions[node.ID]
+			layout.positions[node.ID] = struct{ x, y, width, height int }{pos.x, pos.y, width, height}
+			continue
+		}
+		layout.positions[node.ID] = struct{ x, y, width, height int }{x, layout.bottom, width, height}
+		x += width + 40
+		rowHeight = maxInt(rowHeight, height)
+	}
+	if rowHeight > 0 {
+		layout.width = maxInt(layout.width, x+10)
+		layout.bottom += rowHeight + 60
+	}
+
+	return r.renderer.render(merged, layout, w)
+}
+
+// mergeClass は2つの版のクラス図を、要素ごとの変更を付けた1つの図にまとめる
+func mergeClass(old, new *class.Diagram) *class.Diagram {
+	merged := &class.Diagram{Notes: new.Notes}
+
+	oldIDs, newIDs := classNodeIDs(old.Nodes), classNodeIDs(new.Nodes)
+	for _, m := range align(oldIDs, newIDs, oldIDs, newIDs) {
+		switch {
+		case m.new < 0:
+			merged.Nodes = append(merged.Nodes, markClassNode(old.Nodes[m.old], common.ChangeRemoved))
+		case m.old < 0:
+			merged.Nodes = append(merged.Nodes, markClassNode(new.Nodes[m.new], common.ChangeAdded))
+		default:
+			merged.Nodes = append(merged.Nodes, mergeClassNode(old.Nodes[m.old], new.Nodes[m.new]))
+		}
+	}
+
+	oldKeys, oldLoose := classEdgeKeys(old.Edges)
+	newKeys, newLoose := classEdgeKeys(new.Edges)
+	for _, m := range align(oldKeys, newKeys, oldLoose, newLoose) {
+		var edge class.Edge
+		switch {
+		case m.new < 0:
+			edge = old.Edges[m.old]
+			edge.Change = common.ChangeRemoved
+		case m.old < 0:
+			edge = new.Edges[m.new]
+			edge.Change = common.ChangeAdded
+		default:
+			edge = new.Edges[m.new]
+			if oldKeys[m.old] != newKeys[m.new] {
+				edge.Change = common.ChangeChanged
+			}
+		}
+		merged.Edges = append(merged.Edges, edge)
+	}
+	return merged
+}
+
+func classNodeIDs(nodes []class.Node) []string {
+	ids := make([]string, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID
+	}
+	return ids
+}
+
+// classEdgeKeys はエッジを対応付けるキー（ラベルや装飾を含む）と、両端と種類だけのキーを返す
+func classEdgeKeys(edges []class.Edge) (keys, loose []string) {
+	for _, edge := range edges {
+		key := edge.From + "\x00" + edge.To + "\x00" + string(edge.Type)
+		loose = append(loose, key)
+		keys = append(keys, key+"\x00"+edge.Label+"\x00"+string(edge.Decoration)+"\x00"+string(edge.LineStyle))
+	}
+	return keys, loose
+}
+
+// markClassNode は追加・削除されたノードとその属性・メソッドに変更を付ける
+func markClassNode(node class.Node, change common.Change) class.Node {
+	node.Change = change
+	node.Attributes = append([]class.Attribute(nil), node.Attributes...)
+	for i := range node.Attributes {
+		node.Attributes[i].Change = change
+	}
+	node.Methods = append([]class.Method(nil), node.Methods...)
+	for i := range node.Methods {
+		node.Methods[i].Change = change
+	}
+	return node
+}
+
+// mergeClassNode は両方の版にあるノードの属性・メソッドを名前で対応付けてまとめる
+func mergeClassNode(old, new class.Node) class.Node {
+	node := new
+	node.Attributes, node.Methods = nil, nil
+	changed := old.Name != new.Name || old.Stereotype != new.Stereotype
+
+	oldKeys, oldLoose := attributeKeys(old.Attributes)
+	newKeys, newLoose := attributeKeys(new.Attributes)
+	for _, m := range align(oldKeys, newKeys, oldLoose, newLoose) {
+		var attr class.Attribute
+		switch {
+		case m.new < 0:
+			attr = old.Attributes[m.old]
+			attr.Change = common.ChangeRemoved
+		case m.old < 0:
+			attr = new.Attributes[m.new]
+			attr.Change = common.ChangeAdded
+		default:
+			attr = new.Attributes[m.new]
+			if oldKeys[m.old] != newKeys[m.new] {
+				attr.Change = common.ChangeChanged
+			}
+		}
+		changed = changed || attr.Change != ""
+		node.Attributes = append(node.Attributes, attr)
+	}
+
+	oldKeys, oldLoose = methodKeys(old.Methods)
+	newKeys, newLoose = methodKeys(new.Methods)
+	for _, m := range align(oldKeys, newKeys, oldLoose, newLoose) {
+		var method class.Method
+		switch {
+		case m.new < 0:
+			method = old.Methods[m.old]
+			method.Change = common.ChangeRemoved
+		case m.old < 0:
+			method = new.Methods[m.new]
+			method.Change = common.ChangeAdded
+		default:
+			method = new.Methods[m.new]
+			if oldKeys[m.old] != newKeys[m.new] {
+				method.Change = common.ChangeChanged
+			}
+		}
+		changed = changed || method.Change != ""
+		node.Methods = append(node.Methods, method)
+	}
+
+	if changed {
+		node.Change = common.ChangeChanged
+	}
+	return node
+}
+
+// attributeKeys は属性の表示（可視性・名前・型）と名前を返す
+func attributeKeys(attrs []class.Attribute) (keys, names []string) {
+	for _, attr := range attrs {
+		keys = append(keys, visibilitySymbol(attr.Visibility)+attr.Name+": "+attr.Type)
+		names = append(names, attr.Name)
+	}
+	return keys, names
+}
+
+// methodKeys はメソッドの表示（可視性・シグネチャ）と名前を返す
+func methodKeys(methods []class.Method) (keys, names []string) {
+	r := &ClassRenderer{}
+	for _, method := range methods {
+		keys = append(keys, visibilitySymbol(class.Visibility(method.Visibility))+r.formatMethod(method))
+		names = append(names, method.Name)
+	}
+	return keys, names
+}
@@ -80,7 +80,7 @@ func (r *ClassRenderer) calculateDistributedEndpoints(
 
 // renderEdgeImproved は改良されたエッジ描画
 func (r *ClassRenderer) renderEdgeImproved(c *canvas.Canvas, edge class.Edge, x1, y1, x2, y2 int, nodePositions map[string]struct{ x, y, width, height int }) {
-	opts := []canvas.Option{canvas.Stroke(canvas.ColorEdge)}
+	opts := []canvas.Option{canvas.Stroke(classEdgeColor(edge))}
 	if edge.LineStyle == class.LineStyleDashed {
 		opts = append(opts, canvas.Dashed())
 	}
@@ -116,10 +116,10 @@ func (r *ClassRenderer) renderEdgeImproved(c *canvas.Canvas, edge class.Edge, x1
 	if edge.Label != "" {
 		midX := (x1 + x2) / 2
 		midY := (y1 + y2) / 2
-		c.Text(midX, midY-5, edge.Label,
+		c.Text(midX, midY-5, edge.Label, append([]canvas.Option{
 			canvas.TextAnchor("middle"),
-			canvas.Fill(canvas.ColorEdgeLabel),
-		)
+			canvas.Fill(classEdgeLabelColor(edge)),
+		}, strikethrough(edge.Change)...)...)
 	}
 }
 
@@ -387,7 +387,7 @@ func (r *ClassRenderer) renderVerticalEdge(c *canvas.Canvas, edge class.Edge,
 	toX := toCenterX + toOffset
 	toY := toPos.y + toPos.height
 
-	opts := []canvas.Option{canvas.Stroke(canvas.ColorEdge)}
+	opts := []canvas.Option{canvas.Stroke(classEdgeColor(edge))}
 	if edge.LineStyle == class.LineStyleDashed {
 		opts = append(opts, canvas.Dashed())
 	}
@@ -428,10 +428,10 @@ func (r *ClassRenderer) renderVerticalEdge(c *canvas.Canvas, edge class.Edge,
 	if edge.Label != "" {
 		midX := (fromX + toX) / 2
 		midY := (fromY + toY) / 2
-		c.Text(midX, midY-5, edge.Label,
+		c.Text(midX, midY-5, edge.Label, append([]canvas.Option{
 			canvas.TextAnchor("middle"),
-			canvas.Fill(canvas.ColorEdgeLabel),
-		)
+			canvas.Fill(classEdgeLabelColor(edge)),
+		}, strikethrough(edge.Change)...)...)
 	}
 }
 
@@ -514,18 +514,34 @@ func (r *ClassRenderer) zShapeIntersectsObstacles(fromX, fromY, toX, toY, midY i
 
 // drawArrowHead はエッジの装飾（矢印先端）を描画
 func (r *ClassRenderer) drawArrowHead(c *canvas.Canvas, edge class.Edge, fromX, fromY, toX, toY int) {
+	color := classEdgeColor(edge)
 	switch edge.Decoration {
 	case class.DecorationTriangle:
-		c.Polygon(trianglePoints(toX, toY, fromX, fromY), canvas.Fill(canvas.ColorNodeFill), canvas.Stroke(canvas.ColorEdge))
+		c.Polygon(trianglePoints(toX, toY, fromX, fromY), canvas.Fill(canvas.ColorNodeFill), canvas.Stroke(color))
 	case class.DecorationFilledDiamond:
-		c.Polygon(diamondPoints(fromX, fromY, toX, toY), canvas.Fill(canvas.ColorEdge))
+		c.Polygon(diamondPoints(fromX, fromY, toX, toY), canvas.Fill(color))
 	case class.DecorationEmptyDiamond:
-		c.Polygon(diamondPoints(fromX, fromY, toX, toY), canvas.Fill(canvas.ColorNodeFill), canvas.Stroke(canvas.ColorEdge))
+		c.Polygon(diamondPoints(fromX, fromY, toX, toY), canvas.Fill(canvas.ColorNodeFill), canvas.Stroke(color))
 	default:
-		c.Polygon(trianglePoints(toX, toY, fromX, fromY), canvas.Fill(canvas.ColorEdge))
+		c.Polygon(trianglePoints(toX, toY, fromX, fromY), canvas.Fill(color))
 	}
 }
 
+// classEdgeColor はエッジの線の色を返す（強調するエッジは強調色、差分の図では変更の色）
+func classEdgeColor(edge class.Edge) string {
+	if edge.Highlighted {
+		return canvas.ColorHighlight
+	}
+	return changeColor(edge.Change, canvas.ColorEdge)
+}
+
+func classEdgeLabelColor(edge class.Edge) string {
+	if edge.Highlighted {
+		return canvas.ColorHighlight
+	}
+	return changeColor(edge.Change, canvas.ColorEdgeLabel)
+}
+
 func trianglePoints(x, y, fromX, fromY int) string {
 	// 矢印の方向を計算
 	dx := float64(x - fromX)
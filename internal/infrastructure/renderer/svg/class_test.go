@@ -6,10 +6,11 @@ import (
 	"testing"
 
 	"pact/internal/domain/diagram/class"
+	"pact/internal/infrastructure/renderer/canvas"
 )
 
 // =============================================================================
-// RCL001-RCL013: ClassRenderer Tests
+// RCL001-RCL014: ClassRenderer Tests
 // =============================================================================
 
 // RCL001: 空図
@@ -297,3 +298,34 @@ func TestClassRenderer_Layout(t *testing.T) {
 		t.Errorf("expected at least 3 rect elements, got %d", rectCount)
 	}
 }
+
+// RCL014: 依存の循環などの強調表示
+func TestClassRenderer_Highlighted(t *testing.T) {
+	diagram := &class.Diagram{
+		Nodes: []class.Node{
+			{ID: "A", Name: "A", Highlighted: true},
+			{ID: "B", Name: "B", Highlighted: true},
+			{ID: "C", Name: "C"},
+		},
+		Edges: []class.Edge{
+			{From: "A", To: "B", Type: class.EdgeTypeDependency, Decoration: class.DecorationArrow, Highlighted: true},
+			{From: "B", To: "C", Type: class.EdgeTypeDependency, Decoration: class.DecorationArrow},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewClassRenderer().Render(diagram, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svg := buf.String()
+	if strings.Count(svg, `fill="`+canvas.ColorHighlightFill+`"`) != 2 {
+		t.Error("expected two highlighted nodes")
+	}
+	if !strings.Contains(svg, `fill="`+canvas.ColorHighlight+`"`) {
+		t.Error("expected highlighted arrow head")
+	}
+	if !strings.Contains(svg, `stroke="`+canvas.ColorEdge+`"`) {
+		t.Error("expected the other edge to keep the normal color")
+	}
+}
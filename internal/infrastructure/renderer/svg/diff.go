@@ -0,0 +1,127 @@
+package svg
+
+import (
+	"pact/internal/domain/diagram/common"
+	"pact/internal/infrastructure/renderer/canvas"
+)
+
+// changeColor は差分の図で変更された要素の線・文字の色を返す（変更がなければ base）
+func changeColor(change common.Change, base string) string {
+	switch change {
+	case common.ChangeAdded:
+		return canvas.ColorAdded
+	case common.ChangeRemoved:
+		return canvas.ColorRemoved
+	case common.ChangeChanged:
+		return canvas.ColorChanged
+	}
+	return base
+}
+
+// changeStyle は差分の図で変更された要素の塗りと枠線の描画オプションを返す
+func changeStyle(change common.Change) []canvas.Option {
+	fill := ""
+	switch change {
+	case common.ChangeAdded:
+		fill = canvas.ColorAddedFill
+	case common.ChangeRemoved:
+		fill = canvas.ColorRemovedFill
+	case common.ChangeChanged:
+		fill = canvas.ColorChangedFill
+	default:
+		return nil
+	}
+	return []canvas.Option{canvas.Fill(fill), canvas.Stroke(changeColor(change, ""))}
+}
+
+// changeStroke は変更された複合状態などの枠線だけを変更の色にする（子要素を読みやすく保つ）
+func changeStroke(change common.Change) []canvas.Option {
+	if change == "" {
+		return nil
+	}
+	return []canvas.Option{canvas.Stroke(changeColor(change, ""))}
+}
+
+// changeText は変更された要素の文字を変更の色にする（削除された要素は取り消し線を引く）
+func changeText(change common.Change) []canvas.Option {
+	if change == "" {
+		return nil
+	}
+	return append([]canvas.Option{canvas.Fill(changeColor(change, ""))}, strikethrough(change)...)
+}
+
+// strikethrough は削除された要素の文字に取り消し線を引く描画オプションを返す
+func strikethrough(change common.Change) []canvas.Option {
+	if change != common.ChangeRemoved {
+		return nil
+	}
+	return []canvas.Option{canvas.TextDecoration("line-through")}
+}
+
+// match は2つの版の要素の対応（old・new の一方が -1 なら削除・追加）
+type match struct {
+	old, new int
+}
+
+// align は2つの列を対応付け、変更前の並びを保ったまま追加・削除された要素を差し込んだ順で返す
+// key が一致する要素を最長共通部分列で対応付け、その間に残った要素のうち loose が一致するものを
+// 変更された要素として対応付ける
+func align(oldKeys, newKeys, oldLoose, newLoose []string) []match {
+	var result []match
+	oldStart, newStart := 0, 0
+	for _, p := range lcs(oldKeys, newKeys) {
+		result = alignGap(result, oldLoose[oldStart:p.old], newLoose[newStart:p.new], oldStart, newStart)
+		result = append(result, p)
+		oldStart, newStart = p.old+1, p.new+1
+	}
+	return alignGap(result, oldLoose[oldStart:], newLoose[newStart:], oldStart, newStart)
+}
+
+// alignGap は key で対応付かなかった区間を loose で対応付け、削除・追加の順に並べる
+func alignGap(result []match, oldLoose, newLoose []string, oldOffset, newOffset int) []match {
+	i, j := 0, 0
+	for _, p := range append(lcs(oldLoose, newLoose), match{len(oldLoose), len(newLoose)}) {
+		for ; i < p.old; i++ {
+			result = append(result, match{oldOffset + i, -1})
+		}
+		for ; j < p.new; j++ {
+			result = append(result, match{-1, newOffset + j})
+		}
+		if p.old < len(oldLoose) {
+			result = append(result, match{oldOffset + p.old, newOffset + p.new})
+			i, j = p.old+1, p.new+1
+		}
+	}
+	return result
+}
+
+// lcs は最長共通部分列で対応付けた要素の位置を返す
+func lcs(a, b []string) []match {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = maxInt(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	var pairs []match
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			pairs = append(pairs, match{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
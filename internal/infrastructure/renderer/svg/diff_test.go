@@ -0,0 +1,332 @@
+package svg
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+	"testing"
+
+	"pact/internal/domain/diagram/class"
+	"pact/internal/domain/diagram/flow"
+	"pact/internal/domain/diagram/sequence"
+	"pact/internal/domain/diagram/state"
+	"pact/internal/infrastructure/renderer/canvas"
+)
+
+// textPosition は指定したテキストの描画位置（"x,y"）を返す
+func textPosition(t *testing.T, svg, text string) string {
+	t.Helper()
+	m := regexp.MustCompile(`<text x="(\d+)" y="(\d+)"[^>]*>` + regexp.QuoteMeta(html.EscapeString(text)) + `</text>`).FindStringSubmatch(svg)
+	if m == nil {
+		t.Fatalf("text %q not found", text)
+	}
+	return m[1] + "," + m[2]
+}
+
+// textElement は指定したテキストの <text> 要素を返す
+func textElement(t *testing.T, svg, text string) string {
+	t.Helper()
+	m := regexp.MustCompile(`<text[^>]*>` + regexp.QuoteMeta(html.EscapeString(text)) + `</text>`).FindString(svg)
+	if m == "" {
+		t.Fatalf("text %q not found", text)
+	}
+	return m
+}
+
+func assertChangeText(t *testing.T, svg, text, color string, struck bool) {
+	t.Helper()
+	element := textElement(t, svg, text)
+	if !strings.Contains(element, `fill="`+color+`"`) {
+		t.Errorf("expected %q in %s: %s", text, color, element)
+	}
+	if strings.Contains(element, `text-decoration="line-through"`) != struck {
+		t.Errorf("unexpected strikethrough of %q: %s", text, element)
+	}
+}
+
+// drawOrder は <defs> より後の矩形とテンプレートを描画順に "要素 x,y" で返す
+func drawOrder(svg string) []string {
+	if i := strings.Index(svg, "</defs>"); i >= 0 {
+		svg = svg[i:]
+	}
+	var order []string
+	for _, m := range regexp.MustCompile(`<(rect|use)(?: href="#([\w-]+)")? x="(-?\d+)" y="(-?\d+)"`).FindAllStringSubmatch(svg, -1) {
+		name := m[1]
+		if m[2] != "" {
+			name = m[2]
+		}
+		order = append(order, name+" "+m[3]+","+m[4])
+	}
+	return order
+}
+
+func assertDrawOrder(t *testing.T, svg string, want ...string) {
+	t.Helper()
+	if got := drawOrder(svg); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected draw order:\ngot:\n  %s\nwant:\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+// =============================================================================
+// RDF001-RDF005: DiffRenderer Tests
+// =============================================================================
+
+// RDF001: 追加・削除・変更の対応付け
+func TestAlign(t *testing.T) {
+	old := []string{"a", "b1", "c", "d"}
+	new := []string{"a", "b2", "c", "e"}
+	loose := func(keys []string) []string {
+		l := make([]string, len(keys))
+		for i, k := range keys {
+			l[i] = k[:1]
+		}
+		return l
+	}
+
+	var got []string
+	for _, m := range align(old, new, loose(old), loose(new)) {
+		switch {
+		case m.new < 0:
+			got = append(got, "-"+old[m.old])
+		case m.old < 0:
+			got = append(got, "+"+new[m.new])
+		case old[m.old] != new[m.new]:
+			got = append(got, "~"+new[m.new])
+		default:
+			got = append(got, new[m.new])
+		}
+	}
+	if strings.Join(got, " ") != "a ~b2 c -d +e" {
+		t.Errorf("unexpected alignment: %v", got)
+	}
+}
+
+// RDF002: クラス図の差分
+func TestClassDiffRenderer(t *testing.T) {
+	old := &class.Diagram{
+		Nodes: []class.Node{
+			{ID: "Order", Name: "Order", Attributes: []class.Attribute{
+				{Name: "id", Type: "string", Visibility: class.VisibilityPublic},
+				{Name: "total", Type: "int", Visibility: class.VisibilityPublic},
+				{Name: "memo", Type: "string", Visibility: class.VisibilityPublic},
+			}},
+			{ID: "Gateway", Name: "Gateway"},
+		},
+		Edges: []class.Edge{{From: "Order", To: "Gateway", Type: class.EdgeTypeDependency, Decoration: class.DecorationArrow}},
+	}
+	new := &class.Diagram{
+		Nodes: []class.Node{
+			{ID: "Order", Name: "Order", Attributes: []class.Attribute{
+				{Name: "id", Type: "string", Visibility: class.VisibilityPublic},
+				{Name: "total", Type: "float", Visibility: class.VisibilityPublic},
+				{Name: "note", Type: "string", Visibility: class.VisibilityPublic},
+			}},
+			{ID: "Mailer", Name: "Mailer"},
+		},
+		Edges: []class.Edge{{From: "Order", To: "Mailer", Type: class.EdgeTypeDependency, Decoration: class.DecorationArrow}},
+	}
+
+	var before, diff bytes.Buffer
+	if err := NewClassRenderer().Render(old, &before); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := NewClassDiffRenderer().Render(old, new, &diff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svg := diff.String()
+	for _, name := range []string{"Order", "Gateway"} {
+		if textPosition(t, svg, name) != textPosition(t, before.String(), name) {
+			t.Errorf("expected %s to keep its position", name)
+		}
+	}
+	assertChangeText(t, svg, "Order", canvas.ColorChanged, false)
+	assertChangeText(t, svg, "Gateway", canvas.ColorRemoved, true)
+	assertChangeText(t, svg, "Mailer", canvas.ColorAdded, false)
+	assertChangeText(t, svg, "+ total: float", canvas.ColorChanged, false)
+	assertChangeText(t, svg, "+ memo: string", canvas.ColorRemoved, true)
+	assertChangeText(t, svg, "+ note: string", canvas.ColorAdded, false)
+	if strings.Contains(textElement(t, svg, "+ id: string"), "fill=\""+canvas.ColorChanged) {
+		t.Error("expected the unchanged attribute to keep the normal color")
+	}
+	for _, color := range []string{canvas.ColorAdded, canvas.ColorRemoved} {
+		if !strings.Contains(svg, `stroke="`+color+`"`) {
+			t.Errorf("expected an edge drawn in %s", color)
+		}
+	}
+}
+
+// RDF003: 状態図の差分
+func TestStateDiffRenderer(t *testing.T) {
+	on := func(event string) state.Trigger { return &state.EventTrigger{Event: event} }
+	old := &state.Diagram{
+		States: []state.State{
+			{ID: "__initial__", Type: state.StateTypeInitial},
+			{ID: "Pending", Name: "Pending", Type: state.StateTypeAtomic},
+			{ID: "Paid", Name: "Paid", Type: state.StateTypeAtomic},
+			{ID: "Cancelled", Name: "Cancelled", Type: state.StateTypeAtomic},
+		},
+		Transitions: []state.Transition{
+			{From: "__initial__", To: "Pending"},
+			{From: "Pending", To: "Paid", Trigger: on("pay")},
+			{From: "Pending", To: "Cancelled", Trigger: on("cancel")},
+			{From: "Paid", To: "Pending", Trigger: on("retry"), Actions: []string{"notify"}},
+		},
+	}
+	new := &state.Diagram{
+		States: []state.State{
+			{ID: "__initial__", Type: state.StateTypeInitial},
+			{ID: "Pending", Name: "Pending", Type: state.StateTypeAtomic},
+			{ID: "Paid", Name: "Paid", Type: state.StateTypeAtomic, Entry: []string{"receipt"}},
+			{ID: "Refunded", Name: "Refunded", Type: state.StateTypeAtomic},
+		},
+		Transitions: []state.Transition{
+			{From: "__initial__", To: "Pending"},
+			{From: "Pending", To: "Paid", Trigger: on("pay")},
+			{From: "Paid", To: "Refunded", Trigger: on("refund")},
+			{From: "Paid", To: "Pending", Trigger: on("retry"), Actions: []string{"notify", "log"}},
+		},
+	}
+
+	var before, diff bytes.Buffer
+	if err := NewStateRenderer().Render(old, &before); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := NewStateDiffRenderer().Render(old, new, &diff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svg := diff.String()
+	for _, name := range []string{"Pending", "Paid", "Cancelled"} {
+		if textPosition(t, svg, name) != textPosition(t, before.String(), name) {
+			t.Errorf("expected %s to keep its position", name)
+		}
+	}
+	assertChangeText(t, svg, "Paid", canvas.ColorChanged, false)
+	assertChangeText(t, svg, "Cancelled", canvas.ColorRemoved, true)
+	assertChangeText(t, svg, "Refunded", canvas.ColorAdded, false)
+	assertChangeText(t, svg, "cancel", canvas.ColorRemoved, true)
+	assertChangeText(t, svg, "refund", canvas.ColorAdded, false)
+	assertChangeText(t, svg, "retry / notify, log", canvas.ColorChanged, false)
+	if strings.Contains(textElement(t, svg, "Pending"), "fill=\""+canvas.ColorChanged) {
+		t.Error("expected the unchanged state to keep the normal color")
+	}
+}
+
+// RDF004: フローチャートの差分（ノードIDが版をまたいで異なる）
+func TestFlowDiffRenderer(t *testing.T) {
+	old := &flow.Diagram{
+		Nodes: []flow.Node{
+			{ID: "node_1", Label: "Start", Shape: flow.NodeShapeTerminal},
+			{ID: "node_2", Label: "Validate", Shape: flow.NodeShapeProcess},
+			{ID: "node_3", Label: "Save", Shape: flow.NodeShapeDatabase},
+			{ID: "node_4", Label: "End", Shape: flow.NodeShapeTerminal},
+		},
+		Edges: []flow.Edge{
+			{From: "node_1", To: "node_2"},
+			{From: "node_2", To: "node_3"},
+			{From: "node_3", To: "node_4"},
+		},
+	}
+	new := &flow.Diagram{
+		Nodes: []flow.Node{
+			{ID: "node_1", Label: "Start", Shape: flow.NodeShapeTerminal},
+			{ID: "node_2", Label: "Validate order", Shape: flow.NodeShapeProcess},
+			{ID: "node_3", Label: "End", Shape: flow.NodeShapeTerminal},
+			{ID: "node_4", Label: "Notify", Shape: flow.NodeShapeIO},
+		},
+		Edges: []flow.Edge{
+			{From: "node_1", To: "node_2"},
+			{From: "node_2", To: "node_3"},
+			{From: "node_3", To: "node_4", Label: "done"},
+		},
+	}
+
+	var before, diff bytes.Buffer
+	if err := NewFlowRenderer().Render(old, &before); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := NewFlowDiffRenderer().Render(old, new, &diff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svg := diff.String()
+	for _, label := range []string{"Start", "Save", "End"} {
+		if textPosition(t, svg, label) != textPosition(t, before.String(), label) {
+			t.Errorf("expected %s to keep its position", label)
+		}
+	}
+	if textPosition(t, svg, "Validate order") != textPosition(t, before.String(), "Validate") {
+		t.Error("expected the changed node to keep its position")
+	}
+	assertChangeText(t, svg, "Validate order", canvas.ColorChanged, false)
+	assertChangeText(t, svg, "Save", canvas.ColorRemoved, true)
+	assertChangeText(t, svg, "Notify", canvas.ColorAdded, false)
+	assertChangeText(t, svg, "done", canvas.ColorAdded, false)
+
+	merged := mergeFlow(old, new)
+	ids := make(map[string]bool)
+	for _, node := range merged.Nodes {
+		if ids[node.ID] {
+			t.Errorf("duplicate node ID: %s", node.ID)
+		}
+		ids[node.ID] = true
+	}
+	var edges []string
+	for _, edge := range merged.Edges {
+		edges = append(edges, string(edge.Change)+":"+edge.From+"->"+edge.To)
+	}
+	if got := strings.Join(edges, " "); got != ":node_1->node_2 removed:node_2->node_3 removed:node_3->node_4 added:node_2->node_4 added:node_4->node_4'" {
+		t.Errorf("unexpected edges: %s", got)
+	}
+}
+
+// RDF005: シーケンス図の差分
+func TestSequenceDiffRenderer(t *testing.T) {
+	old := &sequence.Diagram{
+		Participants: []sequence.Participant{
+			{ID: "Checkout", Name: "Checkout"},
+			{ID: "Payments", Name: "Payments"},
+			{ID: "Audit", Name: "Audit"},
+		},
+		Events: []sequence.Event{
+			&sequence.MessageEvent{From: "Checkout", To: "Payments", Label: "charge(amount)"},
+			&sequence.FragmentEvent{Type: sequence.FragmentTypeOpt, Label: "audited", Events: []sequence.Event{
+				&sequence.MessageEvent{From: "Checkout", To: "Audit", Label: "record()"},
+			}},
+		},
+	}
+	new := &sequence.Diagram{
+		Participants: []sequence.Participant{
+			{ID: "Checkout", Name: "Checkout"},
+			{ID: "Mailer", Name: "Mailer"},
+			{ID: "Payments", Name: "Payments"},
+		},
+		Events: []sequence.Event{
+			&sequence.MessageEvent{From: "Checkout", To: "Payments", Label: "charge(amount, currency)"},
+			&sequence.MessageEvent{From: "Checkout", To: "Mailer", Label: "send()", MessageType: sequence.MessageTypeAsync},
+		},
+	}
+
+	var before, diff bytes.Buffer
+	if err := NewSequenceRenderer().Render(old, &before); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := NewSequenceDiffRenderer().Render(old, new, &diff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svg := diff.String()
+	for _, name := range []string{"Checkout", "Payments", "Audit"} {
+		if textPosition(t, svg, name) != textPosition(t, before.String(), name) {
+			t.Errorf("expected %s to keep its position", name)
+		}
+	}
+	assertChangeText(t, svg, "Audit", canvas.ColorRemoved, true)
+	assertChangeText(t, svg, "Mailer", canvas.ColorAdded, false)
+	assertChangeText(t, svg, "charge(amount, currency)", canvas.ColorChanged, false)
+	assertChangeText(t, svg, "[opt] audited", canvas.ColorRemoved, true)
+	assertChangeText(t, svg, "record()", canvas.ColorRemoved, true)
+	assertChangeText(t, svg, "send()", canvas.ColorAdded, false)
+}
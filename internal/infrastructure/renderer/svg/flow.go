@@ -17,34 +17,47 @@ func NewFlowRenderer() *FlowRenderer {
 
 // Render はフローチャートをSVGにレンダリングする
 func (r *FlowRenderer) Render(diagram *flow.Diagram, w io.Writer) error {
+	return r.render(diagram, r.layout(diagram), w)
+}
+
+// flowLayout はフローチャートのノードの配置
+type flowLayout struct {
+	positions     map[string]struct{ x, y int } // ノードの上端の中心
+	width, height int                           // キャンバスのサイズ
+	bottom        int                           // 次のノードのY座標
+}
+
+// layout はノードを上から順に配置する（スイムレーンがあればレーンごとに配置する）
+func (r *FlowRenderer) layout(diagram *flow.Diagram) *flowLayout {
+	if len(diagram.Swimlanes) > 0 {
+		return r.layoutWithSwimlanes(diagram)
+	}
+	return r.layoutWithoutSwimlanes(diagram)
+}
+
+// render は配置済みのノードとエッジ・スイムレーン・ノートを描画する
+func (r *FlowRenderer) render(diagram *flow.Diagram, layout *flowLayout, w io.Writer) error {
 	c := canvas.New()
 
 	// テンプレートレジストリを適用
 	registry := canvas.NewBuiltinRegistry()
 	registry.ApplyTo(c)
 
-	// ノードの位置を計算
-	nodePositions := make(map[string]struct{ x, y int })
+	c.SetSize(layout.width, layout.height)
+	nodePositions := layout.positions
 	nodeInfo := make(map[string]flow.Node)
 
-	// ノードをIDでマップ
+	// ノードを描画
 	for _, node := range diagram.Nodes {
 		nodeInfo[node.ID] = node
+		if pos, ok := nodePositions[node.ID]; ok {
+			r.renderFlowNode(c, node, pos.x, pos.y)
+		}
 	}
 
-	// エッジから接続情報を構築
-	outEdges := make(map[string][]flow.Edge)
-	for _, edge := range diagram.Edges {
-		outEdges[edge.From] = append(outEdges[edge.From], edge)
-	}
-
-	// スイムレーンがあるかチェック
-	hasSwimlanes := len(diagram.Swimlanes) > 0
-
-	if hasSwimlanes {
-		r.renderWithSwimlanes(c, diagram, nodePositions, nodeInfo)
-	} else {
-		r.renderWithoutSwimlanes(c, diagram, nodePositions, nodeInfo)
+	// スイムレーンの枠とヘッダーを描画
+	if len(diagram.Swimlanes) > 0 {
+		r.renderSwimlanes(c, diagram.Swimlanes, layout.height)
 	}
 
 	// エッジを描画
@@ -84,12 +97,17 @@ func (r *FlowRenderer) Render(diagram *flow.Diagram, w io.Writer) error {
 	return err
 }
 
-// renderWithSwimlanes はスイムレーン付きでレンダリングする
-func (r *FlowRenderer) renderWithSwimlanes(c *canvas.Canvas, diagram *flow.Diagram, nodePositions map[string]struct{ x, y int }, nodeInfo map[string]flow.Node) {
-	swimlaneWidth := 200
-	headerHeight := 40
-	yStep := 80
-	startY := headerHeight + 30
+const (
+	flowSwimlaneWidth = 200
+	flowHeaderHeight  = 40
+	flowYStep         = 80
+	flowMainX         = 400
+	flowBranchX       = 550
+)
+
+// layoutWithSwimlanes はノードを所属するスイムレーンの列に配置する
+func (r *FlowRenderer) layoutWithSwimlanes(diagram *flow.Diagram) *flowLayout {
+	startY := flowHeaderHeight + 30
 
 	// スイムレーンIDからインデックスへのマップ
 	swimlaneIndex := make(map[string]int)
@@ -104,14 +122,10 @@ func (r *FlowRenderer) renderWithSwimlanes(c *canvas.Canvas, diagram *flow.Diagr
 	}
 
 	// ノードを順番に配置
+	positions := make(map[string]struct{ x, y int })
 	maxY := startY
 	for _, node := range diagram.Nodes {
-		slIdx, ok := swimlaneIndex[node.Swimlane]
-		if !ok {
-			slIdx = 0 // デフォルトは最初のスイムレーン
-		}
-
-		x := 50 + slIdx*swimlaneWidth + swimlaneWidth/2
+		x := r.swimlaneX(diagram.Swimlanes, node.Swimlane)
 		y := swimlaneY[node.Swimlane]
 
 		// 分岐先のNoラベルがある場合はY位置を調整
@@ -123,12 +137,11 @@ func (r *FlowRenderer) renderWithSwimlanes(c *canvas.Canvas, diagram *flow.Diagr
 			}
 		}
 
-		nodePositions[node.ID] = struct{ x, y int }{x, y}
-		r.renderFlowNode(c, node, x, y)
+		positions[node.ID] = struct{ x, y int }{x, y}
 
-		swimlaneY[node.Swimlane] = y + yStep
-		if y+yStep > maxY {
-			maxY = y + yStep
+		swimlaneY[node.Swimlane] = y + flowYStep
+		if y+flowYStep > maxY {
+			maxY = y + flowYStep
 		}
 	}
 
@@ -137,23 +150,37 @@ func (r *FlowRenderer) renderWithSwimlanes(c *canvas.Canvas, diagram *flow.Diagr
 	if height < 600 {
 		height = 600
 	}
-	width := 50 + len(diagram.Swimlanes)*swimlaneWidth + 50
+	width := 50 + len(diagram.Swimlanes)*flowSwimlaneWidth + 50
 	if width < 800 {
 		width = 800
 	}
-	c.SetSize(width, height)
+	return &flowLayout{positions: positions, width: width, height: height, bottom: maxY}
+}
 
-	// スイムレーンの枠とヘッダーを描画
-	for i, sl := range diagram.Swimlanes {
-		x := 50 + i*swimlaneWidth
+// swimlaneX はスイムレーンの列の中心のX座標を返す（不明なスイムレーンは最初の列）
+func (r *FlowRenderer) swimlaneX(swimlanes []flow.Swimlane, id string) int {
+	index := 0 // デフォルトは最初のスイムレーン
+	for i, sl := range swimlanes {
+		if sl.ID == id {
+			index = i
+			break
+		}
+	}
+	return 50 + index*flowSwimlaneWidth + flowSwimlaneWidth/2
+}
+
+// renderSwimlanes はスイムレーンの枠とヘッダーを描画する
+func (r *FlowRenderer) renderSwimlanes(c *canvas.Canvas, swimlanes []flow.Swimlane, height int) {
+	for i, sl := range swimlanes {
+		x := 50 + i*flowSwimlaneWidth
 
 		// ヘッダー背景
-		c.Rect(x, 0, swimlaneWidth, headerHeight,
+		c.Rect(x, 0, flowSwimlaneWidth, flowHeaderHeight,
 			canvas.Fill(canvas.ColorHeaderFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
 		)
 		// ヘッダーテキスト
-		c.Text(x+swimlaneWidth/2, headerHeight/2+5, sl.Name,
+		c.Text(x+flowSwimlaneWidth/2, flowHeaderHeight/2+5, sl.Name,
 			canvas.TextAnchor("middle"),
 			canvas.Fill(canvas.ColorNodeText),
 			canvas.FontWeight("bold"),
@@ -163,36 +190,34 @@ func (r *FlowRenderer) renderWithSwimlanes(c *canvas.Canvas, diagram *flow.Diagr
 		c.Line(x, 0, x, height, canvas.Stroke(canvas.ColorSectionLine))
 	}
 	// 最後の縦線
-	c.Line(50+len(diagram.Swimlanes)*swimlaneWidth, 0, 50+len(diagram.Swimlanes)*swimlaneWidth, height, canvas.Stroke(canvas.ColorSectionLine))
+	right := 50 + len(swimlanes)*flowSwimlaneWidth
+	c.Line(right, 0, right, height, canvas.Stroke(canvas.ColorSectionLine))
 	// ヘッダーの下線
-	c.Line(50, headerHeight, 50+len(diagram.Swimlanes)*swimlaneWidth, headerHeight, canvas.Stroke(canvas.ColorNodeStroke))
+	c.Line(50, flowHeaderHeight, right, flowHeaderHeight, canvas.Stroke(canvas.ColorNodeStroke))
 }
 
-// renderWithoutSwimlanes はスイムレーンなしでレンダリングする
-func (r *FlowRenderer) renderWithoutSwimlanes(c *canvas.Canvas, diagram *flow.Diagram, nodePositions map[string]struct{ x, y int }, nodeInfo map[string]flow.Node) {
-	mainX := 400
-	branchX := 550
+// layoutWithoutSwimlanes はノードを1列に配置する（Noの分岐先は右の列）
+func (r *FlowRenderer) layoutWithoutSwimlanes(diagram *flow.Diagram) *flowLayout {
+	positions := make(map[string]struct{ x, y int })
 	y := 50
-	yStep := 80
 
 	for _, node := range diagram.Nodes {
-		x := mainX
+		x := flowMainX
 		for _, edge := range diagram.Edges {
 			if edge.To == node.ID && edge.Label == "No" {
-				x = branchX
+				x = flowBranchX
 				break
 			}
 		}
-		nodePositions[node.ID] = struct{ x, y int }{x, y}
-		r.renderFlowNode(c, node, x, y)
-		y += yStep
+		positions[node.ID] = struct{ x, y int }{x, y}
+		y += flowYStep
 	}
 
 	height := y + 50
 	if height < 600 {
 		height = 600
 	}
-	c.SetSize(800, height)
+	return &flowLayout{positions: positions, width: 800, height: height, bottom: y}
 }
 
 // calculateFlowNodeWidth はフローノードの幅を計算する
@@ -219,79 +244,83 @@ func (r *FlowRenderer) renderFlowNode(c *canvas.Canvas, node flow.Node, x, y int
 }
 
 func (r *FlowRenderer) renderFlowNodeWithWidth(c *canvas.Canvas, node flow.Node, x, y, width int) {
+	style := changeStyle(node.Change)
 	switch node.Shape {
 	case flow.NodeShapeTerminal:
-		c.Stadium(x-width/2, y, width, 40,
+		c.Stadium(x-width/2, y, width, 40, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
 			canvas.StrokeWidth(2),
 			canvas.Filter("drop-shadow"),
-		)
+		}, style...)...)
 	case flow.NodeShapeProcess:
-		c.Rect(x-width/2, y, width, 40,
+		c.Rect(x-width/2, y, width, 40, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
 			canvas.StrokeWidth(2),
 			canvas.Filter("drop-shadow"),
-		)
+		}, style...)...)
 	case flow.NodeShapeDecision:
 		// 判断ノードは幅を少し大きめに
 		diamondWidth := width
 		if diamondWidth < 80 {
 			diamondWidth = 80
 		}
-		c.Diamond(x, y+20, diamondWidth, 40,
+		c.Diamond(x, y+20, diamondWidth, 40, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
 			canvas.StrokeWidth(2),
 			canvas.Filter("drop-shadow"),
-		)
+		}, style...)...)
 	case flow.NodeShapeDatabase:
-		c.Cylinder(x-width/2, y, width, 50,
+		c.Cylinder(x-width/2, y, width, 50, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
-		)
+		}, style...)...)
 	case flow.NodeShapeIO:
-		c.Parallelogram(x-width/2, y, width, 40, 15,
+		c.Parallelogram(x-width/2, y, width, 40, 15, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
 			canvas.StrokeWidth(2),
 			canvas.Filter("drop-shadow"),
-		)
+		}, style...)...)
 	default:
-		c.Rect(x-width/2, y, width, 40,
+		c.Rect(x-width/2, y, width, 40, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
 			canvas.StrokeWidth(2),
 			canvas.Filter("drop-shadow"),
-		)
+		}, style...)...)
 	}
 
 	if node.Label != "" {
-		c.Text(x, y+25, node.Label,
+		c.Text(x, y+25, node.Label, append([]canvas.Option{
 			canvas.TextAnchor("middle"),
 			canvas.Fill(canvas.ColorNodeText),
-		)
+		}, changeText(node.Change)...)...)
 	}
 }
 
 func (r *FlowRenderer) renderFlowEdge(c *canvas.Canvas, edge flow.Edge, x1, y1, x2, y2 int) {
+	color := changeColor(edge.Change, canvas.ColorEdge)
+	arrow := flowArrowFill(edge)
+
 	// 直交ルーティングで矢印を描画
 	if x1 == x2 {
 		// 垂直方向のみ
-		c.Line(x1, y1, x2, y2, canvas.Stroke(canvas.ColorEdge))
-		c.DrawArrowHead(x2, y2, x1, y1)
+		c.Line(x1, y1, x2, y2, canvas.Stroke(color))
+		c.DrawArrowHead(x2, y2, x1, y1, arrow...)
 	} else if y1 == y2 {
 		// 水平方向のみ
-		c.Line(x1, y1, x2, y2, canvas.Stroke(canvas.ColorEdge))
-		c.DrawArrowHead(x2, y2, x1, y1)
+		c.Line(x1, y1, x2, y2, canvas.Stroke(color))
+		c.DrawArrowHead(x2, y2, x1, y1, arrow...)
 	} else {
 		// L字型ルーティング
 		midY := (y1 + y2) / 2
-		c.Line(x1, y1, x1, midY, canvas.Stroke(canvas.ColorEdge))
-		c.Line(x1, midY, x2, midY, canvas.Stroke(canvas.ColorEdge))
-		c.Line(x2, midY, x2, y2, canvas.Stroke(canvas.ColorEdge))
-		c.DrawArrowHead(x2, y2, x2, midY)
+		c.Line(x1, y1, x1, midY, canvas.Stroke(color))
+		c.Line(x1, midY, x2, midY, canvas.Stroke(color))
+		c.Line(x2, midY, x2, y2, canvas.Stroke(color))
+		c.DrawArrowHead(x2, y2, x2, midY, arrow...)
 	}
 
 	// ラベルがある場合は表示
@@ -304,18 +333,34 @@ func (r *FlowRenderer) renderFlowEdge(c *canvas.Canvas, edge flow.Edge, x1, y1,
 			labelX = (x1 + x2) / 2
 			labelY = (y1 + y2) / 2
 		}
-		c.Text(labelX, labelY, edge.Label, canvas.Fill(canvas.ColorEdgeLabel))
+		c.Text(labelX, labelY, edge.Label, flowEdgeLabel(edge)...)
 	}
 }
 
 func (r *FlowRenderer) renderBranchEdge(c *canvas.Canvas, edge flow.Edge, x1, y1, x2, y2 int) {
+	color := changeColor(edge.Change, canvas.ColorEdge)
+
 	// L字型のパスを描画（右に出てから下に曲がる）
 	midX := x2
-	c.Line(x1, y1, midX, y1, canvas.Stroke(canvas.ColorEdge))
-	c.Arrow(midX, y1, x2, y2, canvas.Stroke(canvas.ColorEdge))
+	c.Line(x1, y1, midX, y1, canvas.Stroke(color))
+	c.Line(midX, y1, x2, y2, canvas.Stroke(color))
+	c.DrawArrowHead(x2, y2, midX, y1, flowArrowFill(edge)...)
 
 	// ラベル
 	if edge.Label != "" {
-		c.Text(x1+20, y1-5, edge.Label, canvas.Fill(canvas.ColorEdgeLabel))
+		c.Text(x1+20, y1-5, edge.Label, flowEdgeLabel(edge)...)
 	}
 }
+
+// flowArrowFill は差分の図で変更されたエッジの矢印を変更の色で塗るオプションを返す
+func flowArrowFill(edge flow.Edge) []canvas.Option {
+	if edge.Change == "" {
+		return nil
+	}
+	return []canvas.Option{canvas.Fill(changeColor(edge.Change, ""))}
+}
+
+// flowEdgeLabel はエッジのラベルの描画オプションを返す
+func flowEdgeLabel(edge flow.Edge) []canvas.Option {
+	return append([]canvas.Option{canvas.Fill(canvas.ColorEdgeLabel)}, changeText(edge.Change)...)
+}
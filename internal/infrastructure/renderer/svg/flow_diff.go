@@ -0,0 +1,155 @@
+package svg
+
+import (
+	"io"
+
+	"pact/internal/domain/diagram/common"
+	"pact/internal/domain/diagram/flow"
+)
+
+// FlowDiffRenderer は2つの版のフローチャートの差分を1つのSVGにレンダリングする
+type FlowDiffRenderer struct {
+	renderer *FlowRenderer
+}
+
+// NewFlowDiffRenderer は新しいFlowDiffRendererを作成する
+func NewFlowDiffRenderer() *FlowDiffRenderer {
+	return &FlowDiffRenderer{renderer: NewFlowRenderer()}
+}
+
+// Render は変更前と変更後のフローチャートを重ねてレンダリングする
+// 変更前からあるノードは変更前の図と同じ位置に置き、追加されたノードはその下に並べる
+// （まとめた図のノードIDは、変更前からあるノードでは変更前の版のIDになる）
+func (r *FlowDiffRenderer) Render(old, new *flow.Diagram, w io.Writer) error {
+	merged := mergeFlow(old, new)
+	layout := r.renderer.layout(old)
+
+	// 追加されたノードを変更前の図の下に並べる（スイムレーンがあれば所属するレーンの列）
+	y := layout.bottom
+	for _, node := range merged.Nodes {
+		if node.Change != common.ChangeAdded {
+			continue
+		}
+		x := flowMainX
+		if len(merged.Swimlanes) > 0 {
+			x = r.renderer.swimlaneX(merged.Swimlanes, node.Swimlane)
+		}
+		layout.positions[node.ID] = struct{ x, y int }{x, y}
+		y += flowYStep
+	}
+	layout.height = maxInt(layout.height, y+50)
+
+	return r.renderer.render(merged, layout, w)
+}
+
+// mergeFlow は2つの版のフローチャートを、ノードとエッジごとの変更を付けた1つの図にまとめる
+// フローのノードIDは生成順の連番で版をまたいで対応しないため、ノードは並び順と形状・ラベルで対応付ける
+func mergeFlow(old, new *flow.Diagram) *flow.Diagram {
+	merged := &flow.Diagram{}
+
+	// 変更後の版のノードID → まとめた図のノードID
+	newIDs := make(map[string]string)
+	usedIDs := make(map[string]bool)
+	for _, node := range old.Nodes {
+		usedIDs[node.ID] = true
+	}
+
+	oldKeys, oldLoose := flowNodeKeys(old.Nodes)
+	newKeys, newLoose := flowNodeKeys(new.Nodes)
+	matches := align(oldKeys, newKeys, oldLoose, newLoose)
+	for _, m := range matches {
+		if m.old >= 0 && m.new >= 0 {
+			newIDs[new.Nodes[m.new].ID] = old.Nodes[m.old].ID
+		}
+	}
+	for _, m := range matches {
+		var node flow.Node
+		switch {
+		case m.new < 0:
+			node = old.Nodes[m.old]
+			node.Change = common.ChangeRemoved
+		case m.old < 0:
+			node = new.Nodes[m.new]
+			node.Change = common.ChangeAdded
+			// 変更前の版のノードIDと重ならないIDにする
+			id := node.ID
+			for usedIDs[id] {
+				id += "'"
+			}
+			usedIDs[id] = true
+			newIDs[node.ID] = id
+			node.ID = id
+		default:
+			node = new.Nodes[m.new]
+			node.ID = old.Nodes[m.old].ID
+			if oldKeys[m.old] != newKeys[m.new] {
+				node.Change = common.ChangeChanged
+			}
+		}
+		merged.Nodes = append(merged.Nodes, node)
+	}
+
+	newEdges := make([]flow.Edge, len(new.Edges))
+	for i, edge := range new.Edges {
+		edge.From, edge.To = newIDs[edge.From], newIDs[edge.To]
+		newEdges[i] = edge
+	}
+	oldKeys, oldLoose = flowEdgeKeys(old.Edges)
+	newKeys, newLoose = flowEdgeKeys(newEdges)
+	for _, m := range align(oldKeys, newKeys, oldLoose, newLoose) {
+		var edge flow.Edge
+		switch {
+		case m.new < 0:
+			edge = old.Edges[m.old]
+			edge.Change = common.ChangeRemoved
+		case m.old < 0:
+			edge = newEdges[m.new]
+			edge.Change = common.ChangeAdded
+		default:
+			edge = newEdges[m.new]
+			if oldKeys[m.old] != newKeys[m.new] {
+				edge.Change = common.ChangeChanged
+			}
+		}
+		merged.Edges = append(merged.Edges, edge)
+	}
+
+	// スイムレーンは変更前の並びに追加されたものを続ける（変更前の列の位置を保つ）
+	swimlanes := make(map[string]bool)
+	for _, sl := range old.Swimlanes {
+		swimlanes[sl.ID] = true
+		merged.Swimlanes = append(merged.Swimlanes, sl)
+	}
+	for _, sl := range new.Swimlanes {
+		if !swimlanes[sl.ID] {
+			merged.Swimlanes = append(merged.Swimlanes, sl)
+		}
+	}
+
+	for _, note := range new.Notes {
+		if id, ok := newIDs[note.AttachTo]; ok {
+			note.AttachTo = id
+		}
+		merged.Notes = append(merged.Notes, note)
+	}
+	return merged
+}
+
+// flowNodeKeys はノードの形状・ラベル・スイムレーンのキーと、形状だけのキーを返す
+func flowNodeKeys(nodes []flow.Node) (keys, shapes []string) {
+	for _, node := range nodes {
+		keys = append(keys, string(node.Shape)+"\x00"+node.Label+"\x00"+node.Swimlane)
+		shapes = append(shapes, string(node.Shape))
+	}
+	return keys, shapes
+}
+
+// flowEdgeKeys はエッジの両端とラベルのキーと、両端だけのキーを返す
+func flowEdgeKeys(edges []flow.Edge) (keys, loose []string) {
+	for _, edge := range edges {
+		key := edge.From + "\x00" + edge.To
+		loose = append(loose, key)
+		keys = append(keys, key+"\x00"+edge.Label)
+	}
+	return keys, loose
+}
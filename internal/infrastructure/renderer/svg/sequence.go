@@ -2,6 +2,7 @@ package svg
 
 import (
 	"io"
+	"sort"
 
 	"pact/internal/domain/diagram/sequence"
 	"pact/internal/infrastructure/renderer/canvas"
@@ -98,20 +99,22 @@ func (r *SequenceRenderer) renderEvents(c *canvas.Canvas, events []sequence.Even
 			}
 
 			// メッセージの矢印を描画
+			color := changeColor(e.Change, canvas.ColorEdge)
 			switch e.MessageType {
 			case sequence.MessageTypeAsync:
-				c.Line(fromX, *y, toX, *y, canvas.Stroke(canvas.ColorEdge), canvas.Dashed())
-				r.drawOpenArrow(c, fromX, toX, *y)
+				c.Line(fromX, *y, toX, *y, canvas.Stroke(color), canvas.Dashed())
+				r.drawOpenArrow(c, fromX, toX, *y, color)
 			case sequence.MessageTypeReturn:
-				c.Line(fromX, *y, toX, *y, canvas.Stroke(canvas.ColorEdge), canvas.Dashed())
-				r.drawOpenArrow(c, fromX, toX, *y)
+				c.Line(fromX, *y, toX, *y, canvas.Stroke(color), canvas.Dashed())
+				r.drawOpenArrow(c, fromX, toX, *y, color)
 				// returnでアクティベーション終了
 				if startY, ok := activations[e.From]; ok {
 					r.drawActivationBar(c, fromX, startY, *y)
 					delete(activations, e.From)
 				}
 			default: // sync
-				c.Arrow(fromX, *y, toX, *y, canvas.Stroke(canvas.ColorEdge))
+				c.Line(fromX, *y, toX, *y, canvas.Stroke(color))
+				c.DrawArrowHead(toX, *y, fromX, *y, messageArrowFill(e)...)
 				// syncでターゲットをアクティベート
 				if _, ok := activations[e.To]; !ok {
 					activations[e.To] = *y
@@ -120,10 +123,10 @@ func (r *SequenceRenderer) renderEvents(c *canvas.Canvas, events []sequence.Even
 
 			// ラベル
 			midX := (fromX + toX) / 2
-			c.Text(midX, *y-5, e.Label,
+			c.Text(midX, *y-5, e.Label, append([]canvas.Option{
 				canvas.TextAnchor("middle"),
 				canvas.Fill(canvas.ColorNodeText),
-			)
+			}, changeText(e.Change)...)...)
 
 			*y += 40
 
@@ -152,12 +155,12 @@ func (r *SequenceRenderer) renderEvents(c *canvas.Canvas, events []sequence.Even
 
 			// 枠を描画（参加者数に応じた幅）
 			c.Rect(50, startY-10, frameWidth, *y-startY+20,
-				canvas.Stroke(canvas.ColorNodeStroke), canvas.Fill("none"),
+				canvas.Stroke(changeColor(e.Change, canvas.ColorNodeStroke)), canvas.Fill("none"),
 			)
-			c.Text(60, startY, "["+string(e.Type)+"] "+e.Label,
+			c.Text(60, startY, "["+string(e.Type)+"] "+e.Label, append([]canvas.Option{
 				canvas.Fill(canvas.ColorEdgeLabel),
 				canvas.FontWeight("bold"),
-			)
+			}, changeText(e.Change)...)...)
 
 			// alt の区切り線を描画
 			if altSeparatorY > 0 {
@@ -209,20 +212,25 @@ func (r *SequenceRenderer) renderEvents(c *canvas.Canvas, events []sequence.Even
 			noteX := x + 20
 			noteY := *y - 10
 			c.Rect(noteX, noteY, noteWidth, noteHeight,
-				canvas.Fill(fillColor), canvas.Stroke(strokeColor),
+				canvas.Fill(fillColor), canvas.Stroke(changeColor(e.Change, strokeColor)),
 			)
-			c.Text(noteX+10, *y+5, e.Text, canvas.Fill(canvas.ColorNodeText))
+			c.Text(noteX+10, *y+5, e.Text, append([]canvas.Option{canvas.Fill(canvas.ColorNodeText)}, changeText(e.Change)...)...)
 
 			*y += 30
 		}
 	}
 
-	// 残っているアクティベーションを閉じる
-	for participant, startY := range activations {
-		if x, ok := participantX[participant]; ok {
-			r.drawActivationBar(c, x, startY, *y)
+	// 残っているアクティベーションを左の参加者から順に閉じる
+	open := make([]string, 0, len(activations))
+	for participant := range activations {
+		if _, ok := participantX[participant]; ok {
+			open = append(open, participant)
 		}
 	}
+	sort.Slice(open, func(i, j int) bool { return participantX[open[i]] < participantX[open[j]] })
+	for _, participant := range open {
+		r.drawActivationBar(c, participantX[participant], activations[participant], *y)
+	}
 }
 
 // drawActivationBar はアクティベーションバーを描画する
@@ -234,51 +242,53 @@ func (r *SequenceRenderer) drawActivationBar(c *canvas.Canvas, x, startY, endY i
 	)
 }
 
-func (r *SequenceRenderer) drawOpenArrow(c *canvas.Canvas, fromX, toX, y int) {
+func (r *SequenceRenderer) drawOpenArrow(c *canvas.Canvas, fromX, toX, y int, color string) {
 	if toX > fromX {
-		c.Line(toX-8, y-5, toX, y, canvas.Stroke(canvas.ColorEdge))
-		c.Line(toX-8, y+5, toX, y, canvas.Stroke(canvas.ColorEdge))
+		c.Line(toX-8, y-5, toX, y, canvas.Stroke(color))
+		c.Line(toX-8, y+5, toX, y, canvas.Stroke(color))
 	} else {
-		c.Line(toX+8, y-5, toX, y, canvas.Stroke(canvas.ColorEdge))
-		c.Line(toX+8, y+5, toX, y, canvas.Stroke(canvas.ColorEdge))
+		c.Line(toX+8, y-5, toX, y, canvas.Stroke(color))
+		c.Line(toX+8, y+5, toX, y, canvas.Stroke(color))
 	}
 }
 
+// messageArrowFill は差分の図で変更されたメッセージの矢印を変更の色で塗るオプションを返す
+func messageArrowFill(e *sequence.MessageEvent) []canvas.Option {
+	if e.Change == "" {
+		return nil
+	}
+	return []canvas.Option{canvas.Fill(changeColor(e.Change, ""))}
+}
+
 
 func (r *SequenceRenderer) renderParticipantWithWidth(c *canvas.Canvas, p sequence.Participant, x, y, width int) {
+	name := append([]canvas.Option{
+		canvas.TextAnchor("middle"),
+		canvas.Fill(canvas.ColorNodeText),
+		canvas.FontWeight("bold"),
+	}, changeText(p.Change)...)
+
 	switch p.Type {
 	case sequence.ParticipantTypeActor:
 		// アクターテンプレートを使用（固定プロポーション）
 		c.UseTemplate("actor", x-20, y, 40, 55)
-		c.Text(x, y+60, p.Name,
-			canvas.TextAnchor("middle"),
-			canvas.Fill(canvas.ColorNodeText),
-			canvas.FontWeight("bold"),
-		)
+		c.Text(x, y+60, p.Name, name...)
 	case sequence.ParticipantTypeDatabase:
-		c.Cylinder(x-width/2, y, width, 50,
+		c.Cylinder(x-width/2, y, width, 50, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
-		)
-		c.Text(x, y+60, p.Name,
-			canvas.TextAnchor("middle"),
-			canvas.Fill(canvas.ColorNodeText),
-			canvas.FontWeight("bold"),
-		)
+		}, changeStyle(p.Change)...)...)
+		c.Text(x, y+60, p.Name, name...)
 	default:
-		c.Rect(x-width/2, y, width, 40,
+		c.Rect(x-width/2, y, width, 40, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
 			canvas.StrokeWidth(2),
 			canvas.Filter("drop-shadow"),
-		)
-		c.Text(x, y+25, p.Name,
-			canvas.TextAnchor("middle"),
-			canvas.Fill(canvas.ColorNodeText),
-			canvas.FontWeight("bold"),
-		)
+		}, changeStyle(p.Change)...)...)
+		c.Text(x, y+25, p.Name, name...)
 	}
 
 	// ライフライン（破線）
-	c.Line(x, y+50, x, 500, canvas.Stroke(canvas.ColorNodeStroke), canvas.Dashed())
+	c.Line(x, y+50, x, 500, canvas.Stroke(changeColor(p.Change, canvas.ColorNodeStroke)), canvas.Dashed())
 }
@@ -0,0 +1,161 @@
+package svg
+
+import (
+	"io"
+	"strconv"
+
+	"pact/internal/domain/diagram/common"
+	"pact/internal/domain/diagram/sequence"
+)
+
+// SequenceDiffRenderer は2つの版のシーケンス図の差分を1つのSVGにレンダリングする
+type SequenceDiffRenderer struct {
+	renderer *SequenceRenderer
+}
+
+// NewSequenceDiffRenderer は新しいSequenceDiffRendererを作成する
+func NewSequenceDiffRenderer() *SequenceDiffRenderer {
+	return &SequenceDiffRenderer{renderer: NewSequenceRenderer()}
+}
+
+// Render は変更前と変更後のシーケンス図を重ねてレンダリングする
+// 参加者は変更前の並びに追加された参加者を続け、変更前の参加者の位置を保つ
+// イベントは削除されたものも元の順序のまま残す
+func (r *SequenceDiffRenderer) Render(old, new *sequence.Diagram, w io.Writer) error {
+	return r.renderer.Render(mergeSequence(old, new), w)
+}
+
+// mergeSequence は2つの版のシーケンス図を、参加者とイベントごとの変更を付けた1つの図にまとめる
+func mergeSequence(old, new *sequence.Diagram) *sequence.Diagram {
+	merged := &sequence.Diagram{Notes: new.Notes}
+
+	newParticipants := make(map[string]sequence.Participant)
+	for _, p := range new.Participants {
+		newParticipants[p.ID] = p
+	}
+	oldParticipants := make(map[string]bool)
+	for _, p := range old.Participants {
+		oldParticipants[p.ID] = true
+		if n, ok := newParticipants[p.ID]; !ok {
+			p.Change = common.ChangeRemoved
+		} else if n.Name != p.Name || n.Type != p.Type {
+			p = n
+			p.Change = common.ChangeChanged
+		}
+		merged.Participants = append(merged.Participants, p)
+	}
+	for _, p := range new.Participants {
+		if !oldParticipants[p.ID] {
+			p.Change = common.ChangeAdded
+			merged.Participants = append(merged.Participants, p)
+		}
+	}
+
+	merged.Events, _ = mergeEvents(old.Events, new.Events)
+	return merged
+}
+
+// mergeEvents はイベントの列を対応付けてまとめ、変更があったかどうかを返す
+// メッセージは両端、フラグメントは種類、注釈は参加者と種類が同じなら変更として対応付ける
+func mergeEvents(old, new []sequence.Event) ([]sequence.Event, bool) {
+	oldKeys, oldLoose := eventKeys(old)
+	newKeys, newLoose := eventKeys(new)
+	var merged []sequence.Event
+	changed := false
+	for _, m := range align(oldKeys, newKeys, oldLoose, newLoose) {
+		switch {
+		case m.new < 0:
+			merged = append(merged, markEvent(old[m.old], common.ChangeRemoved))
+			changed = true
+		case m.old < 0:
+			merged = append(merged, markEvent(new[m.new], common.ChangeAdded))
+			changed = true
+		default:
+			event, eventChanged := mergeEvent(old[m.old], new[m.new], oldKeys[m.old] != newKeys[m.new])
+			merged = append(merged, event)
+			changed = changed || eventChanged
+		}
+	}
+	return merged, changed
+}
+
+// mergeEvent は対応付いたイベントをまとめる（フラグメントは中のイベントもまとめる）
+func mergeEvent(old, new sequence.Event, changed bool) (sequence.Event, bool) {
+	change := common.Change("")
+	if changed {
+		change = common.ChangeChanged
+	}
+	switch e := new.(type) {
+	case *sequence.MessageEvent:
+		m := *e
+		m.Change = change
+		return &m, changed
+	case *sequence.NoteEvent:
+		n := *e
+		n.Change = change
+		return &n, changed
+	case *sequence.FragmentEvent:
+		o := old.(*sequence.FragmentEvent)
+		f := *e
+		var thenChanged, elseChanged bool
+		f.Events, thenChanged = mergeEvents(o.Events, e.Events)
+		f.AltEvents, elseChanged = mergeEvents(o.AltEvents, e.AltEvents)
+		if changed || thenChanged || elseChanged || o.AltLabel != e.AltLabel {
+			f.Change = common.ChangeChanged
+		}
+		return &f, f.Change != ""
+	}
+	return new, changed
+}
+
+// markEvent は追加・削除されたイベントに変更を付ける（フラグメントは中のイベントにも付ける）
+func markEvent(event sequence.Event, change common.Change) sequence.Event {
+	switch e := event.(type) {
+	case *sequence.MessageEvent:
+		m := *e
+		m.Change = change
+		return &m
+	case *sequence.NoteEvent:
+		n := *e
+		n.Change = change
+		return &n
+	case *sequence.FragmentEvent:
+		f := *e
+		f.Change = change
+		f.Events = markEvents(e.Events, change)
+		f.AltEvents = markEvents(e.AltEvents, change)
+		return &f
+	}
+	return event
+}
+
+func markEvents(events []sequence.Event, change common.Change) []sequence.Event {
+	marked := make([]sequence.Event, len(events))
+	for i, event := range events {
+		marked[i] = markEvent(event, change)
+	}
+	return marked
+}
+
+// eventKeys はイベントの内容のキーと、変更として対応付けるためのキーを返す
+func eventKeys(events []sequence.Event) (keys, loose []string) {
+	for _, event := range events {
+		var key, detail string
+		switch e := event.(type) {
+		case *sequence.MessageEvent:
+			key = "message\x00" + e.From + "\x00" + e.To
+			detail = e.Label + "\x00" + string(e.MessageType)
+		case *sequence.FragmentEvent:
+			key = "fragment\x00" + string(e.Type)
+			detail = e.Label
+		case *sequence.NoteEvent:
+			key = "note\x00" + e.Participant + "\x00" + string(e.NoteType)
+			detail = e.Text
+		case *sequence.ActivationEvent:
+			key = "activation\x00" + e.Participant + "\x00" + strconv.FormatBool(e.Active)
+		}
+		loose = append(loose, key)
+		keys = append(keys, key+"\x00"+detail)
+	}
+	return keys, loose
+}
@@ -118,3 +118,32 @@ func TestSeqRenderer_ParticipantType_Database(t *testing.T) {
 		t.Error("expected ellipse for database cylinder")
 	}
 }
+
+// RSQ006: シーケンス図は閉じていないアクティベーションを左の参加者から順に描画する
+func TestSequenceRenderer_DrawOrder(t *testing.T) {
+	diagram := &sequence.Diagram{
+		Participants: []sequence.Participant{
+			{ID: "Checkout", Name: "Checkout"},
+			{ID: "Payments", Name: "Payments"},
+			{ID: "Audit", Name: "Audit"},
+		},
+		Events: []sequence.Event{
+			&sequence.MessageEvent{From: "Checkout", To: "Audit", Label: "record()"},
+			&sequence.MessageEvent{From: "Checkout", To: "Payments", Label: "charge()"},
+		},
+	}
+
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		if err := NewSequenceRenderer().Render(diagram, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertDrawOrder(t, buf.String(),
+			"rect 50,50",
+			"rect 160,50",
+			"rect 270,50",
+			"rect 195,160",
+			"rect 305,120",
+		)
+	}
+}
@@ -23,12 +23,19 @@ func NewStateRenderer() *StateRenderer {
 
 // Render は状態図をSVGにレンダリングする
 func (r *StateRenderer) Render(diagram *state.Diagram, w io.Writer) error {
-	c := canvas.New()
+	return r.render(diagram, r.layout(diagram), w)
+}
 
-	// テンプレートレジストリを適用
-	registry := canvas.NewBuiltinRegistry()
-	registry.ApplyTo(c)
+// stateLayout は状態図の状態の配置
+type stateLayout struct {
+	positions     map[string]struct{ x, y int } // 状態の中心
+	sizes         map[string]struct{ w, h int }
+	width, height int // キャンバスのサイズ
+	bottom        int // 次の行の中心のY座標
+}
 
+// layout は初期状態・通常状態・終了状態を行と列に配置する
+func (r *StateRenderer) layout(diagram *state.Diagram) *stateLayout {
 	// 状態をタイプ別に分類
 	var initialState *state.State
 	var finalStates []*state.State
@@ -90,42 +97,74 @@ func (r *StateRenderer) Render(diagram *state.Diagram, w io.Writer) error {
 	if height < 600 {
 		height = 600
 	}
-	c.SetSize(totalWidth, height)
 
 	// 状態の位置とサイズを記録
-	statePositions := make(map[string]struct{ x, y int })
-	stateSizes := make(map[string]struct{ w, h int })
+	l := &stateLayout{
+		positions: make(map[string]struct{ x, y int }),
+		sizes:     make(map[string]struct{ w, h int }),
+		width:     totalWidth,
+		height:    height,
+	}
 
-	// 初期状態を描画（テンプレート使用）
+	// 初期状態
 	if initialState != nil {
-		ix := colCenters[0]
-		iy := 50
-		statePositions[initialState.ID] = struct{ x, y int }{ix, iy}
-		stateSizes[initialState.ID] = struct{ w, h int }{20, 20}
-		c.UseTemplate("initial-state", ix-10, iy-10, 20, 20)
+		l.positions[initialState.ID] = struct{ x, y int }{colCenters[0], 50}
+		l.sizes[initialState.ID] = struct{ w, h int }{20, 20}
 	}
 
-	// 通常状態を描画
+	// 通常状態
 	startY := 120
 	for i, s := range normalStates {
 		col := i % cols
 		row := i / cols
-		sx := colCenters[col]
-		sy := startY + row*120
-		statePositions[s.ID] = struct{ x, y int }{sx, sy}
-		sw := stateWidths[s.ID]
-		sh := r.calculateStateHeight(*s)
-		stateSizes[s.ID] = struct{ w, h int }{sw, sh}
-		r.renderState(c, *s, sx, sy)
+		l.positions[s.ID] = struct{ x, y int }{colCenters[col], startY + row*120}
+		l.sizes[s.ID] = struct{ w, h int }{stateWidths[s.ID], r.calculateStateHeight(*s)}
 	}
 
-	// 終了状態を描画（テンプレート使用）
+	// 終了状態
 	finalY := startY + rows*120 + 40
 	for i, s := range finalStates {
-		fx := colCenters[0] + i*150
-		statePositions[s.ID] = struct{ x, y int }{fx, finalY}
-		stateSizes[s.ID] = struct{ w, h int }{24, 24}
-		c.UseTemplate("final-state", fx-12, finalY-12, 24, 24)
+		l.positions[s.ID] = struct{ x, y int }{colCenters[0] + i*150, finalY}
+		l.sizes[s.ID] = struct{ w, h int }{24, 24}
+	}
+
+	l.bottom = startY + rows*120
+	if len(finalStates) > 0 {
+		l.bottom = finalY + 80
+	}
+	return l
+}
+
+// render は配置済みの状態と遷移・ノートを描画する
+func (r *StateRenderer) render(diagram *state.Diagram, layout *stateLayout, w io.Writer) error {
+	c := canvas.New()
+
+	// テンプレートレジストリを適用
+	registry := canvas.NewBuiltinRegistry()
+	registry.ApplyTo(c)
+
+	c.SetSize(layout.width, layout.height)
+	statePositions := layout.positions
+	stateSizes := layout.sizes
+
+	// 状態を初期状態・通常状態・終了状態の順に描画する（初期状態・終了状態はテンプレート使用）
+	for pass := 0; pass < 3; pass++ {
+		for _, s := range diagram.States {
+			pos, ok := statePositions[s.ID]
+			if !ok || drawPass(s.Type) != pass {
+				continue
+			}
+			switch s.Type {
+			case state.StateTypeInitial:
+				c.UseTemplate("initial-state", pos.x-10, pos.y-10, 20, 20)
+				r.renderPseudoStateChange(c, s, pos.x, pos.y, 10)
+			case state.StateTypeFinal:
+				c.UseTemplate("final-state", pos.x-12, pos.y-12, 24, 24)
+				r.renderPseudoStateChange(c, s, pos.x, pos.y, 12)
+			default:
+				r.renderState(c, s, pos.x, pos.y)
+			}
+		}
 	}
 
 	// 遷移を描画（直交ルーティング）
@@ -165,6 +204,25 @@ func (r *StateRenderer) Render(diagram *state.Diagram, w io.Writer) error {
 	return err
 }
 
+// drawPass は状態を描画する順番（初期状態、通常状態、終了状態）を返す
+func drawPass(t state.StateType) int {
+	switch t {
+	case state.StateTypeInitial:
+		return 0
+	case state.StateTypeFinal:
+		return 2
+	}
+	return 1
+}
+
+// renderPseudoStateChange は差分の図で追加・削除された初期状態・終了状態を変更の色の円で囲む
+func (r *StateRenderer) renderPseudoStateChange(c *canvas.Canvas, s state.State, x, y, radius int) {
+	if s.Change == "" {
+		return
+	}
+	c.Circle(x, y, radius+4, canvas.Fill("none"), canvas.Stroke(changeColor(s.Change, "")), canvas.StrokeWidth(2))
+}
+
 // calculateStateHeight は状態ボックスの高さを計算する
 func (r *StateRenderer) calculateStateHeight(s state.State) int {
 	hasActions := len(s.Entry) > 0 || len(s.Exit) > 0
@@ -230,17 +288,17 @@ func (r *StateRenderer) renderState(c *canvas.Canvas, s state.State, x, y int) {
 		height = 60 + len(s.Entry)*15 + len(s.Exit)*15
 	}
 
-	c.RoundRect(x-width/2, y-20, width, height, 10, 10,
+	c.RoundRect(x-width/2, y-20, width, height, 10, 10, append([]canvas.Option{
 		canvas.Fill(canvas.ColorNodeFill),
 		canvas.Stroke(canvas.ColorNodeStroke),
 		canvas.StrokeWidth(2),
 		canvas.Filter("drop-shadow"),
-	)
-	c.Text(x, y+5, s.Name,
+	}, append(highlight(s.Highlighted), changeStyle(s.Change)...)...)...)
+	c.Text(x, y+5, s.Name, append([]canvas.Option{
 		canvas.TextAnchor("middle"),
 		canvas.Fill(canvas.ColorNodeText),
 		canvas.FontWeight("bold"),
-	)
+	}, changeText(s.Change)...)...)
 
 	// Entry/Exitアクションを描画
 	if hasActions {
@@ -256,3 +314,19 @@ func (r *StateRenderer) renderState(c *canvas.Canvas, s state.State, x, y int) {
 		}
 	}
 }
+
+// highlight はシミュレーションで通った状態を強調する描画オプションを返す
+func highlight(highlighted bool) []canvas.Option {
+	if !highlighted {
+		return nil
+	}
+	return []canvas.Option{canvas.Fill(canvas.ColorHighlightFill), canvas.Stroke(canvas.ColorHighlight)}
+}
+
+// edgeColor は遷移の線の色を返す（実行された遷移は強調色、差分の図では変更の色）
+func edgeColor(t state.Transition) string {
+	if t.Highlighted {
+		return canvas.ColorHighlight
+	}
+	return changeColor(t.Change, canvas.ColorEdge)
+}
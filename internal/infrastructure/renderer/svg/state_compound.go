@@ -19,19 +19,19 @@ func (r *StateRenderer) renderCompoundState(c *canvas.Canvas, s state.State, x,
 	height := 30 + rows*50 + 20 // ヘッダー + 子状態 + マージン
 
 	// 複合状態の外枠
-	c.RoundRect(x-width/2, y-20, width, height, 10, 10,
+	c.RoundRect(x-width/2, y-20, width, height, 10, 10, append([]canvas.Option{
 		canvas.Fill(canvas.ColorHeaderFill),
 		canvas.Stroke(canvas.ColorNodeStroke),
 		canvas.StrokeWidth(2),
 		canvas.Filter("drop-shadow"),
-	)
+	}, append(highlightStroke(s.Highlighted), changeStroke(s.Change)...)...)...)
 
 	// 状態名（上部）
-	c.Text(x, y, s.Name,
+	c.Text(x, y, s.Name, append([]canvas.Option{
 		canvas.TextAnchor("middle"),
 		canvas.Fill(canvas.ColorNodeText),
 		canvas.FontWeight("bold"),
-	)
+	}, changeText(s.Change)...)...)
 	c.Line(x-width/2, y+10, x+width/2, y+10, canvas.Stroke(canvas.ColorSectionLine))
 
 	// 子状態を描画
@@ -44,14 +44,14 @@ func (r *StateRenderer) renderCompoundState(c *canvas.Canvas, s state.State, x,
 		cy := childY + row*50
 
 		// 子状態を通常の状態として描画
-		c.RoundRect(cx-35, cy-15, 70, 30, 8, 8,
+		c.RoundRect(cx-35, cy-15, 70, 30, 8, 8, append([]canvas.Option{
 			canvas.Fill(canvas.ColorNodeFill),
 			canvas.Stroke(canvas.ColorNodeStroke),
-		)
-		c.Text(cx, cy+5, child.Name,
+		}, append(highlight(child.Highlighted), changeStyle(child.Change)...)...)...)
+		c.Text(cx, cy+5, child.Name, append([]canvas.Option{
 			canvas.TextAnchor("middle"),
 			canvas.Fill(canvas.ColorNodeText),
-		)
+		}, changeText(child.Change)...)...)
 	}
 }
 
@@ -67,19 +67,19 @@ func (r *StateRenderer) renderParallelState(c *canvas.Canvas, s state.State, x,
 	height := 100
 
 	// 並行状態の外枠
-	c.RoundRect(x-width/2, y-20, width, height, 10, 10,
+	c.RoundRect(x-width/2, y-20, width, height, 10, 10, append([]canvas.Option{
 		canvas.Fill(canvas.ColorHeaderFill),
 		canvas.Stroke(canvas.ColorNodeStroke),
 		canvas.StrokeWidth(2),
 		canvas.Filter("drop-shadow"),
-	)
+	}, append(highlightStroke(s.Highlighted), changeStroke(s.Change)...)...)...)
 
 	// 状態名（上部）
-	c.Text(x, y, s.Name,
+	c.Text(x, y, s.Name, append([]canvas.Option{
 		canvas.TextAnchor("middle"),
 		canvas.Fill(canvas.ColorNodeText),
 		canvas.FontWeight("bold"),
-	)
+	}, changeText(s.Change)...)...)
 	c.Line(x-width/2, y+10, x+width/2, y+10, canvas.Stroke(canvas.ColorSectionLine))
 
 	// 各リージョンを描画
@@ -100,14 +100,14 @@ func (r *StateRenderer) renderParallelState(c *canvas.Canvas, s state.State, x,
 				c.Text(rx, stateY, "...", canvas.TextAnchor("middle"), canvas.Fill(canvas.ColorEdgeLabel))
 				break
 			}
-			c.RoundRect(rx-30, stateY-10, 60, 20, 5, 5,
+			c.RoundRect(rx-30, stateY-10, 60, 20, 5, 5, append([]canvas.Option{
 				canvas.Fill(canvas.ColorNodeFill),
 				canvas.Stroke(canvas.ColorNodeStroke),
-			)
-			c.Text(rx, stateY+5, child.Name,
+			}, append(highlight(child.Highlighted), changeStyle(child.Change)...)...)...)
+			c.Text(rx, stateY+5, child.Name, append([]canvas.Option{
 				canvas.TextAnchor("middle"),
 				canvas.Fill(canvas.ColorNodeText),
-			)
+			}, changeText(child.Change)...)...)
 			stateY += 25
 		}
 
@@ -118,3 +118,11 @@ func (r *StateRenderer) renderParallelState(c *canvas.Canvas, s state.State, x,
 		}
 	}
 }
+
+// highlightStroke は通った複合状態・並行状態の枠線だけを強調する（子状態を読みやすく保つ）
+func highlightStroke(highlighted bool) []canvas.Option {
+	if !highlighted {
+		return nil
+	}
+	return []canvas.Option{canvas.Stroke(canvas.ColorHighlight)}
+}
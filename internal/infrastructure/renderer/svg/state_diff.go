@@ -0,0 +1,186 @@
+package svg
+
+import (
+	"io"
+	"strings"
+
+	"pact/internal/domain/diagram/common"
+	"pact/internal/domain/diagram/state"
+)
+
+// StateDiffRenderer は2つの版の状態図の差分を1つのSVGにレンダリングする
+type StateDiffRenderer struct {
+	renderer *StateRenderer
+}
+
+// NewStateDiffRenderer は新しいStateDiffRendererを作成する
+func NewStateDiffRenderer() *StateDiffRenderer {
+	return &StateDiffRenderer{renderer: NewStateRenderer()}
+}
+
+// Render は変更前と変更後の状態図を重ねてレンダリングする
+// 変更前からある状態は変更前の図と同じ位置に置き、追加された状態はその下の行に並べる
+func (r *StateDiffRenderer) Render(old, new *state.Diagram, w io.Writer) error {
+	merged := mergeState(old, new)
+	layout := r.renderer.layout(old)
+
+	x, added := 50, false
+	for _, s := range merged.States {
+		width, height := r.renderer.calculateStateWidth(s), r.renderer.calculateStateHeight(s)
+		switch s.Type {
+		case state.StateTypeInitial:
+			width, height = 20, 20
+		case state.StateTypeFinal:
+			width, height = 24, 24
+		}
+		if s.Change != common.ChangeAdded {
+			// entry/exit の増減に合わせてサイズだけを変える
+			layout.sizes[s.ID] = struct{ w, h int }{width, height}
+			continue
+		}
+		layout.positions[s.ID] = struct{ x, y int }{x + width/2, layout.bottom}
+		layout.sizes[s.ID] = struct{ w, h int }{width, height}
+		x += width + 40
+		added = true
+	}
+	if added {
+		layout.width = maxInt(layout.width, x+10)
+		layout.height = maxInt(layout.height, layout.bottom+120)
+	}
+
+	return r.renderer.render(merged, layout, w)
+}
+
+// mergeState は2つの版の状態図を、状態と遷移ごとの変更を付けた1つの図にまとめる
+func mergeState(old, new *state.Diagram) *state.Diagram {
+	return &state.Diagram{
+		States:      mergeStates(old.States, new.States),
+		Transitions: mergeTransitions(old.Transitions, new.Transitions),
+		Notes:       new.Notes,
+	}
+}
+
+// mergeStates は状態をIDで対応付け、子状態とリージョンも含めてまとめる
+func mergeStates(old, new []state.State) []state.State {
+	oldIDs, newIDs := stateIDs(old), stateIDs(new)
+	var merged []state.State
+	for _, m := range align(oldIDs, newIDs, oldIDs, newIDs) {
+		switch {
+		case m.new < 0:
+			merged = append(merged, markState(old[m.old], common.ChangeRemoved))
+		case m.old < 0:
+			merged = append(merged, markState(new[m.new], common.ChangeAdded))
+		default:
+			merged = append(merged, mergeStateNode(old[m.old], new[m.new]))
+		}
+	}
+	return merged
+}
+
+func stateIDs(states []state.State) []string {
+	ids := make([]string, len(states))
+	for i, s := range states {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+// markState は追加・削除された状態とその子状態に変更を付ける
+func markState(s state.State, change common.Change) state.State {
+	s.Change = change
+	s.Children = markStates(s.Children, change)
+	regions := s.Regions
+	s.Regions = nil
+	for _, region := range regions {
+		region.States = markStates(region.States, change)
+		s.Regions = append(s.Regions, region)
+	}
+	return s
+}
+
+func markStates(states []state.State, change common.Change) []state.State {
+	var marked []state.State
+	for _, s := range states {
+		marked = append(marked, markState(s, change))
+	}
+	return marked
+}
+
+// mergeStateNode は両方の版にある状態をまとめる（名前・種類・entry/exit・子状態の変更は変更とする）
+func mergeStateNode(old, new state.State) state.State {
+	s := new
+	changed := old.Name != new.Name || old.Type != new.Type ||
+		strings.Join(old.Entry, "\x00") != strings.Join(new.Entry, "\x00") ||
+		strings.Join(old.Exit, "\x00") != strings.Join(new.Exit, "\x00")
+
+	s.Children = mergeStates(old.Children, new.Children)
+	for _, child := range s.Children {
+		changed = changed || child.Change != ""
+	}
+
+	oldRegions := make(map[string]state.Region)
+	for _, region := range old.Regions {
+		oldRegions[region.Name] = region
+	}
+	s.Regions = nil
+	for _, region := range new.Regions {
+		o, ok := oldRegions[region.Name]
+		if !ok {
+			changed = true
+			s.Regions = append(s.Regions, region)
+			continue
+		}
+		region.States = mergeStates(o.States, region.States)
+		region.Transitions = mergeTransitions(o.Transitions, region.Transitions)
+		for _, child := range region.States {
+			changed = changed || child.Change != ""
+		}
+		for _, t := range region.Transitions {
+			changed = changed || t.Change != ""
+		}
+		s.Regions = append(s.Regions, region)
+	}
+	changed = changed || len(old.Regions) != len(new.Regions)
+
+	if changed {
+		s.Change = common.ChangeChanged
+	}
+	return s
+}
+
+// mergeTransitions は遷移を両端・トリガー・ガードで対応付け、アクションの変更を変更とする
+func mergeTransitions(old, new []state.Transition) []state.Transition {
+	oldKeys, oldLoose := transitionKeys(old)
+	newKeys, newLoose := transitionKeys(new)
+	var merged []state.Transition
+	for _, m := range align(oldKeys, newKeys, oldLoose, newLoose) {
+		var t state.Transition
+		switch {
+		case m.new < 0:
+			t = old[m.old]
+			t.Change = common.ChangeRemoved
+		case m.old < 0:
+			t = new[m.new]
+			t.Change = common.ChangeAdded
+		default:
+			t = new[m.new]
+			if oldKeys[m.old] != newKeys[m.new] {
+				t.Change = common.ChangeChanged
+			}
+		}
+		merged = append(merged, t)
+	}
+	return merged
+}
+
+// transitionKeys は遷移のラベルを含むキーと、アクションを除いたキーを返す
+func transitionKeys(transitions []state.Transition) (keys, loose []string) {
+	r := &StateRenderer{}
+	for _, t := range transitions {
+		key := t.From + "\x00" + t.To + "\x00"
+		keys = append(keys, key+r.buildTransitionLabel(t))
+		t.Actions = nil
+		loose = append(loose, key+r.buildTransitionLabel(t))
+	}
+	return keys, loose
+}
@@ -6,10 +6,11 @@ import (
 	"testing"
 
 	"pact/internal/domain/diagram/state"
+	"pact/internal/infrastructure/renderer/canvas"
 )
 
 // =============================================================================
-// RST001-RST014: StateRenderer Tests
+// RST001-RST016: StateRenderer Tests
 // =============================================================================
 
 // RST001: 空図
@@ -112,3 +113,60 @@ func TestStateRenderer_StateWithName(t *testing.T) {
 		t.Error("expected state name in output")
 	}
 }
+
+// RST015: シミュレーションで通った状態と遷移の強調表示
+func TestStateRenderer_Highlighted(t *testing.T) {
+	diagram := &state.Diagram{
+		States: []state.State{
+			{ID: "Pending", Name: "Pending", Type: state.StateTypeAtomic, Highlighted: true},
+			{ID: "Shipped", Name: "Shipped", Type: state.StateTypeAtomic},
+		},
+		Transitions: []state.Transition{
+			{From: "Pending", To: "Shipped", Trigger: &state.EventTrigger{Event: "ship"}, Highlighted: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewStateRenderer().Render(diagram, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svg := buf.String()
+	if !strings.Contains(svg, `fill="`+canvas.ColorHighlightFill+`"`) {
+		t.Error("expected highlighted state fill")
+	}
+	if strings.Count(svg, `stroke="`+canvas.ColorHighlight+`"`) < 2 {
+		t.Error("expected highlighted state border and transition line")
+	}
+	if !strings.Contains(svg, `fill="`+canvas.ColorHighlight+`"`) {
+		t.Error("expected highlighted arrow head and label")
+	}
+}
+
+// RST016: 状態図は初期状態・通常状態・終了状態の順に描画する（宣言順によらない）
+func TestStateRenderer_DrawOrder(t *testing.T) {
+	diagram := &state.Diagram{
+		States: []state.State{
+			{ID: "__final__", Type: state.StateTypeFinal},
+			{ID: "Pending", Name: "Pending", Type: state.StateTypeAtomic},
+			{ID: "__initial__", Type: state.StateTypeInitial},
+			{ID: "Paid", Name: "Paid", Type: state.StateTypeAtomic},
+		},
+		Transitions: []state.Transition{
+			{From: "__initial__", To: "Pending"},
+			{From: "Pending", To: "Paid", Trigger: &state.EventTrigger{Event: "pay"}},
+			{From: "Paid", To: "__final__"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewStateRenderer().Render(diagram, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertDrawOrder(t, buf.String(),
+		"initial-state 90,40",
+		"rect 60,100",
+		"rect 200,100",
+		"final-state 88,268",
+	)
+}
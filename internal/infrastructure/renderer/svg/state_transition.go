@@ -21,6 +21,7 @@ func (r *StateRenderer) renderOrthogonalTransition(c *canvas.Canvas, t state.Tra
 
 	// 始点・終点ノードを除いた障害物リスト
 	obstacles := r.filterObstacles(nodeBounds, x1, y1, w1, h1, x2, y2, w2, h2)
+	color := edgeColor(t)
 
 	if dx == 0 && dy == 0 {
 		// 自己遷移
@@ -28,11 +29,11 @@ func (r *StateRenderer) renderOrthogonalTransition(c *canvas.Canvas, t state.Tra
 		startY = y1
 		endX = x1
 		endY = y1 - h1/2 - 10
-		c.Line(startX, startY, startX+30, startY, canvas.Stroke(canvas.ColorEdge))
-		c.Line(startX+30, startY, startX+30, startY-30, canvas.Stroke(canvas.ColorEdge))
-		c.Line(startX+30, startY-30, endX, startY-30, canvas.Stroke(canvas.ColorEdge))
-		c.Line(endX, startY-30, endX, endY, canvas.Stroke(canvas.ColorEdge))
-		c.DrawArrowHead(endX, endY, endX, startY-30)
+		c.Line(startX, startY, startX+30, startY, canvas.Stroke(color))
+		c.Line(startX+30, startY, startX+30, startY-30, canvas.Stroke(color))
+		c.Line(startX+30, startY-30, endX, startY-30, canvas.Stroke(color))
+		c.Line(endX, startY-30, endX, endY, canvas.Stroke(color))
+		c.DrawArrowHead(endX, endY, endX, startY-30, arrowFill(t)...)
 		midX = startX + 15
 		midY = startY - 15
 	} else if abs(dx) > abs(dy) {
@@ -51,7 +52,7 @@ func (r *StateRenderer) renderOrthogonalTransition(c *canvas.Canvas, t state.Tra
 
 		// ウェイポイントを計算（障害物回避付き）
 		waypoints := r.calculateStateWaypoints(startX, startY, endX, endY, obstacles)
-		midX, midY = r.drawWaypointsAndGetMid(c, waypoints)
+		midX, midY = r.drawWaypointsAndGetMid(c, waypoints, t)
 	} else {
 		// 主に垂直方向
 		if dy > 0 {
@@ -68,17 +69,21 @@ func (r *StateRenderer) renderOrthogonalTransition(c *canvas.Canvas, t state.Tra
 
 		// ウェイポイントを計算（障害物回避付き）
 		waypoints := r.calculateStateWaypoints(startX, startY, endX, endY, obstacles)
-		midX, midY = r.drawWaypointsAndGetMid(c, waypoints)
+		midX, midY = r.drawWaypointsAndGetMid(c, waypoints, t)
 	}
 
 	// ラベルを構築
 	label := r.buildTransitionLabel(t)
 	if label != "" {
 		labelX, labelY := r.findSafeLabelPosition(midX, midY-5-labelOffset, label, nodeBounds)
-		c.Text(labelX, labelY, label,
+		labelColor := changeColor(t.Change, canvas.ColorEdgeLabel)
+		if t.Highlighted {
+			labelColor = canvas.ColorHighlight
+		}
+		c.Text(labelX, labelY, label, append([]canvas.Option{
 			canvas.TextAnchor("middle"),
-			canvas.Fill(canvas.ColorEdgeLabel),
-		)
+			canvas.Fill(labelColor),
+		}, strikethrough(t.Change)...)...)
 	}
 }
 
@@ -198,20 +203,21 @@ func (r *StateRenderer) pathIntersectsObstacles(x1, y1, x2, y2 int, obstacles []
 }
 
 // drawWaypointsAndGetMid はウェイポイントを描画し、中間点を返す
-func (r *StateRenderer) drawWaypointsAndGetMid(c *canvas.Canvas, waypoints []struct{ x, y int }) (int, int) {
+func (r *StateRenderer) drawWaypointsAndGetMid(c *canvas.Canvas, waypoints []struct{ x, y int }, t state.Transition) (int, int) {
+	color := edgeColor(t)
 	if len(waypoints) < 2 {
 		return 0, 0
 	}
 
 	// パスを描画
 	for i := 0; i < len(waypoints)-1; i++ {
-		c.Line(waypoints[i].x, waypoints[i].y, waypoints[i+1].x, waypoints[i+1].y, canvas.Stroke(canvas.ColorEdge))
+		c.Line(waypoints[i].x, waypoints[i].y, waypoints[i+1].x, waypoints[i+1].y, canvas.Stroke(color))
 	}
 
 	// 矢印を描画
 	last := waypoints[len(waypoints)-1]
 	prev := waypoints[len(waypoints)-2]
-	c.DrawArrowHead(last.x, last.y, prev.x, prev.y)
+	c.DrawArrowHead(last.x, last.y, prev.x, prev.y, arrowFill(t)...)
 
 	// 中間点を計算（パスの中央セグメント上）
 	midIdx := len(waypoints) / 2
@@ -312,3 +318,11 @@ func (r *StateRenderer) buildTransitionLabel(t state.Transition) string {
 	}
 	return label
 }
+
+// arrowFill は実行された遷移の矢印を強調色で、差分の図で変更された遷移の矢印を変更の色で塗るオプションを返す
+func arrowFill(t state.Transition) []canvas.Option {
+	if !t.Highlighted && t.Change == "" {
+		return nil
+	}
+	return []canvas.Option{canvas.Fill(edgeColor(t))}
+}
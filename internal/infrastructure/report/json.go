@@ -0,0 +1,33 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"pact/internal/domain/errors"
+)
+
+// jsonReport は JSON 出力の構造
+type jsonReport struct {
+	Diagnostics []errors.Diagnostic    `json:"diagnostics"`
+	Errors      int                    `json:"errors"`
+	Warnings    int                    `json:"warnings"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+}
+
+// WriteJSON は診断を JSON で出力する
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := jsonReport{
+		Diagnostics: r.Diagnostics,
+		Errors:      r.Count(errors.SeverityError),
+		Warnings:    r.Count(errors.SeverityWarning),
+		Properties:  r.Properties,
+	}
+	if out.Diagnostics == nil {
+		out.Diagnostics = []errors.Diagnostic{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
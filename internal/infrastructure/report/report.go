@@ -0,0 +1,118 @@
+// Package report writes diagnostics as text, JSON or SARIF.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"pact/internal/domain/errors"
+)
+
+// Format は診断の出力形式
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatSARIF Format = "sarif"
+)
+
+// ParseFormat は文字列を出力形式に変換する
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON, FormatSARIF:
+		return f, nil
+	}
+	return "", &ReportError{Format: s, Message: "unsupported format (expected text, json or sarif)"}
+}
+
+// Report は出力する診断結果の集まりを表す
+type Report struct {
+	Tool        string
+	Version     string
+	Diagnostics []errors.Diagnostic
+	// Properties はコマンド固有の集計値（カバレッジなど）
+	Properties map[string]interface{}
+}
+
+// Add はエラーを file に紐づく診断として追加する
+func (r *Report) Add(file string, err error) {
+	r.Diagnostics = append(r.Diagnostics, errors.NewDiagnostic(file, err))
+}
+
+// Count は重大度ごとの件数を返す
+func (r *Report) Count(severity errors.Severity) int {
+	n := 0
+	for _, d := range r.Diagnostics {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Sort は診断をファイル・位置の順に並べる（同一ファイル内の出力順は安定）
+func (r *Report) Sort() {
+	sort.SliceStable(r.Diagnostics, func(i, j int) bool {
+		a, b := r.Diagnostics[i], r.Diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// Write は指定形式で診断を出力する
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatSARIF:
+		return r.WriteSARIF(w)
+	case FormatText, "":
+		return r.WriteText(w)
+	}
+	return &ReportError{Format: string(format), Message: "unsupported format"}
+}
+
+// WriteText はエラーと警告を分けて人間向けに出力する
+func (r *Report) WriteText(w io.Writer) error {
+	for _, section := range []struct {
+		title    string
+		severity errors.Severity
+	}{
+		{"Errors", errors.SeverityError},
+		{"Warnings", errors.SeverityWarning},
+	} {
+		if r.Count(section.severity) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "\n%s:\n", section.title); err != nil {
+			return err
+		}
+		for _, d := range r.Diagnostics {
+			if d.Severity != section.severity {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "  %s\n", d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReportError は診断出力のエラー
+type ReportError struct {
+	Format  string
+	Message string
+}
+
+func (e *ReportError) Error() string {
+	return "report " + e.Format + ": " + e.Message
+}
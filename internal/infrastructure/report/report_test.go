@@ -0,0 +1,143 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+)
+
+func sampleReport() *Report {
+	r := &Report{Tool: "pact", Version: "1.0.0"}
+	r.Add("specs/b.pact", &errors.Warning{Pos: ast.Position{Line: 2, Column: 1}, Code: "unused-type", Message: "type 'T' is defined but not referenced"})
+	r.Add("specs/a.pact", &errors.ValidationError{Pos: ast.Position{Line: 5, Column: 3}, Type: "undefined", Name: "Missing", Message: "referenced component/type is not defined"})
+	r.Add("specs/a.pact", &errors.ParseError{Pos: ast.Position{Line: 1, Column: 11}, Message: "unexpected token"})
+	r.Add("", &errors.CycleError{Cycle: []string{"a.pact"}})
+	return r
+}
+
+// =============================================================================
+// RP001-RP004: Report
+// =============================================================================
+
+// RP001: 出力形式の解析
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"text", "JSON", "sarif"} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q) failed: %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+// RP002: テキスト出力はエラーと警告を分ける
+func TestReport_WriteText(t *testing.T) {
+	r := sampleReport()
+	r.Sort()
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	errIdx := strings.Index(out, "Errors:")
+	warnIdx := strings.Index(out, "Warnings:")
+	if errIdx < 0 || warnIdx < errIdx {
+		t.Fatalf("expected errors before warnings, got:\n%s", out)
+	}
+	if !strings.Contains(out, "specs/a.pact:1:11: error[syntax-error]: unexpected token") {
+		t.Errorf("unexpected text output:\n%s", out)
+	}
+}
+
+// RP003: JSON 出力
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Diagnostics []errors.Diagnostic `json:"diagnostics"`
+		Errors      int                 `json:"errors"`
+		Warnings    int                 `json:"warnings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if out.Errors != 3 || out.Warnings != 1 || len(out.Diagnostics) != 4 {
+		t.Errorf("unexpected counts: %+v", out)
+	}
+	if d := out.Diagnostics[1]; d.File != "specs/a.pact" || d.Line != 5 || d.Column != 3 || d.Code != "undefined" {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
+
+// RP004: SARIF 出力の必須プロパティ
+func TestReport_WriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().WriteSARIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var log map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log["version"] != "2.1.0" || log["$schema"] == nil {
+		t.Fatalf("missing version or schema: %v", log)
+	}
+
+	run := log["runs"].([]interface{})[0].(map[string]interface{})
+	driver := run["tool"].(map[string]interface{})["driver"].(map[string]interface{})
+	if driver["name"] != "pact" {
+		t.Errorf("unexpected driver name %v", driver["name"])
+	}
+	rules := driver["rules"].([]interface{})
+	results := run["results"].([]interface{})
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+
+	for _, raw := range results {
+		res := raw.(map[string]interface{})
+		idx := int(res["ruleIndex"].(float64))
+		if rules[idx].(map[string]interface{})["id"] != res["ruleId"] {
+			t.Errorf("ruleIndex %d does not match ruleId %v", idx, res["ruleId"])
+		}
+		if level := res["level"]; level != "error" && level != "warning" {
+			t.Errorf("invalid level %v", level)
+		}
+		if res["message"].(map[string]interface{})["text"] == "" {
+			t.Error("message text must not be empty")
+		}
+		locs, ok := res["locations"].([]interface{})
+		if !ok {
+			continue
+		}
+		phys := locs[0].(map[string]interface{})["physicalLocation"].(map[string]interface{})
+		if region, ok := phys["region"].(map[string]interface{}); ok && region["startLine"].(float64) < 1 {
+			t.Errorf("startLine must be >= 1: %v", region)
+		}
+	}
+
+	// 位置のない診断は locations を持たない
+	if _, ok := results[3].(map[string]interface{})["locations"]; ok {
+		t.Error("expected no locations for cycle error without file")
+	}
+}
+
+// RP005: 絶対パスは file URI になる
+func TestSarifURI(t *testing.T) {
+	if got := sarifURI("specs/a b.pact"); got != "specs/a%20b.pact" {
+		t.Errorf("unexpected relative URI %q", got)
+	}
+	if got := sarifURI("/tmp/a.pact"); got != "file:///tmp/a.pact" {
+		t.Errorf("unexpected absolute URI %q", got)
+	}
+}
@@ -0,0 +1,189 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"pact/internal/domain/errors"
+)
+
+// SARIF 2.1.0 のスキーマとバージョン
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// ruleDescriptions はルールコードの説明
+var ruleDescriptions = map[string]string{
+	errors.CodeSyntax:               "The file could not be parsed.",
+	errors.CodeSemantic:             "The specification is semantically invalid.",
+	errors.CodeImport:               "An import could not be resolved.",
+	errors.CodeImportCycle:          "Imports form a cycle.",
+	errors.CodeConfig:               "The project configuration is invalid.",
+	errors.CodeEmptyDeclaration:     "A declaration has no members.",
+	errors.CodeMissingComponent:     "A dependency target is not declared by any component.",
+	errors.CodeDependencyCycle:      "Components depend on each other in a cycle.",
+	errors.CodeDependencyRule:       "A relation violates a dependency rule in the project configuration.",
+	errors.CodeMissingSpec:          "A source file has no corresponding spec.",
+	errors.CodeOrphanedSpec:         "A spec has no corresponding source file.",
+	errors.CodeCoverage:             "Spec coverage is below the configured threshold.",
+	errors.CodeUnreachableState:     "A state cannot be reached from the initial state.",
+	errors.CodeDeadlockState:        "A non-final state has no outgoing transitions.",
+	errors.CodeFinalTransition:      "A final state has outgoing transitions.",
+	errors.CodeNondeterministic:     "Transitions on the same event have missing or overlapping guards.",
+	errors.CodeMissingInitial:       "A compound state or region has no initial state.",
+	errors.CodeParallelIncompletion: "A region of a parallel state can never complete.",
+	errors.CodeUnassignedVariable:   "A flow variable may be read before it is assigned.",
+	errors.CodeUnusedAssignment:     "A value assigned in a flow is never read.",
+	errors.CodeShadowedVariable:     "A loop variable shadows a parameter or an outer variable.",
+	errors.CodeUnknownMethod:        "A flow calls a method that the dependency does not provide.",
+	errors.CodeArgumentCount:        "A flow calls a method with the wrong number of arguments.",
+	errors.CodeUnknownField:         "A flow accesses a field that the type does not declare.",
+	errors.CodeUndeclaredThrow:      "A flow can throw an error that its method does not declare.",
+	errors.CodeUnraisedThrow:        "A method declares an error that its flow never throws.",
+	errors.CodeTypeMismatch:         "A value contradicts the declared type.",
+	"duplicate":                     "A name is declared more than once.",
+	"undefined":                     "A referenced component or type is not defined.",
+	"invalid":                       "A declaration has an invalid value.",
+	"deadcode":                      "A statement can never be reached.",
+	"unused-import":                 "An import is never referenced.",
+	"unused-type":                   "A type is defined but never referenced.",
+	"deprecated":                    "A deprecated declaration is referenced.",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool              `json:"tool"`
+	Results    []sarifResult          `json:"results"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// WriteSARIF は診断を SARIF 2.1.0 形式で出力する
+func (r *Report) WriteSARIF(w io.Writer) error {
+	tool := r.Tool
+	if tool == "" {
+		tool = "pact"
+	}
+
+	run := sarifRun{
+		Tool:       sarifTool{Driver: sarifDriver{Name: tool, Version: r.Version, Rules: []sarifRule{}}},
+		Results:    []sarifResult{},
+		Properties: r.Properties,
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, d := range r.Diagnostics {
+		idx, ok := ruleIndex[d.Code]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			ruleIndex[d.Code] = idx
+			desc := ruleDescriptions[d.Code]
+			if desc == "" {
+				desc = d.Code
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               d.Code,
+				ShortDescription: sarifMessage{Text: desc},
+			})
+		}
+
+		result := sarifResult{
+			RuleID:    d.Code,
+			RuleIndex: idx,
+			Level:     sarifLevel(d.Severity),
+			Message:   sarifMessage{Text: d.Message},
+		}
+		if d.File != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: sarifURI(d.File)},
+			}}
+			// SARIF の行・列は 1 始まりのため、位置不明なら region を省略する
+			if d.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: d.Line}
+				if d.Column > 0 {
+					loc.PhysicalLocation.Region.StartColumn = d.Column
+				}
+			}
+			result.Locations = []sarifLocation{loc}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// sarifLevel は重大度を SARIF の level に変換する
+func sarifLevel(s errors.Severity) string {
+	if s == errors.SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// sarifURI はファイルパスを SARIF の URI 参照に変換する
+// 相対パスはスラッシュ区切りの相対参照、絶対パスは file URI にする
+func sarifURI(path string) string {
+	slashed := filepath.ToSlash(path)
+	if filepath.IsAbs(path) {
+		if !strings.HasPrefix(slashed, "/") {
+			slashed = "/" + slashed
+		}
+		return (&url.URL{Scheme: "file", Path: slashed}).String()
+	}
+	return (&url.URL{Path: slashed}).String()
+}
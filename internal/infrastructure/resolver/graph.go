@@ -0,0 +1,95 @@
+package resolver
+
+import (
+	"path/filepath"
+	"sort"
+
+	"pact/internal/domain/ast"
+)
+
+// ImportGraph はファイル間のインポート関係を保持し、逆引きを提供する
+// パスはすべて filepath.Clean 済みの絶対パスで扱う
+type ImportGraph struct {
+	imports   map[string][]string
+	importers map[string]map[string]bool
+}
+
+// NewImportGraph は新しいImportGraphを作成する
+func NewImportGraph() *ImportGraph {
+	return &ImportGraph{
+		imports:   make(map[string][]string),
+		importers: make(map[string]map[string]bool),
+	}
+}
+
+// Update はファイルのインポート関係を spec の内容で置き換える
+func (g *ImportGraph) Update(path string, spec *ast.SpecFile) {
+	path = absPath(path)
+	g.Remove(path)
+
+	if spec == nil {
+		return
+	}
+
+	dir := filepath.Dir(path)
+	var targets []string
+	for _, imp := range spec.Imports {
+		target := absPath(normalizePath(dir, imp.Path))
+		targets = append(targets, target)
+		if g.importers[target] == nil {
+			g.importers[target] = make(map[string]bool)
+		}
+		g.importers[target][path] = true
+	}
+	g.imports[path] = targets
+}
+
+// Remove はファイルのインポート関係を削除する
+func (g *ImportGraph) Remove(path string) {
+	path = absPath(path)
+	for _, target := range g.imports[path] {
+		delete(g.importers[target], path)
+		if len(g.importers[target]) == 0 {
+			delete(g.importers, target)
+		}
+	}
+	delete(g.imports, path)
+}
+
+// Imports はファイルが直接インポートしているファイルを返す
+func (g *ImportGraph) Imports(path string) []string {
+	return g.imports[absPath(path)]
+}
+
+// Dependents はファイルを直接・間接的にインポートしているファイルをパス順で返す
+func (g *ImportGraph) Dependents(path string) []string {
+	start := absPath(path)
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	var result []string
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for importer := range g.importers[cur] {
+			if visited[importer] {
+				continue
+			}
+			visited[importer] = true
+			result = append(result, importer)
+			queue = append(queue, importer)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// absPath はパスを絶対パスに正規化する（失敗時は Clean のみ）
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
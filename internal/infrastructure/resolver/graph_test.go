@@ -0,0 +1,87 @@
+package resolver
+
+import (
+	"path/filepath"
+	"testing"
+
+	"pact/internal/domain/ast"
+)
+
+// =============================================================================
+// IG001-IG003: ImportGraph
+// =============================================================================
+
+func specWithImports(paths ...string) *ast.SpecFile {
+	spec := &ast.SpecFile{}
+	for _, p := range paths {
+		spec.Imports = append(spec.Imports, ast.ImportDecl{Path: p})
+	}
+	return spec
+}
+
+// IG001: 推移的なインポート元の取得
+func TestImportGraph_Dependents(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.pact")
+	b := filepath.Join(dir, "b.pact")
+	c := filepath.Join(dir, "sub", "c.pact")
+
+	g := NewImportGraph()
+	g.Update(a, specWithImports("./b.pact"))
+	g.Update(b, specWithImports("./sub/c.pact"))
+	g.Update(c, specWithImports())
+
+	deps := g.Dependents(c)
+	if len(deps) != 2 {
+		t.Fatalf("expected 2 dependents, got %v", deps)
+	}
+	if deps[0] != a || deps[1] != b {
+		t.Errorf("expected [%s %s], got %v", a, b, deps)
+	}
+
+	if len(g.Dependents(a)) != 0 {
+		t.Errorf("expected no dependents for a.pact, got %v", g.Dependents(a))
+	}
+}
+
+// IG002: 更新で古い関係が置き換わる
+func TestImportGraph_Update(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.pact")
+	b := filepath.Join(dir, "b.pact")
+
+	g := NewImportGraph()
+	g.Update(a, specWithImports("./b.pact"))
+	if len(g.Dependents(b)) != 1 {
+		t.Fatalf("expected 1 dependent, got %v", g.Dependents(b))
+	}
+
+	g.Update(a, specWithImports())
+	if len(g.Dependents(b)) != 0 {
+		t.Errorf("expected no dependents after update, got %v", g.Dependents(b))
+	}
+	if len(g.Imports(a)) != 0 {
+		t.Errorf("expected no imports, got %v", g.Imports(a))
+	}
+}
+
+// IG003: 循環インポートでも停止する
+func TestImportGraph_Cycle(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.pact")
+	b := filepath.Join(dir, "b.pact")
+
+	g := NewImportGraph()
+	g.Update(a, specWithImports("./b.pact"))
+	g.Update(b, specWithImports("./a.pact"))
+
+	deps := g.Dependents(a)
+	if len(deps) != 1 || deps[0] != b {
+		t.Errorf("expected [%s], got %v", b, deps)
+	}
+
+	g.Remove(b)
+	if len(g.Dependents(a)) != 0 {
+		t.Errorf("expected no dependents after remove, got %v", g.Dependents(a))
+	}
+}
@@ -178,43 +178,42 @@ func (r *Resolver) resolveFromMap(
 }
 
 // ResolveFile は単一ファイルのインポートを解決する
+// 結果は依存順で、最後が file 自身になる
 func (r *Resolver) ResolveFile(file *ast.SpecFile, basePath string) ([]*ast.SpecFile, error) {
 	visited := make(map[string]bool)
 	inProgress := make(map[string]bool)
 	var result []*ast.SpecFile
 
-	if err := r.resolveRecursive(file, basePath, visited, inProgress, &result); err != nil {
+	if err := r.resolveRecursive(file, normalizePath(basePath, file.Path), visited, inProgress, &result); err != nil {
 		return nil, err
 	}
 
 	return result, nil
 }
 
+// resolveRecursive は正規化済みのパス path を持つファイルのインポートを再帰的に解決する
 func (r *Resolver) resolveRecursive(
 	file *ast.SpecFile,
-	basePath string,
+	path string,
 	visited map[string]bool,
 	inProgress map[string]bool,
 	result *[]*ast.SpecFile,
 ) error {
-	// 正規化されたパスを使用
-	normalPath := normalizePath(basePath, file.Path)
-
 	// サイクル検出
-	if inProgress[normalPath] {
-		return &errors.CycleError{Cycle: []string{normalPath}}
+	if inProgress[path] {
+		return &errors.CycleError{Cycle: []string{path}}
 	}
 
 	// 既に処理済み
-	if visited[normalPath] {
+	if visited[path] {
 		return nil
 	}
 
-	inProgress[normalPath] = true
-	defer func() { delete(inProgress, normalPath) }()
+	inProgress[path] = true
+	defer func() { delete(inProgress, path) }()
 
 	// インポートを処理
-	fileDir := filepath.Dir(normalPath)
+	fileDir := filepath.Dir(path)
 	for _, imp := range file.Imports {
 		// インポートパスを解決
 		impPath := normalizePath(fileDir, imp.Path)
@@ -228,20 +227,23 @@ func (r *Resolver) resolveRecursive(
 				Cause:   err,
 			}
 		}
+		if impFile.Path == "" {
+			impFile.Path = impPath
+		}
 
-		if err := r.resolveRecursive(impFile, filepath.Dir(impPath), visited, inProgress, result); err != nil {
+		if err := r.resolveRecursive(impFile, impPath, visited, inProgress, result); err != nil {
 			return err
 		}
 	}
 
-	visited[normalPath] = true
+	visited[path] = true
 	*result = append(*result, file)
 	return nil
 }
 
 // normalizePath はパスを正規化する
 func normalizePath(base, path string) string {
-	if base == "." || base == "" {
+	if base == "." || base == "" || filepath.IsAbs(path) {
 		return filepath.Clean(path)
 	}
 	return filepath.Clean(filepath.Join(base, path))
@@ -337,3 +337,42 @@ func TestResolver_ImportParseError(t *testing.T) {
 		t.Error("expected Cause to be set")
 	}
 }
+
+// IR011: サブディレクトリからの相対インポート
+func TestResolver_NestedRelativePath(t *testing.T) {
+	fileA := &ast.SpecFile{
+		Path:    "a.pact",
+		Imports: []ast.ImportDecl{{Path: "./sub/b.pact"}},
+	}
+	fileB := &ast.SpecFile{Imports: []ast.ImportDecl{{Path: "./c.pact"}, {Path: "../d.pact"}}}
+	fileC := &ast.SpecFile{}
+	fileD := &ast.SpecFile{}
+
+	parser := &MockParser{
+		files: map[string]*ast.SpecFile{
+			"sub/b.pact": fileB,
+			"sub/c.pact": fileC,
+			"d.pact":     fileD,
+		},
+	}
+	resolver := NewResolver(parser)
+
+	result, err := resolver.ResolveFile(fileA, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var paths []string
+	for _, f := range result {
+		paths = append(paths, f.Path)
+	}
+	expected := []string{"sub/c.pact", "d.pact", "sub/b.pact", "a.pact"}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("result[%d] = %q, expected %q", i, paths[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,501 @@
+package scaffold
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"pact/internal/domain/ast"
+	"pact/internal/infrastructure/project"
+)
+
+// FromGo は source_root 以下の Go ソースファイルごとに仕様ファイルを生成する
+//
+//	struct                 → type（フィールドの可視性は Go の公開規則に従う）
+//	string の名前付き型と定数 → enum
+//	interface              → provides
+//	コンポーネントを持つフィールド → depends on（interface）/ contains（struct）
+//	New* コンストラクタの引数     → depends on
+//
+// interface か、New<型名> のコンストラクタを持つ struct をコンポーネントとみなす
+func FromGo(p *project.Project) ([]*File, error) {
+	loader, sources, err := newGoLoader(p)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &goGenerator{project: p, loader: loader, specs: make(map[types.Object]*goast.TypeSpec)}
+	var files []*File
+	for _, src := range sources {
+		f, err := g.generate(src)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+type goGenerator struct {
+	project *project.Project
+	loader  *goLoader
+	specs   map[types.Object]*goast.TypeSpec // 型の宣言（埋め込み interface の展開用）
+}
+
+// fileGenerator は一つのソースファイルから仕様を組み立てる
+type fileGenerator struct {
+	*goGenerator
+	source   string
+	pactPath string
+	info     *types.Info
+	imports  map[string]bool
+}
+
+func (g *goGenerator) generate(src string) (*File, error) {
+	pactPath, err := g.project.PactPathFor(src)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(g.project.Root, src)
+	if err != nil {
+		rel = src
+	}
+
+	comp := ast.ComponentDecl{Name: Identifier(project.ComponentName(src))}
+	spec := &ast.SpecFile{Path: pactPath}
+	file := &File{Source: rel, Path: pactPath, Spec: spec}
+
+	goFile, pkg := g.loader.file(src)
+	if goFile == nil || pkg.Info == nil {
+		spec.Components = []ast.ComponentDecl{comp}
+		return file, nil
+	}
+	g.index(pkg)
+
+	fg := &fileGenerator{
+		goGenerator: g,
+		source:      absPath(src),
+		pactPath:    pactPath,
+		info:        pkg.Info,
+		imports:     make(map[string]bool),
+	}
+	relations := make(map[string]bool)
+	addRelation := func(kind ast.RelationKind, target string) {
+		if relations[target] {
+			return
+		}
+		relations[target] = true
+		comp.Body.Relations = append(comp.Body.Relations, ast.RelationDecl{Kind: kind, Target: target})
+	}
+
+	for _, decl := range goFile.Decls {
+		switch decl := decl.(type) {
+		case *goast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range decl.Specs {
+				fg.typeSpec(s.(*goast.TypeSpec), pkg, &comp, addRelation)
+			}
+		case *goast.FuncDecl:
+			if decl.Recv != nil || !strings.HasPrefix(decl.Name.Name, "New") {
+				continue
+			}
+			// コンストラクタで受け取るコンポーネントは依存先
+			for _, param := range decl.Type.Params.List {
+				if target, _, ok := fg.componentRef(param.Type); ok {
+					addRelation(ast.RelationDependsOn, target)
+				}
+			}
+		}
+	}
+
+	for path := range fg.imports {
+		spec.Imports = append(spec.Imports, ast.ImportDecl{Path: path})
+	}
+	sort.Slice(spec.Imports, func(i, j int) bool { return spec.Imports[i].Path < spec.Imports[j].Path })
+	spec.Components = []ast.ComponentDecl{comp}
+	return file, nil
+}
+
+// index はパッケージの型宣言を記録する
+func (g *goGenerator) index(pkg *goPackage) {
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*goast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gen.Specs {
+				ts := s.(*goast.TypeSpec)
+				if obj := pkg.Info.Defs[ts.Name]; obj != nil {
+					g.specs[obj] = ts
+				}
+			}
+		}
+	}
+}
+
+func (fg *fileGenerator) typeSpec(ts *goast.TypeSpec, pkg *goPackage, comp *ast.ComponentDecl, addRelation func(ast.RelationKind, string)) {
+	name := Identifier(ts.Name.Name)
+	obj, _ := fg.info.Defs[ts.Name].(*types.TypeName)
+
+	if ts.Assign.IsValid() {
+		base := fg.typeExpr(ts.Type)
+		comp.Body.Types = append(comp.Body.Types, ast.TypeDecl{Name: name, Kind: ast.TypeKindAlias, BaseType: &base})
+		return
+	}
+
+	switch t := ts.Type.(type) {
+	case *goast.StructType:
+		typ := ast.TypeDecl{Name: name, Kind: ast.TypeKindStruct}
+		for _, field := range t.Fields.List {
+			if target, kind, ok := fg.componentRef(field.Type); ok {
+				addRelation(kind, target)
+				continue
+			}
+			names := fieldNames(field)
+			for _, n := range names {
+				visibility := ast.VisibilityPrivate
+				if token.IsExported(n) {
+					visibility = ast.VisibilityPublic
+				}
+				typ.Fields = append(typ.Fields, ast.FieldDecl{
+					Name:       Identifier(n),
+					Type:       fg.typeExpr(field.Type),
+					Visibility: visibility,
+				})
+			}
+		}
+		comp.Body.Types = append(comp.Body.Types, typ)
+
+	case *goast.InterfaceType:
+		iface := ast.InterfaceDecl{Name: name}
+		fg.interfaceMethods(t, &iface, make(map[string]bool))
+		comp.Body.Provides = append(comp.Body.Provides, iface)
+
+	default:
+		if values := enumValues(obj, pkg); len(values) > 0 {
+			comp.Body.Types = append(comp.Body.Types, ast.TypeDecl{Name: name, Kind: ast.TypeKindEnum, Values: values})
+			return
+		}
+		base := fg.typeExpr(ts.Type)
+		comp.Body.Types = append(comp.Body.Types, ast.TypeDecl{Name: name, Kind: ast.TypeKindAlias, BaseType: &base})
+	}
+}
+
+// fieldNames は struct のフィールド名を返す（埋め込みフィールドは型名）
+func fieldNames(field *goast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, n := range field.Names {
+			names[i] = n.Name
+		}
+		return names
+	}
+	expr := field.Type
+	if star, ok := expr.(*goast.StarExpr); ok {
+		expr = star.X
+	}
+	if index, ok := expr.(*goast.IndexExpr); ok {
+		expr = index.X
+	}
+	switch e := expr.(type) {
+	case *goast.Ident:
+		return []string{e.Name}
+	case *goast.SelectorExpr:
+		return []string{e.Sel.Name}
+	}
+	return nil
+}
+
+// enumValues は string を基底型とする名前付き型の定数から enum の値を宣言順に返す
+func enumValues(obj *types.TypeName, pkg *goPackage) []string {
+	if obj == nil || pkg.Types == nil {
+		return nil
+	}
+	basic, ok := obj.Type().Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsString == 0 {
+		return nil
+	}
+
+	var consts []*types.Const
+	scope := pkg.Types.Scope()
+	for _, n := range scope.Names() {
+		if c, ok := scope.Lookup(n).(*types.Const); ok && types.Identical(c.Type(), obj.Type()) {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	seen := make(map[string]bool)
+	var values []string
+	for _, c := range consts {
+		value := EnumValue(obj.Name(), c.Name())
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// interfaceMethods は interface のメソッドを宣言順に追加する
+// 対象のソースで宣言された interface の埋め込みは展開する
+func (fg *fileGenerator) interfaceMethods(t *goast.InterfaceType, iface *ast.InterfaceDecl, seen map[string]bool) {
+	for _, field := range t.Methods.List {
+		if ft, ok := field.Type.(*goast.FuncType); ok {
+			for _, n := range field.Names {
+				if !seen[n.Name] {
+					seen[n.Name] = true
+					iface.Methods = append(iface.Methods, fg.method(n.Name, ft))
+				}
+			}
+			continue
+		}
+		tn, ok := fg.typeObject(field.Type).(*types.TypeName)
+		if !ok || !fg.loader.isSource(tn) {
+			continue
+		}
+		// 埋め込み元のパッケージの型情報でメソッドを変換する
+		pkg := fg.loader.load(filepath.Dir(fg.loader.declFile(tn)))
+		if pkg == nil || pkg.Info == nil {
+			continue
+		}
+		fg.index(pkg)
+		if ts, ok := fg.specs[tn]; ok {
+			if embedded, ok := ts.Type.(*goast.InterfaceType); ok {
+				sub := *fg
+				sub.info = pkg.Info
+				sub.interfaceMethods(embedded, iface, seen)
+			}
+		}
+	}
+}
+
+// method は Go のメソッドシグネチャを変換する
+// 先頭の context.Context は async、末尾の error は throws Error として扱う
+func (fg *fileGenerator) method(name string, ft *goast.FuncType) ast.MethodDecl {
+	method := ast.MethodDecl{Name: Identifier(name)}
+
+	index := 0
+	for i, field := range ft.Params.List {
+		names := field.Names
+		if len(names) == 0 {
+			names = []*goast.Ident{nil}
+		}
+		for _, n := range names {
+			index++
+			if i == 0 && index == 1 && fg.isContext(field.Type) {
+				method.Async = true
+				continue
+			}
+			paramName := fmt.Sprintf("arg%d", index)
+			if n != nil && n.Name != "_" {
+				paramName = n.Name
+			}
+			var typ ast.TypeExpr
+			if ellipsis, ok := field.Type.(*goast.Ellipsis); ok {
+				typ = arrayOf(fg.typeExpr(ellipsis.Elt))
+			} else {
+				typ = fg.typeExpr(field.Type)
+			}
+			method.Params = append(method.Params, ast.ParamDecl{Name: Identifier(paramName), Type: typ})
+		}
+	}
+
+	var results []goast.Expr
+	if ft.Results != nil {
+		for _, field := range ft.Results.List {
+			for i := 0; i < max(len(field.Names), 1); i++ {
+				results = append(results, field.Type)
+			}
+		}
+	}
+	if n := len(results); n > 0 && fg.isError(results[n-1]) {
+		method.Throws = []string{"Error"}
+		results = results[:n-1]
+	}
+	if len(results) > 0 {
+		ret := fg.typeExpr(results[0])
+		method.ReturnType = &ret
+	}
+	return method
+}
+
+// typeExpr は Go の型式を .pact の型に変換する
+func (fg *fileGenerator) typeExpr(expr goast.Expr) ast.TypeExpr {
+	switch e := expr.(type) {
+	case *goast.ParenExpr:
+		return fg.typeExpr(e.X)
+	case *goast.StarExpr:
+		t := fg.typeExpr(e.X)
+		t.Nullable = true
+		return t
+	case *goast.ArrayType:
+		if ident, ok := e.Elt.(*goast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") && fg.isUniverse(ident) {
+			return ast.TypeExpr{Name: "bytes"}
+		}
+		return arrayOf(fg.typeExpr(e.Elt))
+	case *goast.MapType:
+		return ast.TypeExpr{Name: "Map", TypeParams: []ast.TypeExpr{fg.typeExpr(e.Key), fg.typeExpr(e.Value)}}
+	case *goast.IndexExpr:
+		return fg.typeExpr(e.X)
+	case *goast.IndexListExpr:
+		return fg.typeExpr(e.X)
+	case *goast.Ident:
+		if fg.isUniverse(e) {
+			return ast.TypeExpr{Name: builtinName(e.Name)}
+		}
+		return fg.namedType(fg.info.Uses[e])
+	case *goast.SelectorExpr:
+		if pkg := fg.importedPackage(e); pkg != "" {
+			switch pkg + "." + e.Sel.Name {
+			case "time.Time":
+				return ast.TypeExpr{Name: "datetime"}
+			case "time.Duration":
+				return ast.TypeExpr{Name: "int"}
+			}
+		}
+		return fg.namedType(fg.info.Uses[e.Sel])
+	}
+	// interface・func・chan・無名 struct は any とする
+	return ast.TypeExpr{Name: "any"}
+}
+
+// arrayOf は要素型の配列を返す（入れ子の配列は Array<T> を要素とする）
+func arrayOf(elem ast.TypeExpr) ast.TypeExpr {
+	elem.Nullable = false
+	if elem.Array {
+		elem = ast.TypeExpr{Name: "Array", TypeParams: []ast.TypeExpr{elem}}
+	}
+	elem.Array = true
+	return elem
+}
+
+// builtinName は Go の組み込み型に対応する .pact の型名を返す
+func builtinName(name string) string {
+	switch name {
+	case "string":
+		return "string"
+	case "bool":
+		return "bool"
+	case "float32", "float64":
+		return "float"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+		return "int"
+	}
+	return "any"
+}
+
+// namedType は対象のソースで宣言された型を名前で参照し、必要なら import を追加する
+// interface や外部パッケージの型は any とする
+func (fg *fileGenerator) namedType(obj types.Object) ast.TypeExpr {
+	tn, ok := obj.(*types.TypeName)
+	if !ok || !fg.loader.isSource(tn) {
+		return ast.TypeExpr{Name: "any"}
+	}
+	if _, ok := tn.Type().Underlying().(*types.Interface); ok {
+		return ast.TypeExpr{Name: "any"}
+	}
+	fg.addImport(fg.loader.declFile(tn))
+	return ast.TypeExpr{Name: Identifier(tn.Name())}
+}
+
+// componentRef はフィールドや引数の型が他のファイルのコンポーネントなら、
+// そのファイルのコンポーネント名と関係の種類を返す
+func (fg *fileGenerator) componentRef(expr goast.Expr) (string, ast.RelationKind, bool) {
+	if star, ok := expr.(*goast.StarExpr); ok {
+		expr = star.X
+	}
+	tn, ok := fg.typeObject(expr).(*types.TypeName)
+	if !ok || !fg.loader.isSource(tn) {
+		return "", "", false
+	}
+	declFile := fg.loader.declFile(tn)
+	if declFile == fg.source {
+		return "", "", false
+	}
+
+	var kind ast.RelationKind
+	switch tn.Type().Underlying().(type) {
+	case *types.Interface:
+		kind = ast.RelationDependsOn
+	case *types.Struct:
+		if _, ok := tn.Pkg().Scope().Lookup("New" + tn.Name()).(*types.Func); !ok {
+			return "", "", false
+		}
+		kind = ast.RelationContains
+	default:
+		return "", "", false
+	}
+	fg.addImport(declFile)
+	return Identifier(project.ComponentName(declFile)), kind, true
+}
+
+// typeObject は型名の式が指すオブジェクトを返す
+func (fg *fileGenerator) typeObject(expr goast.Expr) types.Object {
+	switch e := expr.(type) {
+	case *goast.Ident:
+		return fg.info.Uses[e]
+	case *goast.SelectorExpr:
+		return fg.info.Uses[e.Sel]
+	case *goast.IndexExpr:
+		return fg.typeObject(e.X)
+	case *goast.IndexListExpr:
+		return fg.typeObject(e.X)
+	}
+	return nil
+}
+
+// addImport は宣言元のソースファイルに対応する仕様ファイルを import に加える
+func (fg *fileGenerator) addImport(declFile string) {
+	if declFile == fg.source {
+		return
+	}
+	target, err := fg.project.PactPathFor(declFile)
+	if err != nil {
+		return
+	}
+	rel, err := filepath.Rel(filepath.Dir(fg.pactPath), target)
+	if err != nil {
+		return
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	fg.imports[rel] = true
+}
+
+// importedPackage はセレクタ式の修飾子が import したパッケージならそのパスを返す
+func (fg *fileGenerator) importedPackage(e *goast.SelectorExpr) string {
+	ident, ok := e.X.(*goast.Ident)
+	if !ok {
+		return ""
+	}
+	if pkgName, ok := fg.info.Uses[ident].(*types.PkgName); ok {
+		return pkgName.Imported().Path()
+	}
+	return ""
+}
+
+func (fg *fileGenerator) isUniverse(ident *goast.Ident) bool {
+	obj := fg.info.Uses[ident]
+	return obj == nil && types.Universe.Lookup(ident.Name) != nil || obj != nil && obj.Parent() == types.Universe
+}
+
+func (fg *fileGenerator) isContext(expr goast.Expr) bool {
+	sel, ok := expr.(*goast.SelectorExpr)
+	return ok && sel.Sel.Name == "Context" && fg.importedPackage(sel) == "context"
+}
+
+func (fg *fileGenerator) isError(expr goast.Expr) bool {
+	ident, ok := expr.(*goast.Ident)
+	return ok && ident.Name == "error" && fg.isUniverse(ident)
+}
@@ -0,0 +1,218 @@
+package scaffold
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"pact/internal/infrastructure/project"
+)
+
+// goPackage は型検査した Go パッケージ
+type goPackage struct {
+	Dir   string
+	Files []*ast.File
+	Paths []string // Files と同じ順のファイルパス（絶対パス）
+	Types *types.Package
+	Info  *types.Info
+
+	loading bool
+}
+
+// goLoader は source_root 以下の Go パッケージを読み込み、型検査する
+// source_root 外の import は中身のない空のパッケージとして扱い、型検査のエラーは無視する
+type goLoader struct {
+	Fset     *token.FileSet
+	sources  map[string]bool       // 対象のソースファイル（絶対パス）
+	packages map[string]*goPackage // ディレクトリ → パッケージ
+	external map[string]*types.Package
+	modPath  string // go.mod のモジュールパス
+	modRoot  string // go.mod があるディレクトリ
+}
+
+// newLoader は sources を対象にしたローダーを作成する
+// root から上位に辿って見つけた go.mod で import パスをディレクトリに対応づける
+func newLoader(root string, sources []string) *goLoader {
+	l := &goLoader{
+		Fset:     token.NewFileSet(),
+		sources:  make(map[string]bool),
+		packages: make(map[string]*goPackage),
+		external: make(map[string]*types.Package),
+	}
+	for _, src := range sources {
+		l.sources[absPath(src)] = true
+	}
+	l.modRoot, l.modPath = findModule(root)
+	return l
+}
+
+// load はディレクトリのパッケージを読み込んで返す（対象のファイルがなければ nil）
+func (l *goLoader) load(dir string) *goPackage {
+	dir = absPath(dir)
+	if pkg, ok := l.packages[dir]; ok {
+		return pkg
+	}
+
+	var paths []string
+	for src := range l.sources {
+		if filepath.Dir(src) == dir {
+			paths = append(paths, src)
+		}
+	}
+	if len(paths) == 0 {
+		l.packages[dir] = nil
+		return nil
+	}
+	sort.Strings(paths)
+
+	pkg := &goPackage{Dir: dir, loading: true}
+	l.packages[dir] = pkg
+	for _, p := range paths {
+		// 構文エラーがあっても読めた部分は使う
+		file, _ := parser.ParseFile(l.Fset, p, nil, parser.ParseComments)
+		if file == nil {
+			continue
+		}
+		pkg.Files = append(pkg.Files, file)
+		pkg.Paths = append(pkg.Paths, p)
+	}
+
+	pkg.Info = &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: l, Error: func(error) {}}
+	pkg.Types, _ = conf.Check(l.importPath(dir), l.Fset, pkg.Files, pkg.Info)
+	pkg.loading = false
+	return pkg
+}
+
+// file はソースファイルの構文木とそのパッケージを返す
+func (l *goLoader) file(path string) (*ast.File, *goPackage) {
+	path = absPath(path)
+	pkg := l.load(filepath.Dir(path))
+	if pkg == nil {
+		return nil, nil
+	}
+	for i, p := range pkg.Paths {
+		if p == path {
+			return pkg.Files[i], pkg
+		}
+	}
+	return nil, pkg
+}
+
+// Import は types.Importer を実装する
+func (l *goLoader) Import(importPath string) (*types.Package, error) {
+	if dir, ok := l.dirFor(importPath); ok {
+		if pkg := l.load(dir); pkg != nil && !pkg.loading && pkg.Types != nil {
+			return pkg.Types, nil
+		}
+	}
+
+	if pkg, ok := l.external[importPath]; ok {
+		return pkg, nil
+	}
+	pkg := types.NewPackage(importPath, packageName(importPath))
+	pkg.MarkComplete()
+	l.external[importPath] = pkg
+	return pkg, nil
+}
+
+// isSource はオブジェクトが対象のソースファイルで宣言されているかを返す
+func (l *goLoader) isSource(obj types.Object) bool {
+	return obj != nil && obj.Pos().IsValid() && l.sources[l.Fset.Position(obj.Pos()).Filename]
+}
+
+// declFile はオブジェクトを宣言したソースファイルを返す
+func (l *goLoader) declFile(obj types.Object) string {
+	return l.Fset.Position(obj.Pos()).Filename
+}
+
+func (l *goLoader) dirFor(importPath string) (string, bool) {
+	if l.modPath == "" {
+		return "", false
+	}
+	if importPath == l.modPath {
+		return l.modRoot, true
+	}
+	if rest := strings.TrimPrefix(importPath, l.modPath+"/"); rest != importPath {
+		return filepath.Join(l.modRoot, filepath.FromSlash(rest)), true
+	}
+	return "", false
+}
+
+func (l *goLoader) importPath(dir string) string {
+	if l.modPath != "" {
+		if rel, err := filepath.Rel(l.modRoot, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			if rel == "." {
+				return l.modPath
+			}
+			return l.modPath + "/" + filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(dir)
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// packageName は import パスから既定のパッケージ名を推定する
+func packageName(importPath string) string {
+	name := path.Base(importPath)
+	if majorVersion.MatchString(name) {
+		name = path.Base(path.Dir(importPath))
+	}
+	if i := strings.LastIndexAny(name, ".-"); i >= 0 && strings.HasPrefix(name, "go-") {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, ".go")
+}
+
+// findModule は dir から上位に go.mod を探し、そのディレクトリとモジュールパスを返す
+func findModule(dir string) (root, modPath string) {
+	dir = absPath(dir)
+	for {
+		if f, err := os.Open(filepath.Join(dir, "go.mod")); err == nil {
+			defer f.Close()
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				fields := strings.Fields(scanner.Text())
+				if len(fields) >= 2 && fields[0] == "module" {
+					return dir, strings.Trim(fields[1], `"`)
+				}
+			}
+			return dir, ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+func absPath(p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return filepath.Clean(p)
+	}
+	return abs
+}
+
+// newGoLoader はプロジェクトのソースファイルを対象にしたローダーを作成する
+func newGoLoader(p *project.Project) (*goLoader, []string, error) {
+	sources, err := p.SourceFiles()
+	if err != nil {
+		return nil, nil, err
+	}
+	return newLoader(p.SourceRoot(), sources), sources, nil
+}
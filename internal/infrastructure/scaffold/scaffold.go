@@ -0,0 +1,68 @@
+// Package scaffold generates .pact specs from existing source code.
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"pact/internal/domain/ast"
+	"pact/internal/domain/errors"
+	"pact/internal/infrastructure/formatter"
+	"pact/internal/infrastructure/parser"
+)
+
+// File はソースファイルから生成した仕様ファイル
+type File struct {
+	Source string // 元のソースファイル（Root からの相対パス）
+	Path   string // 仕様ファイルの出力先
+	Spec   *ast.SpecFile
+}
+
+// Content は仕様ファイルの内容を返す
+func (f *File) Content() []byte {
+	header := "// Source: " + filepath.ToSlash(f.Source) + "\n"
+	return append([]byte(header), formatter.Print(f.Spec)...)
+}
+
+// Write は仕様ファイルを書き込み、書き込んだファイルのパスを返す
+// force でなければ既存の仕様ファイルは上書きしない
+func Write(files []*File, force bool) ([]string, error) {
+	var written []string
+	for _, f := range files {
+		if _, err := os.Stat(f.Path); err == nil && !force {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return written, &errors.ConfigError{Path: f.Path, Message: err.Error()}
+		}
+		if err := os.WriteFile(f.Path, f.Content(), 0644); err != nil {
+			return written, &errors.ConfigError{Path: f.Path, Message: err.Error()}
+		}
+		written = append(written, f.Path)
+	}
+	return written, nil
+}
+
+// Identifier はソースの名前を .pact の識別子にする
+// 予約語と重なる名前は末尾に "_" を付ける
+func Identifier(name string) string {
+	if parser.IsKeyword(name) {
+		return name + "_"
+	}
+	return name
+}
+
+// EnumValue は定数名から enum の値を導出する
+// 型名の接頭辞は取り除く（例: Status の StatusPending → Pending）
+func EnumValue(typeName, constName string) string {
+	if rest := strings.TrimPrefix(constName, typeName); rest != constName && rest != "" {
+		if r := rune(rest[0]); unicode.IsUpper(r) || r == '_' {
+			if trimmed := strings.TrimLeft(rest, "_"); trimmed != "" {
+				constName = trimmed
+			}
+		}
+	}
+	return Identifier(constName)
+}
@@ -0,0 +1,318 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pact/internal/domain/ast"
+	domainConfig "pact/internal/domain/config"
+	"pact/internal/infrastructure/parser"
+	"pact/internal/infrastructure/project"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+}
+
+// scaffoldGo は Go のソースから仕様を生成し、ソースの相対パスごとに返す
+func scaffoldGo(t *testing.T, files map[string]string) map[string]*File {
+	t.Helper()
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.21\n"
+	writeFiles(t, root, files)
+
+	generated, err := FromGo(project.New(root, domainConfig.Default()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := make(map[string]*File)
+	for _, f := range generated {
+		result[filepath.ToSlash(f.Source)] = f
+	}
+	return result
+}
+
+// content は生成された仕様の内容を返し、再パースできることを確認する
+func content(t *testing.T, f *File) string {
+	t.Helper()
+	if f == nil {
+		t.Fatal("expected generated file")
+	}
+	text := string(f.Content())
+	if _, err := parser.NewParser(parser.NewLexer(text)).Parse(); err != nil {
+		t.Fatalf("generated spec does not parse: %v\n%s", err, text)
+	}
+	return text
+}
+
+func assertContains(t *testing.T, text string, wants ...string) {
+	t.Helper()
+	for _, want := range wants {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+// =============================================================================
+// SC001-SC006: Go からの生成
+// =============================================================================
+
+// SC001: struct は type になり、フィールドの可視性は Go の公開規則に従う
+func TestFromGo_Struct(t *testing.T) {
+	files := scaffoldGo(t, map[string]string{
+		"src/user/model.go": `package user
+
+import "time"
+
+type User struct {
+	ID        string
+	Age       int
+	score     float64
+	Email     *string
+	Roles     []string
+	Meta      map[string]int
+	Avatar    []byte
+	CreatedAt time.Time
+}
+`,
+	})
+
+	f := files["src/user/model.go"]
+	if !strings.HasSuffix(filepath.ToSlash(f.Path), "/.pact/user/model.pact") {
+		t.Errorf("unexpected output path: %s", f.Path)
+	}
+	assertContains(t, content(t, f),
+		"// Source: src/user/model.go",
+		"component Model {",
+		"type User {",
+		"+ID: string",
+		"+Age: int",
+		"-score: float",
+		"+Email: string?",
+		"+Roles: string[]",
+		"+Meta: Map<string, int>",
+		"+Avatar: bytes",
+		"+CreatedAt: datetime",
+	)
+}
+
+// SC002: string の名前付き型と定数は enum になる
+func TestFromGo_Enum(t *testing.T) {
+	files := scaffoldGo(t, map[string]string{
+		"src/order/status.go": `package order
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusShipped Status = "shipped"
+	Unrelated            = "x"
+)
+
+type Code int
+
+type Alias = string
+`,
+	})
+
+	f := files["src/order/status.go"]
+	spec := f.Spec.Components[0]
+	if len(spec.Body.Types) != 3 {
+		t.Fatalf("expected 3 types, got %d", len(spec.Body.Types))
+	}
+	enum := spec.Body.Types[0]
+	if enum.Kind != ast.TypeKindEnum || strings.Join(enum.Values, ",") != "Pending,Shipped" {
+		t.Errorf("unexpected enum: %+v", enum)
+	}
+	assertContains(t, content(t, f), "type Code = int", "type Alias = string")
+}
+
+// SC003: interface は provides になり、context は async、error は throws になる
+func TestFromGo_Interface(t *testing.T) {
+	files := scaffoldGo(t, map[string]string{
+		"src/repo/repository.go": `package repo
+
+import "context"
+
+type Reader interface {
+	Get(ctx context.Context, id string) (*Item, error)
+}
+
+type Repository interface {
+	Reader
+	Save(ctx context.Context, item Item) error
+	Find(string, ...int) []Item
+	Count() int
+}
+
+type Item struct {
+	Name string
+}
+`,
+	})
+
+	assertContains(t, content(t, files["src/repo/repository.go"]),
+		"provides Reader {",
+		"async Get(id: string) -> Item? throws Error",
+		"provides Repository {",
+		"async Save(item: Item) throws Error",
+		"Find(arg1: string, arg2: int[]) -> Item[]",
+		"Count() -> int",
+	)
+
+	repo := files["src/repo/repository.go"].Spec.Components[0].Body.Provides[1]
+	if len(repo.Methods) != 4 || repo.Methods[0].Name != "Get" {
+		t.Errorf("expected embedded methods to be expanded first, got %+v", repo.Methods)
+	}
+}
+
+// SC004: 他のファイルのコンポーネントへの依存は depends on / contains になる
+func TestFromGo_Relations(t *testing.T) {
+	files := scaffoldGo(t, map[string]string{
+		"src/payment/gateway.go": `package payment
+
+type Gateway interface {
+	Charge(amount int) error
+}
+`,
+		"src/order/cache.go": `package order
+
+type Cache struct{}
+
+func NewCache() *Cache { return &Cache{} }
+`,
+		"src/order/service.go": `package order
+
+import "example.com/app/src/payment"
+
+type Service struct {
+	cache   *Cache
+	retries int
+}
+
+func NewService(gateway payment.Gateway, cache *Cache) *Service {
+	return &Service{cache: cache}
+}
+`,
+	})
+
+	text := content(t, files["src/order/service.go"])
+	assertContains(t, text,
+		`import "../payment/gateway.pact"`,
+		`import "./cache.pact"`,
+		"contains Cache",
+		"depends on Gateway",
+		"-retries: int",
+	)
+	if strings.Contains(text, "cache:") {
+		t.Errorf("component field should become a relation:\n%s", text)
+	}
+
+	rels := files["src/order/service.go"].Spec.Components[0].Body.Relations
+	if len(rels) != 2 {
+		t.Errorf("expected relations to be deduplicated, got %+v", rels)
+	}
+}
+
+// SC005: 他のファイルの型への参照は import を追加し、予約語の名前は変換する
+func TestFromGo_ImportsAndKeywords(t *testing.T) {
+	files := scaffoldGo(t, map[string]string{
+		"src/shop/item.go": `package shop
+
+type Item struct {
+	Name string
+}
+`,
+		"src/shop/cart.go": `package shop
+
+type Cart struct {
+	Items []*Item
+	state string
+	Entry string
+}
+
+type Notifier func(string)
+`,
+	})
+
+	text := content(t, files["src/shop/cart.go"])
+	assertContains(t, text,
+		`import "./item.pact"`,
+		"+Items: Item[]",
+		"-state_: string",
+		"+Entry: string",
+		"type Notifier = any",
+	)
+	if strings.Contains(text, "import \"./cart.pact\"") {
+		t.Errorf("should not import itself:\n%s", text)
+	}
+}
+
+// SC006: 既存の仕様は force なしでは上書きしない
+func TestWrite_KeepExisting(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "a.pact")
+	writeFiles(t, root, map[string]string{"a.pact": "component A {}\n"})
+
+	files := []*File{
+		{Source: "src/a.go", Path: existing, Spec: &ast.SpecFile{Components: []ast.ComponentDecl{{Name: "A"}}}},
+		{Source: "src/b.go", Path: filepath.Join(root, "sub", "b.pact"), Spec: &ast.SpecFile{Components: []ast.ComponentDecl{{Name: "B"}}}},
+	}
+
+	written, err := Write(files, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(written) != 1 || written[0] != files[1].Path {
+		t.Errorf("unexpected written files: %v", written)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "component A {}\n" {
+		t.Errorf("existing spec was overwritten: %s", data)
+	}
+
+	if written, _ := Write(files, true); len(written) != 2 {
+		t.Errorf("expected both files with force, got %v", written)
+	}
+}
+
+// =============================================================================
+// SC010-SC011: 名前の変換
+// =============================================================================
+
+// SC010: enum の値は型名の接頭辞を除く
+func TestEnumValue(t *testing.T) {
+	tests := []struct{ typ, name, want string }{
+		{"Status", "StatusPending", "Pending"},
+		{"Status", "Status_Done", "Done"},
+		{"Status", "Statusquo", "Statusquo"},
+		{"Status", "Active", "Active"},
+		{"Status", "Status", "Status"},
+	}
+	for _, tt := range tests {
+		if got := EnumValue(tt.typ, tt.name); got != tt.want {
+			t.Errorf("EnumValue(%q, %q) = %q, want %q", tt.typ, tt.name, got, tt.want)
+		}
+	}
+}
+
+// SC011: 予約語と重なる名前には "_" を付ける
+func TestIdentifier(t *testing.T) {
+	if got := Identifier("type"); got != "type_" {
+		t.Errorf("expected type_, got %q", got)
+	}
+	if got := Identifier("Name"); got != "Name" {
+		t.Errorf("expected Name, got %q", got)
+	}
+}
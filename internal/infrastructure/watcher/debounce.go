@@ -0,0 +1,84 @@
+package watcher
+
+import "time"
+
+// DefaultDebounce は連続イベントをまとめる待ち時間のデフォルト値
+const DefaultDebounce = 200 * time.Millisecond
+
+// Debounce は短時間に連続したイベントをまとめて通知する
+// window の間に新しいイベントが来なくなった時点で、それまでのイベントを
+// パスごとに1件へ集約して送出する。入力チャネルが閉じると残りを送出して閉じる
+func Debounce(events <-chan WatchEvent, window time.Duration) <-chan []WatchEvent {
+	if window <= 0 {
+		window = DefaultDebounce
+	}
+
+	out := make(chan []WatchEvent)
+
+	go func() {
+		defer close(out)
+
+		var pending []WatchEvent
+		index := make(map[string]int)
+		var timer *time.Timer
+		var fire <-chan time.Time
+
+		flush := func() {
+			if len(pending) > 0 {
+				out <- pending
+			}
+			pending = nil
+			index = make(map[string]int)
+			fire = nil
+		}
+
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					if timer != nil {
+						timer.Stop()
+					}
+					flush()
+					return
+				}
+				if i, exists := index[ev.Path]; exists {
+					pending[i] = merge(pending[i], ev)
+				} else {
+					index[ev.Path] = len(pending)
+					pending = append(pending, ev)
+				}
+				if timer == nil {
+					timer = time.NewTimer(window)
+				} else {
+					if !timer.Stop() {
+						select {
+						case <-timer.C:
+						default:
+						}
+					}
+					timer.Reset(window)
+				}
+				fire = timer.C
+			case <-fire:
+				flush()
+			}
+		}
+	}()
+
+	return out
+}
+
+// merge は同一パスの2つのイベントを1つにまとめる
+func merge(prev, next WatchEvent) WatchEvent {
+	switch {
+	case prev.Type == WatchEventCreated && next.Type == WatchEventModified:
+		// 作成直後の保存は作成として扱う
+		return prev
+	case prev.Type == WatchEventDeleted && next.Type == WatchEventCreated:
+		// 削除して作り直すエディタの保存は変更として扱う
+		return WatchEvent{Path: next.Path, Type: WatchEventModified}
+	default:
+		return next
+	}
+}
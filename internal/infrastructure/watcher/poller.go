@@ -0,0 +1,189 @@
+package watcher
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval はポーリング間隔のデフォルト値
+const DefaultPollInterval = 500 * time.Millisecond
+
+// fileState はファイルの変更検知に使う状態
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// PollingWatcher はファイルの更新時刻を定期的に比較する Watcher の実装
+// cgo や OS 固有の API に依存しないため、どの環境でも動作する
+type PollingWatcher struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPollingWatcher は新しいPollingWatcherを作成する
+func NewPollingWatcher(interval time.Duration) *PollingWatcher {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &PollingWatcher{interval: interval}
+}
+
+// Watch はファイル監視を開始する
+func (w *PollingWatcher) Watch(config WatchMode) (<-chan WatchEvent, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !config.Enabled {
+		return nil, &WatchError{Message: "watch mode is disabled"}
+	}
+	if w.stop != nil {
+		return nil, &WatchError{Message: "watcher is already running"}
+	}
+	if len(config.Paths) == 0 {
+		return nil, &WatchError{Message: "no paths to watch"}
+	}
+	for _, p := range config.Paths {
+		if _, err := os.Stat(p); err != nil {
+			return nil, &WatchError{Path: p, Message: err.Error()}
+		}
+	}
+
+	extensions := config.Extensions
+	if len(extensions) == 0 {
+		extensions = []string{".pact"}
+	}
+
+	events := make(chan WatchEvent)
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	snapshot := scan(config.Paths, extensions)
+	go w.run(config.Paths, extensions, snapshot, events, w.stop, w.done)
+
+	return events, nil
+}
+
+// Stop はファイル監視を停止する
+func (w *PollingWatcher) Stop() error {
+	w.mu.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mu.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+	close(stop)
+	<-done
+	return nil
+}
+
+// run はポーリングループを実行する
+func (w *PollingWatcher) run(
+	paths []string,
+	extensions []string,
+	prev map[string]fileState,
+	events chan<- WatchEvent,
+	stop <-chan struct{},
+	done chan<- struct{},
+) {
+	defer close(done)
+	defer close(events)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		cur := scan(paths, extensions)
+		for _, ev := range diff(prev, cur) {
+			select {
+			case events <- ev:
+			case <-stop:
+				return
+			}
+		}
+		prev = cur
+	}
+}
+
+// scan は監視対象のファイル状態を収集する
+func scan(paths []string, extensions []string) map[string]fileState {
+	result := make(map[string]fileState)
+	for _, root := range paths {
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// 走査中に削除されたファイルなどは無視する
+				return nil
+			}
+			if d.IsDir() || !hasExtension(path, extensions) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			result[filepath.Clean(path)] = fileState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+	}
+	return result
+}
+
+// diff は2つのスナップショットの差分をイベントとして返す（パス順）
+func diff(prev, cur map[string]fileState) []WatchEvent {
+	var events []WatchEvent
+	for path, state := range cur {
+		old, ok := prev[path]
+		switch {
+		case !ok:
+			events = append(events, WatchEvent{Path: path, Type: WatchEventCreated})
+		case !old.modTime.Equal(state.modTime) || old.size != state.size:
+			events = append(events, WatchEvent{Path: path, Type: WatchEventModified})
+		}
+	}
+	for path := range prev {
+		if _, ok := cur[path]; !ok {
+			events = append(events, WatchEvent{Path: path, Type: WatchEventDeleted})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
+
+// hasExtension はパスが対象の拡張子を持つかを返す
+func hasExtension(path string, extensions []string) bool {
+	ext := filepath.Ext(path)
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchError はファイル監視のエラー
+type WatchError struct {
+	Path    string
+	Message string
+}
+
+func (e *WatchError) Error() string {
+	if e.Path == "" {
+		return "watch: " + e.Message
+	}
+	return "watch " + e.Path + ": " + e.Message
+}
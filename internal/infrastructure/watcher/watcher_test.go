@@ -0,0 +1,185 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+func nextEvent(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return WatchEvent{}
+}
+
+// =============================================================================
+// WT001-WT005: PollingWatcher
+// =============================================================================
+
+// WT001: ファイル作成の検知（サブディレクトリを含む）
+func TestPollingWatcher_Created(t *testing.T) {
+	dir := t.TempDir()
+	w := NewPollingWatcher(10 * time.Millisecond)
+	events, err := w.Watch(NewWatchMode(dir))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = w.Stop() }()
+
+	path := filepath.Join(dir, "nested", "a.pact")
+	writeFile(t, path, "component A { }")
+
+	ev := nextEvent(t, events)
+	if ev.Type != WatchEventCreated {
+		t.Errorf("expected WatchEventCreated, got %v", ev.Type)
+	}
+	if ev.Path != path {
+		t.Errorf("expected path %q, got %q", path, ev.Path)
+	}
+}
+
+// WT002: ファイル変更・削除の検知
+func TestPollingWatcher_ModifiedAndDeleted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.pact")
+	writeFile(t, path, "component A { }")
+
+	w := NewPollingWatcher(10 * time.Millisecond)
+	events, err := w.Watch(NewWatchMode(dir))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = w.Stop() }()
+
+	writeFile(t, path, "component A { type T { id: string } }")
+	if ev := nextEvent(t, events); ev.Type != WatchEventModified {
+		t.Errorf("expected WatchEventModified, got %v", ev.Type)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if ev := nextEvent(t, events); ev.Type != WatchEventDeleted {
+		t.Errorf("expected WatchEventDeleted, got %v", ev.Type)
+	}
+}
+
+// WT003: 拡張子フィルタ
+func TestPollingWatcher_IgnoresOtherExtensions(t *testing.T) {
+	dir := t.TempDir()
+	w := NewPollingWatcher(10 * time.Millisecond)
+	events, err := w.Watch(NewWatchMode(dir))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = w.Stop() }()
+
+	writeFile(t, filepath.Join(dir, "a_class.svg"), "<svg/>")
+	writeFile(t, filepath.Join(dir, "b.pact"), "component B { }")
+
+	ev := nextEvent(t, events)
+	if filepath.Base(ev.Path) != "b.pact" {
+		t.Errorf("expected only b.pact event, got %q", ev.Path)
+	}
+}
+
+// WT004: Stop でチャネルが閉じる
+func TestPollingWatcher_Stop(t *testing.T) {
+	w := NewPollingWatcher(10 * time.Millisecond)
+	events, err := w.Watch(NewWatchMode(t.TempDir()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected events channel to be closed")
+	}
+	// 2回目のStopはエラーにならない
+	if err := w.Stop(); err != nil {
+		t.Errorf("unexpected error on second stop: %v", err)
+	}
+}
+
+// WT005: 存在しないパス・二重起動
+func TestPollingWatcher_Errors(t *testing.T) {
+	w := NewPollingWatcher(10 * time.Millisecond)
+	if _, err := w.Watch(NewWatchMode("/nonexistent/path")); err == nil {
+		t.Error("expected error for nonexistent path")
+	}
+
+	if _, err := w.Watch(NewWatchMode(t.TempDir())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = w.Stop() }()
+	if _, err := w.Watch(NewWatchMode(t.TempDir())); err == nil {
+		t.Error("expected error when already running")
+	}
+}
+
+// =============================================================================
+// WD001-WD002: Debounce
+// =============================================================================
+
+// WD001: 連続イベントの集約
+func TestDebounce_Coalesces(t *testing.T) {
+	in := make(chan WatchEvent)
+	out := Debounce(in, 50*time.Millisecond)
+
+	go func() {
+		in <- WatchEvent{Path: "a.pact", Type: WatchEventCreated}
+		in <- WatchEvent{Path: "a.pact", Type: WatchEventModified}
+		in <- WatchEvent{Path: "b.pact", Type: WatchEventModified}
+		in <- WatchEvent{Path: "b.pact", Type: WatchEventModified}
+	}()
+
+	select {
+	case batch := <-out:
+		if len(batch) != 2 {
+			t.Fatalf("expected 2 events, got %d: %v", len(batch), batch)
+		}
+		if batch[0].Path != "a.pact" || batch[0].Type != WatchEventCreated {
+			t.Errorf("unexpected first event: %+v", batch[0])
+		}
+		if batch[1].Path != "b.pact" || batch[1].Type != WatchEventModified {
+			t.Errorf("unexpected second event: %+v", batch[1])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for batch")
+	}
+
+	close(in)
+	if _, ok := <-out; ok {
+		t.Error("expected output channel to be closed")
+	}
+}
+
+// WD002: 削除後の再作成は変更として扱う
+func TestDebounce_DeleteThenCreate(t *testing.T) {
+	in := make(chan WatchEvent, 2)
+	in <- WatchEvent{Path: "a.pact", Type: WatchEventDeleted}
+	in <- WatchEvent{Path: "a.pact", Type: WatchEventCreated}
+	close(in)
+
+	batch := <-Debounce(in, 10*time.Millisecond)
+	if len(batch) != 1 || batch[0].Type != WatchEventModified {
+		t.Errorf("expected single modified event, got %v", batch)
+	}
+}
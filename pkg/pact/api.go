@@ -12,8 +12,10 @@ import (
 	"pact/internal/domain/diagram/flow"
 	"pact/internal/domain/diagram/sequence"
 	"pact/internal/domain/diagram/state"
+	"pact/internal/infrastructure/formatter"
 	"pact/internal/infrastructure/parser"
 	"pact/internal/infrastructure/renderer/svg"
+	"pact/internal/infrastructure/resolver"
 )
 
 // Type aliases for public use
@@ -59,7 +61,36 @@ func (c *Client) ParseFile(path string) (*ast.SpecFile, error) {
 	if err != nil {
 		return nil, err
 	}
-	return c.ParseString(string(content))
+	spec, err := c.ParseString(string(content))
+	if spec != nil {
+		spec.Path = path
+	}
+	return spec, err
+}
+
+// ParseFileWithImports parses a .pact file together with its transitive imports.
+// The file itself comes first, followed by its imports in dependency order,
+// so the result can be passed directly to the To* methods.
+// If the file parses but its imports cannot be resolved, the result holds
+// only the file itself, together with the error.
+func (c *Client) ParseFileWithImports(path string) ([]*ast.SpecFile, error) {
+	spec, err := c.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered, err := resolver.NewResolver(c).ResolveFile(spec, "")
+	if err != nil {
+		return []*ast.SpecFile{spec}, err
+	}
+
+	files := []*ast.SpecFile{spec}
+	for _, f := range ordered {
+		if f != spec {
+			files = append(files, f)
+		}
+	}
+	return files, nil
 }
 
 // ParseString parses a .pact string and returns the AST.
@@ -69,24 +100,43 @@ func (c *Client) ParseString(content string) (*ast.SpecFile, error) {
 	return p.Parse()
 }
 
+// Format returns the canonical form of a .pact source, keeping its comments.
+// Sources with syntax errors are returned as an error without formatting.
+func (c *Client) Format(src []byte) ([]byte, error) {
+	return formatter.Format(src)
+}
+
 // ToClassDiagram transforms the AST to a class diagram.
-func (c *Client) ToClassDiagram(spec *ast.SpecFile) (*class.Diagram, error) {
-	return c.service.TransformClassDiagram([]*ast.SpecFile{spec}, nil)
+// Imported files are transformed together with spec so cross-file relations resolve.
+func (c *Client) ToClassDiagram(spec *ast.SpecFile, imports ...*ast.SpecFile) (*class.Diagram, error) {
+	return c.service.TransformClassDiagram(withImports(spec, imports), nil)
 }
 
 // ToSequenceDiagram transforms the AST to a sequence diagram for the given flow.
-func (c *Client) ToSequenceDiagram(spec *ast.SpecFile, flowName string) (*sequence.Diagram, error) {
-	return c.service.TransformSequenceDiagram([]*ast.SpecFile{spec}, &transformer.SequenceOptions{FlowName: flowName})
+func (c *Client) ToSequenceDiagram(spec *ast.SpecFile, flowName string, imports ...*ast.SpecFile) (*sequence.Diagram, error) {
+	return c.service.TransformSequenceDiagram(withImports(spec, imports), &transformer.SequenceOptions{FlowName: flowName})
 }
 
 // ToStateDiagram transforms the AST to a state diagram for the given states.
-func (c *Client) ToStateDiagram(spec *ast.SpecFile, statesName string) (*state.Diagram, error) {
-	return c.service.TransformStateDiagram([]*ast.SpecFile{spec}, &transformer.StateOptions{StatesName: statesName})
+func (c *Client) ToStateDiagram(spec *ast.SpecFile, statesName string, imports ...*ast.SpecFile) (*state.Diagram, error) {
+	return c.service.TransformStateDiagram(withImports(spec, imports), &transformer.StateOptions{StatesName: statesName})
 }
 
 // ToFlowchart transforms the AST to a flowchart for the given flow.
-func (c *Client) ToFlowchart(spec *ast.SpecFile, flowName string) (*flow.Diagram, error) {
-	return c.service.TransformFlowchart([]*ast.SpecFile{spec}, &transformer.FlowOptions{FlowName: flowName})
+func (c *Client) ToFlowchart(spec *ast.SpecFile, flowName string, imports ...*ast.SpecFile) (*flow.Diagram, error) {
+	return c.service.TransformFlowchart(withImports(spec, imports), &transformer.FlowOptions{FlowName: flowName})
+}
+
+// withImports returns spec followed by its imported files.
+func withImports(spec *ast.SpecFile, imports []*ast.SpecFile) []*ast.SpecFile {
+	files := make([]*ast.SpecFile, 0, len(imports)+1)
+	files = append(files, spec)
+	for _, f := range imports {
+		if f != spec {
+			files = append(files, f)
+		}
+	}
+	return files
 }
 
 // RenderClassDiagram renders a class diagram to SVG.
@@ -108,3 +158,25 @@ func (c *Client) RenderStateDiagram(diagram *state.Diagram, w io.Writer) error {
 func (c *Client) RenderFlowchart(diagram *flow.Diagram, w io.Writer) error {
 	return c.flowRenderer.Render(diagram, w)
 }
+
+// RenderClassDiagramDiff renders the changes from old to new as a single class diagram SVG.
+// Added elements are drawn in green, removed ones in red and struck through, and changed ones in amber.
+// Classes present in old keep their positions from old.
+func (c *Client) RenderClassDiagramDiff(old, new *class.Diagram, w io.Writer) error {
+	return svg.NewClassDiffRenderer().Render(old, new, w)
+}
+
+// RenderSequenceDiagramDiff renders the changes from old to new as a single sequence diagram SVG.
+func (c *Client) RenderSequenceDiagramDiff(old, new *sequence.Diagram, w io.Writer) error {
+	return svg.NewSequenceDiffRenderer().Render(old, new, w)
+}
+
+// RenderStateDiagramDiff renders the changes from old to new as a single state diagram SVG.
+func (c *Client) RenderStateDiagramDiff(old, new *state.Diagram, w io.Writer) error {
+	return svg.NewStateDiffRenderer().Render(old, new, w)
+}
+
+// RenderFlowchartDiff renders the changes from old to new as a single flowchart SVG.
+func (c *Client) RenderFlowchartDiff(old, new *flow.Diagram, w io.Writer) error {
+	return svg.NewFlowDiffRenderer().Render(old, new, w)
+}
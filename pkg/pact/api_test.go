@@ -2,8 +2,13 @@ package pact
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"pact/internal/domain/diagram/class"
+	"pact/internal/infrastructure/renderer/canvas"
 )
 
 // =============================================================================
@@ -302,3 +307,140 @@ component Process {
 		t.Error("expected valid SVG output")
 	}
 }
+
+// =============================================================================
+// A016-A017: import を含む変換
+// =============================================================================
+
+func writeSpec(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// A016: import の推移的な読み込み
+func TestAPI_ParseFileWithImports(t *testing.T) {
+	dir := t.TempDir()
+	main := writeSpec(t, dir, "order.pact", "import \"./billing/payment.pact\"\ncomponent Order { depends on Payment }")
+	writeSpec(t, dir, "billing/payment.pact", "import \"../shared/money.pact\"\ncomponent Payment { }")
+	writeSpec(t, dir, "shared/money.pact", "component Money { }")
+
+	client := New()
+	files, err := client.ParseFileWithImports(main)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(files))
+	}
+	if files[0].Path != main {
+		t.Errorf("expected the file itself first, got %q", files[0].Path)
+	}
+	if filepath.Base(files[1].Path) != "money.pact" || filepath.Base(files[2].Path) != "payment.pact" {
+		t.Errorf("expected imports in dependency order, got %q, %q", files[1].Path, files[2].Path)
+	}
+}
+
+// A017: import 先のコンポーネントがクラス図に含まれる
+func TestAPI_ToClassDiagram_WithImports(t *testing.T) {
+	dir := t.TempDir()
+	main := writeSpec(t, dir, "order.pact", "import \"./payment.pact\"\ncomponent Order { depends on Payment }")
+	writeSpec(t, dir, "payment.pact", "component Payment { }")
+
+	client := New()
+	files, err := client.ParseFileWithImports(main)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	diagram, err := client.ToClassDiagram(files[0], files[1:]...)
+	if err != nil {
+		t.Fatalf("transform error: %v", err)
+	}
+
+	nodes := make(map[string]bool)
+	for _, n := range diagram.Nodes {
+		nodes[n.ID] = true
+	}
+	for _, e := range diagram.Edges {
+		if !nodes[e.From] || !nodes[e.To] {
+			t.Errorf("dangling edge %s -> %s", e.From, e.To)
+		}
+	}
+	if !nodes["Payment"] {
+		t.Error("expected imported component Payment as a node")
+	}
+}
+
+// =============================================================================
+// A018: 整形
+// =============================================================================
+
+// A018: コメントを保持した整形
+func TestAPI_Format(t *testing.T) {
+	client := New()
+	out, err := client.Format([]byte("// users\ncomponent User{type Data{id:string}}"))
+	if err != nil {
+		t.Fatalf("format error: %v", err)
+	}
+	want := "// users\ncomponent User {\n  type Data {\n    id: string\n  }\n}\n"
+	if string(out) != want {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+// =============================================================================
+// A019: 差分の図
+// =============================================================================
+
+// A019: 2つの版のクラス図の差分を1つのSVGにレンダリング
+func TestAPI_RenderClassDiagramDiff(t *testing.T) {
+	client := New()
+	toDiagram := func(src string) *class.Diagram {
+		spec, err := client.ParseString(src)
+		if err != nil {
+			t.Fatalf("parse error: %v", err)
+		}
+		diagram, err := client.ToClassDiagram(spec)
+		if err != nil {
+			t.Fatalf("transform error: %v", err)
+		}
+		return diagram
+	}
+	old := toDiagram(`component User { type Data { id: string } }`)
+	new := toDiagram(`component User { type Data { id: string } }
+component Order { depends on User }`)
+
+	var buf bytes.Buffer
+	if err := client.RenderClassDiagramDiff(old, new, &buf); err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if !strings.Contains(buf.String(), canvas.ColorAdded) {
+		t.Error("expected added component drawn in the added color")
+	}
+}
+
+// =============================================================================
+// A020: import を解決できないファイル
+// =============================================================================
+
+// A020: import を解決できない場合は本体だけを返す
+func TestAPI_ParseFileWithImports_MissingImport(t *testing.T) {
+	dir := t.TempDir()
+	main := writeSpec(t, dir, "order.pact", "import \"./missing.pact\"\ncomponent Order { }")
+
+	client := New()
+	files, err := client.ParseFileWithImports(main)
+	if err == nil {
+		t.Fatal("expected an error for the missing import")
+	}
+	if len(files) != 1 || files[0].Path != main {
+		t.Fatalf("expected only the file itself, got %d files", len(files))
+	}
+}
@@ -1,11 +1,14 @@
 package e2e
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // =============================================================================
@@ -99,8 +102,73 @@ func TestCLI_Init_Content(t *testing.T) {
 	}
 }
 
+// E004: init のフラグが設定ファイルに反映される
+func TestCLI_Init_Flags(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	cmd := exec.Command(binary, "init",
+		"--source-root", "./app", "--pact-root", "./specs", "--output-dir", "./out",
+		"--language", "go", "--diagrams", "class,flow", "--exclude", "vendor")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("init failed: %v\noutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, ".pactconfig"))
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	for _, want := range []string{"source_root: ./app", "pact_root: ./specs", "output_dir: ./out", "- flow", "- vendor"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected config to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+// E005: init --scaffold でソース構造を反映した仕様を作成し、そのまま generate できる
+func TestCLI_Init_Scaffold(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	if err := os.MkdirAll(filepath.Join(dir, "src", "auth"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTestPactFile(t, dir, "src/auth/service.go", "package auth")
+
+	cmd := exec.Command(binary, "init", "--scaffold")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("init failed: %v\noutput: %s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".pact", "auth", "service.pact")); err != nil {
+		t.Fatal("expected .pact/auth/service.pact to be created")
+	}
+
+	cmd = exec.Command(binary, "generate")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generate failed: %v\noutput: %s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "diagrams", "auth", "service_class.svg")); err != nil {
+		t.Error("expected diagrams/auth/service_class.svg to be generated")
+	}
+}
+
+// E006: 不明なフラグ
+func TestCLI_Init_UnknownFlag(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	cmd := exec.Command(binary, "init", "--bogus")
+	cmd.Dir = dir
+	if _, err := cmd.CombinedOutput(); err == nil {
+		t.Error("expected error for unknown flag")
+	}
+}
+
 // =============================================================================
-// E010-E01A: generate コマンド
+// E010-E01F: generate コマンド
 // =============================================================================
 
 func createTestPactFile(t *testing.T, dir, name, content string) string {
@@ -313,8 +381,142 @@ func TestCLI_Generate_InvalidSpec(t *testing.T) {
 	}
 }
 
+// E01B: プロジェクトモード（引数なし）
+func TestCLI_Generate_ProjectMode(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	config := `source_root: ./src
+pact_root: ./.pact
+output_dir: ./diagrams
+diagrams:
+  - class
+exclude:
+  - "*_draft.pact"
+`
+	createTestPactFile(t, dir, ".pactconfig", config)
+	for _, sub := range []string{".pact/auth", ".pact/order", "src/auth"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createTestPactFile(t, dir, ".pact/auth/service.pact", `component AuthService { }`)
+	createTestPactFile(t, dir, ".pact/order/handler.pact", `component OrderHandler { flow Handle { return ok } }`)
+	createTestPactFile(t, dir, ".pact/order/wip_draft.pact", `component Draft { }`)
+
+	// サブディレクトリから実行してもプロジェクトルートを見つける
+	cmd := exec.Command(binary, "generate")
+	cmd.Dir = filepath.Join(dir, "src", "auth")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("generate failed: %v\noutput: %s", err, output)
+	}
+
+	for _, p := range []string{"diagrams/auth/service_class.svg", "diagrams/order/handler_class.svg"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); err != nil {
+			t.Errorf("expected %s to be generated", p)
+		}
+	}
+	// diagrams に含まれない種類・除外ファイルは生成されない
+	if _, err := os.Stat(filepath.Join(dir, "diagrams/order/handler_flow_Handle.svg")); err == nil {
+		t.Error("flow diagram should not be generated when not listed in diagrams")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "diagrams/order/wip_draft_class.svg")); err == nil {
+		t.Error("excluded file should not be generated")
+	}
+}
+
+// E01C: 設定ファイルなし・引数なし
+func TestCLI_Generate_NoArgsNoConfig(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	cmd := exec.Command(binary, "generate")
+	cmd.Dir = dir
+	if _, err := cmd.CombinedOutput(); err == nil {
+		t.Error("expected error without files and .pactconfig")
+	}
+}
+
+// E01D: import 先のコンポーネントを含めてクラス図を生成する
+func TestCLI_Generate_Imports(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	if err := os.MkdirAll(filepath.Join(dir, "billing"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTestPactFile(t, dir, "order.pact", "import \"./billing/payment.pact\"\ncomponent Order { depends on PaymentGateway }")
+	createTestPactFile(t, dir, "billing/payment.pact", `component PaymentGateway { type Receipt { id: string } }`)
+
+	cmd := exec.Command(binary, "generate", "-t", "class", "order.pact")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generate failed: %v\noutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "order_class.svg"))
+	if err != nil {
+		t.Fatalf("class diagram not generated: %v", err)
+	}
+	for _, name := range []string{"PaymentGateway", "Receipt"} {
+		if !strings.Contains(string(content), name) {
+			t.Errorf("expected imported %s in class diagram", name)
+		}
+	}
+}
+
+// E01E: 解決できない import はエラー
+func TestCLI_Generate_MissingImport(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "order.pact", "import \"./nowhere.pact\"\ncomponent Order { }")
+
+	cmd := exec.Command(binary, "generate", "order.pact")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected error for missing import, output: %s", output)
+	}
+	if !strings.Contains(string(output), "nowhere.pact") {
+		t.Errorf("expected import path in error, got: %s", output)
+	}
+}
+
+// E01F: エイリアス付き import の同名コンポーネントを区別してクラス図を生成する
+func TestCLI_Generate_QualifiedImports(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "order.pact", `import "./billing.pact" as billing
+import "./shipping.pact" as shipping
+component Order {
+  depends on billing.Gateway
+  depends on shipping.Gateway
+}`)
+	createTestPactFile(t, dir, "billing.pact", `component Gateway { }`)
+	createTestPactFile(t, dir, "shipping.pact", `component Gateway { }`)
+
+	cmd := exec.Command(binary, "generate", "-t", "class", "order.pact")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generate failed: %v\noutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "order_class.svg"))
+	if err != nil {
+		t.Fatalf("class diagram not generated: %v", err)
+	}
+	for _, name := range []string{"billing.Gateway", "shipping.Gateway"} {
+		if !strings.Contains(string(content), name) {
+			t.Errorf("expected %s in class diagram", name)
+		}
+	}
+}
+
 // =============================================================================
-// E020-E023: validate コマンド
+// E020-E02B: validate コマンド
 // =============================================================================
 
 // E020: 有効ファイルの検証
@@ -380,6 +582,232 @@ func TestCLI_Validate_ErrorOutput(t *testing.T) {
 	}
 }
 
+// runExitCode はコマンドを実行し、出力と終了コードを返す
+func runExitCode(t *testing.T, dir, binary string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return string(output), 0
+	}
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("failed to run %v: %v", args, err)
+	}
+	return string(output), exitErr.ExitCode()
+}
+
+// E024: 意味エラーと構文エラーで終了コードが異なる
+func TestCLI_Validate_ExitCodes(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "semantic.pact", `component A { depends on Missing }`)
+	createTestPactFile(t, dir, "syntax.pact", `component { broken`)
+
+	output, code := runExitCode(t, dir, binary, "validate", "semantic.pact")
+	if code != 3 {
+		t.Errorf("expected exit code 3 for semantic errors, got %d\noutput: %s", code, output)
+	}
+	if !strings.Contains(output, "Errors:") || !strings.Contains(output, "semantic.pact:1:") {
+		t.Errorf("expected positioned error, got:\n%s", output)
+	}
+
+	output, code = runExitCode(t, dir, binary, "validate", "semantic.pact", "syntax.pact")
+	if code != 2 {
+		t.Errorf("expected exit code 2 for syntax errors, got %d\noutput: %s", code, output)
+	}
+}
+
+// E025: 警告は --strict のときだけ失敗扱い
+func TestCLI_Validate_Strict(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "warn.pact", `component A { type Unused { id: string } }`)
+
+	output, code := runExitCode(t, dir, binary, "validate", "warn.pact")
+	if code != 0 {
+		t.Errorf("expected success without --strict, got %d\noutput: %s", code, output)
+	}
+	if !strings.Contains(output, "Warnings:") || !strings.Contains(output, "unused-type") {
+		t.Errorf("expected warnings section, got:\n%s", output)
+	}
+
+	output, code = runExitCode(t, dir, binary, "validate", "--strict", "warn.pact")
+	if code != 4 {
+		t.Errorf("expected exit code 4 with --strict, got %d\noutput: %s", code, output)
+	}
+}
+
+// E026: import 先の宣言を参照できる
+func TestCLI_Validate_Imports(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "order.pact", "import \"./payment.pact\"\ncomponent Order { depends on Payment }")
+	createTestPactFile(t, dir, "payment.pact", `component Payment { }`)
+
+	output, code := runExitCode(t, dir, binary, "validate", "order.pact")
+	if code != 0 {
+		t.Errorf("expected imported component to resolve, got %d\noutput: %s", code, output)
+	}
+	if strings.Contains(output, "unused-import") {
+		t.Errorf("import is referenced, got:\n%s", output)
+	}
+
+	createTestPactFile(t, dir, "broken.pact", "import \"./nowhere.pact\"\ncomponent Broken { }")
+	output, code = runExitCode(t, dir, binary, "validate", "broken.pact")
+	if code != 3 || !strings.Contains(output, "nowhere.pact") {
+		t.Errorf("expected import error, got %d\noutput: %s", code, output)
+	}
+}
+
+// E027: 引数なしでプロジェクト全体を検証
+func TestCLI_Validate_Project(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, ".pactconfig", "pact_root: ./specs\n")
+	if err := os.MkdirAll(filepath.Join(dir, "specs", "auth"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTestPactFile(t, dir, "specs/auth/service.pact", `component Auth { depends on Missing }`)
+
+	output, code := runExitCode(t, dir, binary, "validate")
+	if code != 3 || !strings.Contains(output, "Missing") {
+		t.Errorf("expected project-wide validation error, got %d\noutput: %s", code, output)
+	}
+}
+
+// E028: --format json は位置・コード・重大度を含む
+func TestCLI_Validate_FormatJSON(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "a.pact", "component A {\n  depends on Missing\n}")
+
+	cmd := exec.Command(binary, "validate", "--format", "json", "a.pact")
+	cmd.Dir = dir
+	stdout, _ := cmd.Output()
+
+	var out struct {
+		Diagnostics []struct {
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+			Column   int    `json:"column"`
+			Code     string `json:"code"`
+			Severity string `json:"severity"`
+		} `json:"diagnostics"`
+		Errors int `json:"errors"`
+	}
+	if err := json.Unmarshal(stdout, &out); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if out.Errors != 1 || len(out.Diagnostics) != 1 {
+		t.Fatalf("unexpected diagnostics: %s", stdout)
+	}
+	d := out.Diagnostics[0]
+	if d.File != "a.pact" || d.Line != 2 || d.Column == 0 || d.Code != "undefined" || d.Severity != "error" {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
+
+// E029: --format sarif は SARIF 2.1.0 のログを出力する
+func TestCLI_Validate_FormatSARIF(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "broken.pact", `component { broken`)
+
+	cmd := exec.Command(binary, "validate", "--format", "sarif", "broken.pact")
+	cmd.Dir = dir
+	stdout, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 2 {
+		t.Errorf("expected exit code 2, got %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(stdout, &log); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) == 0 {
+		t.Fatalf("unexpected SARIF: %s", stdout)
+	}
+	if r := log.Runs[0].Results[0]; r.RuleID != "syntax-error" || r.Level != "error" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+}
+
+// E02A: 修飾名の未知のエイリアス・未宣言の名前は意味エラー
+func TestCLI_Validate_QualifiedReferences(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "billing.pact", `component PaymentService { type Invoice { id: string } }`)
+	createTestPactFile(t, dir, "order.pact", `import "./billing.pact" as billing
+component Order {
+  type Receipt { invoice: billing.Invoice }
+  depends on billing.PaymentService
+}`)
+	createTestPactFile(t, dir, "bad.pact", `import "./billing.pact" as billing
+component Order {
+  depends on billing.Refund
+  depends on ledger.Book
+}`)
+
+	if output, code := runExitCode(t, dir, binary, "validate", "order.pact"); code != 0 {
+		t.Errorf("expected qualified references to resolve, got %d\noutput: %s", code, output)
+	}
+
+	output, code := runExitCode(t, dir, binary, "validate", "bad.pact")
+	if code != 3 {
+		t.Errorf("expected exit code 3, got %d\noutput: %s", code, output)
+	}
+	for _, want := range []string{"'Refund' is not declared in import 'billing'", "unknown import alias 'ledger'"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+// E02B: dir/... は再帰的に展開し、.pactignore の対象を除いてパス順に検証する
+func TestCLI_Validate_RecursivePattern(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, ".pactignore", "drafts\n")
+	for _, sub := range []string{"specs/billing", "specs/drafts"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createTestPactFile(t, dir, "specs/order.pact", `component Order { }`)
+	createTestPactFile(t, dir, "specs/billing/invoice.pact", `component Invoice { }`)
+	createTestPactFile(t, dir, "specs/drafts/broken.pact", `component { broken`)
+
+	for _, pattern := range []string{"specs/...", "specs/**/*.pact"} {
+		output, code := runExitCode(t, dir, binary, "validate", pattern)
+		if code != 0 {
+			t.Errorf("%s: expected ignored draft to be skipped, got %d\noutput: %s", pattern, code, output)
+		}
+		billing := strings.Index(output, filepath.Join("specs", "billing", "invoice.pact"))
+		order := strings.Index(output, filepath.Join("specs", "order.pact"))
+		if billing < 0 || order < 0 || billing > order {
+			t.Errorf("%s: expected nested specs in path order, got:\n%s", pattern, output)
+		}
+	}
+}
+
 // =============================================================================
 // E030-E034: check コマンド
 // =============================================================================
@@ -459,21 +887,1430 @@ func TestCLI_Check_ParseError(t *testing.T) {
 	_ = output
 }
 
-// =============================================================================
-// E040-E042: watch コマンド
-// =============================================================================
+// E035: プロジェクトのカバレッジ（欠落・孤立した仕様）
+func TestCLI_Check_Missing_Coverage(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
 
-// E040: ファイル変更監視
-func TestCLI_Watch_FileChange(t *testing.T) {
-	t.Skip("watch command requires background process and file system events")
+	createTestPactFile(t, dir, ".pactconfig", "source_root: ./src\npact_root: ./.pact\nlanguage: go\n")
+	for _, sub := range []string{"src/auth", "src/order", ".pact/auth", ".pact/legacy"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createTestPactFile(t, dir, "src/auth/service.go", "package auth")
+	createTestPactFile(t, dir, "src/order/handler.go", "package order")
+	createTestPactFile(t, dir, ".pact/auth/service.pact", "component Service { }")
+	createTestPactFile(t, dir, ".pact/legacy/old.pact", "component Old { }")
+
+	cmd := exec.Command(binary, "check", "--missing")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("check failed below default threshold: %v\noutput: %s", err, output)
+	}
+
+	outputStr := string(output)
+	for _, want := range []string{
+		"src/order/handler.go -> .pact/order/handler.pact",
+		"Orphaned specs (1)",
+		".pact/legacy/old.pact",
+		"Coverage: 50.0% (1/2 source files)",
+	} {
+		if !strings.Contains(outputStr, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, outputStr)
+		}
+	}
 }
 
-// E041: 新規ファイル監視
-func TestCLI_Watch_NewFile(t *testing.T) {
-	t.Skip("watch command requires background process and file system events")
+// E036: 閾値未満で非ゼロ終了
+func TestCLI_Check_Missing_Threshold(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, ".pactconfig", "source_root: ./src\ncoverage_threshold: 80\n")
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTestPactFile(t, dir, "src/main.go", "package main")
+
+	cmd := exec.Command(binary, "check", "--missing")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected failure below configured threshold, output: %s", output)
+	}
+	if !strings.Contains(string(output), "below threshold 80.0%") {
+		t.Errorf("expected threshold message, got: %s", output)
+	}
+
+	// コマンドラインの閾値が設定より優先される
+	cmd = exec.Command(binary, "check", "--missing", "--threshold", "0")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("expected success with --threshold 0: %v\noutput: %s", err, output)
+	}
 }
 
-// E042: ファイル削除監視
-func TestCLI_Watch_DeleteFile(t *testing.T) {
-	t.Skip("watch command requires background process and file system events")
+// E037: check --missing --format json でカバレッジを出力
+func TestCLI_Check_Missing_FormatJSON(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, ".pactconfig", "source_root: ./src\n")
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTestPactFile(t, dir, "src/main.go", "package main")
+
+	cmd := exec.Command(binary, "check", "--missing", "--format", "json")
+	cmd.Dir = dir
+	stdout, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+
+	var out struct {
+		Diagnostics []struct {
+			File string `json:"file"`
+			Code string `json:"code"`
+		} `json:"diagnostics"`
+		Properties map[string]float64 `json:"properties"`
+	}
+	if err := json.Unmarshal(stdout, &out); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if len(out.Diagnostics) != 1 || out.Diagnostics[0].Code != "missing-spec" || out.Diagnostics[0].File != "src/main.go" {
+		t.Errorf("unexpected diagnostics: %s", stdout)
+	}
+	if out.Properties["coverage"] != 0 || out.Properties["sources"] != 1 {
+		t.Errorf("unexpected properties: %v", out.Properties)
+	}
+}
+
+// E038: import 先のコンポーネントは欠落扱いにしない
+func TestCLI_Check_Missing_Imports(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	if err := os.MkdirAll(filepath.Join(dir, "specs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTestPactFile(t, dir, "specs/order.pact", "import \"../lib/payment.pact\"\ncomponent Order { depends on Payment }")
+	if err := os.MkdirAll(filepath.Join(dir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTestPactFile(t, dir, "lib/payment.pact", `component Payment { }`)
+
+	output, code := runExitCode(t, dir, binary, "check", "--missing", "specs")
+	if code != 0 || !strings.Contains(output, "No missing components") {
+		t.Errorf("expected imported component to count as declared, got %d\noutput: %s", code, output)
+	}
+}
+
+// E039: 推移的な import の循環を検出する
+func TestCLI_Validate_ImportCycle(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "a.pact", "import \"./b.pact\"\ncomponent A { depends on B }")
+	createTestPactFile(t, dir, "b.pact", "import \"./c.pact\"\ncomponent B { depends on C }")
+	createTestPactFile(t, dir, "c.pact", "import \"./a.pact\"\ncomponent C { depends on A }")
+
+	output, code := runExitCode(t, dir, binary, "validate", "a.pact")
+	if code != 3 || !strings.Contains(output, "import-cycle") {
+		t.Errorf("expected import cycle error, got %d\noutput: %s", code, output)
+	}
+}
+
+// =============================================================================
+// E040-E042: watch コマンド
+// =============================================================================
+
+// startWatch は watch コマンドをバックグラウンドで起動する
+func startWatch(t *testing.T, binary, dir string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(binary, "watch", "--interval", "20ms", "--debounce", "20ms", ".")
+	cmd.Dir = dir
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start watch: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd
+}
+
+// waitForFile はファイルが作成されるまで待つ
+func waitForFile(t *testing.T, path string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", filepath.Base(path))
+}
+
+// E040: ファイル変更監視（インポート元も再生成される）
+func TestCLI_Watch_FileChange(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "a.pact", `import "./b.pact"
+component A { depends on B }`)
+	createTestPactFile(t, dir, "b.pact", `component B { }`)
+
+	startWatch(t, binary, dir)
+	waitForFile(t, filepath.Join(dir, "a_class.svg"))
+	waitForFile(t, filepath.Join(dir, "b_class.svg"))
+
+	// 初回生成の出力を消して、b.pact の変更で a.pact も再生成されることを確認
+	_ = os.Remove(filepath.Join(dir, "a_class.svg"))
+	time.Sleep(50 * time.Millisecond)
+	createTestPactFile(t, dir, "b.pact", `component B { type Data { id: string } }`)
+
+	waitForFile(t, filepath.Join(dir, "a_class.svg"))
+}
+
+// E041: 新規ファイル監視
+func TestCLI_Watch_NewFile(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "a.pact", `component A { }`)
+
+	startWatch(t, binary, dir)
+	waitForFile(t, filepath.Join(dir, "a_class.svg"))
+
+	createTestPactFile(t, dir, "c.pact", `component C { }`)
+	waitForFile(t, filepath.Join(dir, "c_class.svg"))
+}
+
+// E042: ファイル削除監視（インポート元が再生成される）
+func TestCLI_Watch_DeleteFile(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "a.pact", `import "./b.pact"
+component A { }`)
+	createTestPactFile(t, dir, "b.pact", `component B { }`)
+
+	startWatch(t, binary, dir)
+	waitForFile(t, filepath.Join(dir, "a_class.svg"))
+
+	_ = os.Remove(filepath.Join(dir, "a_class.svg"))
+	time.Sleep(50 * time.Millisecond)
+	if err := os.Remove(filepath.Join(dir, "b.pact")); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForFile(t, filepath.Join(dir, "a_class.svg"))
+}
+
+// E043: パースエラーでも監視を継続する
+func TestCLI_Watch_ParseErrorContinues(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	createTestPactFile(t, dir, "broken.pact", `component { broken`)
+
+	startWatch(t, binary, dir)
+	time.Sleep(100 * time.Millisecond)
+
+	// 監視が継続していれば新しいファイルの図が生成される
+	createTestPactFile(t, dir, "ok.pact", `component Ok { }`)
+	waitForFile(t, filepath.Join(dir, "ok_class.svg"))
+}
+
+// =============================================================================
+// E050-E053: fmt コマンド
+// =============================================================================
+
+const unformattedSpec = `// orders
+component Order{
+type Item{+id:string // key
+}
+depends on Repo
+}
+`
+
+const formattedSpec = `// orders
+component Order {
+  type Item {
+    +id: string // key
+  }
+
+  depends on Repo
+}
+`
+
+// E050: 整形結果を標準出力に出力する
+func TestCLI_Fmt_Stdout(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	path := createTestPactFile(t, dir, "order.pact", unformattedSpec)
+
+	cmd := exec.Command(binary, "fmt", path)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("fmt failed: %v", err)
+	}
+	if string(output) != formattedSpec {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != unformattedSpec {
+		t.Error("fmt without -w should not modify the file")
+	}
+}
+
+// E051: -w でファイルを書き換える（2回目は変化しない）
+func TestCLI_Fmt_Write(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	path := createTestPactFile(t, dir, "order.pact", unformattedSpec)
+
+	for i := 0; i < 2; i++ {
+		if output, code := runExitCode(t, dir, binary, "fmt", "-w", path); code != 0 {
+			t.Fatalf("fmt -w failed (%d): %s", code, output)
+		}
+		content, _ := os.ReadFile(path)
+		if string(content) != formattedSpec {
+			t.Errorf("unexpected content after run %d:\n%s", i+1, content)
+		}
+	}
+}
+
+// E052: -l と --check で未整形ファイルを一覧する
+func TestCLI_Fmt_ListAndCheck(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	createTestPactFile(t, dir, "messy.pact", unformattedSpec)
+	createTestPactFile(t, dir, "clean.pact", formattedSpec)
+
+	output, code := runExitCode(t, dir, binary, "fmt", "-l", ".")
+	if code != 0 {
+		t.Errorf("fmt -l should succeed, got %d: %s", code, output)
+	}
+	if !strings.Contains(output, "messy.pact") || strings.Contains(output, "clean.pact") {
+		t.Errorf("expected only messy.pact to be listed, got:\n%s", output)
+	}
+
+	output, code = runExitCode(t, dir, binary, "fmt", "--check", ".")
+	if code != 1 {
+		t.Errorf("fmt --check should exit 1, got %d: %s", code, output)
+	}
+	if !strings.Contains(output, "messy.pact") {
+		t.Errorf("expected messy.pact in output, got:\n%s", output)
+	}
+
+	if output, code := runExitCode(t, dir, binary, "fmt", "--check", "clean.pact"); code != 0 {
+		t.Errorf("fmt --check on formatted file should exit 0, got %d: %s", code, output)
+	}
+}
+
+// E053: 構文エラーは書き換えずに終了コード2
+func TestCLI_Fmt_SyntaxError(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	path := createTestPactFile(t, dir, "broken.pact", `component { broken`)
+
+	output, code := runExitCode(t, dir, binary, "fmt", "-w", path)
+	if code != 2 {
+		t.Errorf("expected exit code 2, got %d: %s", code, output)
+	}
+	if !strings.Contains(output, "broken.pact:1:") {
+		t.Errorf("expected positioned error, got:\n%s", output)
+	}
+	content, _ := os.ReadFile(path)
+	if string(content) != `component { broken` {
+		t.Error("file with syntax errors should not be modified")
+	}
+}
+
+// =============================================================================
+// E060: lsp コマンド
+// =============================================================================
+
+// lspFrame は Content-Length ヘッダ付きの JSON-RPC メッセージを作る
+func lspFrame(body string) string {
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+// E060: stdio で initialize から exit まで応答する
+func TestCLI_LSP_Session(t *testing.T) {
+	binary := buildCLI(t)
+
+	input := lspFrame(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`) +
+		lspFrame(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///tmp/x.pact","languageId":"pact","version":1,"text":"component X {"}}}`) +
+		lspFrame(`{"jsonrpc":"2.0","id":2,"method":"shutdown"}`) +
+		lspFrame(`{"jsonrpc":"2.0","method":"exit"}`)
+
+	cmd := exec.Command(binary, "lsp", "--stdio")
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("lsp failed: %v\n%s", err, output)
+	}
+
+	out := string(output)
+	if !strings.Contains(out, `"definitionProvider":true`) {
+		t.Errorf("expected capabilities in output:\n%s", out)
+	}
+	if !strings.Contains(out, `"textDocument/publishDiagnostics"`) || !strings.Contains(out, `"syntax-error"`) {
+		t.Errorf("expected syntax diagnostics in output:\n%s", out)
+	}
+	if !strings.Contains(out, `"id":2,"result":null`) {
+		t.Errorf("expected shutdown response in output:\n%s", out)
+	}
+}
+
+// =============================================================================
+// E070-E071: scaffold コマンド
+// =============================================================================
+
+// writeFiles は dir からの相対パスと内容の組でファイルを作成する
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// goProject は scaffold・verify 用の Go プロジェクト
+var goProject = map[string]string{
+	".pactconfig": "source_root: ./src\npact_root: ./.pact\nlanguage: go\n",
+	"go.mod":      "module example.com/shop\n\ngo 1.21\n",
+	"src/payment/gateway.go": `package payment
+
+import "context"
+
+type Gateway interface {
+	Charge(ctx context.Context, orderID string, amount int) (string, error)
+}
+`,
+	"src/order/service.go": `package order
+
+import "example.com/shop/src/payment"
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusPaid    Status = "paid"
+)
+
+type Order struct {
+	ID     string
+	Status Status
+	note   *string
+}
+
+type Service struct {
+	gateway payment.Gateway
+}
+
+func NewService(gateway payment.Gateway) *Service { return &Service{gateway: gateway} }
+`,
+}
+
+// E070: Go のソースから仕様を生成し、生成結果が検証を通る
+func TestCLI_Scaffold_Go(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, goProject)
+
+	out, code := runExitCode(t, dir, binary, "scaffold", "go")
+	if code != 0 {
+		t.Fatalf("scaffold failed (%d): %s", code, out)
+	}
+	if !strings.Contains(out, "Scaffolded 2 spec(s)") {
+		t.Errorf("expected summary, got: %s", out)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, ".pact", "order", "service.pact"))
+	if err != nil {
+		t.Fatalf("expected generated spec: %v", err)
+	}
+	for _, want := range []string{
+		"// Source: src/order/service.go",
+		`import "../payment/gateway.pact"`,
+		"enum Status {",
+		"-note: string?",
+		"depends on Gateway",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %q in generated spec:\n%s", want, content)
+		}
+	}
+
+	if out, code := runExitCode(t, dir, binary, "validate"); code != 0 {
+		t.Errorf("generated specs should be valid (%d): %s", code, out)
+	}
+}
+
+// E071: 既存の仕様は --force なしでは上書きしない
+func TestCLI_Scaffold_KeepExisting(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, goProject)
+	writeFiles(t, dir, map[string]string{".pact/order/service.pact": "component Service {}\n"})
+
+	out, code := runExitCode(t, dir, binary, "scaffold", "go")
+	if code != 0 {
+		t.Fatalf("scaffold failed (%d): %s", code, out)
+	}
+	if !strings.Contains(out, "1 existing spec(s) kept") {
+		t.Errorf("expected skipped count, got: %s", out)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, ".pact", "order", "service.pact"))
+	if string(content) != "component Service {}\n" {
+		t.Errorf("existing spec was overwritten:\n%s", content)
+	}
+
+	if out, code := runExitCode(t, dir, binary, "scaffold", "go", "--force"); code != 0 {
+		t.Fatalf("scaffold --force failed (%d): %s", code, out)
+	}
+	content, _ = os.ReadFile(filepath.Join(dir, ".pact", "order", "service.pact"))
+	if !strings.Contains(string(content), "type Order {") {
+		t.Errorf("expected spec to be overwritten with --force:\n%s", content)
+	}
+}
+
+// =============================================================================
+// E080-E081: verify コマンド
+// =============================================================================
+
+// E080: scaffold した仕様はコードと一致する
+func TestCLI_Verify_Match(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, goProject)
+
+	if out, code := runExitCode(t, dir, binary, "scaffold", "go"); code != 0 {
+		t.Fatalf("scaffold failed (%d): %s", code, out)
+	}
+	out, code := runExitCode(t, dir, binary, "verify")
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, out)
+	}
+	if !strings.Contains(out, "All specs match the code!") {
+		t.Errorf("expected success message, got: %s", out)
+	}
+}
+
+// E081: 仕様とコードの差分を validate と同じ形式で報告する
+func TestCLI_Verify_Drift(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, goProject)
+	writeFiles(t, dir, map[string]string{
+		".pact/order/service.pact": `import "../payment/gateway.pact"
+
+component Service {
+  enum Status {
+    Pending
+    Cancelled
+  }
+
+  type Order {
+    +ID: int
+    -note: string?
+    +Total: float
+  }
+
+  depends on Gateway
+}
+`,
+		".pact/payment/gateway.pact": `component Gateway {
+  provides Gateway {
+    Charge(orderID: string, amount: int) -> string throws Error
+  }
+}
+`,
+	})
+
+	out, code := runExitCode(t, dir, binary, "verify")
+	if code != 3 {
+		t.Fatalf("expected exit 3, got %d: %s", code, out)
+	}
+	for _, want := range []string{
+		".pact/order/service.pact:4:3: error[drift-missing]: 'Status.Cancelled'",
+		"error[drift-extra]: 'Status.Paid'",
+		".pact/order/service.pact:10:6: error[drift-mismatch]: 'Order.ID': type mismatch: spec int, code string",
+		"error[drift-missing]: 'Order.Total'",
+		".pact/payment/gateway.pact:3:5: error[drift-mismatch]: 'Gateway.Charge': async mismatch",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "'Gateway': dependency") {
+		t.Errorf("declared dependency should match the code:\n%s", out)
+	}
+}
+
+// =============================================================================
+// E090-E091: codegen コマンド
+// =============================================================================
+
+// E090: Go のコードを生成し、再実行しても内容は変わらない
+func TestCLI_Codegen_Go(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	createTestPactFile(t, dir, "auth.pact", `component AuthService {
+  type User {
+    +id: string
+    -passwordHash: string
+  }
+  depends on UserRepository
+  provides AuthService {
+    async Login(email: string) -> User? throws InvalidCredentials
+  }
+}
+`)
+
+	out, code := runExitCode(t, dir, binary, "codegen", "go", "-o", "model", "auth.pact")
+	if code != 0 {
+		t.Fatalf("codegen failed (%d): %s", code, out)
+	}
+	path := filepath.Join(dir, "model", "auth_gen.go")
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected generated file: %v", err)
+	}
+	for _, want := range []string{
+		"package model",
+		"ErrInvalidCredentials = errors.New(\"invalid credentials\")",
+		"Login(ctx context.Context, email string) (*User, error)",
+		"func NewAuthService(userRepository UserRepository) *AuthServiceImpl {",
+	} {
+		if !strings.Contains(string(first), want) {
+			t.Errorf("expected %q in generated code:\n%s", want, first)
+		}
+	}
+
+	out, code = runExitCode(t, dir, binary, "codegen", "go", "-o", "model", "auth.pact")
+	if code != 0 || !strings.Contains(out, "0 file(s), 1 unchanged") {
+		t.Errorf("expected second run to be a no-op (%d): %s", code, out)
+	}
+	second, _ := os.ReadFile(path)
+	if string(first) != string(second) {
+		t.Errorf("generated code changed on the second run")
+	}
+}
+
+// E091: 未知の生成先はエラー
+func TestCLI_Codegen_UnknownTarget(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	out, code := runExitCode(t, dir, binary, "codegen", "cobol", "x.pact")
+	if code == 0 || !strings.Contains(out, "unknown codegen target: cobol") {
+		t.Errorf("expected unknown target error (%d): %s", code, out)
+	}
+}
+
+// =============================================================================
+// E100-E101: codegen ts
+// =============================================================================
+
+// E100: TypeScript の型を生成し、import 先の型は import type で取り込む
+func TestCLI_Codegen_TypeScript(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"model.pact": `component Model {
+  type User {
+    +id: string
+    +createdAt: datetime
+  }
+}
+`,
+		"auth.pact": `import "./model.pact"
+
+component Auth {
+  enum Role { Admin Member }
+  provides AuthService {
+    async Login(email: string) -> User? throws InvalidCredentials
+  }
+}
+`,
+	})
+
+	out, code := runExitCode(t, dir, binary, "codegen", "ts", "--dts", "-o", "web", "auth.pact", "model.pact")
+	if code != 0 {
+		t.Fatalf("codegen failed (%d): %s", code, out)
+	}
+	auth, err := os.ReadFile(filepath.Join(dir, "web", "auth.d.ts"))
+	if err != nil {
+		t.Fatalf("expected generated file: %v", err)
+	}
+	for _, want := range []string{
+		`import type { User } from "./model";`,
+		`export type Role = "Admin" | "Member";`,
+		"Login(email: string): Promise<User | null>;",
+	} {
+		if !strings.Contains(string(auth), want) {
+			t.Errorf("expected %q in generated code:\n%s", want, auth)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "web", "model.d.ts")); err != nil {
+		t.Errorf("expected model.d.ts: %v", err)
+	}
+}
+
+// E101: language と型の対応表は .pactconfig から読む
+func TestCLI_Codegen_TypeScriptConfig(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		".pactconfig": `source_root: ./src
+pact_root: ./.pact
+language: typescript
+codegen:
+  typescript:
+    types:
+      datetime: Date
+`,
+		".pact/event.pact": `component Event {
+  type Event {
+    +at: datetime
+  }
+}
+`,
+	})
+
+	out, code := runExitCode(t, dir, binary, "codegen", "-o", "web")
+	if code != 0 {
+		t.Fatalf("codegen failed (%d): %s", code, out)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "web", "event.ts"))
+	if err != nil {
+		t.Fatalf("expected generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "  at: Date;") {
+		t.Errorf("expected configured type mapping:\n%s", content)
+	}
+}
+
+// =============================================================================
+// E102: codegen fsm
+// =============================================================================
+
+// E102: 生成したステートマシンをコンパイルし、階層・並行状態・ガード・時間遷移の振る舞いを確かめる
+func TestCLI_Codegen_FSM(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/job\n\ngo 1.21\n",
+		"job.pact": `component Job {
+  states JobState {
+    initial Idle
+    final Done
+
+    state Idle {
+      entry [resetCounter]
+    }
+
+    state Active {
+      initial Running
+      exit [cleanup]
+      state Running {}
+      state Paused {}
+      Running -> Paused on pause
+      Paused -> Running on resume when !expired
+    }
+
+    parallel Checkout {
+      region Payment {
+        initial Authorizing
+        state Authorizing {}
+        state Charged {}
+        Authorizing -> Charged on charged
+      }
+      region Shipping {
+        initial Packing
+        state Packing {}
+        state Shipped {}
+        Packing -> Shipped on shipped
+      }
+    }
+
+    Idle -> Active on start do [logStart]
+    Active -> Idle after 5m
+    Active -> Checkout on checkout
+    Checkout -> Done when allDone
+  }
+}
+`,
+		"job_test.go": `package job
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type recorder struct{ calls []string }
+
+func (r *recorder) ResetCounter() { r.calls = append(r.calls, "resetCounter") }
+func (r *recorder) Cleanup()      { r.calls = append(r.calls, "cleanup") }
+func (r *recorder) LogStart()     { r.calls = append(r.calls, "logStart") }
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func TestMachine(t *testing.T) {
+	rec := &recorder{}
+	clk := &clock{now: time.Unix(0, 0)}
+	expired, done := false, false
+	m := NewJobMachine(rec, JobGuards{
+		Expired: func() bool { return expired },
+		AllDone: func() bool { return done },
+	}, clk)
+
+	check := func(want string) {
+		t.Helper()
+		if got := fmt.Sprint(m.State()); got != want {
+			t.Fatalf("state = %s, want %s", got, want)
+		}
+	}
+
+	m.Start()
+	check("[Idle]")
+	if !m.Fire(JobEventStart) {
+		t.Fatal("start should fire")
+	}
+	check("[Running]")
+	if !m.Is(JobStateActive) {
+		t.Fatal("Active should be active")
+	}
+	m.Fire(JobEventPause)
+	expired = true
+	if m.Fire(JobEventResume) {
+		t.Fatal("guarded resume should be ignored")
+	}
+	check("[Paused]")
+
+	clk.now = clk.now.Add(5 * time.Minute)
+	if !m.Tick() {
+		t.Fatal("after 5m should fire")
+	}
+	check("[Idle]")
+
+	m.Fire(JobEventStart)
+	m.Fire(JobEventCheckout)
+	check("[Authorizing Packing]")
+	m.Fire(JobEventShipped)
+	check("[Authorizing Shipped]")
+	done = true
+	m.Fire(JobEventCharged)
+	check("[Done]")
+	if !m.Done() {
+		t.Fatal("machine should be done")
+	}
+
+	want := "[resetCounter logStart cleanup resetCounter logStart cleanup]"
+	if got := fmt.Sprint(rec.calls); got != want {
+		t.Fatalf("actions = %s, want %s", got, want)
+	}
+}
+`,
+	})
+
+	out, code := runExitCode(t, dir, binary, "codegen", "fsm", "-p", "job", "job.pact")
+	if code != 0 {
+		t.Fatalf("codegen failed (%d): %s", code, out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "job_fsm.go")); err != nil {
+		t.Fatalf("expected job_fsm.go: %v", err)
+	}
+
+	cmd := exec.Command("go", "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	if result, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated machine failed its test: %v\n%s", err, result)
+	}
+}
+
+// =============================================================================
+// E110-E112: simulate
+// =============================================================================
+
+const simulateSpec = `component Order {
+  states OrderState {
+    initial Pending
+    final Delivered
+    state Pending {
+      entry [createOrder]
+    }
+    state Active {
+      initial Packing
+      state Packing {}
+      state Packed {}
+      Packing -> Packed on packed
+    }
+    Pending -> Active on pay when order.paid do [charge]
+    Pending -> Cancelled after 30m
+    Active -> Delivered on deliver
+  }
+}
+`
+
+// E110: スクリプトの各ステップで状態の構成・アクション・無視されたイベントを表示する
+func TestCLI_Simulate_Script(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	createTestPactFile(t, dir, "order.pact", simulateSpec)
+
+	out, code := runExitCode(t, dir, binary, "simulate", "-e", "pay order.paid=true pay packed refund", "order.pact")
+	if code != 0 {
+		t.Fatalf("simulate failed (%d): %s", code, out)
+	}
+	for _, want := range []string{
+		"init\n  entry  createOrder (Pending)\n  state  Pending\n",
+		"pay\n  ignored (no enabled transition)\n  unbound order.paid\n  state  Pending\n",
+		"  take   Pending -> Active on pay when order.paid\n  do     charge\n  state  Active/Packing\n",
+		"packed\n  take   Packing -> Packed on packed\n  state  Active/Packed\n",
+		"refund\n  ignored (unknown event)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+// E111: 時間を進めた after の遷移と、通った経路を強調した状態図
+func TestCLI_Simulate_Trace(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	createTestPactFile(t, dir, "order.pact", simulateSpec)
+	createTestPactFile(t, dir, "timeout.txt", "# no payment\n+10m\n+20m\n")
+
+	out, code := runExitCode(t, dir, binary, "simulate", "--states", "OrderState", "--trace", "trace.svg", "order.pact", "timeout.txt")
+	if code != 0 {
+		t.Fatalf("simulate failed (%d): %s", code, out)
+	}
+	if !strings.Contains(out, "+20m (t=30m)\n  take   Pending -> Cancelled after 30m\n  state  Cancelled\n") {
+		t.Errorf("expected the timeout transition:\n%s", out)
+	}
+	svg, err := os.ReadFile(filepath.Join(dir, "trace.svg"))
+	if err != nil {
+		t.Fatalf("expected trace diagram: %v", err)
+	}
+	if !strings.Contains(string(svg), "#dd6b20") {
+		t.Errorf("expected the visited path to be highlighted")
+	}
+
+	out, code = runExitCode(t, dir, binary, "simulate", "--states", "Missing", "order.pact")
+	if code == 0 || !strings.Contains(out, "states not found: Missing (available: OrderState)") {
+		t.Errorf("expected unknown states error (%d): %s", code, out)
+	}
+}
+
+// E112: 対話モード
+func TestCLI_Simulate_REPL(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	createTestPactFile(t, dir, "order.pact", simulateSpec)
+
+	cmd := exec.Command(binary, "simulate", "-i", "order.pact")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader("order.paid=true pay\n:state\n:reset\n:events\n:quit\n")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("simulate -i failed: %v\n%s", err, output)
+	}
+	for _, want := range []string{
+		"Simulating OrderState.",
+		"  state  Active/Packing (t=0s)",
+		"  events pay, deliver, packed",
+	} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Count(string(output), "init\n") != 2 {
+		t.Errorf("expected :reset to restart the machine:\n%s", output)
+	}
+}
+
+// =============================================================================
+// E120-E121: check --cycles
+// =============================================================================
+
+// E120: ファイルをまたぐ depends on の循環を、各関係の位置とともに報告する
+func TestCLI_Check_Cycles(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"a.pact": "component A {\n  depends on B\n}\n",
+		"b.pact": "component B {\n  depends on C\n  depends on D\n}\n\ncomponent D {}\n",
+		"c.pact": "component C {\n  depends on A\n}\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "check", "--cycles", "--cycles-diagram", "cycles.svg", ".")
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d: %s", code, out)
+	}
+	for _, want := range []string{
+		"  A -> B -> C -> A\n",
+		"    a.pact:2:3: A depends on B\n",
+		"    b.pact:2:3: B depends on C\n",
+		"    c.pact:2:3: C depends on A\n",
+		"dependency cycles found",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	svg, err := os.ReadFile(filepath.Join(dir, "cycles.svg"))
+	if err != nil {
+		t.Fatalf("expected cycles diagram: %v", err)
+	}
+	if !strings.Contains(string(svg), "#dd6b20") {
+		t.Errorf("expected the cycle to be highlighted")
+	}
+
+	out, code = runExitCode(t, dir, binary, "check", "--cycles", "--format", "json", ".")
+	if code != 1 || !strings.Contains(out, `"code": "dependency-cycle"`) || !strings.Contains(out, `"file": "a.pact"`) {
+		t.Errorf("expected a dependency-cycle diagnostic (%d): %s", code, out)
+	}
+}
+
+// E121: 循環がなければ成功する
+func TestCLI_Check_NoCycles(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"a.pact": "component A {\n  depends on B\n}\n\ncomponent B {}\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "check", "--cycles", ".")
+	if code != 0 || !strings.Contains(out, "No dependency cycles") {
+		t.Errorf("expected success (%d): %s", code, out)
+	}
+}
+
+// =============================================================================
+// E122-E124: check --rules
+// =============================================================================
+
+// writeLayeredProject はレイヤーのアノテーションを付けた仕様と依存関係のルールを書き出す
+func writeLayeredProject(t *testing.T, dir string) {
+	writeFiles(t, dir, map[string]string{
+		".pactconfig": `pact_root: ./.pact
+rules:
+  - name: domain-independence
+    from: { annotation: '@layer("domain")' }
+    forbid:
+      - path: infra/**
+  - name: external-via-api
+    message: call external services from the api layer
+    to: { target_type: external }
+    allow_from:
+      - annotation: '@layer("api")'
+`,
+		".pact/api/order.pact":        "@layer(\"api\")\ncomponent OrderController {\n  depends on OrderService\n  depends on PaymentGateway: external\n}\n",
+		".pact/domain/order.pact":     "@layer(\"domain\")\ncomponent OrderService {\n  depends on OrderRepository\n  depends on Mailer: external\n}\n",
+		".pact/infra/repository.pact": "@layer(\"infrastructure\")\ncomponent OrderRepository {}\n",
+	})
+}
+
+// E122: ルールへの違反をルール名と関係の位置とともに報告する
+func TestCLI_Check_Rules(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeLayeredProject(t, dir)
+
+	out, code := runExitCode(t, dir, binary, "check", "--rules", ".pact/...")
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d: %s", code, out)
+	}
+	for _, want := range []string{
+		"  .pact/domain/order.pact:3:3: rule 'domain-independence': OrderService depends on OrderRepository is forbidden\n",
+		"  .pact/domain/order.pact:4:3: rule 'external-via-api': OrderService depends on Mailer is not allowed from 'OrderService' (call external services from the api layer)\n",
+		"dependency rule violations found",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "OrderController") {
+		t.Errorf("api layer should not violate the rules:\n%s", out)
+	}
+
+	out, code = runExitCode(t, dir, binary, "check", "--rules", "--format", "json", ".pact/...")
+	if code != 1 || !strings.Contains(out, `"code": "dependency-rule"`) || !strings.Contains(out, "rule 'domain-independence'") {
+		t.Errorf("expected dependency-rule diagnostics (%d): %s", code, out)
+	}
+}
+
+// E123: 違反がなければ成功する
+func TestCLI_Check_RulesPass(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeLayeredProject(t, dir)
+	writeFiles(t, dir, map[string]string{
+		".pact/domain/order.pact": "@layer(\"domain\")\ncomponent OrderService {}\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "check", "--rules", ".pact/...")
+	if code != 0 || !strings.Contains(out, "No dependency rule violations") {
+		t.Errorf("expected success (%d): %s", code, out)
+	}
+}
+
+// E124: 不正なルールは設定のエラー
+func TestCLI_Check_InvalidRules(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		".pactconfig": "rules:\n  - name: no-condition\n",
+		"a.pact":      "component A {}\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "check", "--rules", ".")
+	if code == 0 || !strings.Contains(out, "no-condition") {
+		t.Errorf("expected a config error (%d): %s", code, out)
+	}
+}
+
+// =============================================================================
+// E125-E127: diff
+// =============================================================================
+
+const diffOldSpec = `component OrderService {
+  type Order {
+    id: string
+  }
+
+  provides OrderAPI {
+    Create(amount: float) -> Order
+    Cancel(id: string)
+  }
+
+  states Lifecycle {
+    initial Pending
+    state Pending {}
+    state Paid {}
+    Pending -> Paid on pay
+  }
+}
+`
+
+// E125: 破壊的な変更と互換性のある変更を分けて表示し、破壊的な変更があれば終了コード 5
+func TestCLI_Diff_Files(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"old.pact": diffOldSpec,
+		"new.pact": strings.NewReplacer(
+			"    id: string\n", "    id: string\n    note: string?\n",
+			"Create(amount: float) -> Order", "Create(amount: int) -> Order throws Declined",
+			"    Cancel(id: string)\n", "",
+			"    state Paid {}\n", "    state Paid {}\n    state Cancelled {}\n    Pending -> Cancelled on cancel\n",
+		).Replace(diffOldSpec),
+	})
+
+	out, code := runExitCode(t, dir, binary, "diff", "old.pact", "new.pact")
+	if code != 5 {
+		t.Fatalf("expected exit 5, got %d: %s", code, out)
+	}
+	for _, want := range []string{
+		"Breaking changes (3):\n",
+		"  ~ new.pact:8:12: changed parameter 'OrderService.OrderAPI.Create(amount)': float -> int (narrowed type)\n",
+		"  ~ new.pact:8:5: changed method 'OrderService.OrderAPI.Create': no throws -> throws Declined (newly thrown 'Declined')\n",
+		"  - old.pact:8:5: removed method 'OrderService.OrderAPI.Cancel': Cancel(id: string)\n",
+		"Compatible changes (3):\n",
+		"  + new.pact:4:5: added field 'Order.note': string? (optional field)\n",
+		"  + new.pact:15:5: added state 'OrderService.Lifecycle.Cancelled'\n",
+		"  + new.pact:16:5: added transition 'OrderService.Lifecycle: Pending -> Cancelled on cancel'\n",
+		"3 breaking, 3 compatible change(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out, code = runExitCode(t, dir, binary, "diff", "old.pact", "old.pact")
+	if code != 0 || !strings.Contains(out, "No changes") {
+		t.Errorf("expected no changes (%d): %s", code, out)
+	}
+}
+
+// E126: JSON で出力する
+func TestCLI_Diff_JSON(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"old.pact": diffOldSpec,
+		"new.pact": strings.Replace(diffOldSpec, "    Cancel(id: string)\n", "", 1),
+	})
+
+	out, code := runExitCode(t, dir, binary, "diff", "--format", "json", "old.pact", "new.pact")
+	if code != 5 {
+		t.Fatalf("expected exit 5, got %d: %s", code, out)
+	}
+	jsonOut := out[:strings.LastIndex(out, "}")+1]
+	var result struct {
+		Breaking   int `json:"breaking"`
+		Compatible int `json:"compatible"`
+		Changes    []struct {
+			Kind     string `json:"kind"`
+			Element  string `json:"element"`
+			Name     string `json:"name"`
+			Breaking bool   `json:"breaking"`
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(jsonOut), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Breaking != 1 || result.Compatible != 0 || len(result.Changes) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	c := result.Changes[0]
+	if c.Kind != "removed" || c.Element != "method" || c.Name != "OrderService.OrderAPI.Cancel" || !c.Breaking || c.File != "old.pact" || c.Line != 8 {
+		t.Errorf("unexpected change: %+v", c)
+	}
+}
+
+// E127: ディレクトリどうしを比較し、ファイル間の移動は変更にしない
+func TestCLI_Diff_Directories(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"v1/order.pact":       "component Order {\n  depends on Payment\n}\n\ncomponent Payment {}\n",
+		"v2/order.pact":       "component Order {\n  depends on Payment\n  depends on Mailer\n}\n",
+		"v2/billing/pay.pact": "component Payment {}\n\ncomponent Mailer {}\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "diff", "v1", "v2")
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, out)
+	}
+	for _, want := range []string{
+		"  + v2/order.pact:3:3: added relation 'Order depends on Mailer'\n",
+		"  + v2/billing/pay.pact:3:1: added component 'Mailer'\n",
+		"0 breaking, 2 compatible change(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "'Payment'") {
+		t.Errorf("moving a component to another file is not a change:\n%s", out)
+	}
+
+	if out, code := runExitCode(t, dir, binary, "diff", "v1", "v2/order.pact"); code == 0 || !strings.Contains(out, "cannot compare a file with a directory") {
+		t.Errorf("expected an error (%d): %s", code, out)
+	}
+}
+
+// =============================================================================
+// E128: check --cycles の同じ名前のコンポーネント
+// =============================================================================
+
+// E128: 別の仕様にある同じ名前のコンポーネントへの依存は循環にしない
+func TestCLI_Check_CyclesSameName(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"sales/order.pact":   "import \"../billing/order.pact\" as billing\n\ncomponent Order {\n  depends on billing.Order\n}\n",
+		"billing/order.pact": "component Order {}\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "check", "--cycles", "sales/order.pact")
+	if code != 0 || !strings.Contains(out, "No dependency cycles") {
+		t.Errorf("expected no cycles (%d): %s", code, out)
+	}
+}
+
+// =============================================================================
+// E129-E130: パス指定なしの check
+// =============================================================================
+
+// E129: パス指定なしなら pact_root の仕様の循環を検査する
+func TestCLI_Check_CyclesInProject(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	cmd := exec.Command(binary, "init")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("init failed: %v\noutput: %s", err, output)
+	}
+	writeFiles(t, dir, map[string]string{
+		".pact/a.pact": "component A {\n  depends on B\n}\n",
+		".pact/b.pact": "component B {\n  depends on A\n}\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "check", "--cycles")
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d: %s", code, out)
+	}
+	if !strings.Contains(out, "  A -> B -> A\n") || !strings.Contains(out, "dependency cycles found") {
+		t.Errorf("expected the cycle in .pact to be reported:\n%s", out)
+	}
+	if strings.Contains(out, "Error parsing") {
+		t.Errorf("the .pact directory should not be parsed as a file:\n%s", out)
+	}
+}
+
+// E130: パス指定なしなら pact_root の仕様をルールと照合する
+func TestCLI_Check_RulesInProject(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeLayeredProject(t, dir)
+
+	out, code := runExitCode(t, dir, binary, "check", "--rules")
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d: %s", code, out)
+	}
+	if !strings.Contains(out, "rule 'domain-independence': OrderService depends on OrderRepository is forbidden") {
+		t.Errorf("expected the violation in .pact to be reported:\n%s", out)
+	}
+	if strings.Contains(out, "Error parsing") {
+		t.Errorf("the .pact directory should not be parsed as a file:\n%s", out)
+	}
+}
+
+// =============================================================================
+// E131-E132: dir/... の展開
+// =============================================================================
+
+// E131: ./... は既定の pact_root（.pact）の仕様も検証する
+func TestCLI_Validate_RecursiveIntoPactRoot(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+
+	cmd := exec.Command(binary, "init")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("init failed: %v\noutput: %s", err, output)
+	}
+	writeFiles(t, dir, map[string]string{
+		".pact/order.pact": "component Order { }\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "validate", "./...")
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, out)
+	}
+	if !strings.Contains(out, filepath.Join(".pact", "order.pact")+": OK") {
+		t.Errorf("expected the spec in .pact to be validated:\n%s", out)
+	}
+}
+
+// E132: 何にも一致しない展開は成功扱いにしない
+func TestCLI_EmptyExpansionFails(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		"empty/notes.md": "",
+	})
+
+	for _, args := range [][]string{
+		{"validate", "nothing/..."},
+		{"validate", "empty"},
+		{"check", "empty"},
+		{"check", "--cycles", "empty/..."},
+	} {
+		out, code := runExitCode(t, dir, binary, args...)
+		if code == 0 {
+			t.Errorf("%v: expected a non-zero exit code:\n%s", args, out)
+		}
+		if strings.Contains(out, "All files valid!") {
+			t.Errorf("%v: nothing was validated:\n%s", args, out)
+		}
+	}
+
+	out, _ := runExitCode(t, dir, binary, "validate", "nothing/...")
+	if !strings.Contains(out, "file not found: nothing/...") {
+		t.Errorf("expected the unmatched pattern to be reported:\n%s", out)
+	}
+}
+
+// =============================================================================
+// E133: check --missing と他の検査の組み合わせ
+// =============================================================================
+
+// E133: プロジェクト内の --missing --cycles はカバレッジの後に循環も検査する
+func TestCLI_Check_MissingWithCycles(t *testing.T) {
+	binary := buildCLI(t)
+	dir := setupTestDir(t)
+	writeFiles(t, dir, map[string]string{
+		".pactconfig":  "source_root: ./src\npact_root: ./.pact\nlanguage: go\n",
+		"src/a.go":     "package src",
+		"src/b.go":     "package src",
+		".pact/a.pact": "component A {\n  depends on B\n}\n",
+		".pact/b.pact": "component B {\n  depends on A\n}\n",
+	})
+
+	out, code := runExitCode(t, dir, binary, "check", "--missing", "--cycles")
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d: %s", code, out)
+	}
+	for _, want := range []string{"Coverage: 100.0% (2/2 source files)", "  A -> B -> A\n", "dependency cycles found"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// JSON では両方の結果を1つの文書にまとめる
+	cmd := exec.Command(binary, "check", "--missing", "--cycles", "--format", "json")
+	cmd.Dir = dir
+	stdout, _ := cmd.Output()
+	var report struct {
+		Diagnostics []struct {
+			Code string `json:"code"`
+		} `json:"diagnostics"`
+		Properties map[string]float64 `json:"properties"`
+	}
+	if err := json.Unmarshal(stdout, &report); err != nil {
+		t.Fatalf("stdout is not a single JSON document: %v\n%s", err, stdout)
+	}
+	if len(report.Diagnostics) != 1 || report.Diagnostics[0].Code != "dependency-cycle" {
+		t.Errorf("unexpected diagnostics: %s", stdout)
+	}
+	if report.Properties["coverage"] != 100 || report.Properties["files"] != 2 {
+		t.Errorf("unexpected properties: %v", report.Properties)
+	}
+}
+
+// =============================================================================
+// E134: 同梱のサンプル
+// =============================================================================
+
+// E134: sample/ の仕様はすべて既定の検証を通る
+func TestCLI_Validate_Samples(t *testing.T) {
+	binary := buildCLI(t)
+	samples, err := filepath.Abs("../../sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, code := runExitCode(t, samples, binary, "validate", "./...")
+	if code != 0 {
+		t.Fatalf("expected the samples to validate, got exit %d:\n%s", code, out)
+	}
+	if !strings.Contains(out, filepath.Join("pact", "sequence", "04_chain_4.pact")+": OK") {
+		t.Errorf("expected every sample to be validated:\n%s", out)
+	}
 }